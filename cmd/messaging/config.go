@@ -0,0 +1,34 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/ercancavusoglu/messaging/internal/config"
+)
+
+// runConfig handles "config print", which writes the resolved settings as YAML with the secrets
+// redacted; the output can be used as a config file once the secrets are filled in
+func runConfig(cfg *config.Config, args []string) error {
+	flags := flag.NewFlagSet("config", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: messaging config print")
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.Arg(0) != "print" {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	out, err := cfg.Redacted().YAML()
+	if err != nil {
+		return fmt.Errorf("failed to render configuration: %v", err)
+	}
+
+	_, err = os.Stdout.Write(out)
+	return err
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"flag"
+
+	"github.com/ercancavusoglu/messaging/internal/adapters"
+	"github.com/ercancavusoglu/messaging/internal/config"
+)
+
+// runConsume sends queued messages until SIGINT or SIGTERM, then waits for the messages in flight
+func runConsume(container *adapters.Container, args []string) error {
+	flags := flag.NewFlagSet("consume", flag.ExitOnError)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if container.Config.EventBus.Driver == config.DriverMemory {
+		container.Logger.Warn("[Consume] The memory event bus only reaches this process, so no messages will be queued for it; use the standalone command")
+	}
+
+	worker, err := container.NewWorker()
+	if err != nil {
+		return err
+	}
+
+	logger := container.Logger
+	logger.Info("=== Starting Message Consumer ===")
+
+	if err := worker.Start(); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go container.WatchConfig(ctx)
+
+	logger.Info("[Consume] Started successfully!")
+	logger.Info("Press Ctrl+C to shutdown...")
+	waitForSignal()
+
+	logger.Info("[Consume] Shutting down...")
+	worker.Stop()
+	logger.Info("[Consume] Shutdown complete")
+	return nil
+}
@@ -0,0 +1,163 @@
+// Command messaging runs the messaging service. Every subcommand wires only the dependencies it uses.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/ercancavusoglu/messaging/internal/adapters"
+	"github.com/ercancavusoglu/messaging/internal/config"
+	"github.com/joho/godotenv"
+)
+
+const usage = `usage: messaging [-config file] [-<setting>=<value> ...] <command> [arguments]
+
+commands:
+  serve           run the HTTP API and the scheduler
+  consume         run the workers that send queued messages
+  standalone      run the API, the scheduler and the workers in one process
+  migrate         apply or revert database migrations
+  send            create a message from the terminal
+  replay-failed   move failed messages back to pending
+  status          show the scheduler status and message counts
+  config print    show the resolved configuration with secrets redacted
+
+Settings come from the defaults, the config file, the environment and the flags, in increasing
+precedence. Run "messaging -h" for the settings and "messaging <command> -h" for the arguments of
+a command.`
+
+type command func(container *adapters.Container, args []string) error
+
+var commands = map[string]command{
+	"serve":         runServe,
+	"consume":       runConsume,
+	"standalone":    runStandalone,
+	"migrate":       runMigrate,
+	"send":          runSend,
+	"replay-failed": runReplayFailed,
+	"status":        runStatus,
+}
+
+// envFile holds variables for local development; the process environment takes precedence over it
+const envFile = ".env"
+
+func main() {
+	// A missing .env file is fine when the environment is configured otherwise, e.g. in containers
+	dotenv, envErr := godotenv.Read(envFile)
+
+	global := flag.NewFlagSet("messaging", flag.ExitOnError)
+	global.Usage = func() {
+		fmt.Fprintf(global.Output(), "%s\n\nsettings:\n", usage)
+		global.PrintDefaults()
+	}
+	defaultConfigFile, _ := environment(dotenv)("CONFIG_FILE")
+	configFile := global.String("config", defaultConfigFile, "YAML config file (CONFIG_FILE)")
+	for _, setting := range config.Default().Fields() {
+		if setting.Env == "" {
+			continue
+		}
+		global.String(setting.Key, "", fmt.Sprintf("overrides %s", setting.Env))
+	}
+	global.Parse(os.Args[1:])
+
+	if global.NArg() < 1 {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	name := global.Arg(0)
+	if name == "help" {
+		fmt.Println(usage)
+		return
+	}
+
+	run, ok := commands[name]
+	if !ok && name != "config" {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s\n", name, usage)
+		os.Exit(2)
+	}
+
+	overrides := make(map[string]string)
+	global.Visit(func(f *flag.Flag) {
+		if f.Name != "config" {
+			overrides[f.Name] = f.Value.String()
+		}
+	})
+
+	load := func() (*config.Config, error) {
+		return loadConfig(*configFile, overrides)
+	}
+
+	cfg, err := load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid configuration:\n%v\n", err)
+		os.Exit(1)
+	}
+
+	// Printing the configuration needs neither the logger nor any connection
+	if name == "config" {
+		if err := runConfig(cfg, global.Args()[1:]); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", name, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	container, err := adapters.NewContainer(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to initialize container: %v\n", err)
+		os.Exit(1)
+	}
+
+	if envErr != nil {
+		container.Logger.Warnf("Warning: .env file not found or error loading it: %v", envErr)
+	}
+	container.ConfigWatcher = config.NewWatcher(load, cfg, *configFile, envFile)
+
+	err = run(container, global.Args()[1:])
+	if closeErr := container.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", name, err)
+		os.Exit(1)
+	}
+}
+
+// loadConfig resolves and validates the configuration. The .env file is read on every call, so a
+// reload picks up values rotated in it.
+func loadConfig(path string, overrides map[string]string) (*config.Config, error) {
+	dotenv, _ := godotenv.Read(envFile)
+
+	cfg, err := config.Load(path, environment(dotenv), overrides)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// environment looks a variable up in the process environment, then in the .env variables
+func environment(dotenv map[string]string) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		if value, ok := os.LookupEnv(name); ok {
+			return value, true
+		}
+		value, ok := dotenv[name]
+		return value, ok
+	}
+}
+
+// waitForSignal blocks until the process is asked to stop
+func waitForSignal() {
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	<-sigChan
+}
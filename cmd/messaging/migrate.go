@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/ercancavusoglu/messaging/internal/adapters"
+)
+
+const migrateUsage = `usage: messaging migrate <command>
+
+commands:
+  up [version]   apply pending migrations, up to version when given
+  down [steps]   revert the last applied migrations (default 1)
+  status         list migrations and whether they are applied
+  version        print the current and the expected schema version`
+
+// runMigrate only connects to Postgres and skips the schema check the other commands make
+func runMigrate(container *adapters.Container, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing command\n%s", migrateUsage)
+	}
+
+	migrator, err := container.Migrator()
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "up":
+		target, err := optionalNumber(args[1:], 0)
+		if err != nil {
+			return err
+		}
+
+		applied, err := migrator.Up(target)
+		for _, migration := range applied {
+			fmt.Printf("applied %03d_%s\n", migration.Version, migration.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			fmt.Println("no pending migrations")
+		}
+	case "down":
+		steps, err := optionalNumber(args[1:], 1)
+		if err != nil {
+			return err
+		}
+
+		reverted, err := migrator.Down(steps)
+		for _, migration := range reverted {
+			fmt.Printf("reverted %03d_%s\n", migration.Version, migration.Name)
+		}
+		if err != nil {
+			return err
+		}
+	case "status":
+		statuses, err := migrator.Status()
+		if err != nil {
+			return err
+		}
+
+		for _, status := range statuses {
+			applied := "pending"
+			if status.AppliedAt != nil {
+				applied = "applied " + status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%03d_%-40s %s\n", status.Version, status.Name, applied)
+		}
+	case "version":
+		version, err := migrator.Version()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("database: %d, release: %d\n", version, migrator.Latest())
+	default:
+		return fmt.Errorf("unknown command %q\n%s", args[0], migrateUsage)
+	}
+
+	return nil
+}
+
+func optionalNumber(args []string, fallback int) (int, error) {
+	if len(args) == 0 {
+		return fallback, nil
+	}
+
+	value, err := strconv.Atoi(args[0])
+	if err != nil || value < 1 {
+		return 0, fmt.Errorf("invalid number: %q", args[0])
+	}
+	return value, nil
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/ercancavusoglu/messaging/internal/adapters"
+	"github.com/ercancavusoglu/messaging/internal/adapters/persistance/postgres"
+	"github.com/ercancavusoglu/messaging/internal/ports"
+)
+
+// runReplayFailed moves failed messages back to pending so the scheduler sends them again
+func runReplayFailed(container *adapters.Container, args []string) error {
+	flags := flag.NewFlagSet("replay-failed", flag.ExitOnError)
+	tenantID := flags.Int64("tenant", 0, "only replay the messages of this tenant")
+	since := flags.Duration("since", 24*time.Hour, "only replay messages created within this window")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	db, err := container.Database()
+	if err != nil {
+		return err
+	}
+
+	var repo ports.Repository = postgres.NewMessageRepository(db)
+	if *tenantID != 0 {
+		repo = repo.ForTenant(*tenantID)
+	}
+
+	requeued, err := repo.RequeueFailed(time.Now().Add(-*since))
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("requeued %d failed messages\n", requeued)
+	return nil
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/ercancavusoglu/messaging/internal/adapters"
+	"github.com/ercancavusoglu/messaging/internal/domain"
+)
+
+// runSend stores a pending message for a tenant; the scheduler of a running server picks it up like any
+// message created through the API
+func runSend(container *adapters.Container, args []string) error {
+	flags := flag.NewFlagSet("send", flag.ExitOnError)
+	tenantID := flags.Int64("tenant", 0, "tenant the message is sent for (required)")
+	channel := flags.String("channel", "", "sms, email, push or whatsapp (default sms)")
+	to := flags.String("to", "", "recipient phone number, email address or device token (required)")
+	subject := flags.String("subject", "", "email subject")
+	content := flags.String("content", "", "message content")
+	priority := flags.String("priority", "", "critical, normal or bulk (default normal)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *tenantID == 0 || *to == "" {
+		flags.Usage()
+		return errors.New("-tenant and -to are required")
+	}
+
+	messages, tenants, err := container.MessageStore()
+	if err != nil {
+		return err
+	}
+
+	tenant, err := tenants.Get(*tenantID)
+	if err != nil {
+		return err
+	}
+
+	class, err := domain.ParsePriority(*priority)
+	if err != nil {
+		return err
+	}
+
+	kind, err := domain.ParseChannel(*channel)
+	if err != nil {
+		return err
+	}
+
+	msg, err := domain.NewChannelMessage(kind, *to, *subject, *content, container.MessageRules())
+	if err != nil {
+		return err
+	}
+	msg.Priority = class
+
+	if err := messages.CreateMessage(tenant, msg); err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(msg); err != nil {
+		return fmt.Errorf("failed to print message: %v", err)
+	}
+
+	return nil
+}
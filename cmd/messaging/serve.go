@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"time"
+
+	"github.com/ercancavusoglu/messaging/internal/adapters"
+	"github.com/ercancavusoglu/messaging/internal/config"
+)
+
+// runServe runs the HTTP API and competes for the scheduler leadership until SIGINT or SIGTERM
+func runServe(container *adapters.Container, args []string) error {
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if container.Config.EventBus.Driver == config.DriverMemory {
+		container.Logger.Warn("[Serve] The memory event bus only reaches this process, so no worker will send the queued messages; use the standalone command")
+	}
+
+	server, err := container.NewServer()
+	if err != nil {
+		return err
+	}
+
+	logger := container.Logger
+	logger.Info("=== Starting Messaging API ===")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if err := server.Start(ctx); err != nil {
+		return err
+	}
+	go container.WatchConfig(ctx)
+
+	logger.Info("[Serve] Started successfully!")
+	logger.Info("Press Ctrl+C to shutdown...")
+	waitForSignal()
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer shutdownCancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+
+	logger.Info("[Serve] Shutdown complete")
+	return nil
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"time"
+
+	"github.com/ercancavusoglu/messaging/internal/adapters"
+)
+
+// runStandalone runs the HTTP API, the scheduler and the workers in one process until SIGINT or SIGTERM.
+// With the memory event bus driver it needs no RabbitMQ.
+func runStandalone(container *adapters.Container, args []string) error {
+	flags := flag.NewFlagSet("standalone", flag.ExitOnError)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	server, err := container.NewServer()
+	if err != nil {
+		return err
+	}
+
+	worker, err := container.NewWorker()
+	if err != nil {
+		return err
+	}
+
+	logger := container.Logger
+	logger.Info("=== Starting Messaging (standalone) ===")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if err := worker.Start(); err != nil {
+		return err
+	}
+	if err := server.Start(ctx); err != nil {
+		return err
+	}
+	go container.WatchConfig(ctx)
+
+	logger.Info("[Standalone] Started successfully!")
+	logger.Info("Press Ctrl+C to shutdown...")
+	waitForSignal()
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer shutdownCancel()
+
+	// Nothing new is queued once the API and the scheduler stopped; the workers then handle what the
+	// event bus still holds before they stop
+	shutdownErr := server.Shutdown(shutdownCtx)
+	if err := container.CloseEventBus(); err != nil {
+		logger.Errorf("[Standalone] Event bus did not drain: %v", err)
+	}
+	worker.Stop()
+
+	if shutdownErr != nil {
+		return shutdownErr
+	}
+
+	logger.Info("[Standalone] Shutdown complete")
+	return nil
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/ercancavusoglu/messaging/internal/adapters"
+	"github.com/ercancavusoglu/messaging/internal/adapters/persistance/postgres"
+	"github.com/ercancavusoglu/messaging/internal/adapters/scheduler"
+	"github.com/ercancavusoglu/messaging/internal/domain"
+)
+
+// runStatus prints the schema version, the cluster-wide scheduler status and the message counts
+func runStatus(container *adapters.Container, args []string) error {
+	flags := flag.NewFlagSet("status", flag.ExitOnError)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	migrator, err := container.Migrator()
+	if err != nil {
+		return err
+	}
+
+	version, err := migrator.Version()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("schema:     version %d of %d\n", version, migrator.Latest())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	state, err := container.SchedulerState()
+	if err != nil {
+		return err
+	}
+
+	status, err := scheduler.ReadStatus(ctx, state, container.Config.Scheduler.Interval)
+	if err != nil {
+		return err
+	}
+
+	if status.Running {
+		fmt.Printf("scheduler:  running on %s every %s\n", status.Leader, status.Interval)
+	} else {
+		fmt.Printf("scheduler:  stopped (interval %s)\n", status.Interval)
+	}
+	if status.LastTickAt != nil {
+		fmt.Printf("last tick:  %s, published %d, failed %d\n",
+			status.LastTickAt.Format(time.RFC3339), status.LastTickPublished, status.LastTickFailed)
+	}
+	if status.LastError != "" {
+		fmt.Printf("last error: %s\n", status.LastError)
+	}
+
+	db, err := container.Database()
+	if err != nil {
+		return err
+	}
+
+	counts, err := postgres.NewMessageRepository(db).CountByStatus()
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("messages:")
+	for _, messageStatus := range []domain.MessageStatus{
+		domain.StatusPending, domain.StatusQueued, domain.StatusSending, domain.StatusSent,
+		domain.StatusFailed, domain.StatusSuppressed, domain.StatusCancelled,
+	} {
+		fmt.Printf("  %-11s %d\n", messageStatus, counts[messageStatus])
+	}
+
+	return nil
+}
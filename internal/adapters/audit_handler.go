@@ -0,0 +1,59 @@
+package adapters
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/ercancavusoglu/messaging/internal/domain"
+	"github.com/ercancavusoglu/messaging/internal/ports"
+)
+
+type AuditHandler struct {
+	auditService ports.AuditService
+}
+
+func NewAuditHandler(auditService ports.AuditService) *AuditHandler {
+	return &AuditHandler{
+		auditService: auditService,
+	}
+}
+
+func (h *AuditHandler) ListAuditLogs(w http.ResponseWriter, r *http.Request) {
+	limit := 0
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid limit: %q", value))
+			return
+		}
+		limit = parsed
+	}
+
+	entries, err := h.auditService.List(limit)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, entries)
+}
+
+// recordAudit stores who performed action on behalf of the request; failures are logged, not returned,
+// because the action itself has already happened
+func recordAudit(auditService ports.AuditService, r *http.Request, action, result string) {
+	actor := "anonymous"
+	if principal, ok := PrincipalFromContext(r.Context()); ok {
+		actor = principal.Actor
+	}
+
+	entry := &domain.AuditEntry{
+		Actor:      actor,
+		Action:     action,
+		Result:     result,
+		RemoteAddr: r.RemoteAddr,
+	}
+	if err := auditService.Record(entry); err != nil {
+		fmt.Printf("[Audit] Failed to record %s by %s: %v\n", action, actor, err)
+	}
+}
@@ -0,0 +1,40 @@
+package adapters
+
+import (
+	"github.com/ercancavusoglu/messaging/internal/domain"
+	"github.com/ercancavusoglu/messaging/internal/ports"
+)
+
+const (
+	defaultAuditListLimit = 100
+	maxAuditListLimit     = 1000
+)
+
+type auditService struct {
+	repo   ports.AuditRepository
+	logger ports.Logger
+}
+
+func NewAuditService(repo ports.AuditRepository, logger ports.Logger) ports.AuditService {
+	return &auditService{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// Record persists the entry and mirrors it to the application log, so the action is traceable
+// even when the database write fails
+func (s *auditService) Record(entry *domain.AuditEntry) error {
+	s.logger.Infof("[Audit] actor=%s action=%s result=%q remote=%s", entry.Actor, entry.Action, entry.Result, entry.RemoteAddr)
+	return s.repo.Create(entry)
+}
+
+func (s *auditService) List(limit int) ([]*domain.AuditEntry, error) {
+	if limit <= 0 {
+		limit = defaultAuditListLimit
+	}
+	if limit > maxAuditListLimit {
+		limit = maxAuditListLimit
+	}
+	return s.repo.List(limit)
+}
@@ -0,0 +1,42 @@
+package adapters
+
+import (
+	"testing"
+
+	"github.com/ercancavusoglu/messaging/internal/adapters/mocks"
+	"github.com/ercancavusoglu/messaging/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAuditService_Record(t *testing.T) {
+	mockRepo := &mocks.MockAuditRepository{}
+	mockLogger := &mocks.MockLogger{}
+	service := NewAuditService(mockRepo, mockLogger)
+
+	entry := &domain.AuditEntry{Actor: "admin", Action: domain.AuditActionSchedulerStop, Result: "ok"}
+	mockLogger.On("Infof", mock.Anything, mock.Anything).Return()
+	mockRepo.On("Create", entry).Return(nil)
+
+	assert.NoError(t, service.Record(entry))
+
+	// Kayıt veritabanına yazılmadan önce loglanmalı
+	mockLogger.AssertExpectations(t)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAuditService_List_ClampsLimit(t *testing.T) {
+	mockRepo := &mocks.MockAuditRepository{}
+	service := NewAuditService(mockRepo, &mocks.MockLogger{})
+
+	mockRepo.On("List", defaultAuditListLimit).Return([]*domain.AuditEntry{}, nil)
+	mockRepo.On("List", maxAuditListLimit).Return([]*domain.AuditEntry{}, nil)
+
+	_, err := service.List(0)
+	assert.NoError(t, err)
+
+	_, err = service.List(1000000)
+	assert.NoError(t, err)
+
+	mockRepo.AssertExpectations(t)
+}
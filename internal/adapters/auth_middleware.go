@@ -0,0 +1,130 @@
+package adapters
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/ercancavusoglu/messaging/internal/domain"
+	"github.com/ercancavusoglu/messaging/internal/ports"
+	"github.com/gorilla/mux"
+)
+
+type contextKey string
+
+const principalContextKey contextKey = "principal"
+
+var (
+	errMissingAPIKey   = errors.New("missing api key")
+	errMissingTenant   = errors.New("endpoint requires a tenant api key")
+	errCrossOriginCall = errors.New("cross-origin requests are not allowed")
+)
+
+// AuthMiddleware authenticates requests with an API key sent as "Authorization: Bearer <key>" or
+// "X-API-Key: <key>". The operator key (ADMIN_API_KEY) gets every scope; any other key must belong
+// to an active tenant. Keys are never read from cookies or the query string, so a browser cannot
+// attach them to a forged cross-site request.
+func AuthMiddleware(tenantService ports.TenantService, adminAPIKey string) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			apiKey := apiKeyFromRequest(r)
+			if apiKey == "" {
+				writeError(w, http.StatusUnauthorized, errMissingAPIKey)
+				return
+			}
+
+			if adminAPIKey != "" && subtle.ConstantTimeCompare([]byte(apiKey), []byte(adminAPIKey)) == 1 {
+				next.ServeHTTP(w, r.WithContext(WithPrincipal(r.Context(), domain.AdminPrincipal())))
+				return
+			}
+
+			tenant, err := tenantService.Authenticate(apiKey)
+			if errors.Is(err, domain.ErrInvalidAPIKey) {
+				writeError(w, http.StatusUnauthorized, err)
+				return
+			}
+			if err != nil {
+				writeError(w, http.StatusInternalServerError, err)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(WithPrincipal(r.Context(), domain.TenantPrincipal(tenant))))
+		})
+	}
+}
+
+// RequireScope rejects callers whose API key was not granted the scope
+func RequireScope(scope domain.Scope) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, ok := PrincipalFromContext(r.Context())
+			if !ok {
+				writeError(w, http.StatusUnauthorized, errMissingAPIKey)
+				return
+			}
+
+			if !principal.HasScope(scope) {
+				writeError(w, http.StatusForbidden, fmt.Errorf("api key lacks the %s scope", scope))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// SameOriginMiddleware rejects state-changing requests that a browser sent from another origin
+func SameOriginMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			if origin := r.Header.Get("Origin"); origin != "" {
+				parsed, err := url.Parse(origin)
+				if err != nil || !strings.EqualFold(parsed.Host, r.Host) {
+					writeError(w, http.StatusForbidden, errCrossOriginCall)
+					return
+				}
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func WithPrincipal(ctx context.Context, principal *domain.Principal) context.Context {
+	return context.WithValue(ctx, principalContextKey, principal)
+}
+
+func PrincipalFromContext(ctx context.Context) (*domain.Principal, bool) {
+	principal, ok := ctx.Value(principalContextKey).(*domain.Principal)
+	return principal, ok && principal != nil
+}
+
+// WithTenant is a shorthand for storing a tenant principal
+func WithTenant(ctx context.Context, tenant *domain.Tenant) context.Context {
+	return WithPrincipal(ctx, domain.TenantPrincipal(tenant))
+}
+
+func TenantFromContext(ctx context.Context) (*domain.Tenant, bool) {
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok || principal.Tenant == nil {
+		return nil, false
+	}
+	return principal.Tenant, true
+}
+
+func apiKeyFromRequest(r *http.Request) string {
+	if key := r.Header.Get("X-API-Key"); key != "" {
+		return strings.TrimSpace(key)
+	}
+
+	scheme, token, found := strings.Cut(r.Header.Get("Authorization"), " ")
+	if found && strings.EqualFold(scheme, "Bearer") {
+		return strings.TrimSpace(token)
+	}
+
+	return ""
+}
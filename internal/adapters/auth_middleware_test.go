@@ -0,0 +1,150 @@
+package adapters
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ercancavusoglu/messaging/internal/adapters/mocks"
+	"github.com/ercancavusoglu/messaging/internal/adapters/stream"
+	"github.com/ercancavusoglu/messaging/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// principalEcho writes the authenticated actor so tests can assert what reached the handler
+var principalEcho = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	principal, _ := PrincipalFromContext(r.Context())
+	w.Write([]byte(principal.Actor))
+})
+
+func TestAuthMiddleware(t *testing.T) {
+	mockService := &mocks.MockTenantService{}
+	handler := AuthMiddleware(mockService, "admin-secret")(principalEcho)
+
+	mockService.On("Authenticate", "msk_valid").Return(&domain.Tenant{ID: 7, Name: "payments"}, nil)
+	mockService.On("Authenticate", "msk_revoked").Return(nil, domain.ErrInvalidAPIKey)
+
+	tests := []struct {
+		name          string
+		header        string
+		value         string
+		expected      int
+		expectedActor string
+	}{
+		{name: "Bearer token", header: "Authorization", value: "Bearer msk_valid", expected: http.StatusOK, expectedActor: "tenant:7"},
+		{name: "API key header", header: "X-API-Key", value: "msk_valid", expected: http.StatusOK, expectedActor: "tenant:7"},
+		{name: "Admin key", header: "X-API-Key", value: "admin-secret", expected: http.StatusOK, expectedActor: domain.AdminActor},
+		{name: "Invalid key", header: "X-API-Key", value: "msk_revoked", expected: http.StatusUnauthorized},
+		{name: "Missing key", expected: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/messages", nil)
+			if tt.header != "" {
+				req.Header.Set(tt.header, tt.value)
+			}
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expected, w.Code)
+			if tt.expected == http.StatusOK {
+				assert.Equal(t, tt.expectedActor, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestAuthMiddleware_EmptyAdminKeyDisablesAdmin(t *testing.T) {
+	mockService := &mocks.MockTenantService{}
+	handler := AuthMiddleware(mockService, "")(principalEcho)
+
+	mockService.On("Authenticate", "").Return(nil, domain.ErrInvalidAPIKey)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/messages", nil)
+	req.Header.Set("Authorization", "Bearer ")
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestSameOriginMiddleware(t *testing.T) {
+	handler := SameOriginMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	req := httptest.NewRequest(http.MethodPost, "http://api.example.com/api/v1/scheduler/stop", nil)
+	req.Header.Set("Origin", "https://evil.example.com")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	req = httptest.NewRequest(http.MethodPost, "http://api.example.com/api/v1/scheduler/stop", nil)
+	req.Header.Set("Origin", "http://api.example.com")
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func newTestRouter(tenants *mocks.MockTenantService, inbound *mocks.MockInboundService, audit *mocks.MockAuditService) http.Handler {
+	return NewRouter(
+		NewMessageHandler(&mocks.MockMessageService{}, &mocks.MockTemplateService{}, audit, nil, domain.DefaultMessageRules()),
+		NewSuppressionHandler(&mocks.MockSuppressionService{}),
+		NewInboundHandler(inbound, testInboundSignatures),
+		NewTemplateHandler(&mocks.MockTemplateService{}),
+		NewCampaignHandler(&mocks.MockCampaignService{}),
+		NewCallbackHandler(&mocks.MockCallbackService{}),
+		NewStreamHandler(stream.NewHub(&mocks.MockLogger{})),
+		NewTenantHandler(tenants),
+		NewAuditHandler(audit),
+		tenants,
+		"admin-secret",
+	)
+}
+
+func TestNewRouter_InboundRequiresSignatureInsteadOfAPIKey(t *testing.T) {
+	mockInbound := &mocks.MockInboundService{}
+	router := newTestRouter(&mocks.MockTenantService{}, mockInbound, &mocks.MockAuditService{})
+
+	mockInbound.On("Receive", mock.AnythingOfType("*domain.InboundMessage")).Return(nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/messages", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	body := `{"from":"+905551234567","content":"STOP"}`
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/inbound/client_one", strings.NewReader(body)))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	mockInbound.AssertNotCalled(t, "Receive", mock.Anything)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/inbound/client_one", strings.NewReader(body))
+	req.Header.Set("X-Signature", signInbound("inbound-key", body))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusAccepted, w.Code)
+}
+
+func TestNewRouter_SchedulerRequiresScopeAndPost(t *testing.T) {
+	mockTenants := &mocks.MockTenantService{}
+	router := newTestRouter(mockTenants, &mocks.MockInboundService{}, &mocks.MockAuditService{})
+
+	mockTenants.On("Authenticate", "msk_valid").Return(&domain.Tenant{ID: 7, Scopes: domain.DefaultTenantScopes}, nil)
+
+	// Tenant anahtarında scheduler:admin yetkisi yok
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/scheduler/stop", nil)
+	req.Header.Set("X-API-Key", "msk_valid")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	// GET artık durum değiştiremez
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/scheduler/stop", nil)
+	req.Header.Set("X-API-Key", "admin-secret")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.NotEqual(t, http.StatusOK, w.Code)
+}
@@ -0,0 +1,237 @@
+package callback
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/ercancavusoglu/messaging/internal/domain"
+	"github.com/ercancavusoglu/messaging/internal/ports"
+)
+
+const (
+	pollInterval   = time.Second
+	claimBatchSize = 50
+	// claimLease keeps a claimed delivery from other dispatchers while it is being attempted
+	claimLease     = time.Minute
+	requestTimeout = 10 * time.Second
+
+	HeaderEvent     = "X-Callback-Event"
+	HeaderTimestamp = "X-Callback-Timestamp"
+	HeaderSignature = "X-Callback-Signature"
+)
+
+// Dispatcher records message events for the tenants' callback subscriptions and POSTs them, retrying
+// failed attempts with backoff. Deliveries are stored before they are attempted, so they survive restarts.
+type Dispatcher struct {
+	repo     ports.CallbackRepository
+	client   *http.Client
+	interval time.Duration
+	stop     chan struct{}
+	wg       sync.WaitGroup
+	mu       sync.Mutex
+	running  bool
+	logger   ports.Logger
+}
+
+func NewDispatcher(repo ports.CallbackRepository, logger ports.Logger) *Dispatcher {
+	return &Dispatcher{
+		repo:     repo,
+		client:   newClient(domain.IsPublicIP),
+		interval: pollInterval,
+		logger:   logger,
+	}
+}
+
+// newClient returns the client deliveries are sent with. It only connects to addresses allowed accepts,
+// checked on the resolved address of every connection so a host cannot be re-pointed after validation,
+// ignores proxies, which would hide the target address, and does not follow redirects.
+func newClient(allowed func(net.IP) bool) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: requestTimeout,
+		Control: func(network, address string, c syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !allowed(ip) {
+				return fmt.Errorf("%w: %s", domain.ErrCallbackHostNotAllowed, host)
+			}
+			return nil
+		},
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   requestTimeout,
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// HandleEvent records a delivery of the event for every subscription of the message's tenant. It never
+// returns an error: a rejected message.queued event would be requeued and the message sent again.
+func (d *Dispatcher) HandleEvent(e ports.Event) error {
+	envelope, ok := e.(*domain.EventEnvelope)
+	if !ok {
+		d.logger.Errorf("[Callback] Unexpected event type %T", e)
+		return nil
+	}
+
+	update, err := domain.NewMessageUpdate(envelope)
+	if err != nil {
+		d.logger.Errorf("[Callback] Failed to decode event: %v", err)
+		return nil
+	}
+
+	msg := update.Message
+	if msg.TenantID == 0 {
+		return nil
+	}
+
+	subscriptions, err := d.repo.ListForEvent(msg.TenantID, update.Event)
+	if err != nil {
+		d.logger.Errorf("[Callback] Failed to list subscriptions for %s [tenant: %d]: %v", update.Event, msg.TenantID, err)
+		return nil
+	}
+
+	for _, subscription := range subscriptions {
+		delivery, err := domain.NewCallbackDelivery(subscription, msg.ID, *update)
+		if err != nil {
+			d.logger.Errorf("[Callback] Failed to build delivery: %v", err)
+			return nil
+		}
+		if err := d.repo.CreateDelivery(delivery); err != nil {
+			d.logger.Errorf("[Callback] Failed to record %s for subscription %d [message: %d]: %v", update.Event, subscription.ID, msg.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// Start polls for due deliveries until Stop is called
+func (d *Dispatcher) Start() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.running {
+		return
+	}
+	d.running = true
+	d.stop = make(chan struct{})
+
+	d.logger.Info("[Callback] Starting dispatcher...")
+	d.wg.Add(1)
+	go d.run(d.stop)
+}
+
+// Stop waits for the attempts in flight to finish
+func (d *Dispatcher) Stop() {
+	d.mu.Lock()
+	if !d.running {
+		d.mu.Unlock()
+		return
+	}
+	d.running = false
+	close(d.stop)
+	d.mu.Unlock()
+
+	d.wg.Wait()
+	d.logger.Info("[Callback] Dispatcher stopped")
+}
+
+func (d *Dispatcher) run(stop chan struct{}) {
+	defer d.wg.Done()
+
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			d.dispatchDue()
+		}
+	}
+}
+
+// dispatchDue attempts a batch of due deliveries concurrently
+func (d *Dispatcher) dispatchDue() {
+	deliveries, err := d.repo.ClaimDueDeliveries(claimBatchSize, claimLease)
+	if err != nil {
+		d.logger.Errorf("[Callback] Failed to claim deliveries: %v", err)
+		return
+	}
+
+	var wg sync.WaitGroup
+	for _, delivery := range deliveries {
+		wg.Add(1)
+		go func(delivery *domain.CallbackDelivery) {
+			defer wg.Done()
+			d.deliver(delivery)
+		}(delivery)
+	}
+	wg.Wait()
+}
+
+func (d *Dispatcher) deliver(delivery *domain.CallbackDelivery) {
+	subscription, err := d.repo.Get(delivery.TenantID, delivery.SubscriptionID)
+	if err != nil {
+		// A deleted subscription takes its deliveries with it
+		d.logger.Errorf("[Callback] Failed to load subscription %d: %v", delivery.SubscriptionID, err)
+		return
+	}
+
+	d.Attempt(subscription, delivery)
+
+	if delivery.Status == domain.CallbackFailed {
+		d.logger.Warnf("[Callback] Giving up delivery %d to %s after %d attempts: %s", delivery.ID, subscription.URL, delivery.Attempts, delivery.LastError)
+	}
+
+	if err := d.repo.UpdateDelivery(delivery); err != nil {
+		d.logger.Errorf("[Callback] Failed to update delivery %d: %v", delivery.ID, err)
+	}
+}
+
+// Attempt POSTs the delivery's payload to the subscription and records the outcome on the delivery.
+// The body is signed with the subscription's secret, see domain.SignCallback.
+func (d *Dispatcher) Attempt(subscription *domain.CallbackSubscription, delivery *domain.CallbackDelivery) {
+	now := time.Now()
+
+	req, err := http.NewRequest(http.MethodPost, subscription.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		delivery.Fail(0, fmt.Sprintf("failed to create request: %v", err), now)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, delivery.Event)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(now.Unix(), 10))
+	req.Header.Set(HeaderSignature, "sha256="+domain.SignCallback(subscription.Secret, now, delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		delivery.Fail(0, fmt.Sprintf("failed to send request: %v", err), time.Now())
+		return
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		delivery.Fail(resp.StatusCode, fmt.Sprintf("unexpected status code: %d", resp.StatusCode), time.Now())
+		return
+	}
+
+	delivery.Succeed(resp.StatusCode, time.Now())
+}
@@ -0,0 +1,204 @@
+package callback
+
+import (
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ercancavusoglu/messaging/internal/adapters/mocks"
+	"github.com/ercancavusoglu/messaging/internal/domain"
+	"github.com/ercancavusoglu/messaging/internal/ports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockLogger struct {
+	ports.Logger
+}
+
+func (m *mockLogger) Info(args ...interface{})                  {}
+func (m *mockLogger) Infof(format string, args ...interface{})  {}
+func (m *mockLogger) Errorf(format string, args ...interface{}) {}
+func (m *mockLogger) Warnf(format string, args ...interface{})  {}
+
+// newLocalDispatcher returns a dispatcher allowed to reach the loopback test servers
+func newLocalDispatcher(repo ports.CallbackRepository) *Dispatcher {
+	dispatcher := NewDispatcher(repo, &mockLogger{})
+	dispatcher.client = newClient(func(net.IP) bool { return true })
+	return dispatcher
+}
+
+func envelope(t *testing.T, event ports.Event) *domain.EventEnvelope {
+	data, err := json.Marshal(event)
+	require.NoError(t, err)
+	return &domain.EventEnvelope{Name: event.EventName(), OccurredOn: event.OccurredAt(), AggregateID: event.GetAggregateID(), Data: data}
+}
+
+func TestDispatcher_HandleEvent(t *testing.T) {
+	repo := &mocks.MockCallbackRepository{}
+	dispatcher := NewDispatcher(repo, &mockLogger{})
+
+	msg := &domain.Message{ID: 11, TenantID: 7, Channel: domain.ChannelSMS, To: "+905551234567", Content: "Merhaba", Status: domain.StatusQueued}
+	subscription := &domain.CallbackSubscription{ID: 3, TenantID: 7, Events: []string{domain.EventMessageSent}}
+
+	repo.On("ListForEvent", int64(7), domain.EventMessageSent).Return([]*domain.CallbackSubscription{subscription}, nil)
+	repo.On("CreateDelivery", mock.MatchedBy(func(delivery *domain.CallbackDelivery) bool {
+		var payload domain.MessageUpdate
+		json.Unmarshal(delivery.Payload, &payload)
+		return delivery.SubscriptionID == 3 && delivery.MessageID == 11 && delivery.Status == domain.CallbackPending &&
+			payload.Event == domain.EventMessageSent && payload.Message.Status == domain.StatusSent && payload.Message.MessageID == "prov-1" && payload.Message.Provider == "client_one"
+	})).Return(nil)
+
+	event := domain.NewMessageSentEvent(msg, "prov-1", "client_one")
+	assert.NoError(t, dispatcher.HandleEvent(envelope(t, &event)))
+
+	repo.AssertExpectations(t)
+}
+
+func TestDispatcher_HandleEvent_NeverRejects(t *testing.T) {
+	repo := &mocks.MockCallbackRepository{}
+	dispatcher := NewDispatcher(repo, &mockLogger{})
+
+	// Reddedilen message.queued olayı mesajın yeniden gönderilmesine yol açar
+	repo.On("ListForEvent", int64(7), domain.EventMessageQueued).Return(nil, assert.AnError)
+
+	event := domain.NewMessageQueuedEvent(&domain.Message{ID: 11, TenantID: 7})
+	assert.NoError(t, dispatcher.HandleEvent(envelope(t, &event)))
+
+	// Kiracısız mesajların aboneliği olamaz
+	untenanted := domain.NewMessageQueuedEvent(&domain.Message{ID: 12})
+	assert.NoError(t, dispatcher.HandleEvent(envelope(t, &untenanted)))
+
+	repo.AssertNumberOfCalls(t, "ListForEvent", 1)
+}
+
+func TestDispatcher_Attempt_Signed(t *testing.T) {
+	var received *http.Request
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	dispatcher := newLocalDispatcher(&mocks.MockCallbackRepository{})
+	subscription := &domain.CallbackSubscription{ID: 3, TenantID: 7, URL: server.URL, Secret: "whsec_abc"}
+	delivery := &domain.CallbackDelivery{ID: 5, Event: domain.EventMessageSent, Payload: json.RawMessage(`{"event":"message.sent"}`), Status: domain.CallbackPending}
+
+	dispatcher.Attempt(subscription, delivery)
+
+	assert.Equal(t, domain.CallbackDelivered, delivery.Status)
+	assert.Equal(t, http.StatusNoContent, delivery.ResponseStatus)
+	assert.Equal(t, 1, delivery.Attempts)
+	assert.NotNil(t, delivery.DeliveredAt)
+
+	require.NotNil(t, received)
+	assert.Equal(t, `{"event":"message.sent"}`, string(body))
+	assert.Equal(t, domain.EventMessageSent, received.Header.Get(HeaderEvent))
+
+	unix, err := strconv.ParseInt(received.Header.Get(HeaderTimestamp), 10, 64)
+	require.NoError(t, err)
+	expected := "sha256=" + domain.SignCallback("whsec_abc", time.Unix(unix, 0), body)
+	assert.Equal(t, expected, received.Header.Get(HeaderSignature))
+}
+
+func TestDispatcher_Attempt_RetriesWithBackoff(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	dispatcher := newLocalDispatcher(&mocks.MockCallbackRepository{})
+	subscription := &domain.CallbackSubscription{ID: 3, URL: server.URL, Secret: "whsec_abc"}
+	delivery := &domain.CallbackDelivery{ID: 5, Payload: json.RawMessage(`{}`), Status: domain.CallbackPending, Attempts: 1}
+
+	before := time.Now()
+	dispatcher.Attempt(subscription, delivery)
+
+	assert.Equal(t, domain.CallbackPending, delivery.Status)
+	assert.Equal(t, 2, delivery.Attempts)
+	assert.Equal(t, http.StatusServiceUnavailable, delivery.ResponseStatus)
+	assert.Contains(t, delivery.LastError, "503")
+	assert.True(t, delivery.NextAttemptAt.After(before.Add(19*time.Second)), "second retry waits 20s")
+}
+
+func TestDispatcher_Attempt_RefusesPrivateAddresses(t *testing.T) {
+	called := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+
+	// Varsayılan istemci loopback adresine bağlanmamalı
+	dispatcher := NewDispatcher(&mocks.MockCallbackRepository{}, &mockLogger{})
+	subscription := &domain.CallbackSubscription{ID: 3, URL: server.URL, Secret: "whsec_abc"}
+	delivery := &domain.CallbackDelivery{ID: 5, Payload: json.RawMessage(`{}`), Status: domain.CallbackPending}
+
+	dispatcher.Attempt(subscription, delivery)
+
+	assert.False(t, called)
+	assert.Equal(t, 1, delivery.Attempts)
+	assert.Equal(t, 0, delivery.ResponseStatus)
+	assert.Contains(t, delivery.LastError, domain.ErrCallbackHostNotAllowed.Error())
+}
+
+func TestDispatcher_Attempt_DoesNotFollowRedirects(t *testing.T) {
+	redirected := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/metadata" {
+			redirected = true
+			return
+		}
+		http.Redirect(w, r, "/metadata", http.StatusFound)
+	}))
+	defer server.Close()
+
+	dispatcher := newLocalDispatcher(&mocks.MockCallbackRepository{})
+	subscription := &domain.CallbackSubscription{ID: 3, URL: server.URL + "/hooks", Secret: "whsec_abc"}
+	delivery := &domain.CallbackDelivery{ID: 5, Payload: json.RawMessage(`{}`), Status: domain.CallbackPending}
+
+	dispatcher.Attempt(subscription, delivery)
+
+	assert.False(t, redirected)
+	assert.Equal(t, domain.CallbackPending, delivery.Status)
+	assert.Equal(t, http.StatusFound, delivery.ResponseStatus)
+}
+
+func TestDispatcher_DeliversDueDeliveries(t *testing.T) {
+	calls := make(chan struct{}, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls <- struct{}{}
+	}))
+	defer server.Close()
+
+	repo := &mocks.MockCallbackRepository{}
+	dispatcher := newLocalDispatcher(repo)
+	dispatcher.interval = 10 * time.Millisecond
+
+	delivery := &domain.CallbackDelivery{ID: 5, SubscriptionID: 3, TenantID: 7, Payload: json.RawMessage(`{}`), Status: domain.CallbackPending}
+	repo.On("ClaimDueDeliveries", claimBatchSize, claimLease).Return([]*domain.CallbackDelivery{delivery}, nil).Once()
+	repo.On("ClaimDueDeliveries", claimBatchSize, claimLease).Return(nil, nil)
+	repo.On("Get", int64(7), int64(3)).Return(&domain.CallbackSubscription{ID: 3, TenantID: 7, URL: server.URL, Secret: "whsec_abc"}, nil)
+	updated := make(chan *domain.CallbackDelivery, 1)
+	repo.On("UpdateDelivery", delivery).Run(func(args mock.Arguments) {
+		updated <- args.Get(0).(*domain.CallbackDelivery)
+	}).Return(nil)
+
+	dispatcher.Start()
+	defer dispatcher.Stop()
+
+	select {
+	case got := <-updated:
+		assert.Equal(t, domain.CallbackDelivered, got.Status)
+	case <-time.After(time.Second):
+		t.Fatal("delivery was not attempted")
+	}
+	assert.Len(t, calls, 1)
+}
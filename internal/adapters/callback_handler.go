@@ -0,0 +1,141 @@
+package adapters
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/ercancavusoglu/messaging/internal/domain"
+	"github.com/ercancavusoglu/messaging/internal/ports"
+)
+
+type CallbackHandler struct {
+	callbackService ports.CallbackService
+}
+
+func NewCallbackHandler(callbackService ports.CallbackService) *CallbackHandler {
+	return &CallbackHandler{
+		callbackService: callbackService,
+	}
+}
+
+type createCallbackRequest struct {
+	URL    string   `json:"url"`
+	Events []string `json:"events"`
+}
+
+func (h *CallbackHandler) ListCallbacks(w http.ResponseWriter, r *http.Request) {
+	tenant, ok := TenantFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusForbidden, errMissingTenant)
+		return
+	}
+
+	subscriptions, err := h.callbackService.List(tenant.ID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, subscriptions)
+}
+
+func (h *CallbackHandler) GetCallback(w http.ResponseWriter, r *http.Request) {
+	h.withID(w, r, func(tenantID, id int64) (interface{}, error) {
+		return h.callbackService.Get(tenantID, id)
+	})
+}
+
+// CreateCallback returns the subscription with its signing secret, which is not shown again
+func (h *CallbackHandler) CreateCallback(w http.ResponseWriter, r *http.Request) {
+	tenant, ok := TenantFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusForbidden, errMissingTenant)
+		return
+	}
+
+	var req createCallbackRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %v", err))
+		return
+	}
+
+	subscription := &domain.CallbackSubscription{URL: req.URL, Events: req.Events}
+	if err := h.callbackService.Create(tenant.ID, subscription); err != nil {
+		writeError(w, callbackErrorStatus(err), err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, subscription)
+}
+
+func (h *CallbackHandler) DeleteCallback(w http.ResponseWriter, r *http.Request) {
+	tenant, ok := TenantFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusForbidden, errMissingTenant)
+		return
+	}
+
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := h.callbackService.Delete(tenant.ID, id); err != nil {
+		writeError(w, callbackErrorStatus(err), err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *CallbackHandler) ListDeliveries(w http.ResponseWriter, r *http.Request) {
+	h.withID(w, r, func(tenantID, id int64) (interface{}, error) {
+		return h.callbackService.Deliveries(tenantID, id)
+	})
+}
+
+// PingCallback answers 200 with the logged delivery even when the subscriber rejects the ping
+func (h *CallbackHandler) PingCallback(w http.ResponseWriter, r *http.Request) {
+	h.withID(w, r, func(tenantID, id int64) (interface{}, error) {
+		return h.callbackService.Ping(tenantID, id)
+	})
+}
+
+func (h *CallbackHandler) withID(w http.ResponseWriter, r *http.Request, fn func(tenantID, id int64) (interface{}, error)) {
+	tenant, ok := TenantFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusForbidden, errMissingTenant)
+		return
+	}
+
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	result, err := fn(tenant.ID, id)
+	if err != nil {
+		writeError(w, callbackErrorStatus(err), err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+func callbackErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrCallbackNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrInvalidCallbackURL),
+		errors.Is(err, domain.ErrCallbackHostNotAllowed),
+		errors.Is(err, domain.ErrCallbackEventsMissing),
+		errors.Is(err, domain.ErrInvalidCallbackEvent):
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusInternalServerError
+	}
+}
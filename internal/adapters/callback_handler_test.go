@@ -0,0 +1,108 @@
+package adapters
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ercancavusoglu/messaging/internal/adapters/mocks"
+	"github.com/ercancavusoglu/messaging/internal/domain"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCallbackHandler_CreateCallback(t *testing.T) {
+	mockService := &mocks.MockCallbackService{}
+	handler := NewCallbackHandler(mockService)
+
+	mockService.On("Create", int64(7), mock.MatchedBy(func(subscription *domain.CallbackSubscription) bool {
+		return subscription.URL == "https://hooks.example.com/sms" && len(subscription.Events) == 2
+	})).Return(nil).Run(func(args mock.Arguments) {
+		subscription := args.Get(1).(*domain.CallbackSubscription)
+		subscription.ID = 3
+		subscription.Secret = "whsec_abc"
+	})
+
+	body := `{"url":"https://hooks.example.com/sms","events":["message.sent","message.failed"],"secret":"chosen"}`
+	req := httptest.NewRequest(http.MethodPost, "/callbacks", strings.NewReader(body))
+	req = req.WithContext(WithTenant(req.Context(), &domain.Tenant{ID: 7}))
+	w := httptest.NewRecorder()
+
+	handler.CreateCallback(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var response domain.CallbackSubscription
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Equal(t, int64(3), response.ID)
+	// İmza anahtarı yalnızca oluşturulurken döner ve istemci tarafından seçilemez
+	assert.Equal(t, "whsec_abc", response.Secret)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestCallbackHandler_CreateCallback_Invalid(t *testing.T) {
+	mockService := &mocks.MockCallbackService{}
+	handler := NewCallbackHandler(mockService)
+
+	mockService.On("Create", int64(7), mock.Anything).Return(domain.ErrInvalidCallbackEvent)
+
+	req := httptest.NewRequest(http.MethodPost, "/callbacks", strings.NewReader(`{"url":"https://hooks.example.com","events":["message.read"]}`))
+	req = req.WithContext(WithTenant(req.Context(), &domain.Tenant{ID: 7}))
+	w := httptest.NewRecorder()
+
+	handler.CreateCallback(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+}
+
+func TestCallbackHandler_PingCallback(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{name: "Pinged", wantStatus: http.StatusOK},
+		{name: "Not found", err: domain.ErrCallbackNotFound, wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mocks.MockCallbackService{}
+			handler := NewCallbackHandler(mockService)
+
+			if tt.err != nil {
+				mockService.On("Ping", int64(7), int64(3)).Return(nil, tt.err)
+			} else {
+				mockService.On("Ping", int64(7), int64(3)).Return(&domain.CallbackDelivery{ID: 5, Status: domain.CallbackDelivered, ResponseStatus: 200}, nil)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/callbacks/3/ping", nil)
+			req = mux.SetURLVars(req, map[string]string{"id": "3"})
+			req = req.WithContext(WithTenant(req.Context(), &domain.Tenant{ID: 7}))
+			w := httptest.NewRecorder()
+
+			handler.PingCallback(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCallbackHandler_DeleteCallback_MissingTenant(t *testing.T) {
+	mockService := &mocks.MockCallbackService{}
+	handler := NewCallbackHandler(mockService)
+
+	req := httptest.NewRequest(http.MethodDelete, "/callbacks/3", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "3"})
+	w := httptest.NewRecorder()
+
+	handler.DeleteCallback(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	mockService.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
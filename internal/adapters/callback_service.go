@@ -0,0 +1,114 @@
+package adapters
+
+import (
+	"time"
+
+	"github.com/ercancavusoglu/messaging/internal/domain"
+	"github.com/ercancavusoglu/messaging/internal/ports"
+)
+
+// callbackDeliveryLogSize is the number of latest deliveries returned for a subscription
+const callbackDeliveryLogSize = 100
+
+// callbackAttempter sends a single delivery, see callback.Dispatcher
+type callbackAttempter interface {
+	Attempt(subscription *domain.CallbackSubscription, delivery *domain.CallbackDelivery)
+}
+
+type callbackService struct {
+	repo      ports.CallbackRepository
+	attempter callbackAttempter
+	logger    ports.Logger
+}
+
+func NewCallbackService(repo ports.CallbackRepository, attempter callbackAttempter, logger ports.Logger) ports.CallbackService {
+	return &callbackService{
+		repo:      repo,
+		attempter: attempter,
+		logger:    logger,
+	}
+}
+
+func (s *callbackService) Create(tenantID int64, subscription *domain.CallbackSubscription) error {
+	if err := subscription.Validate(); err != nil {
+		return err
+	}
+
+	secret, err := domain.NewCallbackSecret()
+	if err != nil {
+		return err
+	}
+
+	subscription.TenantID = tenantID
+	subscription.Secret = secret
+
+	return s.repo.Create(subscription)
+}
+
+func (s *callbackService) Get(tenantID, id int64) (*domain.CallbackSubscription, error) {
+	subscription, err := s.repo.Get(tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	return subscription.Redacted(), nil
+}
+
+func (s *callbackService) List(tenantID int64) ([]*domain.CallbackSubscription, error) {
+	subscriptions, err := s.repo.List(tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	redacted := make([]*domain.CallbackSubscription, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		redacted = append(redacted, subscription.Redacted())
+	}
+	return redacted, nil
+}
+
+func (s *callbackService) Delete(tenantID, id int64) error {
+	return s.repo.Delete(tenantID, id)
+}
+
+func (s *callbackService) Deliveries(tenantID, id int64) ([]*domain.CallbackDelivery, error) {
+	if _, err := s.repo.Get(tenantID, id); err != nil {
+		return nil, err
+	}
+
+	deliveries, err := s.repo.ListDeliveries(tenantID, id, callbackDeliveryLogSize)
+	if err != nil {
+		return nil, err
+	}
+	if deliveries == nil {
+		deliveries = []*domain.CallbackDelivery{}
+	}
+	return deliveries, nil
+}
+
+// Ping is attempted once, without retries, so the caller sees the subscriber's answer right away. The
+// attempt is still recorded in the delivery log.
+func (s *callbackService) Ping(tenantID, id int64) (*domain.CallbackDelivery, error) {
+	subscription, err := s.repo.Get(tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	delivery, err := domain.NewCallbackDelivery(subscription, 0, domain.MessageUpdate{
+		Event:      domain.EventCallbackPing,
+		OccurredAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.attempter.Attempt(subscription, delivery)
+	if delivery.Status == domain.CallbackPending {
+		delivery.Status = domain.CallbackFailed
+	}
+
+	if err := s.repo.CreateDelivery(delivery); err != nil {
+		s.logger.Errorf("[Callback] Failed to record ping of subscription %d: %v", subscription.ID, err)
+	}
+
+	return delivery, nil
+}
@@ -0,0 +1,83 @@
+package adapters
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ercancavusoglu/messaging/internal/adapters/mocks"
+	"github.com/ercancavusoglu/messaging/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type fakeAttempter struct {
+	attempt func(delivery *domain.CallbackDelivery)
+}
+
+func (f *fakeAttempter) Attempt(subscription *domain.CallbackSubscription, delivery *domain.CallbackDelivery) {
+	f.attempt(delivery)
+}
+
+func TestCallbackService_Create(t *testing.T) {
+	repo := &mocks.MockCallbackRepository{}
+	service := NewCallbackService(repo, nil, &mocks.MockLogger{})
+
+	repo.On("Create", mock.AnythingOfType("*domain.CallbackSubscription")).Return(nil)
+
+	subscription := &domain.CallbackSubscription{
+		URL:    "https://93.184.216.34/sms",
+		Events: []string{domain.EventMessageSent, domain.EventMessageSent},
+		Secret: "chosen",
+	}
+	err := service.Create(7, subscription)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), subscription.TenantID)
+	assert.Equal(t, []string{domain.EventMessageSent}, subscription.Events)
+	assert.True(t, strings.HasPrefix(subscription.Secret, domain.CallbackSecretPrefix))
+	repo.AssertExpectations(t)
+}
+
+func TestCallbackService_Create_InvalidURL(t *testing.T) {
+	repo := &mocks.MockCallbackRepository{}
+	service := NewCallbackService(repo, nil, &mocks.MockLogger{})
+
+	err := service.Create(7, &domain.CallbackSubscription{URL: "ftp://hooks.example.com", Events: []string{domain.EventMessageSent}})
+
+	assert.ErrorIs(t, err, domain.ErrInvalidCallbackURL)
+	repo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestCallbackService_List_RedactsSecrets(t *testing.T) {
+	repo := &mocks.MockCallbackRepository{}
+	service := NewCallbackService(repo, nil, &mocks.MockLogger{})
+
+	repo.On("List", int64(7)).Return([]*domain.CallbackSubscription{{ID: 3, TenantID: 7, Secret: "whsec_abc"}}, nil)
+
+	subscriptions, err := service.List(7)
+
+	assert.NoError(t, err)
+	assert.Len(t, subscriptions, 1)
+	assert.Empty(t, subscriptions[0].Secret)
+}
+
+func TestCallbackService_Ping_FailureIsNotRetried(t *testing.T) {
+	repo := &mocks.MockCallbackRepository{}
+	attempter := &fakeAttempter{attempt: func(delivery *domain.CallbackDelivery) {
+		delivery.Fail(500, "unexpected status code: 500", time.Now())
+	}}
+	service := NewCallbackService(repo, attempter, &mocks.MockLogger{})
+
+	repo.On("Get", int64(7), int64(3)).Return(&domain.CallbackSubscription{ID: 3, TenantID: 7, Secret: "whsec_abc"}, nil)
+	repo.On("CreateDelivery", mock.MatchedBy(func(delivery *domain.CallbackDelivery) bool {
+		return delivery.Event == domain.EventCallbackPing && delivery.Status == domain.CallbackFailed
+	})).Return(nil)
+
+	delivery, err := service.Ping(7, 3)
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.CallbackFailed, delivery.Status)
+	assert.Equal(t, 500, delivery.ResponseStatus)
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,137 @@
+package adapters
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/ercancavusoglu/messaging/internal/domain"
+	"github.com/ercancavusoglu/messaging/internal/ports"
+)
+
+type CampaignHandler struct {
+	campaignService ports.CampaignService
+}
+
+func NewCampaignHandler(campaignService ports.CampaignService) *CampaignHandler {
+	return &CampaignHandler{
+		campaignService: campaignService,
+	}
+}
+
+func (h *CampaignHandler) ListCampaigns(w http.ResponseWriter, r *http.Request) {
+	tenant, ok := TenantFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusForbidden, errMissingTenant)
+		return
+	}
+
+	campaigns, err := h.campaignService.List(tenant.ID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, campaigns)
+}
+
+func (h *CampaignHandler) GetCampaign(w http.ResponseWriter, r *http.Request) {
+	tenant, ok := TenantFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusForbidden, errMissingTenant)
+		return
+	}
+
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	campaign, err := h.campaignService.Get(tenant.ID, id)
+	if err != nil {
+		writeError(w, campaignErrorStatus(err), err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, campaign)
+}
+
+func (h *CampaignHandler) CreateCampaign(w http.ResponseWriter, r *http.Request) {
+	tenant, ok := TenantFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusForbidden, errMissingTenant)
+		return
+	}
+
+	var campaign domain.Campaign
+	if err := json.NewDecoder(r.Body).Decode(&campaign); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %v", err))
+		return
+	}
+
+	if err := h.campaignService.Create(tenant, &campaign); err != nil {
+		writeError(w, campaignErrorStatus(err), err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, campaign)
+}
+
+func (h *CampaignHandler) PauseCampaign(w http.ResponseWriter, r *http.Request) {
+	h.changeStatus(w, r, h.campaignService.Pause)
+}
+
+func (h *CampaignHandler) ResumeCampaign(w http.ResponseWriter, r *http.Request) {
+	h.changeStatus(w, r, h.campaignService.Resume)
+}
+
+func (h *CampaignHandler) CancelCampaign(w http.ResponseWriter, r *http.Request) {
+	h.changeStatus(w, r, h.campaignService.Cancel)
+}
+
+func (h *CampaignHandler) changeStatus(w http.ResponseWriter, r *http.Request, change func(tenantID, id int64) (*domain.Campaign, error)) {
+	tenant, ok := TenantFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusForbidden, errMissingTenant)
+		return
+	}
+
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	campaign, err := change(tenant.ID, id)
+	if err != nil {
+		writeError(w, campaignErrorStatus(err), err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, campaign)
+}
+
+// campaignErrorStatus maps campaign, template and recipient validation errors to client errors
+func campaignErrorStatus(err error) int {
+	var recipientErr *domain.InvalidRecipientError
+	switch {
+	case errors.Is(err, domain.ErrCampaignNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrInvalidCampaignTransition):
+		return http.StatusConflict
+	case errors.Is(err, domain.ErrQuotaExceeded):
+		return http.StatusTooManyRequests
+	case errors.Is(err, domain.ErrCampaignNameRequired),
+		errors.Is(err, domain.ErrCampaignTemplateRequired),
+		errors.Is(err, domain.ErrCampaignAudienceEmpty),
+		errors.Is(err, domain.ErrCampaignAudienceTooLarge),
+		errors.Is(err, domain.ErrInvalidPriority),
+		errors.Is(err, domain.ErrTemplateNotFound),
+		errors.As(err, &recipientErr):
+		return http.StatusUnprocessableEntity
+	default:
+		return templateErrorStatus(err)
+	}
+}
@@ -0,0 +1,114 @@
+package adapters
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ercancavusoglu/messaging/internal/adapters/mocks"
+	"github.com/ercancavusoglu/messaging/internal/domain"
+	"github.com/ercancavusoglu/messaging/internal/domain/valueobject"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCampaignHandler_CreateCampaign(t *testing.T) {
+	mockService := &mocks.MockCampaignService{}
+	handler := NewCampaignHandler(mockService)
+
+	tenant := &domain.Tenant{ID: 7}
+	mockService.On("Create", tenant, mock.AnythingOfType("*domain.Campaign")).Return(nil).Run(func(args mock.Arguments) {
+		campaign := args.Get(1).(*domain.Campaign)
+		campaign.ID = 4
+		campaign.Status = domain.CampaignRunning
+		campaign.AudienceSize = len(campaign.Audience)
+		campaign.Audience = nil
+	})
+
+	body := `{"name":"Spring sale","template_id":1,"audience":[{"to":"+905551234567"}],"scheduled_at":"2026-01-01T09:00:00Z"}`
+	req := httptest.NewRequest(http.MethodPost, "/campaigns", strings.NewReader(body))
+	req = req.WithContext(WithTenant(req.Context(), tenant))
+	w := httptest.NewRecorder()
+
+	handler.CreateCampaign(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var response domain.Campaign
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Equal(t, int64(4), response.ID)
+	assert.Equal(t, 1, response.AudienceSize)
+	assert.NotNil(t, response.ScheduledAt)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestCampaignHandler_CreateCampaign_InvalidRecipient(t *testing.T) {
+	mockService := &mocks.MockCampaignService{}
+	handler := NewCampaignHandler(mockService)
+
+	mockService.On("Create", mock.Anything, mock.AnythingOfType("*domain.Campaign")).
+		Return(&domain.InvalidRecipientError{Index: 3, Err: valueobject.ErrInvalidPhoneNumberLength})
+
+	req := httptest.NewRequest(http.MethodPost, "/campaigns", strings.NewReader(`{"name":"x","template_id":1}`))
+	req = req.WithContext(WithTenant(req.Context(), &domain.Tenant{ID: 7}))
+	w := httptest.NewRecorder()
+
+	handler.CreateCampaign(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), "audience[3]")
+}
+
+func TestCampaignHandler_PauseCampaign(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{name: "Paused", wantStatus: http.StatusOK},
+		{name: "Not found", err: domain.ErrCampaignNotFound, wantStatus: http.StatusNotFound},
+		{name: "Already completed", err: fmt.Errorf("%w: cannot pause a completed campaign", domain.ErrInvalidCampaignTransition), wantStatus: http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mocks.MockCampaignService{}
+			handler := NewCampaignHandler(mockService)
+
+			if tt.err != nil {
+				mockService.On("Pause", int64(7), int64(4)).Return(nil, tt.err)
+			} else {
+				mockService.On("Pause", int64(7), int64(4)).Return(&domain.Campaign{ID: 4, Status: domain.CampaignPaused}, nil)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/campaigns/4/pause", nil)
+			req = mux.SetURLVars(req, map[string]string{"id": "4"})
+			req = req.WithContext(WithTenant(req.Context(), &domain.Tenant{ID: 7}))
+			w := httptest.NewRecorder()
+
+			handler.PauseCampaign(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCampaignHandler_GetCampaign_MissingTenant(t *testing.T) {
+	mockService := &mocks.MockCampaignService{}
+	handler := NewCampaignHandler(mockService)
+
+	req := httptest.NewRequest(http.MethodGet, "/campaigns/4", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "4"})
+	w := httptest.NewRecorder()
+
+	handler.GetCampaign(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	mockService.AssertNotCalled(t, "Get", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,150 @@
+package adapters
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ercancavusoglu/messaging/internal/domain"
+	"github.com/ercancavusoglu/messaging/internal/ports"
+)
+
+type campaignService struct {
+	repo      ports.CampaignRepository
+	templates ports.TemplateService
+	rules     domain.MessageRules
+	logger    ports.Logger
+}
+
+func NewCampaignService(repo ports.CampaignRepository, templates ports.TemplateService, rules domain.MessageRules, logger ports.Logger) ports.CampaignService {
+	return &campaignService{
+		repo:      repo,
+		templates: templates,
+		rules:     rules,
+		logger:    logger,
+	}
+}
+
+// Create renders the tenant's template for every recipient and stores the campaign with one pending message
+// per recipient; nothing is stored when a single recipient fails validation or the messages would exceed
+// the tenant's daily quota
+func (s *campaignService) Create(tenant *domain.Tenant, campaign *domain.Campaign) error {
+	if err := campaign.Validate(); err != nil {
+		return err
+	}
+
+	template, err := s.templates.Get(tenant.ID, campaign.TemplateID)
+	if err != nil {
+		return err
+	}
+
+	messages := make([]*domain.Message, 0, len(campaign.Audience))
+	for i, recipient := range campaign.Audience {
+		content, err := template.Render(campaign.Locale, campaign.VariablesFor(recipient))
+		if err != nil {
+			return &domain.InvalidRecipientError{Index: i, Err: err}
+		}
+
+		msg, err := domain.NewMessage(recipient.To, content, s.rules)
+		if err != nil {
+			return &domain.InvalidRecipientError{Index: i, Err: err}
+		}
+		messages = append(messages, msg)
+	}
+
+	campaign.TenantID = tenant.ID
+	campaign.Start(time.Now())
+
+	if err := s.repo.Create(campaign, messages, tenant.DailyQuota); err != nil {
+		return err
+	}
+
+	// The audience is only needed to create the messages
+	campaign.Audience = nil
+	return nil
+}
+
+func (s *campaignService) Get(tenantID, id int64) (*domain.Campaign, error) {
+	return s.repo.Get(tenantID, id)
+}
+
+func (s *campaignService) List(tenantID int64) ([]*domain.Campaign, error) {
+	return s.repo.List(tenantID)
+}
+
+// Pause keeps the scheduler from claiming the campaign's pending messages; messages already queued
+// are still sent
+func (s *campaignService) Pause(tenantID, id int64) (*domain.Campaign, error) {
+	return s.transition(tenantID, id, func(campaign *domain.Campaign) error {
+		return campaign.Pause()
+	})
+}
+
+func (s *campaignService) Resume(tenantID, id int64) (*domain.Campaign, error) {
+	return s.transition(tenantID, id, func(campaign *domain.Campaign) error {
+		return campaign.Resume(time.Now())
+	})
+}
+
+// Cancel stops the campaign for good and cancels its messages that were not claimed yet
+func (s *campaignService) Cancel(tenantID, id int64) (*domain.Campaign, error) {
+	campaign, err := s.transition(tenantID, id, func(campaign *domain.Campaign) error {
+		return campaign.Cancel()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	cancelled, err := s.repo.CancelPending(campaign)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Infof("[CampaignService] Cancelled campaign %d with %d pending messages", campaign.ID, cancelled)
+	return campaign, nil
+}
+
+func (s *campaignService) transition(tenantID, id int64, apply func(campaign *domain.Campaign) error) (*domain.Campaign, error) {
+	campaign, err := s.repo.Get(tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	from := campaign.Status
+	if err := apply(campaign); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateStatus(campaign, from); err != nil {
+		return nil, err
+	}
+
+	return campaign, nil
+}
+
+// HandleEvent is subscribed to the message outcome events; messages outside campaigns are ignored
+func (s *campaignService) HandleEvent(event ports.Event) error {
+	delta, ok := domain.CampaignProgressFor(event.EventName())
+	if !ok {
+		return nil
+	}
+
+	envelope, ok := event.(*domain.EventEnvelope)
+	if !ok {
+		return fmt.Errorf("unexpected event type %T", event)
+	}
+
+	var payload struct {
+		Message *domain.Message `json:"message"`
+	}
+	if err := json.Unmarshal(envelope.Data, &payload); err != nil {
+		s.logger.Errorf("[CampaignService] Failed to unmarshal event: %v", err)
+		return fmt.Errorf("failed to unmarshal event: %v", err)
+	}
+
+	if payload.Message == nil || payload.Message.CampaignID == 0 {
+		return nil
+	}
+
+	return s.repo.ApplyProgress(payload.Message.CampaignID, payload.Message.ID, event.EventName(), delta)
+}
@@ -0,0 +1,181 @@
+package adapters
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ercancavusoglu/messaging/internal/adapters/mocks"
+	"github.com/ercancavusoglu/messaging/internal/domain"
+	"github.com/ercancavusoglu/messaging/internal/ports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestCampaign() *domain.Campaign {
+	return &domain.Campaign{
+		Name:       "Spring sale",
+		TemplateID: 1,
+		Variables:  map[string]string{"discount": "20"},
+		Audience: []domain.CampaignRecipient{
+			{To: "+905551234567", Variables: map[string]string{"name": "Ayşe"}},
+			{To: "+905557654321", Variables: map[string]string{"name": "Mehmet", "discount": "30"}},
+		},
+	}
+}
+
+type campaignServiceMocks struct {
+	repo      *mocks.MockCampaignRepository
+	templates *mocks.MockTemplateService
+	logger    *mocks.MockLogger
+}
+
+func newTestCampaignService() (*campaignServiceMocks, ports.CampaignService) {
+	m := &campaignServiceMocks{
+		repo:      &mocks.MockCampaignRepository{},
+		templates: &mocks.MockTemplateService{},
+		logger:    &mocks.MockLogger{},
+	}
+	return m, NewCampaignService(m.repo, m.templates, domain.DefaultMessageRules(), m.logger)
+}
+
+func TestCampaignService_Create(t *testing.T) {
+	m, service := newTestCampaignService()
+
+	m.templates.On("Get", int64(7), int64(1)).Return(&domain.Template{
+		ID:            1,
+		DefaultLocale: "tr",
+		Variants:      map[string]string{"tr": "Merhaba {{name}}, %{{discount}} indirim"},
+	}, nil)
+
+	var created []*domain.Message
+	m.repo.On("Create", mock.AnythingOfType("*domain.Campaign"), mock.AnythingOfType("[]*domain.Message"), 0).Return(nil).Run(func(args mock.Arguments) {
+		created = args.Get(1).([]*domain.Message)
+	})
+
+	campaign := newTestCampaign()
+	err := service.Create(&domain.Tenant{ID: 7}, campaign)
+	assert.NoError(t, err)
+
+	assert.Equal(t, int64(7), campaign.TenantID)
+	assert.Equal(t, domain.CampaignRunning, campaign.Status)
+	assert.Equal(t, domain.PriorityBulk, campaign.Priority)
+	assert.Equal(t, 2, campaign.AudienceSize)
+	assert.Equal(t, 2, campaign.Progress.Pending)
+	assert.Nil(t, campaign.Audience)
+
+	// Alıcı değişkenleri kampanya değişkenlerini ezer
+	assert.Len(t, created, 2)
+	assert.Equal(t, "Merhaba Ayşe, %20 indirim", created[0].Content)
+	assert.Equal(t, "Merhaba Mehmet, %30 indirim", created[1].Content)
+
+	m.repo.AssertExpectations(t)
+}
+
+func TestCampaignService_Create_InvalidRecipient(t *testing.T) {
+	m, service := newTestCampaignService()
+
+	m.templates.On("Get", int64(7), int64(1)).Return(&domain.Template{
+		ID:            1,
+		DefaultLocale: "tr",
+		Variants:      map[string]string{"tr": "Merhaba {{name}}"},
+	}, nil)
+
+	campaign := newTestCampaign()
+	campaign.Audience[1].To = "not-a-number"
+
+	err := service.Create(&domain.Tenant{ID: 7}, campaign)
+
+	var recipientErr *domain.InvalidRecipientError
+	assert.ErrorAs(t, err, &recipientErr)
+	assert.Equal(t, 1, recipientErr.Index)
+	m.repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCampaignService_Create_OtherTenantsTemplate(t *testing.T) {
+	m, service := newTestCampaignService()
+
+	// Şablon 8 numaralı tenant'a ait; 7 numaralı tenant onu bulamamalı
+	m.templates.On("Get", int64(7), int64(1)).Return(nil, domain.ErrTemplateNotFound)
+
+	err := service.Create(&domain.Tenant{ID: 7}, newTestCampaign())
+	assert.ErrorIs(t, err, domain.ErrTemplateNotFound)
+	m.templates.AssertExpectations(t)
+	m.repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCampaignService_Create_QuotaExceeded(t *testing.T) {
+	m, service := newTestCampaignService()
+
+	m.templates.On("Get", int64(7), int64(1)).Return(&domain.Template{
+		ID:            1,
+		DefaultLocale: "tr",
+		Variants:      map[string]string{"tr": "Merhaba {{name}}"},
+	}, nil)
+	// Kota, kampanyayla aynı işlemde repository tarafından denetlenir
+	m.repo.On("Create", mock.AnythingOfType("*domain.Campaign"), mock.AnythingOfType("[]*domain.Message"), 100).Return(domain.ErrQuotaExceeded)
+
+	campaign := newTestCampaign()
+	err := service.Create(&domain.Tenant{ID: 7, DailyQuota: 100}, campaign)
+	assert.ErrorIs(t, err, domain.ErrQuotaExceeded)
+	assert.NotNil(t, campaign.Audience)
+	m.repo.AssertExpectations(t)
+}
+
+func TestCampaignService_Pause(t *testing.T) {
+	m, service := newTestCampaignService()
+
+	m.repo.On("Get", int64(7), int64(4)).Return(&domain.Campaign{ID: 4, TenantID: 7, Status: domain.CampaignRunning}, nil)
+	m.repo.On("UpdateStatus", mock.AnythingOfType("*domain.Campaign"), domain.CampaignRunning).Return(nil)
+
+	campaign, err := service.Pause(7, 4)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.CampaignPaused, campaign.Status)
+	m.repo.AssertExpectations(t)
+}
+
+func TestCampaignService_Resume_NotPaused(t *testing.T) {
+	m, service := newTestCampaignService()
+
+	m.repo.On("Get", int64(7), int64(4)).Return(&domain.Campaign{ID: 4, TenantID: 7, Status: domain.CampaignCompleted}, nil)
+
+	_, err := service.Resume(7, 4)
+	assert.ErrorIs(t, err, domain.ErrInvalidCampaignTransition)
+	m.repo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything)
+}
+
+func TestCampaignService_Cancel(t *testing.T) {
+	m, service := newTestCampaignService()
+
+	m.repo.On("Get", int64(7), int64(4)).Return(&domain.Campaign{ID: 4, TenantID: 7, Status: domain.CampaignPaused}, nil)
+	m.repo.On("UpdateStatus", mock.AnythingOfType("*domain.Campaign"), domain.CampaignPaused).Return(nil)
+	m.repo.On("CancelPending", mock.AnythingOfType("*domain.Campaign")).Return(12, nil)
+	m.logger.On("Infof", mock.Anything, mock.Anything).Return()
+
+	campaign, err := service.Cancel(7, 4)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.CampaignCancelled, campaign.Status)
+	m.repo.AssertExpectations(t)
+}
+
+func TestCampaignService_HandleEvent(t *testing.T) {
+	m, service := newTestCampaignService()
+
+	newEnvelope := func(event interface{ EventName() string }, message *domain.Message) *domain.EventEnvelope {
+		data, err := json.Marshal(map[string]interface{}{"message": message})
+		assert.NoError(t, err)
+		return &domain.EventEnvelope{Name: event.EventName(), Data: data}
+	}
+
+	m.repo.On("ApplyProgress", int64(4), int64(90), domain.EventMessageFailed, domain.CampaignProgress{Pending: -1, Failed: 1}).Return(nil)
+
+	campaignMessage := &domain.Message{ID: 90, CampaignID: 4}
+	err := service.HandleEvent(newEnvelope(domain.NewMessageFailedEvent(campaignMessage, assert.AnError), campaignMessage))
+	assert.NoError(t, err)
+
+	// Kampanyaya ait olmayan mesajlar yok sayılır
+	standalone := &domain.Message{ID: 91}
+	err = service.HandleEvent(newEnvelope(domain.NewMessageSentEvent(standalone, "provider-id", "client_one"), standalone))
+	assert.NoError(t, err)
+
+	m.repo.AssertNumberOfCalls(t, "ApplyProgress", 1)
+}
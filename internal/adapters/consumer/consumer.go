@@ -2,6 +2,7 @@ package consumer
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"sync"
 
@@ -10,25 +11,27 @@ import (
 )
 
 type Consumer struct {
-	webhookClient ports.WebhookClient
-	repo          ports.Repository
-	cache         ports.Cache
-	eventBus      ports.EventBus
-	workers       int
-	workerPool    chan struct{}
-	wg            sync.WaitGroup
-	logger        ports.Logger
+	clients      ports.WebhookClientResolver
+	repo         ports.Repository
+	cache        ports.Cache
+	eventBus     ports.EventBus
+	suppressions ports.SuppressionService
+	workerPool   *workerPool
+	rules        domain.MessageRules
+	wg           sync.WaitGroup
+	logger       ports.Logger
 }
 
-func NewConsumer(webhookClient ports.WebhookClient, repo ports.Repository, cache ports.Cache, eventBus ports.EventBus, workers int, logger ports.Logger) *Consumer {
+func NewConsumer(clients ports.WebhookClientResolver, repo ports.Repository, cache ports.Cache, eventBus ports.EventBus, suppressions ports.SuppressionService, workers WorkerShares, rules domain.MessageRules, logger ports.Logger) *Consumer {
 	return &Consumer{
-		webhookClient: webhookClient,
-		repo:          repo,
-		cache:         cache,
-		eventBus:      eventBus,
-		workers:       workers,
-		workerPool:    make(chan struct{}, workers),
-		logger:        logger,
+		clients:      clients,
+		repo:         repo,
+		cache:        cache,
+		eventBus:     eventBus,
+		suppressions: suppressions,
+		workerPool:   newWorkerPool(workers),
+		rules:        rules,
+		logger:       logger,
 	}
 }
 
@@ -43,14 +46,16 @@ func (c *Consumer) Start() error {
 		}
 
 		msg := evt.Message
-		c.logger.Infof("[Consumer] Processing queued message ID: %d, To: %s", msg.ID, msg.To)
+		priority := msg.Priority.OrDefault()
+		c.logger.Infof("[Consumer] Processing queued message ID: %d, To: %s, Priority: %s", msg.ID, msg.To, priority)
 
-		c.workerPool <- struct{}{}
+		// Blocks this priority's queue until a worker is free, leaving the other classes unaffected
+		shared := c.workerPool.acquire(priority)
 		c.wg.Add(1)
 
 		go func() {
 			defer func() {
-				<-c.workerPool
+				c.workerPool.release(priority, shared)
 				c.wg.Done()
 			}()
 
@@ -62,9 +67,24 @@ func (c *Consumer) Start() error {
 		return nil
 	})
 
+	c.eventBus.Subscribe(domain.EventMessageFallback, func(e ports.Event) error {
+		var evt domain.MessageFallbackEvent
+		if err := json.Unmarshal(e.(*domain.EventEnvelope).Data, &evt); err != nil {
+			c.logger.Errorf("[Consumer] Failed to unmarshal event: %v", err)
+			return fmt.Errorf("failed to unmarshal event: %v", err)
+		}
+
+		return c.fallBack(evt.Message)
+	})
+
 	return nil
 }
 
+// SetWorkerShares resizes the worker pool; messages already being processed are not interrupted
+func (c *Consumer) SetWorkerShares(shares WorkerShares) {
+	c.workerPool.resize(shares)
+}
+
 func (c *Consumer) Stop() {
 	c.wg.Wait()
 }
@@ -72,12 +92,56 @@ func (c *Consumer) Stop() {
 func (c *Consumer) processMessage(msg *domain.Message) error {
 	c.logger.Infof("[Consumer] Processing message [id: %d]", msg.ID)
 
-	webhookResponse, err := c.webhookClient.SendMessage(msg.To, msg.Content)
+	// The event may be a second copy of a message that was requeued or is handled elsewhere already
+	taken, err := c.repo.TakeQueued(msg.ID)
+	if err != nil {
+		c.logger.Errorf("[Consumer] Failed to take message [id: %d]: %v", msg.ID, err)
+		return fmt.Errorf("failed to take message: %v", err)
+	}
+	if !taken {
+		c.logger.Infof("[Consumer] Message is no longer queued, skipping [id: %d]", msg.ID)
+		return nil
+	}
+
+	suppressed, err := c.suppressions.IsSuppressed(msg.TenantID, msg.To)
+	if err != nil {
+		c.logger.Errorf("[Consumer] Failed to check suppression list: %v", err)
+		c.release(msg)
+		return fmt.Errorf("failed to check suppression list: %v", err)
+	}
+
+	if suppressed {
+		c.logger.Infof("[Consumer] Recipient is suppressed, skipping message [id: %d]", msg.ID)
+		if err := c.repo.UpdateStatus(msg.ID, domain.StatusSuppressed, "", ""); err != nil {
+			c.logger.Errorf("[Consumer] Failed to update message status: %v", err)
+			return fmt.Errorf("failed to update message status: %v", err)
+		}
+		c.publishFailed(msg, domain.ErrRecipientSuppressed)
+		return nil
+	}
+
+	webhookClient, err := c.clients.ClientFor(msg.TenantID, msg.Channel.OrDefault())
+	if errors.Is(err, domain.ErrNoChannelProvider) {
+		c.logger.Errorf("[Consumer] Cannot send message [id: %d]: %v", msg.ID, err)
+		if err := c.repo.UpdateStatus(msg.ID, domain.StatusFailed, "", ""); err != nil {
+			c.logger.Errorf("[Consumer] Failed to update message status: %v", err)
+		}
+		c.publishFailed(msg, err)
+		return err
+	}
+	if err != nil {
+		c.logger.Errorf("[Consumer] Failed to resolve webhook client: %v", err)
+		c.release(msg)
+		return fmt.Errorf("failed to resolve webhook client: %v", err)
+	}
+
+	webhookResponse, err := webhookClient.SendMessage(msg)
 	if err != nil {
 		c.logger.Errorf("[Consumer] Failed to send message to webhook: %v", err)
 		if err := c.repo.UpdateStatus(msg.ID, domain.StatusFailed, "", ""); err != nil {
 			c.logger.Errorf("[Consumer] Failed to update message status: %v", err)
 		}
+		c.publishFailed(msg, err)
 		return fmt.Errorf("failed to send message to webhook: %v", err)
 	}
 
@@ -91,12 +155,102 @@ func (c *Consumer) processMessage(msg *domain.Message) error {
 		c.logger.Errorf("[Consumer] Failed to delete message from cache: %v", err)
 	}
 
-	event := domain.NewMessageSentEvent(msg, webhookResponse.MessageID)
+	event := domain.NewMessageSentEvent(msg, webhookResponse.MessageID, webhookResponse.Provider)
 
 	if err := c.eventBus.Publish(&event); err != nil {
 		c.logger.Errorf("[Consumer] Failed to publish message sent event: %v", err)
 	}
 
+	c.awaitDelivery(msg)
+
 	c.logger.Infof("[Consumer] Message processed successfully [id: %d]", msg.ID)
 	return nil
 }
+
+// HandleDeliveryReceipt publishes message.delivered for a receipt reporting delivery; other final states
+// are only logged, since the message already counts as sent
+func (c *Consumer) HandleDeliveryReceipt(receipt domain.DeliveryReceipt) {
+	if !receipt.Delivered() {
+		c.logger.Warnf("[Consumer] Provider %s reports message %s as %s %s", receipt.Provider, receipt.MessageID, receipt.Status, receipt.Error)
+		return
+	}
+
+	msg, err := c.repo.GetByProviderMessageID(receipt.Provider, receipt.MessageID)
+	if err != nil {
+		c.logger.Errorf("[Consumer] Failed to find message for delivery receipt [provider: %s, id: %s]: %v", receipt.Provider, receipt.MessageID, err)
+		return
+	}
+
+	if err := c.repo.MarkDelivered(msg.ID); err != nil {
+		c.logger.Errorf("[Consumer] Failed to mark message delivered [id: %d]: %v", msg.ID, err)
+	}
+
+	event := domain.NewMessageDeliveredEvent(msg)
+	if err := c.eventBus.Publish(&event); err != nil {
+		c.logger.Errorf("[Consumer] Failed to publish message delivered event: %v", err)
+		return
+	}
+
+	c.logger.Infof("[Consumer] Message delivered [id: %d]", msg.ID)
+}
+
+// release sets a message that hit a transient error back to pending so the scheduler claims it again;
+// if that fails too the message stays sending and needs an operator
+func (c *Consumer) release(msg *domain.Message) {
+	if err := c.repo.UpdateStatus(msg.ID, domain.StatusPending, "", ""); err != nil {
+		c.logger.Errorf("[Consumer] Failed to release message [id: %d]: %v", msg.ID, err)
+	}
+}
+
+// publishFailed reports a message that will not be sent, e.g. so its campaign can count it, and moves
+// on to its fallback step without waiting
+func (c *Consumer) publishFailed(msg *domain.Message, reason error) {
+	event := domain.NewMessageFailedEvent(msg, reason)
+	if err := c.eventBus.Publish(&event); err != nil {
+		c.logger.Errorf("[Consumer] Failed to publish message failed event: %v", err)
+	}
+
+	if len(msg.Fallback) > 0 {
+		if err := c.fallBack(msg); err != nil {
+			c.logger.Errorf("[Consumer] Failed to fall back [id: %d]: %v", msg.ID, err)
+		}
+	}
+}
+
+// awaitDelivery publishes the delayed fallback event of a sent message with a fallback step. When the
+// event cannot be published the step is sent right away rather than never.
+func (c *Consumer) awaitDelivery(msg *domain.Message) {
+	if len(msg.Fallback) == 0 {
+		return
+	}
+
+	event := domain.NewMessageFallbackEvent(msg)
+	if err := c.eventBus.Publish(&event); err != nil {
+		c.logger.Errorf("[Consumer] Failed to publish message fallback event, falling back now: %v", err)
+		if err := c.fallBack(msg); err != nil {
+			c.logger.Errorf("[Consumer] Failed to fall back [id: %d]: %v", msg.ID, err)
+		}
+	}
+}
+
+// fallBack creates the message of the next step as a pending child of msg, which the scheduler then sends
+// like any other message. Nothing happens when msg was delivered or has fallen back before.
+func (c *Consumer) fallBack(msg *domain.Message) error {
+	next, err := msg.NextFallback(c.rules)
+	if err != nil {
+		c.logger.Errorf("[Consumer] Cannot fall back [id: %d]: %v", msg.ID, err)
+		return nil
+	}
+
+	err = c.repo.CreateFallback(next)
+	if errors.Is(err, domain.ErrFallbackNotNeeded) {
+		c.logger.Infof("[Consumer] No fallback needed [id: %d]", msg.ID)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create fallback message: %v", err)
+	}
+
+	c.logger.Infof("[Consumer] Message falls back to %s [id: %d, fallback id: %d]", next.Channel, msg.ID, next.ID)
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/ercancavusoglu/messaging/internal/adapters/mocks"
+	"github.com/ercancavusoglu/messaging/internal/adapters/webhook"
 	"github.com/ercancavusoglu/messaging/internal/domain"
 	"github.com/ercancavusoglu/messaging/internal/ports"
 	"github.com/stretchr/testify/assert"
@@ -22,6 +23,7 @@ func (m *mockLogger) Error(args ...interface{})                   {}
 func (m *mockLogger) Errorf(format string, args ...interface{})   {}
 func (m *mockLogger) Warning(args ...interface{})                 {}
 func (m *mockLogger) Warningf(format string, args ...interface{}) {}
+func (m *mockLogger) Warnf(format string, args ...interface{})    {}
 
 func createTestMessage() *domain.Message {
 	return &domain.Message{
@@ -41,9 +43,10 @@ func TestConsumer_ProcessMessage_Success(t *testing.T) {
 	mockRepo := &mocks.MockRepository{}
 	mockCache := &mocks.MockCache{}
 	mockEventBus := &mocks.MockEventBus{}
+	mockSuppressions := &mocks.MockSuppressionService{}
 	logger := &mockLogger{}
 
-	consumer := NewConsumer(mockWebhook, mockRepo, mockCache, mockEventBus, 1, logger)
+	consumer := NewConsumer(webhook.NewTenantClientResolver(webhook.ChannelClients{domain.ChannelSMS: mockWebhook}, nil, nil, 1), mockRepo, mockCache, mockEventBus, mockSuppressions, WorkerShares{Shared: 1}, domain.DefaultMessageRules(), logger)
 
 	msg := createTestMessage()
 	webhookResponse := &domain.WebhookResponse{
@@ -53,7 +56,9 @@ func TestConsumer_ProcessMessage_Success(t *testing.T) {
 	}
 
 	// Mock beklentileri
-	mockWebhook.On("SendMessage", msg.To, msg.Content).Return(webhookResponse, nil)
+	mockRepo.On("TakeQueued", msg.ID).Return(true, nil)
+	mockSuppressions.On("IsSuppressed", msg.TenantID, msg.To).Return(false, nil)
+	mockWebhook.On("SendMessage", msg).Return(webhookResponse, nil)
 	mockRepo.On("UpdateStatus", msg.ID, domain.StatusSent, webhookResponse.MessageID, webhookResponse.Provider).Return(nil)
 	mockCache.On("Set", mock.Anything, mock.Anything).Return(nil)
 	mockEventBus.On("Publish", mock.AnythingOfType("*domain.MessageSentEvent")).Return(nil)
@@ -74,15 +79,19 @@ func TestConsumer_ProcessMessage_WebhookError(t *testing.T) {
 	mockRepo := &mocks.MockRepository{}
 	mockCache := &mocks.MockCache{}
 	mockEventBus := &mocks.MockEventBus{}
+	mockSuppressions := &mocks.MockSuppressionService{}
 	logger := &mockLogger{}
 
-	consumer := NewConsumer(mockWebhook, mockRepo, mockCache, mockEventBus, 1, logger)
+	consumer := NewConsumer(webhook.NewTenantClientResolver(webhook.ChannelClients{domain.ChannelSMS: mockWebhook}, nil, nil, 1), mockRepo, mockCache, mockEventBus, mockSuppressions, WorkerShares{Shared: 1}, domain.DefaultMessageRules(), logger)
 
 	msg := createTestMessage()
 
 	// Mock beklentileri
-	mockWebhook.On("SendMessage", msg.To, msg.Content).Return(nil, assert.AnError)
+	mockRepo.On("TakeQueued", msg.ID).Return(true, nil)
+	mockSuppressions.On("IsSuppressed", msg.TenantID, msg.To).Return(false, nil)
+	mockWebhook.On("SendMessage", msg).Return(nil, assert.AnError)
 	mockRepo.On("UpdateStatus", msg.ID, domain.StatusFailed, "", "").Return(nil)
+	mockEventBus.On("Publish", mock.AnythingOfType("*domain.MessageFailedEvent")).Return(nil)
 
 	// Test
 	err := consumer.processMessage(msg)
@@ -92,6 +101,7 @@ func TestConsumer_ProcessMessage_WebhookError(t *testing.T) {
 	// Beklentilerin karşılandığını kontrol et
 	mockWebhook.AssertExpectations(t)
 	mockRepo.AssertExpectations(t)
+	mockEventBus.AssertExpectations(t)
 }
 
 func TestConsumer_Start(t *testing.T) {
@@ -99,9 +109,10 @@ func TestConsumer_Start(t *testing.T) {
 	mockRepo := &mocks.MockRepository{}
 	mockCache := &mocks.MockCache{}
 	mockEventBus := &mocks.MockEventBus{}
+	mockSuppressions := &mocks.MockSuppressionService{}
 	logger := &mockLogger{}
 
-	consumer := NewConsumer(mockWebhook, mockRepo, mockCache, mockEventBus, 1, logger)
+	consumer := NewConsumer(webhook.NewTenantClientResolver(webhook.ChannelClients{domain.ChannelSMS: mockWebhook}, nil, nil, 1), mockRepo, mockCache, mockEventBus, mockSuppressions, WorkerShares{Shared: 1}, domain.DefaultMessageRules(), logger)
 
 	// Mock event handler'ı yakalayalım
 	var capturedHandler ports.EventHandler
@@ -110,6 +121,7 @@ func TestConsumer_Start(t *testing.T) {
 			capturedHandler = args.Get(1).(ports.EventHandler)
 		}).
 		Return()
+	mockEventBus.On("Subscribe", domain.EventMessageFallback, mock.AnythingOfType("ports.EventHandler")).Return()
 
 	// Consumer'ı başlat
 	err := consumer.Start()
@@ -138,7 +150,9 @@ func TestConsumer_Start(t *testing.T) {
 	}
 
 	// Mock beklentileri
-	mockWebhook.On("SendMessage", msg.To, msg.Content).Return(webhookResponse, nil)
+	mockRepo.On("TakeQueued", msg.ID).Return(true, nil)
+	mockSuppressions.On("IsSuppressed", msg.TenantID, msg.To).Return(false, nil)
+	mockWebhook.On("SendMessage", mock.MatchedBy(func(sent *domain.Message) bool { return sent.ID == msg.ID })).Return(webhookResponse, nil)
 	mockRepo.On("UpdateStatus", msg.ID, domain.StatusSent, webhookResponse.MessageID, webhookResponse.Provider).Return(nil)
 	mockCache.On("Set", mock.Anything, mock.Anything).Return(nil)
 	mockEventBus.On("Publish", mock.AnythingOfType("*domain.MessageSentEvent")).Return(nil)
@@ -156,3 +170,250 @@ func TestConsumer_Start(t *testing.T) {
 	mockCache.AssertExpectations(t)
 	mockEventBus.AssertExpectations(t)
 }
+
+func TestConsumer_ProcessMessage_Suppressed(t *testing.T) {
+	mockWebhook := &mocks.MockWebhookClient{}
+	mockRepo := &mocks.MockRepository{}
+	mockCache := &mocks.MockCache{}
+	mockEventBus := &mocks.MockEventBus{}
+	mockSuppressions := &mocks.MockSuppressionService{}
+	logger := &mockLogger{}
+
+	consumer := NewConsumer(webhook.NewTenantClientResolver(webhook.ChannelClients{domain.ChannelSMS: mockWebhook}, nil, nil, 1), mockRepo, mockCache, mockEventBus, mockSuppressions, WorkerShares{Shared: 1}, domain.DefaultMessageRules(), logger)
+
+	msg := createTestMessage()
+
+	// Mock beklentileri
+	mockRepo.On("TakeQueued", msg.ID).Return(true, nil)
+	mockSuppressions.On("IsSuppressed", msg.TenantID, msg.To).Return(true, nil)
+	mockRepo.On("UpdateStatus", msg.ID, domain.StatusSuppressed, "", "").Return(nil)
+	mockEventBus.On("Publish", mock.AnythingOfType("*domain.MessageFailedEvent")).Return(nil)
+
+	// Test
+	err := consumer.processMessage(msg)
+	assert.NoError(t, err)
+
+	// Webhook hiç çağrılmamalı
+	mockWebhook.AssertNotCalled(t, "SendMessage", mock.Anything)
+	mockSuppressions.AssertExpectations(t)
+	mockRepo.AssertExpectations(t)
+	mockEventBus.AssertExpectations(t)
+}
+
+func TestConsumer_ProcessMessage_SuppressionCheckError(t *testing.T) {
+	mockWebhook := &mocks.MockWebhookClient{}
+	mockRepo := &mocks.MockRepository{}
+	mockCache := &mocks.MockCache{}
+	mockEventBus := &mocks.MockEventBus{}
+	mockSuppressions := &mocks.MockSuppressionService{}
+	logger := &mockLogger{}
+
+	consumer := NewConsumer(webhook.NewTenantClientResolver(webhook.ChannelClients{domain.ChannelSMS: mockWebhook}, nil, nil, 1), mockRepo, mockCache, mockEventBus, mockSuppressions, WorkerShares{Shared: 1}, domain.DefaultMessageRules(), logger)
+
+	msg := createTestMessage()
+
+	mockRepo.On("TakeQueued", msg.ID).Return(true, nil)
+	mockSuppressions.On("IsSuppressed", msg.TenantID, msg.To).Return(false, assert.AnError)
+	// Geçici hatada mesaj kuyrukta kalmamalı, tekrar alınmak üzere pending'e dönmeli
+	mockRepo.On("UpdateStatus", msg.ID, domain.StatusPending, "", "").Return(nil)
+
+	err := consumer.processMessage(msg)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to check suppression list")
+
+	mockWebhook.AssertNotCalled(t, "SendMessage", mock.Anything)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestConsumer_ProcessMessage_ClientLookupErrorReleasesMessage(t *testing.T) {
+	mockWebhook := &mocks.MockWebhookClient{}
+	mockRepo := &mocks.MockRepository{}
+	mockTenants := &mocks.MockTenantRepository{}
+	mockSuppressions := &mocks.MockSuppressionService{}
+
+	resolver := webhook.NewTenantClientResolver(webhook.ChannelClients{domain.ChannelSMS: mockWebhook}, nil, mockTenants, 1)
+	consumer := NewConsumer(resolver, mockRepo, &mocks.MockCache{}, &mocks.MockEventBus{}, mockSuppressions, WorkerShares{Shared: 1}, domain.DefaultMessageRules(), &mockLogger{})
+
+	msg := createTestMessage()
+	msg.TenantID = 7
+
+	mockRepo.On("TakeQueued", msg.ID).Return(true, nil)
+	mockSuppressions.On("IsSuppressed", msg.TenantID, msg.To).Return(false, nil)
+	mockTenants.On("Get", int64(7)).Return(nil, assert.AnError)
+	mockRepo.On("UpdateStatus", msg.ID, domain.StatusPending, "", "").Return(nil)
+
+	err := consumer.processMessage(msg)
+	assert.ErrorContains(t, err, "failed to resolve webhook client")
+
+	mockWebhook.AssertNotCalled(t, "SendMessage", mock.Anything)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestConsumer_ProcessMessage_NoChannelProvider(t *testing.T) {
+	mockWebhook := &mocks.MockWebhookClient{}
+	mockRepo := &mocks.MockRepository{}
+	mockEventBus := &mocks.MockEventBus{}
+	mockSuppressions := &mocks.MockSuppressionService{}
+
+	consumer := NewConsumer(webhook.NewTenantClientResolver(webhook.ChannelClients{domain.ChannelSMS: mockWebhook}, nil, nil, 1), mockRepo, &mocks.MockCache{}, mockEventBus, mockSuppressions, WorkerShares{Shared: 1}, domain.DefaultMessageRules(), &mockLogger{})
+
+	msg := createTestMessage()
+	msg.Channel = domain.ChannelEmail
+	msg.To = "jane@example.com"
+
+	// E-posta sağlayıcısı olmadığından mesaj kuyrukta kalmamalı, başarısız olmalı
+	mockRepo.On("TakeQueued", msg.ID).Return(true, nil)
+	mockSuppressions.On("IsSuppressed", msg.TenantID, msg.To).Return(false, nil)
+	mockRepo.On("UpdateStatus", msg.ID, domain.StatusFailed, "", "").Return(nil)
+	mockEventBus.On("Publish", mock.AnythingOfType("*domain.MessageFailedEvent")).Return(nil)
+
+	err := consumer.processMessage(msg)
+	assert.ErrorIs(t, err, domain.ErrNoChannelProvider)
+
+	mockWebhook.AssertNotCalled(t, "SendMessage", mock.Anything)
+	mockRepo.AssertExpectations(t)
+	mockEventBus.AssertExpectations(t)
+}
+
+func TestConsumer_HandleDeliveryReceipt(t *testing.T) {
+	mockRepo := &mocks.MockRepository{}
+	mockEventBus := &mocks.MockEventBus{}
+
+	consumer := NewConsumer(webhook.NewTenantClientResolver(nil, nil, nil, 1), mockRepo, &mocks.MockCache{}, mockEventBus, &mocks.MockSuppressionService{}, WorkerShares{Shared: 1}, domain.DefaultMessageRules(), &mockLogger{})
+
+	msg := createTestMessage()
+	mockRepo.On("GetByProviderMessageID", "carrier", "sim-1").Return(msg, nil)
+	mockRepo.On("MarkDelivered", msg.ID).Return(nil)
+	mockEventBus.On("Publish", mock.MatchedBy(func(event ports.Event) bool {
+		delivered, ok := event.(*domain.MessageDeliveredEvent)
+		return ok && delivered.Message.ID == msg.ID
+	})).Return(nil)
+
+	consumer.HandleDeliveryReceipt(domain.DeliveryReceipt{Provider: "carrier", MessageID: "sim-1", Status: domain.ReceiptDelivered})
+
+	// Teslim edilemeyen mesaj için olay yayınlanmamalı
+	consumer.HandleDeliveryReceipt(domain.DeliveryReceipt{Provider: "carrier", MessageID: "sim-2", Status: domain.ReceiptUndelivered})
+
+	mockRepo.AssertExpectations(t)
+	mockEventBus.AssertNumberOfCalls(t, "Publish", 1)
+}
+
+func createFallbackMessage() *domain.Message {
+	msg := createTestMessage()
+	msg.Channel = domain.ChannelPush
+	msg.To = "device-token-1"
+	msg.Fallback = []domain.FallbackStep{
+		{Channel: domain.ChannelSMS, To: "+905551234567", WaitSeconds: 30},
+		{Channel: domain.ChannelEmail, To: "jane@example.com", Subject: "Bildirim", WaitSeconds: 60},
+	}
+	return msg
+}
+
+func TestConsumer_ProcessMessage_AwaitsDelivery(t *testing.T) {
+	mockWebhook := &mocks.MockWebhookClient{}
+	mockRepo := &mocks.MockRepository{}
+	mockCache := &mocks.MockCache{}
+	mockEventBus := &mocks.MockEventBus{}
+	mockSuppressions := &mocks.MockSuppressionService{}
+
+	consumer := NewConsumer(webhook.NewTenantClientResolver(webhook.ChannelClients{domain.ChannelPush: mockWebhook}, nil, nil, 1), mockRepo, mockCache, mockEventBus, mockSuppressions, WorkerShares{Shared: 1}, domain.DefaultMessageRules(), &mockLogger{})
+
+	msg := createFallbackMessage()
+
+	mockRepo.On("TakeQueued", msg.ID).Return(true, nil)
+	mockSuppressions.On("IsSuppressed", msg.TenantID, msg.To).Return(false, nil)
+	mockWebhook.On("SendMessage", msg).Return(&domain.WebhookResponse{MessageID: "push_1", Provider: "push"}, nil)
+	mockRepo.On("UpdateStatus", msg.ID, domain.StatusSent, "push_1", "push").Return(nil)
+	mockCache.On("Set", mock.Anything, mock.Anything).Return(nil)
+	mockEventBus.On("Publish", mock.AnythingOfType("*domain.MessageSentEvent")).Return(nil)
+	mockEventBus.On("Publish", mock.MatchedBy(func(event ports.Event) bool {
+		delayed, ok := event.(ports.DelayedEvent)
+		return ok && event.EventName() == domain.EventMessageFallback && delayed.EventDelay() == 30*time.Second
+	})).Return(nil)
+
+	err := consumer.processMessage(msg)
+	assert.NoError(t, err)
+
+	// Teslim beklenirken yedek mesaj hemen oluşturulmamalı
+	mockRepo.AssertNotCalled(t, "CreateFallback", mock.Anything)
+	mockEventBus.AssertExpectations(t)
+}
+
+func TestConsumer_ProcessMessage_FailureFallsBackAtOnce(t *testing.T) {
+	mockWebhook := &mocks.MockWebhookClient{}
+	mockRepo := &mocks.MockRepository{}
+	mockEventBus := &mocks.MockEventBus{}
+	mockSuppressions := &mocks.MockSuppressionService{}
+
+	consumer := NewConsumer(webhook.NewTenantClientResolver(webhook.ChannelClients{domain.ChannelPush: mockWebhook}, nil, nil, 1), mockRepo, &mocks.MockCache{}, mockEventBus, mockSuppressions, WorkerShares{Shared: 1}, domain.DefaultMessageRules(), &mockLogger{})
+
+	msg := createFallbackMessage()
+
+	mockRepo.On("TakeQueued", msg.ID).Return(true, nil)
+	mockSuppressions.On("IsSuppressed", msg.TenantID, msg.To).Return(false, nil)
+	mockWebhook.On("SendMessage", msg).Return(nil, assert.AnError)
+	mockRepo.On("UpdateStatus", msg.ID, domain.StatusFailed, "", "").Return(nil)
+	mockEventBus.On("Publish", mock.AnythingOfType("*domain.MessageFailedEvent")).Return(nil)
+	mockRepo.On("CreateFallback", mock.MatchedBy(func(next *domain.Message) bool {
+		return next.ParentID == msg.ID && next.Channel == domain.ChannelSMS && next.To == "+905551234567" &&
+			next.Content == msg.Content && next.Status == domain.StatusPending && len(next.Fallback) == 1
+	})).Return(nil)
+
+	err := consumer.processMessage(msg)
+	assert.Error(t, err)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestConsumer_FallbackEvent(t *testing.T) {
+	mockRepo := &mocks.MockRepository{}
+	mockEventBus := &mocks.MockEventBus{}
+
+	consumer := NewConsumer(webhook.NewTenantClientResolver(nil, nil, nil, 1), mockRepo, &mocks.MockCache{}, mockEventBus, &mocks.MockSuppressionService{}, WorkerShares{Shared: 1}, domain.DefaultMessageRules(), &mockLogger{})
+
+	var fallbackHandler ports.EventHandler
+	mockEventBus.On("Subscribe", domain.EventMessageQueued, mock.AnythingOfType("ports.EventHandler")).Return()
+	mockEventBus.On("Subscribe", domain.EventMessageFallback, mock.AnythingOfType("ports.EventHandler")).
+		Run(func(args mock.Arguments) {
+			fallbackHandler = args.Get(1).(ports.EventHandler)
+		}).
+		Return()
+	assert.NoError(t, consumer.Start())
+
+	event := domain.NewMessageFallbackEvent(createFallbackMessage())
+	data, err := json.Marshal(event)
+	assert.NoError(t, err)
+	envelope := &domain.EventEnvelope{Name: domain.EventMessageFallback, Data: data}
+
+	// Teslim edilmiş mesaj için yedek gönderilmez, olay yine de onaylanır
+	mockRepo.On("CreateFallback", mock.MatchedBy(func(next *domain.Message) bool {
+		return next.ParentID == 123 && next.Channel == domain.ChannelSMS
+	})).Return(domain.ErrFallbackNotNeeded).Once()
+	assert.NoError(t, fallbackHandler(envelope))
+
+	// Veritabanı hatasında olay yeniden denenmeli
+	mockRepo.On("CreateFallback", mock.Anything).Return(assert.AnError).Once()
+	assert.Error(t, fallbackHandler(envelope))
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestConsumer_ProcessMessage_SkipsMessageNoLongerQueued(t *testing.T) {
+	mockWebhook := &mocks.MockWebhookClient{}
+	mockRepo := &mocks.MockRepository{}
+	mockSuppressions := &mocks.MockSuppressionService{}
+
+	consumer := NewConsumer(webhook.NewTenantClientResolver(webhook.ChannelClients{domain.ChannelSMS: mockWebhook}, nil, nil, 1), mockRepo, &mocks.MockCache{}, &mocks.MockEventBus{}, mockSuppressions, WorkerShares{Shared: 1}, domain.DefaultMessageRules(), &mockLogger{})
+
+	msg := createTestMessage()
+
+	// Yeniden kuyruğa alınan mesajın ikinci kopyası gönderilmemeli
+	mockRepo.On("TakeQueued", msg.ID).Return(false, nil)
+
+	err := consumer.processMessage(msg)
+	assert.NoError(t, err)
+
+	mockSuppressions.AssertNotCalled(t, "IsSuppressed", mock.Anything, mock.Anything)
+	mockWebhook.AssertNotCalled(t, "SendMessage", mock.Anything)
+	mockRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,146 @@
+package consumer
+
+import (
+	"sync"
+
+	"github.com/ercancavusoglu/messaging/internal/domain"
+)
+
+// WorkerShares splits the consumer workers between priority classes. Reserved workers only process
+// their own class; shared workers process any class and are handed to the most urgent waiting class
+// first, so bulk traffic can use idle capacity without holding back critical messages.
+type WorkerShares struct {
+	Reserved map[domain.Priority]int
+	Shared   int
+}
+
+// Total returns the number of messages processed concurrently
+func (s WorkerShares) Total() int {
+	total := s.Shared
+	for _, reserved := range s.Reserved {
+		total += reserved
+	}
+	return total
+}
+
+// workerPool counts the free workers; a negative count means more workers are busy than the current
+// shares allow, after the pool was shrunk, and those workers are retired as they finish
+type workerPool struct {
+	mu       sync.Mutex
+	size     WorkerShares
+	reserved map[domain.Priority]int
+	shared   int
+	waiting  map[domain.Priority][]chan bool
+}
+
+func newWorkerPool(shares WorkerShares) *workerPool {
+	pool := &workerPool{
+		size:     WorkerShares{Reserved: make(map[domain.Priority]int)},
+		reserved: make(map[domain.Priority]int),
+		waiting:  make(map[domain.Priority][]chan bool),
+	}
+	pool.resize(shares)
+
+	return pool
+}
+
+// resize applies new shares without interrupting the messages being processed
+func (p *workerPool) resize(shares WorkerShares) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	shared := shares.Shared
+	for _, priority := range domain.Priorities {
+		// A class without reserved workers relies on the shared ones, so there must be at least one
+		if shares.Reserved[priority] == 0 && shared == 0 {
+			shared = 1
+		}
+	}
+
+	for _, priority := range domain.Priorities {
+		p.reserved[priority] += shares.Reserved[priority] - p.size.Reserved[priority]
+		p.size.Reserved[priority] = shares.Reserved[priority]
+		for p.reserved[priority] > 0 && p.handOver(priority, false) {
+			p.reserved[priority]--
+		}
+	}
+
+	p.shared += shared - p.size.Shared
+	p.size.Shared = shared
+	for p.shared > 0 && p.handOverShared() {
+		p.shared--
+	}
+}
+
+// acquire blocks until a worker is free for the class and reports whether it is a shared one
+func (p *workerPool) acquire(priority domain.Priority) bool {
+	p.mu.Lock()
+	if p.reserved[priority] > 0 {
+		p.reserved[priority]--
+		p.mu.Unlock()
+		return false
+	}
+	if p.shared > 0 && !p.moreUrgentWaiting(priority) {
+		p.shared--
+		p.mu.Unlock()
+		return true
+	}
+
+	slot := make(chan bool, 1)
+	p.waiting[priority] = append(p.waiting[priority], slot)
+	p.mu.Unlock()
+
+	return <-slot
+}
+
+// release hands the worker to the next waiting message: a reserved worker stays within its class,
+// a shared one goes to the most urgent class with a waiting message
+func (p *workerPool) release(priority domain.Priority, shared bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !shared {
+		if p.reserved[priority] >= 0 && p.handOver(priority, false) {
+			return
+		}
+		p.reserved[priority]++
+		return
+	}
+
+	if p.shared >= 0 && p.handOverShared() {
+		return
+	}
+	p.shared++
+}
+
+func (p *workerPool) handOverShared() bool {
+	for _, class := range domain.Priorities {
+		if p.handOver(class, true) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *workerPool) handOver(priority domain.Priority, shared bool) bool {
+	waiting := p.waiting[priority]
+	if len(waiting) == 0 {
+		return false
+	}
+
+	waiting[0] <- shared
+	p.waiting[priority] = waiting[1:]
+	return true
+}
+
+func (p *workerPool) moreUrgentWaiting(priority domain.Priority) bool {
+	for _, class := range domain.Priorities {
+		if class == priority {
+			return false
+		}
+		if len(p.waiting[class]) > 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,96 @@
+package consumer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ercancavusoglu/messaging/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkerPool_ReservedWorkersPerClass(t *testing.T) {
+	pool := newWorkerPool(WorkerShares{
+		Reserved: map[domain.Priority]int{domain.PriorityCritical: 1, domain.PriorityBulk: 1},
+		Shared:   1,
+	})
+
+	// Bulk önce kendi ayrılmış işçisini, sonra paylaşılanı kullanır
+	assert.False(t, pool.acquire(domain.PriorityBulk))
+	assert.True(t, pool.acquire(domain.PriorityBulk))
+
+	// Tüm paylaşılan işçiler bulk tarafından kullanılsa bile critical bekletilmemeli
+	acquired := make(chan bool, 1)
+	go func() { acquired <- pool.acquire(domain.PriorityCritical) }()
+
+	select {
+	case shared := <-acquired:
+		assert.False(t, shared)
+	case <-time.After(time.Second):
+		t.Fatal("critical message waited for a bulk worker")
+	}
+}
+
+func TestWorkerPool_SharedWorkerGoesToMostUrgentClass(t *testing.T) {
+	pool := newWorkerPool(WorkerShares{Shared: 1})
+
+	assert.True(t, pool.acquire(domain.PriorityNormal))
+
+	order := make(chan domain.Priority, 2)
+	waitFor := func(priority domain.Priority) {
+		go func() {
+			shared := pool.acquire(priority)
+			order <- priority
+			pool.release(priority, shared)
+		}()
+	}
+
+	waitFor(domain.PriorityBulk)
+	assert.Eventually(t, func() bool { return waiting(pool, domain.PriorityBulk) == 1 }, time.Second, time.Millisecond)
+	waitFor(domain.PriorityCritical)
+	assert.Eventually(t, func() bool { return waiting(pool, domain.PriorityCritical) == 1 }, time.Second, time.Millisecond)
+
+	// Bulk önce beklemeye başlasa da boşalan işçi önce critical'a verilmeli
+	pool.release(domain.PriorityNormal, true)
+	assert.Equal(t, domain.PriorityCritical, <-order)
+	assert.Equal(t, domain.PriorityBulk, <-order)
+}
+
+func TestWorkerPool_AlwaysHasWorkerForEveryClass(t *testing.T) {
+	pool := newWorkerPool(WorkerShares{Reserved: map[domain.Priority]int{domain.PriorityCritical: 1}})
+
+	assert.True(t, pool.acquire(domain.PriorityBulk))
+}
+
+func TestWorkerPool_Resize(t *testing.T) {
+	pool := newWorkerPool(WorkerShares{Reserved: map[domain.Priority]int{domain.PriorityBulk: 2}, Shared: 1})
+
+	assert.False(t, pool.acquire(domain.PriorityBulk))
+	assert.False(t, pool.acquire(domain.PriorityBulk))
+
+	acquired := make(chan bool, 1)
+	go func() { acquired <- pool.acquire(domain.PriorityBulk) }()
+	assert.True(t, <-acquired)
+
+	go func() { acquired <- pool.acquire(domain.PriorityBulk) }()
+	assert.Eventually(t, func() bool { return waiting(pool, domain.PriorityBulk) == 1 }, time.Second, time.Millisecond)
+
+	// Küçültme çalışan işçileri kesmez, biten işçi bekleyen mesaja verilmez
+	pool.resize(WorkerShares{Reserved: map[domain.Priority]int{domain.PriorityBulk: 1}, Shared: 1})
+	pool.release(domain.PriorityBulk, false)
+	assert.Equal(t, 1, waiting(pool, domain.PriorityBulk))
+
+	// Büyütme bekleyen mesaja hemen işçi verir
+	pool.resize(WorkerShares{Reserved: map[domain.Priority]int{domain.PriorityBulk: 2}, Shared: 1})
+	select {
+	case shared := <-acquired:
+		assert.False(t, shared)
+	case <-time.After(time.Second):
+		t.Fatal("waiting message did not get the added worker")
+	}
+}
+
+func waiting(pool *workerPool, priority domain.Priority) int {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+	return len(pool.waiting[priority])
+}
@@ -3,179 +3,568 @@ package adapters
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"net/http"
-	"os"
-	"time"
+	"reflect"
+	"strings"
+	"sync"
 
+	"github.com/ercancavusoglu/messaging/internal/adapters/callback"
 	"github.com/ercancavusoglu/messaging/internal/adapters/consumer"
+	"github.com/ercancavusoglu/messaging/internal/adapters/email"
 	"github.com/ercancavusoglu/messaging/internal/adapters/eventbus"
 	logrus "github.com/ercancavusoglu/messaging/internal/adapters/logger"
 	"github.com/ercancavusoglu/messaging/internal/adapters/persistance/cache"
 	"github.com/ercancavusoglu/messaging/internal/adapters/persistance/postgres"
+	"github.com/ercancavusoglu/messaging/internal/adapters/persistance/postgres/migrations"
 	"github.com/ercancavusoglu/messaging/internal/adapters/scheduler"
+	"github.com/ercancavusoglu/messaging/internal/adapters/smpp"
+	"github.com/ercancavusoglu/messaging/internal/adapters/stream"
 	"github.com/ercancavusoglu/messaging/internal/adapters/webhook"
+	"github.com/ercancavusoglu/messaging/internal/config"
 	"github.com/ercancavusoglu/messaging/internal/domain"
 	"github.com/ercancavusoglu/messaging/internal/ports"
 	"github.com/go-redis/redis/v8"
-	"github.com/joho/godotenv"
 	_ "github.com/lib/pq"
 )
 
+// Container builds the application's dependencies on first use, so every command only connects to the
+// infrastructure it needs: migrate only opens Postgres, while the API server also needs Redis and RabbitMQ.
 type Container struct {
-	DB             *sql.DB
-	Redis          *redis.Client
-	EventBus       *eventbus.RabbitMQEventBus
-	MessageService ports.MessageService
-	Scheduler      *scheduler.SchedulerService
-	Consumer       *consumer.Consumer
-	Server         *http.Server
-	Logger         ports.Logger
+	Config *config.Config
+	Logger ports.Logger
+	// ConfigWatcher, when set, lets WatchConfig apply configuration changes to the running components
+	ConfigWatcher *config.Watcher
+
+	reloadMu  sync.Mutex
+	reloaders []func(cfg *config.Config)
+
+	db            *sql.DB
+	schemaChecked bool
+	redis         *redis.Client
+	eventBus      eventbus.Bus
+	smppMu        sync.Mutex
+	smppClients   []*smpp.Client
+}
+
+// Server is the HTTP API together with the scheduler, which only ticks on the elected leader
+type Server struct {
+	HTTP        *http.Server
+	Scheduler   *scheduler.SchedulerService
+	Coordinator *scheduler.Coordinator
+	logger      ports.Logger
+}
+
+// Worker sends queued messages, keeps campaign progress up to date and delivers the tenants' status callbacks
+type Worker struct {
+	Consumer  *consumer.Consumer
+	eventBus  ports.EventBus
+	campaigns ports.CampaignService
+	callbacks *callback.Dispatcher
+	logger    ports.Logger
 }
 
-func NewContainer() (*Container, error) {
-	logPath := os.Getenv("LOG_PATH")
-	if logPath == "" {
-		logPath = "logs/dev.log"
+// NewContainer expects a validated configuration, see config.Config.Validate
+func NewContainer(cfg *config.Config) (*Container, error) {
+	if err := cfg.Require("log"); err != nil {
+		return nil, err
 	}
 
-	logger, err := logrus.NewLogrusAdapter(logPath)
+	logger, err := logrus.NewLogrusAdapter(cfg.Log.Path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize logger: %w", err)
 	}
 
-	// Load .env file
-	if err := godotenv.Load(); err != nil {
-		logger.Warnf("Warning: .env file not found or error loading it: %v", err)
+	return &Container{Config: cfg, Logger: logger}, nil
+}
+
+// MessageRules are the message settings the services validate new messages against
+func (c *Container) MessageRules() domain.MessageRules {
+	rules := domain.DefaultMessageRules()
+	if c.Config.Message.DefaultRegion != "" {
+		rules.DefaultRegion = c.Config.Message.DefaultRegion
 	}
+	rules.MaxSegments = c.Config.Message.MaxSegments
+	return rules
+}
 
-	// Initialize database
-	dbURL := fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=%s",
-		os.Getenv("DB_USER"),
-		os.Getenv("DB_PASSWORD"),
-		os.Getenv("DB_HOST"),
-		os.Getenv("DB_PORT"),
-		os.Getenv("DB_NAME"),
-		os.Getenv("DB_SSL_MODE"),
-	)
+// Database returns the Postgres connection after making sure the schema matches this release
+func (c *Container) Database() (*sql.DB, error) {
+	db, err := c.openDatabase()
+	if err != nil {
+		return nil, err
+	}
 
-	logger.Info("Connecting to database...")
-	db, err := sql.Open("postgres", dbURL)
+	if !c.schemaChecked {
+		migrator, err := postgres.NewMigrator(db, migrations.FS)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load migrations: %w", err)
+		}
+		if err := migrator.CheckVersion(); err != nil {
+			return nil, fmt.Errorf("failed to verify database schema: %w", err)
+		}
+		c.schemaChecked = true
+	}
+
+	return db, nil
+}
+
+// Migrator skips the schema check, since migrating is how an outdated schema gets fixed
+func (c *Container) Migrator() (*postgres.Migrator, error) {
+	db, err := c.openDatabase()
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to database: %w", err)
+		return nil, err
 	}
-	logger.Info("Database connection established")
 
-	// Initialize Redis
-	logger.Info("Connecting to Redis...")
-	rdb := redis.NewClient(&redis.Options{
-		Addr: fmt.Sprintf("%s:%s",
-			os.Getenv("REDIS_HOST"),
-			os.Getenv("REDIS_PORT"),
-		),
-	})
-	logger.Info("Redis connection established")
+	return postgres.NewMigrator(db, migrations.FS)
+}
+
+func (c *Container) openDatabase() (*sql.DB, error) {
+	if c.db != nil {
+		return c.db, nil
+	}
+
+	if err := c.Config.Require("database"); err != nil {
+		return nil, err
+	}
+
+	c.Logger.Info("Connecting to database...")
+	db, err := OpenDatabase(c.Config.Database)
+	if err != nil {
+		return nil, err
+	}
+	c.Logger.Info("Database connection established")
+
+	c.db = db
+	return db, nil
+}
+
+func (c *Container) Redis() (*redis.Client, error) {
+	if c.redis == nil {
+		if err := c.Config.Require("redis"); err != nil {
+			return nil, err
+		}
+
+		c.Logger.Info("Connecting to Redis...")
+		c.redis = redis.NewClient(&redis.Options{
+			Addr: c.Config.Redis.Addr(),
+		})
+		c.Logger.Info("Redis connection established")
+	}
+
+	return c.redis, nil
+}
+
+// EventBus connects to RabbitMQ or Kafka, or with the memory driver keeps the events inside this process
+func (c *Container) EventBus() (eventbus.Bus, error) {
+	if c.eventBus == nil {
+		switch c.Config.EventBus.Driver {
+		case config.DriverMemory:
+			c.Logger.Info("Using in-memory event bus")
+			c.eventBus = eventbus.NewMemoryEventBus(c.Config.EventBus.BufferSize)
+			return c.eventBus, nil
+		case config.DriverKafka:
+			if err := c.Config.Require("kafka"); err != nil {
+				return nil, err
+			}
+
+			c.Logger.Info("Connecting to Kafka...")
+			eventBus, err := eventbus.NewKafkaEventBus(c.Config.Kafka)
+			if err != nil {
+				return nil, fmt.Errorf("failed to connect to Kafka: %w", err)
+			}
+			c.Logger.Info("Kafka connection established")
+			c.eventBus = eventBus
+			return c.eventBus, nil
+		}
+
+		if err := c.Config.Require("rabbitmq"); err != nil {
+			return nil, err
+		}
+
+		c.Logger.Info("Connecting to RabbitMQ...")
+		eventBus, err := eventbus.NewRabbitMQEventBus(c.Config.RabbitMQ)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to RabbitMQ: %w", err)
+		}
+		c.Logger.Info("RabbitMQ connection established")
+		c.eventBus = eventBus
+	}
+
+	return c.eventBus, nil
+}
+
+// CloseEventBus closes the event bus before the other connections, so the memory driver can still drain
+// its queues into the database; Close does so too if it was not called
+func (c *Container) CloseEventBus() error {
+	if c.eventBus == nil {
+		return nil
+	}
+
+	err := c.eventBus.Close()
+	c.eventBus = nil
+	if err != nil {
+		c.Logger.Errorf("Failed to close event bus connection: %v", err)
+		return fmt.Errorf("failed to close event bus connection: %w", err)
+	}
+	return nil
+}
+
+// MessageStore returns a message service for commands that only create messages and leave publishing
+// them to the scheduler, so they do not need Redis or RabbitMQ
+func (c *Container) MessageStore() (ports.MessageService, ports.TenantService, error) {
+	db, err := c.Database()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return NewMessageService(postgres.NewMessageRepository(db), nil, nil, nil), NewTenantService(postgres.NewTenantRepository(db)), nil
+}
+
+// NewServer wires the HTTP API and the scheduler; it publishes queued messages but consumes nothing
+func (c *Container) NewServer() (*Server, error) {
+	if err := c.Config.Require("server"); err != nil {
+		return nil, err
+	}
 
-	// Initialize RabbitMQ
-	logger.Info("Connecting to RabbitMQ...")
-	eventBus, err := eventbus.NewRabbitMQEventBus(os.Getenv("RABBITMQ_URL"))
+	db, err := c.Database()
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to RabbitMQ: %w", err)
+		return nil, err
 	}
-	logger.Info("RabbitMQ connection established")
 
-	// Initialize repositories and services
+	eventBus, err := c.EventBus()
+	if err != nil {
+		return nil, err
+	}
+
+	rdb, err := c.Redis()
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := c.Config
+	logger := c.Logger
+
 	logger.Info("Initializing services...")
 	messageRepo := postgres.NewMessageRepository(db)
+	cacheClient := cache.NewRedisAdapter(rdb, cfg.Cache.TTL)
+	tenantSvc := NewTenantService(postgres.NewTenantRepository(db))
+	rules := c.MessageRules()
+	suppressionSvc := NewSuppressionService(postgres.NewSuppressionRepository(db), cacheClient, rules.DefaultRegion)
+	keywordMatcher := domain.NewKeywordMatcher(cfg.Inbound.StopKeywords, cfg.Inbound.StartKeywords, cfg.Inbound.HelpKeywords)
+	inboundSvc := NewInboundService(postgres.NewInboundRepository(db), suppressionSvc, eventBus, keywordMatcher)
+	// The API only queues messages; sending them is the worker's job
+	messageSvc := NewMessageService(messageRepo, nil, cacheClient, eventBus)
+	templateSvc := NewTemplateService(postgres.NewTemplateRepository(db), rules)
+	campaignSvc := NewCampaignService(postgres.NewCampaignRepository(db), templateSvc, rules, logger)
+	callbackRepo := postgres.NewCallbackRepository(db)
+	callbackSvc := NewCallbackService(callbackRepo, callback.NewDispatcher(callbackRepo, logger), logger)
 
-	// Initialize webhook clients
-	webhookClientOne := webhook.NewClient(os.Getenv("WEBHOOK_URL_ONE"), os.Getenv("WEBHOOK_TOKEN_ONE"))
-	webhookClientTwo := webhook.NewClientTwo(os.Getenv("WEBHOOK_URL_TWO"), os.Getenv("WEBHOOK_TOKEN_TWO"))
-	webhookClient := webhook.NewRetryableWebhookClient(
-		[]ports.WebhookClient{
-			webhookClientOne,
-			webhookClientTwo,
-		},
-		2, // maxRetries
-	)
+	// The live stream observes the events through a queue of this process, next to the workers' queues
+	streamHub := stream.NewHub(logger)
+	for _, eventName := range stream.Events {
+		if err := eventBus.Observe(eventName, streamHub.HandleEvent); err != nil {
+			return nil, fmt.Errorf("failed to observe %s events: %w", eventName, err)
+		}
+	}
 
-	cacheClient := cache.NewRedisAdapter(rdb)
-	messageSvc := NewMessageService(messageRepo, webhookClient, cacheClient, eventBus)
-	messageScheduler := scheduler.NewSchedulerService(messageSvc, 2*time.Second, logger)
-	messageConsumer := consumer.NewConsumer(webhookClient, messageRepo, cacheClient, eventBus, 5, logger)
+	messageScheduler := scheduler.NewSchedulerService(messageSvc, cfg.Scheduler.Interval, cfg.Scheduler.BatchSize, cfg.Scheduler.VisibilityTimeout, logger)
+	messageScheduler.SetMaxInFlight(cfg.Scheduler.MaxInFlight)
 
-	eventBus.Subscribe(domain.EventMessageSent, func(event ports.Event) error {
-		logger.Infof("[EventHandler] Handling message.sent event: %+v", event)
-		return nil
+	leaderLock, err := cache.NewRedisLeaderLock(rdb, "scheduler:leader", cfg.Scheduler.Lease)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create scheduler leader lock: %w", err)
+	}
+	schedulerState, err := c.SchedulerState()
+	if err != nil {
+		return nil, err
+	}
+	coordinator := scheduler.NewCoordinator(messageScheduler, leaderLock, schedulerState, cfg.Scheduler.Lease, logger)
+
+	auditSvc := NewAuditService(postgres.NewAuditRepository(db), logger)
+	messageHandler := NewMessageHandler(messageSvc, templateSvc, auditSvc, coordinator, rules)
+	suppressionHandler := NewSuppressionHandler(suppressionSvc)
+	inboundHandler := NewInboundHandler(inboundSvc, cfg.InboundSignatures())
+	templateHandler := NewTemplateHandler(templateSvc)
+	campaignHandler := NewCampaignHandler(campaignSvc)
+	callbackHandler := NewCallbackHandler(callbackSvc)
+	streamHandler := NewStreamHandler(streamHub)
+	tenantHandler := NewTenantHandler(tenantSvc)
+	auditHandler := NewAuditHandler(auditSvc)
+
+	c.onReload(func(cfg *config.Config) {
+		messageScheduler.SetBatchSize(cfg.Scheduler.BatchSize)
+		messageScheduler.SetMaxInFlight(cfg.Scheduler.MaxInFlight)
+		cacheClient.SetTTL(cfg.Cache.TTL)
+		inboundHandler.SetSignatures(cfg.InboundSignatures())
 	})
 
-	messageHandler := NewMessageHandler(messageSvc, messageScheduler)
-	router := NewRouter(messageHandler)
-	server := &http.Server{
-		Addr:    fmt.Sprintf(":%s", os.Getenv("SERVER_PORT")),
+	router := NewRouter(messageHandler, suppressionHandler, inboundHandler, templateHandler, campaignHandler, callbackHandler, streamHandler, tenantHandler, auditHandler, tenantSvc, cfg.Server.AdminAPIKey)
+
+	httpServer := &http.Server{
+		Addr:    fmt.Sprintf(":%s", cfg.Server.Port),
 		Handler: router,
 	}
+	// Open streams would otherwise keep Shutdown waiting until its deadline
+	httpServer.RegisterOnShutdown(streamHub.Close)
 
-	return &Container{
-		DB:             db,
-		Redis:          rdb,
-		EventBus:       eventBus,
-		MessageService: messageSvc,
-		Scheduler:      messageScheduler,
-		Consumer:       messageConsumer,
-		Server:         server,
-		Logger:         logger,
+	return &Server{
+		HTTP:        httpServer,
+		Scheduler:   messageScheduler,
+		Coordinator: coordinator,
+		logger:      logger,
 	}, nil
 }
 
-func (c *Container) Start(ctx context.Context) error {
-	if err := c.Consumer.Start(); err != nil {
-		c.Logger.Errorf("[Container] Consumer error: %v", err)
-		return err
+// SchedulerState is the scheduler state shared by all replicas; with scheduler.auto_start the scheduler
+// runs on boot until someone stops it through the API
+func (c *Container) SchedulerState() (ports.SchedulerState, error) {
+	rdb, err := c.Redis()
+	if err != nil {
+		return nil, err
 	}
 
-	go func() {
-		if err := c.Scheduler.Start(ctx); err != nil {
-			c.Logger.Errorf("[Container] Scheduler error: %v", err)
+	return cache.NewRedisSchedulerState(rdb, c.Config.Scheduler.AutoStart), nil
+}
+
+// NewWorker wires the consumer that sends queued messages through the providers of their channel
+func (c *Container) NewWorker() (*Worker, error) {
+	db, err := c.Database()
+	if err != nil {
+		return nil, err
+	}
+
+	eventBus, err := c.EventBus()
+	if err != nil {
+		return nil, err
+	}
+
+	rdb, err := c.Redis()
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := c.Config
+	logger := c.Logger
+	messageRepo := postgres.NewMessageRepository(db)
+	cacheClient := cache.NewRedisAdapter(rdb, cfg.Cache.TTL)
+	tenantClients := webhook.NewTenantClientResolver(nil, nil, postgres.NewTenantRepository(db), cfg.Webhook.MaxRetries)
+	rules := c.MessageRules()
+	suppressionSvc := NewSuppressionService(postgres.NewSuppressionRepository(db), cacheClient, rules.DefaultRegion)
+	templateSvc := NewTemplateService(postgres.NewTemplateRepository(db), rules)
+
+	messageConsumer := consumer.NewConsumer(tenantClients, messageRepo, cacheClient, eventBus, suppressionSvc, workerShares(cfg.Consumer), rules, logger)
+
+	// SMPP providers report delivery receipts to the consumer, so the clients are built once it exists
+	sharedClients, smppClients := newWebhookClients(cfg.Webhook, messageConsumer.HandleDeliveryReceipt)
+	tenantClients.Reload(sharedClients, cfg.Webhook.ProviderList(), cfg.Webhook.MaxRetries)
+	closeSMPPClients(c.useSMPPClients(smppClients))
+
+	// Messages being sent finish with the provider client and worker they started with
+	webhookSettings := cfg.Webhook
+	c.onReload(func(cfg *config.Config) {
+		// Rebuilding the clients re-binds the SMPP sessions, so it only happens when the providers change
+		if !reflect.DeepEqual(webhookSettings, cfg.Webhook) {
+			webhookSettings = cfg.Webhook
+			sharedClients, smppClients := newWebhookClients(cfg.Webhook, messageConsumer.HandleDeliveryReceipt)
+			tenantClients.Reload(sharedClients, cfg.Webhook.ProviderList(), cfg.Webhook.MaxRetries)
+			go closeSMPPClients(c.useSMPPClients(smppClients))
 		}
-	}()
+		messageConsumer.SetWorkerShares(workerShares(cfg.Consumer))
+		cacheClient.SetTTL(cfg.Cache.TTL)
+	})
+
+	return &Worker{
+		Consumer:  messageConsumer,
+		eventBus:  eventBus,
+		campaigns: NewCampaignService(postgres.NewCampaignRepository(db), templateSvc, rules, logger),
+		callbacks: callback.NewDispatcher(postgres.NewCallbackRepository(db), logger),
+		logger:    logger,
+	}, nil
+}
+
+// Reload applies a new configuration to the components built so far. Settings that only take effect
+// after a restart are reported and otherwise ignored.
+func (c *Container) Reload(cfg *config.Config) {
+	c.reloadMu.Lock()
+	defer c.reloadMu.Unlock()
+
+	if keys := c.Config.RestartRequired(cfg); len(keys) > 0 {
+		c.Logger.Warnf("[Config] Changes to %s take effect after a restart", strings.Join(keys, ", "))
+	}
+
+	for _, reload := range c.reloaders {
+		reload(cfg)
+	}
+	c.Config = cfg
+
+	c.Logger.Info("[Config] Configuration reloaded")
+}
+
+// WatchConfig reloads the configuration on file changes and SIGHUP until ctx is done; a configuration
+// that fails validation is logged and the running one is kept
+func (c *Container) WatchConfig(ctx context.Context) {
+	if c.ConfigWatcher == nil {
+		return
+	}
+
+	c.ConfigWatcher.Run(ctx, c.Reload, func(err error) {
+		c.Logger.Errorf("[Config] Rejected new configuration, keeping the current one: %v", err)
+	})
+}
+
+func (c *Container) onReload(reload func(cfg *config.Config)) {
+	c.reloadMu.Lock()
+	defer c.reloadMu.Unlock()
+	c.reloaders = append(c.reloaders, reload)
+}
+
+// Close releases the connections that were opened
+func (c *Container) Close() error {
+	var errs []error
+
+	closeSMPPClients(c.useSMPPClients(nil))
+
+	if err := c.CloseEventBus(); err != nil {
+		errs = append(errs, err)
+	}
+
+	if c.redis != nil {
+		if err := c.redis.Close(); err != nil {
+			c.Logger.Errorf("Failed to close Redis connection: %v", err)
+			errs = append(errs, fmt.Errorf("failed to close Redis connection: %w", err))
+		}
+	}
+
+	if c.db != nil {
+		if err := c.db.Close(); err != nil {
+			c.Logger.Errorf("Failed to close database connection: %v", err)
+			errs = append(errs, fmt.Errorf("failed to close database connection: %w", err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (s *Server) Start(ctx context.Context) error {
+	// Every replica runs the coordinator; only the elected leader runs the scheduler ticker
+	go s.Coordinator.Run(ctx)
 
 	go func() {
-		c.Logger.Infof("[Container] HTTP server listening on %s", c.Server.Addr)
-		if err := c.Server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			c.Logger.Errorf("[Container] Server error: %v", err)
+		s.logger.Infof("[Server] HTTP server listening on %s", s.HTTP.Addr)
+		if err := s.HTTP.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			s.logger.Errorf("[Server] Server error: %v", err)
 		}
 	}()
 
 	return nil
 }
 
-func (c *Container) Shutdown(ctx context.Context) error {
-	c.Logger.Info("[Container] Shutting down...")
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.logger.Info("[Server] Shutting down...")
 
-	c.Consumer.Stop()
-	c.Scheduler.Stop()
+	s.Coordinator.Close()
 
-	if err := c.Server.Shutdown(ctx); err != nil {
-		c.Logger.Errorf("Failed to shutdown server: %v", err)
+	if err := s.HTTP.Shutdown(ctx); err != nil {
+		s.logger.Errorf("Failed to shutdown server: %v", err)
 		return fmt.Errorf("failed to shutdown server: %w", err)
 	}
 
-	if err := c.DB.Close(); err != nil {
-		c.Logger.Errorf("Failed to close database connection: %v", err)
-		return fmt.Errorf("failed to close database connection: %w", err)
+	return nil
+}
+
+func (w *Worker) Start() error {
+	if err := w.Consumer.Start(); err != nil {
+		w.logger.Errorf("[Worker] Consumer error: %v", err)
+		return err
 	}
 
-	if err := c.Redis.Close(); err != nil {
-		c.Logger.Errorf("Failed to close Redis connection: %v", err)
-		return fmt.Errorf("failed to close Redis connection: %w", err)
+	w.eventBus.Subscribe(domain.EventMessageSent, func(event ports.Event) error {
+		w.logger.Infof("[EventHandler] Handling message.sent event: %+v", event)
+		return nil
+	})
+
+	for _, eventName := range []string{domain.EventMessageSent, domain.EventMessageFailed, domain.EventMessageDelivered} {
+		w.eventBus.Subscribe(eventName, w.campaigns.HandleEvent)
 	}
 
-	if err := c.EventBus.Close(); err != nil {
-		c.Logger.Errorf("Failed to close event bus connection: %v", err)
-		return fmt.Errorf("failed to close event bus connection: %w", err)
+	for _, eventName := range domain.CallbackEvents {
+		w.eventBus.Subscribe(eventName, w.callbacks.HandleEvent)
 	}
+	w.callbacks.Start()
 
-	c.Logger.Info("[Container] Shutdown complete")
 	return nil
 }
+
+// Stop waits for the messages being sent and the callbacks being delivered
+func (w *Worker) Stop() {
+	w.Consumer.Stop()
+	w.callbacks.Stop()
+}
+
+// workerShares keeps workers of every class of its own so a campaign cannot occupy the ones OTP messages need
+func workerShares(settings config.Consumer) consumer.WorkerShares {
+	return consumer.WorkerShares{
+		Reserved: map[domain.Priority]int{
+			domain.PriorityCritical: settings.CriticalWorkers,
+			domain.PriorityNormal:   settings.NormalWorkers,
+			domain.PriorityBulk:     settings.BulkWorkers,
+		},
+		Shared: settings.SharedWorkers,
+	}
+}
+
+// newWebhookClients returns the shared client of every channel configured in the webhook section, whose
+// providers are tried in order, together with the SMPP clients among them. SMPP clients bind in the
+// background and report delivery receipts to onReceipt.
+func newWebhookClients(settings config.Webhook, onReceipt func(domain.DeliveryReceipt)) (webhook.ChannelClients, []*smpp.Client) {
+	providers := make(map[domain.Channel][]ports.WebhookClient)
+	var smppClients []*smpp.Client
+	for _, provider := range settings.ProviderList() {
+		channel := provider.DeliveryChannel()
+		switch provider.Type {
+		case config.TypeSMPP:
+			client := smpp.NewClient(provider, onReceipt)
+			smppClients = append(smppClients, client)
+			providers[channel] = append(providers[channel], client)
+		case config.TypeSMTP:
+			providers[channel] = append(providers[channel], email.NewSMTPProvider(provider))
+		default:
+			providers[channel] = append(providers[channel], webhook.NewHTTPProvider(provider))
+		}
+	}
+
+	clients := make(webhook.ChannelClients, len(providers))
+	for channel, channelProviders := range providers {
+		clients[channel] = webhook.NewRetryableWebhookClient(channelProviders, settings.MaxRetries)
+	}
+	return clients, smppClients
+}
+
+// useSMPPClients records the SMPP clients in use and returns the ones they replace
+func (c *Container) useSMPPClients(clients []*smpp.Client) []*smpp.Client {
+	c.smppMu.Lock()
+	defer c.smppMu.Unlock()
+
+	previous := c.smppClients
+	c.smppClients = clients
+	return previous
+}
+
+// closeSMPPClients unbinds the sessions once the messages being sent through them are sent
+func closeSMPPClients(clients []*smpp.Client) {
+	for _, client := range clients {
+		client.Close()
+	}
+}
+
+// OpenDatabase opens the Postgres database configured in the database section
+func OpenDatabase(settings config.Database) (*sql.DB, error) {
+	db, err := sql.Open("postgres", settings.URL())
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	return db, nil
+}
@@ -0,0 +1,167 @@
+package email
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/ercancavusoglu/messaging/internal/config"
+	"github.com/ercancavusoglu/messaging/internal/domain"
+)
+
+const defaultTimeout = 10 * time.Second
+
+// SMTPProvider delivers email messages through the mail server described by a config.Provider. Every
+// message opens its own connection, so a reload or a restarted server needs no reconnect handling.
+type SMTPProvider struct {
+	spec config.Provider
+}
+
+// NewSMTPProvider fills in the defaults of the settings the spec leaves empty
+func NewSMTPProvider(spec config.Provider) *SMTPProvider {
+	if spec.Timeout == 0 {
+		spec.Timeout = defaultTimeout
+	}
+	if spec.SMTP.TLS == "" {
+		spec.SMTP.TLS = config.SMTPStartTLS
+	}
+
+	return &SMTPProvider{spec: spec}
+}
+
+func (p *SMTPProvider) Name() string {
+	return p.spec.Name
+}
+
+// SendMessage sends the content as a plain text email and returns its Message-ID, without the angle
+// brackets, as the provider message ID
+func (p *SMTPProvider) SendMessage(msg *domain.Message) (*domain.WebhookResponse, error) {
+	log.Printf("[Email] Sending message through %s [to: %s]", p.spec.Name, msg.To)
+
+	messageID, err := p.messageID()
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := p.dial()
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	if p.spec.SMTP.Username != "" {
+		host, _, _ := net.SplitHostPort(p.spec.SMTP.Addr)
+		if err := client.Auth(smtp.PlainAuth("", p.spec.SMTP.Username, p.spec.SMTP.Password, host)); err != nil {
+			return nil, fmt.Errorf("failed to authenticate: %v", err)
+		}
+	}
+
+	if err := client.Mail(p.spec.SMTP.From); err != nil {
+		return nil, fmt.Errorf("mail server rejected sender: %v", err)
+	}
+	if err := client.Rcpt(msg.To); err != nil {
+		return nil, fmt.Errorf("mail server rejected recipient: %v", err)
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return nil, fmt.Errorf("mail server rejected data: %v", err)
+	}
+	if _, err := writer.Write(p.compose(msg, messageID)); err != nil {
+		return nil, fmt.Errorf("failed to write message: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("mail server rejected message: %v", err)
+	}
+
+	if err := client.Quit(); err != nil {
+		log.Printf("[Email] Failed to close session with %s: %v", p.spec.Name, err)
+	}
+
+	return &domain.WebhookResponse{
+		MessageID: messageID,
+		Provider:  p.spec.Name,
+	}, nil
+}
+
+// dial connects within the provider timeout and secures the session as configured
+func (p *SMTPProvider) dial() (*smtp.Client, error) {
+	host, _, err := net.SplitHostPort(p.spec.SMTP.Addr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid smtp address: %v", err)
+	}
+	tlsConfig := &tls.Config{ServerName: host, InsecureSkipVerify: p.spec.InsecureSkipVerify}
+
+	conn, err := net.DialTimeout("tcp", p.spec.SMTP.Addr, p.spec.Timeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect: %v", err)
+	}
+	conn.SetDeadline(time.Now().Add(p.spec.Timeout))
+
+	if p.spec.SMTP.TLS == config.SMTPTLS {
+		conn = tls.Client(conn, tlsConfig)
+	}
+
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to start session: %v", err)
+	}
+
+	if p.spec.SMTP.TLS == config.SMTPStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			client.Close()
+			return nil, fmt.Errorf("mail server %s does not support STARTTLS", p.spec.SMTP.Addr)
+		}
+		if err := client.StartTLS(tlsConfig); err != nil {
+			client.Close()
+			return nil, fmt.Errorf("failed to start TLS: %v", err)
+		}
+	}
+
+	return client, nil
+}
+
+func (p *SMTPProvider) messageID() (string, error) {
+	random := make([]byte, 16)
+	if _, err := rand.Read(random); err != nil {
+		return "", fmt.Errorf("failed to generate message ID: %v", err)
+	}
+
+	domainPart := p.spec.SMTP.From[strings.LastIndexByte(p.spec.SMTP.From, '@')+1:]
+	return hex.EncodeToString(random) + "@" + domainPart, nil
+}
+
+// compose renders the headers and the quoted-printable UTF-8 body
+func (p *SMTPProvider) compose(msg *domain.Message, messageID string) []byte {
+	var out bytes.Buffer
+
+	header := func(name, value string) {
+		fmt.Fprintf(&out, "%s: %s\r\n", name, value)
+	}
+	header("From", p.spec.SMTP.From)
+	header("To", msg.To)
+	header("Subject", mime.QEncoding.Encode("utf-8", msg.Subject))
+	header("Date", time.Now().Format(time.RFC1123Z))
+	header("Message-ID", "<"+messageID+">")
+	header("MIME-Version", "1.0")
+	header("Content-Type", "text/plain; charset=utf-8")
+	header("Content-Transfer-Encoding", "quoted-printable")
+	out.WriteString("\r\n")
+
+	// The text mode of the writer turns line breaks into CRLF
+	body := quotedprintable.NewWriter(&out)
+	body.Write([]byte(msg.Content))
+	body.Close()
+
+	return out.Bytes()
+}
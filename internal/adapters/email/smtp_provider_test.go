@@ -0,0 +1,176 @@
+package email
+
+import (
+	"bufio"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ercancavusoglu/messaging/internal/config"
+	"github.com/ercancavusoglu/messaging/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// smtpSink is a local mail server that accepts every message and keeps the last one
+type smtpSink struct {
+	listener   net.Listener
+	rejectRcpt bool
+
+	mu   sync.Mutex
+	from string
+	to   []string
+	auth string
+	data string
+}
+
+func newSMTPSink(t *testing.T) *smtpSink {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	sink := &smtpSink{listener: listener}
+	go sink.serve()
+	t.Cleanup(func() { listener.Close() })
+
+	return sink
+}
+
+func (s *smtpSink) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.session(conn)
+	}
+}
+
+func (s *smtpSink) session(conn net.Conn) {
+	defer conn.Close()
+	text := textproto.NewConn(conn)
+	text.PrintfLine("220 sink ready")
+
+	for {
+		line, err := text.ReadLine()
+		if err != nil {
+			return
+		}
+		command := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+
+		s.mu.Lock()
+		switch command {
+		case "EHLO", "HELO":
+			text.PrintfLine("250-sink")
+			text.PrintfLine("250 AUTH PLAIN")
+		case "AUTH":
+			credentials, _ := base64.StdEncoding.DecodeString(strings.TrimPrefix(line, "AUTH PLAIN "))
+			s.auth = string(credentials)
+			text.PrintfLine("235 authenticated")
+		case "MAIL":
+			s.from = line
+			text.PrintfLine("250 ok")
+		case "RCPT":
+			if s.rejectRcpt {
+				text.PrintfLine("550 no such user")
+				break
+			}
+			s.to = append(s.to, line)
+			text.PrintfLine("250 ok")
+		case "DATA":
+			text.PrintfLine("354 go ahead")
+			data, _ := io.ReadAll(text.DotReader())
+			s.data = string(data)
+			text.PrintfLine("250 queued")
+		case "QUIT":
+			text.PrintfLine("221 bye")
+			s.mu.Unlock()
+			return
+		default:
+			text.PrintfLine("250 ok")
+		}
+		s.mu.Unlock()
+	}
+}
+
+func (s *smtpSink) provider() config.Provider {
+	return config.Provider{
+		Name: "mail",
+		Type: config.TypeSMTP,
+		SMTP: config.ProviderSMTP{
+			Addr: s.listener.Addr().String(),
+			From: "noreply@example.com",
+			TLS:  config.SMTPPlain,
+		},
+	}
+}
+
+func TestSMTPProvider_SendMessage(t *testing.T) {
+	sink := newSMTPSink(t)
+	spec := sink.provider()
+	spec.SMTP.Username = "mailer"
+	spec.SMTP.Password = "secret"
+
+	msg, err := domain.NewChannelMessage(domain.ChannelEmail, "jane@example.com", "Siparişiniz", "Merhaba Jane,\nsiparişiniz kargoda.", domain.DefaultMessageRules())
+	require.NoError(t, err)
+
+	response, err := NewSMTPProvider(spec).SendMessage(msg)
+	require.NoError(t, err)
+
+	assert.Equal(t, "mail", response.Provider)
+	assert.True(t, strings.HasSuffix(response.MessageID, "@example.com"))
+
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+	assert.Equal(t, "MAIL FROM:<noreply@example.com>", sink.from)
+	assert.Equal(t, []string{"RCPT TO:<jane@example.com>"}, sink.to)
+	assert.Equal(t, "\x00mailer\x00secret", sink.auth)
+
+	parsed, err := mail.ReadMessage(strings.NewReader(sink.data))
+	require.NoError(t, err)
+
+	subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	require.NoError(t, err)
+	assert.Equal(t, "Siparişiniz", subject)
+	assert.Equal(t, "<"+response.MessageID+">", parsed.Header.Get("Message-ID"))
+	assert.Equal(t, "jane@example.com", parsed.Header.Get("To"))
+
+	body, err := io.ReadAll(quotedprintable.NewReader(bufio.NewReader(parsed.Body)))
+	require.NoError(t, err)
+	// The sink reads lines without their CR
+	assert.Equal(t, "Merhaba Jane,\nsiparişiniz kargoda.", strings.TrimSpace(string(body)))
+}
+
+func TestSMTPProvider_RecipientRejected(t *testing.T) {
+	sink := newSMTPSink(t)
+	sink.rejectRcpt = true
+
+	msg, err := domain.NewChannelMessage(domain.ChannelEmail, "nobody@example.com", "", "Hi", domain.DefaultMessageRules())
+	require.NoError(t, err)
+
+	_, err = NewSMTPProvider(sink.provider()).SendMessage(msg)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "mail server rejected recipient: 550")
+}
+
+func TestSMTPProvider_RequiresSTARTTLS(t *testing.T) {
+	sink := newSMTPSink(t)
+	spec := sink.provider()
+	spec.SMTP.TLS = ""
+
+	msg, err := domain.NewChannelMessage(domain.ChannelEmail, "jane@example.com", "", "Hi", domain.DefaultMessageRules())
+	require.NoError(t, err)
+
+	// Varsayılan olarak şifresiz bir sunucuya gönderilmemeli
+	_, err = NewSMTPProvider(spec).SendMessage(msg)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "does not support STARTTLS")
+}
@@ -0,0 +1,244 @@
+package eventbus
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ercancavusoglu/messaging/internal/config"
+	"github.com/ercancavusoglu/messaging/internal/domain"
+	"github.com/ercancavusoglu/messaging/internal/ports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// conformanceTimeout leaves brokers time to join consumer groups
+const conformanceTimeout = 30 * time.Second
+
+type conformanceEvent struct {
+	Name     string    `json:"name"`
+	ID       string    `json:"id"`
+	Occurred time.Time `json:"occurred"`
+}
+
+func (e *conformanceEvent) EventName() string      { return e.Name }
+func (e *conformanceEvent) OccurredAt() time.Time  { return e.Occurred }
+func (e *conformanceEvent) GetAggregateID() string { return e.ID }
+
+type prioritizedConformanceEvent struct {
+	conformanceEvent
+	priority domain.Priority
+}
+
+func (e *prioritizedConformanceEvent) EventPriority() domain.Priority { return e.priority }
+
+type delayedConformanceEvent struct {
+	conformanceEvent
+	delay time.Duration
+}
+
+func (e *delayedConformanceEvent) EventDelay() time.Duration { return e.delay }
+
+// conformanceEventName is unique per run, so events left on a broker by earlier runs are not handled.
+// Event names have two words, see RabbitMQEventBus.
+func conformanceEventName() string {
+	return fmt.Sprintf("conformance.e%d", time.Now().UnixNano())
+}
+
+func newConformanceEvent(name string) *conformanceEvent {
+	return &conformanceEvent{Name: name, ID: "42", Occurred: time.Now().UTC().Truncate(time.Millisecond)}
+}
+
+func receiveWithin(t *testing.T, events <-chan *domain.EventEnvelope, timeout time.Duration) *domain.EventEnvelope {
+	t.Helper()
+
+	select {
+	case env := <-events:
+		return env
+	case <-time.After(timeout):
+		t.Fatal("Event timeout")
+		return nil
+	}
+}
+
+// collect subscribes a handler that forwards the events it handles
+func collect(bus Bus, eventName string) <-chan *domain.EventEnvelope {
+	events := make(chan *domain.EventEnvelope, 10)
+	bus.Subscribe(eventName, func(event ports.Event) error {
+		events <- event.(*domain.EventEnvelope)
+		return nil
+	})
+	return events
+}
+
+// runConformance checks the behaviour every Bus must share, so the application can run on any of them
+func runConformance(t *testing.T, newBus func(t *testing.T) Bus) {
+	t.Run("delivers the event envelope", func(t *testing.T) {
+		bus := newBus(t)
+		name := conformanceEventName()
+		events := collect(bus, name)
+
+		event := newConformanceEvent(name)
+		require.NoError(t, bus.Publish(event))
+
+		env := receiveWithin(t, events, conformanceTimeout)
+		assert.Equal(t, name, env.Name)
+		assert.Equal(t, "42", env.AggregateID)
+		assert.True(t, event.Occurred.Equal(env.OccurredOn))
+
+		data, _ := json.Marshal(event)
+		assert.JSONEq(t, string(data), string(env.Data))
+	})
+
+	t.Run("redelivers an event a handler rejected", func(t *testing.T) {
+		bus := newBus(t)
+		name := conformanceEventName()
+
+		var attempts int32
+		events := make(chan *domain.EventEnvelope, 10)
+		bus.Subscribe(name, func(event ports.Event) error {
+			if atomic.AddInt32(&attempts, 1) == 1 {
+				return errors.New("temporary failure")
+			}
+			events <- event.(*domain.EventEnvelope)
+			return nil
+		})
+
+		require.NoError(t, bus.Publish(newConformanceEvent(name)))
+
+		receiveWithin(t, events, conformanceTimeout)
+		assert.Equal(t, int32(2), atomic.LoadInt32(&attempts))
+	})
+
+	t.Run("acknowledges events nobody handles", func(t *testing.T) {
+		bus := newBus(t)
+		handled := conformanceEventName()
+		events := collect(bus, handled)
+
+		// İşleyicisi olmayan olay kuyruğu tıkamamalı
+		require.NoError(t, bus.Publish(newConformanceEvent(handled+"x")))
+		require.NoError(t, bus.Publish(newConformanceEvent(handled)))
+
+		assert.Equal(t, handled, receiveWithin(t, events, conformanceTimeout).Name)
+	})
+
+	t.Run("delivers prioritized events", func(t *testing.T) {
+		bus := newBus(t)
+		name := conformanceEventName()
+		events := collect(bus, name)
+
+		for _, priority := range domain.Priorities {
+			event := &prioritizedConformanceEvent{conformanceEvent: *newConformanceEvent(name), priority: priority}
+			event.ID = string(priority)
+			require.NoError(t, bus.Publish(event))
+		}
+
+		received := map[string]bool{}
+		for range domain.Priorities {
+			received[receiveWithin(t, events, conformanceTimeout).AggregateID] = true
+		}
+		assert.Equal(t, map[string]bool{"critical": true, "normal": true, "bulk": true}, received)
+	})
+
+	t.Run("holds delayed events back", func(t *testing.T) {
+		bus := newBus(t)
+		name := conformanceEventName()
+		events := collect(bus, name)
+
+		publishedAt := time.Now()
+		event := &delayedConformanceEvent{conformanceEvent: *newConformanceEvent(name), delay: 500 * time.Millisecond}
+		require.NoError(t, bus.Publish(event))
+
+		env := receiveWithin(t, events, conformanceTimeout)
+		assert.Equal(t, name, env.Name)
+		assert.GreaterOrEqual(t, time.Since(publishedAt), 500*time.Millisecond)
+	})
+
+	t.Run("stops delivering to unsubscribed handlers", func(t *testing.T) {
+		bus := newBus(t)
+		name := conformanceEventName()
+
+		var removedCalls int32
+		removed := func(event ports.Event) error {
+			atomic.AddInt32(&removedCalls, 1)
+			return nil
+		}
+		// Aynı fonksiyonun iki kopyasından yalnızca aboneliği kaldırılan susmalı
+		subscription := bus.Subscribe(name, removed)
+		events := collect(bus, name)
+		bus.Subscribe(name, removed)
+		subscription.Unsubscribe()
+
+		require.NoError(t, bus.Publish(newConformanceEvent(name)))
+
+		receiveWithin(t, events, conformanceTimeout)
+		assert.Eventually(t, func() bool { return atomic.LoadInt32(&removedCalls) == 1 }, conformanceTimeout, 10*time.Millisecond)
+	})
+
+	t.Run("hands observers a copy", func(t *testing.T) {
+		bus := newBus(t)
+		name := conformanceEventName()
+
+		observed := make(chan *domain.EventEnvelope, 10)
+		require.NoError(t, bus.Observe(name, func(event ports.Event) error {
+			observed <- event.(*domain.EventEnvelope)
+			// Gözlemci olayı reddedemez
+			return errors.New("ignored")
+		}))
+		events := collect(bus, name)
+
+		require.NoError(t, bus.Publish(&prioritizedConformanceEvent{conformanceEvent: *newConformanceEvent(name), priority: domain.PriorityBulk}))
+
+		assert.Equal(t, name, receiveWithin(t, observed, conformanceTimeout).Name)
+		assert.Equal(t, name, receiveWithin(t, events, conformanceTimeout).Name)
+	})
+}
+
+func TestMemoryEventBus_Conformance(t *testing.T) {
+	runConformance(t, func(t *testing.T) Bus {
+		bus := NewMemoryEventBus(100)
+		t.Cleanup(func() { bus.Close() })
+		return bus
+	})
+}
+
+func TestRabbitMQEventBus_Conformance(t *testing.T) {
+	url := os.Getenv("RABBITMQ_TEST_URL")
+	if url == "" {
+		t.Skip("RABBITMQ_TEST_URL is not set")
+	}
+
+	runConformance(t, func(t *testing.T) Bus {
+		bus, err := NewRabbitMQEventBus(config.RabbitMQ{URL: url, Prefetch: 16, DispatchWorkers: 4})
+		require.NoError(t, err)
+		t.Cleanup(func() { bus.Close() })
+		return bus
+	})
+}
+
+func TestKafkaEventBus_Conformance(t *testing.T) {
+	brokers := os.Getenv("KAFKA_TEST_BROKERS")
+	if brokers == "" {
+		t.Skip("KAFKA_TEST_BROKERS is not set")
+	}
+
+	runConformance(t, func(t *testing.T) Bus {
+		// Her test kendi konu ve grup adlarını kullanır
+		prefix := fmt.Sprintf("conformance-%d", time.Now().UnixNano())
+		bus, err := NewKafkaEventBus(config.Kafka{
+			Brokers:           strings.Split(brokers, ","),
+			GroupID:           prefix,
+			TopicPrefix:       prefix,
+			Partitions:        3,
+			ReplicationFactor: 1,
+		})
+		require.NoError(t, err)
+		t.Cleanup(func() { bus.Close() })
+		return bus
+	})
+}
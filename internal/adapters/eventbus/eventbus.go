@@ -0,0 +1,42 @@
+package eventbus
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ercancavusoglu/messaging/internal/domain"
+	"github.com/ercancavusoglu/messaging/internal/ports"
+)
+
+// Bus is an event bus the application can run on; see RabbitMQEventBus, KafkaEventBus and MemoryEventBus
+type Bus interface {
+	ports.EventBus
+	ports.EventObserver
+	Close() error
+}
+
+// newEnvelope wraps the event the way subscribers receive it, with the event itself as JSON data
+func newEnvelope(event ports.Event) (*domain.EventEnvelope, error) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal event: %v", err)
+	}
+
+	return &domain.EventEnvelope{
+		Name:        event.EventName(),
+		OccurredOn:  event.OccurredAt(),
+		AggregateID: event.GetAggregateID(),
+		Data:        data,
+	}, nil
+}
+
+// handle runs the handlers in order and stops at the first error, after which the event is redelivered
+// to all of them
+func handle(handlers []ports.EventHandler, env *domain.EventEnvelope) error {
+	for _, handler := range handlers {
+		if err := handler(env); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,675 @@
+package eventbus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ercancavusoglu/messaging/internal/config"
+	"github.com/ercancavusoglu/messaging/internal/domain"
+	"github.com/ercancavusoglu/messaging/internal/ports"
+
+	"github.com/segmentio/kafka-go"
+)
+
+const (
+	// kafkaRetryDelay spaces out the redeliveries of an event a handler keeps rejecting, and the retries
+	// after a failed fetch or relay
+	kafkaRetryDelay = time.Second
+	// kafkaDelayRefresh is how often a consuming process looks for delay topics created by other processes
+	kafkaDelayRefresh = 30 * time.Second
+	kafkaTimeout      = 10 * time.Second
+
+	// headerEvent names the event of every message; the delay headers name the topic a delayed event is
+	// relayed to and when, in Unix milliseconds
+	headerEvent       = "event"
+	headerDelayTopic  = "delay-topic"
+	headerDelayUntil  = "delay-until"
+	kafkaDelaySegment = "delay"
+
+	// The dead letter headers record where a rejected event was read from and the last handler error
+	headerDeadTopic     = "dead-topic"
+	headerDeadPartition = "dead-partition"
+	headerDeadOffset    = "dead-offset"
+	headerDeadError     = "dead-error"
+	kafkaDeadSegment    = "dead"
+)
+
+// KafkaEventBus delivers events through Kafka topics the same way RabbitMQEventBus delivers them through
+// queues: prioritized events (see ports.PrioritizedEvent) go to a topic per priority class and every other
+// event to the shared topic, each consumed by a reader of its own. Messages are keyed by the event's
+// aggregate ID, so the events of one message keep their order within a topic.
+//
+// The workers share a consumer group. An event's offset is only committed once all its handlers succeeded;
+// a failing handler gets the event again after kafkaRetryDelay, holding back the partition behind it, up to
+// settings.MaxAttempts times. The event is then moved to the dead letter topic and committed, so one broken
+// event cannot stall its partition for good. An event nobody handles is committed. Events are delivered at least once: after a crash or a rebalance,
+// handled events whose offset was not committed yet are delivered again.
+//
+// Delayed events (see ports.DelayedEvent) wait in a topic per delay. A relay of the consuming processes,
+// in a consumer group of its own, moves each one to its topic once its delay has passed, so like with
+// RabbitMQ a delayed event survives a restart.
+//
+// Nothing is consumed until a handler is subscribed. Observers (see Observe) read the partitions without a
+// consumer group, so they never take events from the workers and leave no group behind.
+type KafkaEventBus struct {
+	settings config.Kafka
+	writer   *kafka.Writer
+
+	handlers    *registry
+	observers   *registry
+	consumeOnce sync.Once
+	mu          sync.RWMutex
+	consuming   bool
+
+	topicsMu    sync.Mutex
+	delayTopics map[string]bool
+	relays      map[string]bool
+
+	observeMu sync.Mutex
+	observing bool
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewKafkaEventBus(settings config.Kafka) (*KafkaEventBus, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	bus := &KafkaEventBus{
+		settings: settings,
+		writer: &kafka.Writer{
+			Addr:         kafka.TCP(settings.Brokers...),
+			Balancer:     &kafka.Hash{},
+			RequiredAcks: kafka.RequireAll,
+			// Publish waits for its event to be written, so there is no batch worth waiting for
+			BatchTimeout: 10 * time.Millisecond,
+			WriteTimeout: kafkaTimeout,
+		},
+		handlers:    newRegistry(),
+		observers:   newRegistry(),
+		delayTopics: make(map[string]bool),
+		relays:      make(map[string]bool),
+		ctx:         ctx,
+		cancel:      cancel,
+	}
+
+	if err := bus.createTopics(append(bus.eventTopics(), bus.deadTopic())...); err != nil {
+		cancel()
+		return nil, err
+	}
+
+	return bus, nil
+}
+
+// eventTopics are the shared topic followed by the topic of every priority class
+func (b *KafkaEventBus) eventTopics() []string {
+	topics := []string{b.sharedTopic()}
+	for _, priority := range domain.Priorities {
+		topics = append(topics, b.priorityTopic(priority))
+	}
+	return topics
+}
+
+func (b *KafkaEventBus) sharedTopic() string {
+	return b.settings.TopicPrefix + ".events"
+}
+
+func (b *KafkaEventBus) priorityTopic(priority domain.Priority) string {
+	return b.sharedTopic() + "." + string(priority)
+}
+
+// delayTopicName gives every delay a topic of its own: the relay waits for the event at the head of a
+// partition, so a shorter delay must never wait behind a longer one
+func (b *KafkaEventBus) delayTopicName(delay time.Duration) string {
+	return fmt.Sprintf("%s.%s.%d", b.sharedTopic(), kafkaDelaySegment, delay.Milliseconds())
+}
+
+// deadTopic receives the events the handlers kept rejecting; nothing consumes it
+func (b *KafkaEventBus) deadTopic() string {
+	return b.sharedTopic() + "." + kafkaDeadSegment
+}
+
+func (b *KafkaEventBus) isDelayTopic(topic string) bool {
+	return strings.HasPrefix(topic, b.sharedTopic()+"."+kafkaDelaySegment+".")
+}
+
+func (b *KafkaEventBus) topicFor(event ports.Event) string {
+	if prioritized, ok := event.(ports.PrioritizedEvent); ok {
+		for _, priority := range domain.Priorities {
+			if prioritized.EventPriority() == priority {
+				return b.priorityTopic(priority)
+			}
+		}
+	}
+	return b.sharedTopic()
+}
+
+// kafkaMessage keys the message by the aggregate ID; events without one are spread over the partitions
+func kafkaMessage(topic string, env *domain.EventEnvelope) (kafka.Message, error) {
+	body, err := json.Marshal(env)
+	if err != nil {
+		return kafka.Message{}, fmt.Errorf("failed to marshal envelope: %v", err)
+	}
+
+	msg := kafka.Message{
+		Topic:   topic,
+		Value:   body,
+		Headers: []kafka.Header{{Key: headerEvent, Value: []byte(env.Name)}},
+	}
+	if env.AggregateID != "" {
+		msg.Key = []byte(env.AggregateID)
+	}
+	return msg, nil
+}
+
+// delayMessage sends the message through the delay topic, with the topic it is relayed to once due
+func delayMessage(msg kafka.Message, delayTopic string, until time.Time) kafka.Message {
+	headers := append([]kafka.Header{}, msg.Headers...)
+	headers = append(headers,
+		kafka.Header{Key: headerDelayTopic, Value: []byte(msg.Topic)},
+		kafka.Header{Key: headerDelayUntil, Value: []byte(strconv.FormatInt(until.UnixMilli(), 10))},
+	)
+
+	msg.Topic = delayTopic
+	msg.Headers = headers
+	return msg
+}
+
+// relayedMessage is the delayed message as it is sent to its topic, with when that is due
+func relayedMessage(msg kafka.Message) (kafka.Message, time.Time, error) {
+	var topic string
+	var until time.Time
+	headers := make([]kafka.Header, 0, len(msg.Headers))
+
+	for _, header := range msg.Headers {
+		switch header.Key {
+		case headerDelayTopic:
+			topic = string(header.Value)
+		case headerDelayUntil:
+			millis, err := strconv.ParseInt(string(header.Value), 10, 64)
+			if err != nil {
+				return kafka.Message{}, time.Time{}, fmt.Errorf("invalid %s header: %q", headerDelayUntil, header.Value)
+			}
+			until = time.UnixMilli(millis)
+		default:
+			headers = append(headers, header)
+		}
+	}
+
+	if topic == "" || until.IsZero() {
+		return kafka.Message{}, time.Time{}, fmt.Errorf("missing %s or %s header", headerDelayTopic, headerDelayUntil)
+	}
+
+	return kafka.Message{Topic: topic, Key: msg.Key, Value: msg.Value, Headers: headers}, until, nil
+}
+
+// deadLetterMessage copies a rejected message to the dead letter topic, recording where it was read from
+// and why it was rejected
+func deadLetterMessage(msg kafka.Message, deadTopic string, reason error) kafka.Message {
+	headers := append([]kafka.Header{}, msg.Headers...)
+	headers = append(headers,
+		kafka.Header{Key: headerDeadTopic, Value: []byte(msg.Topic)},
+		kafka.Header{Key: headerDeadPartition, Value: []byte(strconv.Itoa(msg.Partition))},
+		kafka.Header{Key: headerDeadOffset, Value: []byte(strconv.FormatInt(msg.Offset, 10))},
+		kafka.Header{Key: headerDeadError, Value: []byte(reason.Error())},
+	)
+
+	return kafka.Message{Topic: deadTopic, Key: msg.Key, Value: msg.Value, Headers: headers}
+}
+
+func (b *KafkaEventBus) Publish(event ports.Event) error {
+	env, err := newEnvelope(event)
+	if err != nil {
+		return err
+	}
+
+	msg, err := kafkaMessage(b.topicFor(event), env)
+	if err != nil {
+		return err
+	}
+
+	if delayed, ok := event.(ports.DelayedEvent); ok && delayed.EventDelay() > 0 {
+		delayTopic, err := b.delayTopic(delayed.EventDelay())
+		if err != nil {
+			return err
+		}
+		msg = delayMessage(msg, delayTopic, time.Now().Add(delayed.EventDelay()))
+	}
+
+	if err := b.write(msg); err != nil {
+		return fmt.Errorf("failed to publish event: %v", err)
+	}
+
+	return nil
+}
+
+func (b *KafkaEventBus) write(msg kafka.Message) error {
+	ctx, cancel := context.WithTimeout(context.Background(), kafkaTimeout)
+	defer cancel()
+
+	return b.writer.WriteMessages(ctx, msg)
+}
+
+// delayTopic creates the delay topic the first time this process uses it, and relays it if the process
+// consumes
+func (b *KafkaEventBus) delayTopic(delay time.Duration) (string, error) {
+	topic := b.delayTopicName(delay)
+
+	b.topicsMu.Lock()
+	known := b.delayTopics[topic]
+	b.topicsMu.Unlock()
+
+	if !known {
+		if err := b.createTopics(topic); err != nil {
+			return "", err
+		}
+
+		b.topicsMu.Lock()
+		b.delayTopics[topic] = true
+		b.topicsMu.Unlock()
+	}
+
+	b.mu.RLock()
+	consuming := b.consuming
+	b.mu.RUnlock()
+	if consuming {
+		b.startRelay(topic)
+	}
+
+	return topic, nil
+}
+
+// dial connects to the first broker that answers
+func (b *KafkaEventBus) dial() (*kafka.Conn, error) {
+	var lastErr error
+	for _, broker := range b.settings.Brokers {
+		ctx, cancel := context.WithTimeout(b.ctx, kafkaTimeout)
+		conn, err := kafka.DialContext(ctx, "tcp", broker)
+		cancel()
+		if err == nil {
+			return conn, nil
+		}
+		lastErr = err
+	}
+	return nil, fmt.Errorf("failed to connect to Kafka: %v", lastErr)
+}
+
+// createTopics creates the topics that do not exist yet; only the controller broker creates topics
+func (b *KafkaEventBus) createTopics(topics ...string) error {
+	conn, err := b.dial()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	controller, err := conn.Controller()
+	if err != nil {
+		return fmt.Errorf("failed to find Kafka controller: %v", err)
+	}
+
+	controllerConn, err := kafka.Dial("tcp", net.JoinHostPort(controller.Host, strconv.Itoa(controller.Port)))
+	if err != nil {
+		return fmt.Errorf("failed to connect to Kafka controller: %v", err)
+	}
+	defer controllerConn.Close()
+
+	configs := make([]kafka.TopicConfig, 0, len(topics))
+	for _, topic := range topics {
+		configs = append(configs, kafka.TopicConfig{
+			Topic:             topic,
+			NumPartitions:     b.settings.Partitions,
+			ReplicationFactor: b.settings.ReplicationFactor,
+		})
+	}
+
+	if err := controllerConn.CreateTopics(configs...); err != nil {
+		return fmt.Errorf("failed to create topics: %v", err)
+	}
+	return nil
+}
+
+func (b *KafkaEventBus) Subscribe(eventName string, handler ports.EventHandler) ports.Subscription {
+	subscription := b.handlers.add(eventName, handler)
+	b.consumeOnce.Do(b.startConsumers)
+	return subscription
+}
+
+func (b *KafkaEventBus) startConsumers() {
+	b.mu.Lock()
+	b.consuming = true
+	b.mu.Unlock()
+
+	for _, topic := range b.eventTopics() {
+		b.wg.Add(1)
+		go b.consume(topic)
+	}
+
+	b.wg.Add(1)
+	go b.discoverDelayTopics()
+}
+
+func (b *KafkaEventBus) consume(topic string) {
+	defer b.wg.Done()
+
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:     b.settings.Brokers,
+		GroupID:     b.settings.GroupID,
+		Topic:       topic,
+		StartOffset: kafka.FirstOffset,
+		MaxWait:     time.Second,
+	})
+	defer reader.Close()
+
+	for {
+		msg, err := reader.FetchMessage(b.ctx)
+		if err != nil {
+			if b.ctx.Err() != nil {
+				return
+			}
+			fmt.Printf("[Kafka] Failed to fetch from %s: %v\n", topic, err)
+			if !b.wait(kafkaRetryDelay) {
+				return
+			}
+			continue
+		}
+
+		// Closing while a handler keeps failing leaves the event to be delivered again
+		if !b.process(msg) {
+			return
+		}
+
+		if err := b.commit(reader, msg); err != nil {
+			fmt.Printf("[Kafka] Failed to commit %s/%d@%d: %v\n", msg.Topic, msg.Partition, msg.Offset, err)
+		}
+	}
+}
+
+// process runs the handlers until they all succeed or settings.MaxAttempts runs out, after which the event
+// goes to the dead letter topic, and reports false if the bus was closed first. A message that is no event
+// envelope is skipped, since it would never succeed.
+func (b *KafkaEventBus) process(msg kafka.Message) bool {
+	var env domain.EventEnvelope
+	if err := json.Unmarshal(msg.Value, &env); err != nil {
+		fmt.Printf("[Kafka] Skipping %s/%d@%d, not an event envelope: %v\n", msg.Topic, msg.Partition, msg.Offset, err)
+		return true
+	}
+
+	for attempt := 1; ; attempt++ {
+		handlers := b.handlers.handlers(env.Name)
+		if len(handlers) == 0 {
+			return true
+		}
+
+		err := handle(handlers, &env)
+		if err == nil {
+			return true
+		}
+
+		fmt.Printf("[Kafka] Handler failed for event %s (attempt %d of %d): %v\n", env.Name, attempt, b.settings.MaxAttempts, err)
+		if attempt >= b.settings.MaxAttempts {
+			// Until the dead letter is written the event keeps its place, so it is never lost
+			if deadErr := b.write(deadLetterMessage(msg, b.deadTopic(), err)); deadErr != nil {
+				fmt.Printf("[Kafka] Failed to dead letter %s/%d@%d: %v\n", msg.Topic, msg.Partition, msg.Offset, deadErr)
+			} else {
+				fmt.Printf("[Kafka] Moved event %s at %s/%d@%d to %s\n", env.Name, msg.Topic, msg.Partition, msg.Offset, b.deadTopic())
+				return true
+			}
+		}
+
+		if !b.wait(kafkaRetryDelay) {
+			return false
+		}
+	}
+}
+
+// commit outlives Close, so an event handled while closing is not delivered again
+func (b *KafkaEventBus) commit(reader *kafka.Reader, msg kafka.Message) error {
+	ctx, cancel := context.WithTimeout(context.Background(), kafkaTimeout)
+	defer cancel()
+
+	return reader.CommitMessages(ctx, msg)
+}
+
+// wait reports false if the bus was closed before the duration passed
+func (b *KafkaEventBus) wait(duration time.Duration) bool {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-b.ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// discoverDelayTopics relays the delay topics that exist, including the ones other processes create later
+func (b *KafkaEventBus) discoverDelayTopics() {
+	defer b.wg.Done()
+
+	for {
+		topics, err := b.listDelayTopics()
+		if err != nil {
+			fmt.Printf("[Kafka] Failed to list delay topics: %v\n", err)
+		}
+		for _, topic := range topics {
+			b.startRelay(topic)
+		}
+
+		if !b.wait(kafkaDelayRefresh) {
+			return
+		}
+	}
+}
+
+func (b *KafkaEventBus) listDelayTopics() ([]string, error) {
+	conn, err := b.dial()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	partitions, err := conn.ReadPartitions()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read partitions: %v", err)
+	}
+
+	seen := make(map[string]bool)
+	var topics []string
+	for _, partition := range partitions {
+		if b.isDelayTopic(partition.Topic) && !seen[partition.Topic] {
+			seen[partition.Topic] = true
+			topics = append(topics, partition.Topic)
+		}
+	}
+	return topics, nil
+}
+
+func (b *KafkaEventBus) startRelay(topic string) {
+	b.topicsMu.Lock()
+	defer b.topicsMu.Unlock()
+
+	if b.relays[topic] || b.ctx.Err() != nil {
+		return
+	}
+	b.relays[topic] = true
+
+	b.wg.Add(1)
+	go b.relay(topic)
+}
+
+// relay moves the delay topic's events to their topic once they are due. The offset is only committed
+// once the event was written, so a relay that stops early relays it again after a restart.
+func (b *KafkaEventBus) relay(topic string) {
+	defer b.wg.Done()
+
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:     b.settings.Brokers,
+		GroupID:     b.settings.GroupID + "." + kafkaDelaySegment,
+		Topic:       topic,
+		StartOffset: kafka.FirstOffset,
+		MaxWait:     time.Second,
+	})
+	defer reader.Close()
+
+	for {
+		msg, err := reader.FetchMessage(b.ctx)
+		if err != nil {
+			if b.ctx.Err() != nil {
+				return
+			}
+			fmt.Printf("[Kafka] Failed to fetch from %s: %v\n", topic, err)
+			if !b.wait(kafkaRetryDelay) {
+				return
+			}
+			continue
+		}
+
+		relayed, until, err := relayedMessage(msg)
+		if err != nil {
+			fmt.Printf("[Kafka] Skipping %s/%d@%d: %v\n", msg.Topic, msg.Partition, msg.Offset, err)
+		} else {
+			if !b.wait(time.Until(until)) {
+				return
+			}
+			for {
+				err := b.write(relayed)
+				if err == nil {
+					break
+				}
+				fmt.Printf("[Kafka] Failed to relay delayed event to %s: %v\n", relayed.Topic, err)
+				if !b.wait(kafkaRetryDelay) {
+					return
+				}
+			}
+		}
+
+		if err := b.commit(reader, msg); err != nil {
+			fmt.Printf("[Kafka] Failed to commit %s/%d@%d: %v\n", msg.Topic, msg.Partition, msg.Offset, err)
+		}
+	}
+}
+
+// Observe reads every partition of the event topics from its current end, without a consumer group.
+// Handler errors are only logged, and events published while the process is down are missed.
+func (b *KafkaEventBus) Observe(eventName string, handler ports.EventHandler) error {
+	b.observeMu.Lock()
+	defer b.observeMu.Unlock()
+
+	if !b.observing {
+		if err := b.startObserving(); err != nil {
+			return err
+		}
+		b.observing = true
+	}
+
+	b.observers.add(eventName, handler)
+	return nil
+}
+
+// startObserving starts each reader at the partition's last offset as of now, so an event published
+// once Observe returned is never missed
+func (b *KafkaEventBus) startObserving() error {
+	conn, err := b.dial()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	partitions, err := conn.ReadPartitions(b.eventTopics()...)
+	if err != nil {
+		return fmt.Errorf("failed to read partitions: %v", err)
+	}
+
+	readers := make([]*kafka.Reader, 0, len(partitions))
+	for _, partition := range partitions {
+		offset, err := b.lastOffset(partition)
+		if err != nil {
+			for _, reader := range readers {
+				reader.Close()
+			}
+			return err
+		}
+
+		reader := kafka.NewReader(kafka.ReaderConfig{
+			Brokers:   b.settings.Brokers,
+			Topic:     partition.Topic,
+			Partition: partition.ID,
+			MaxWait:   time.Second,
+		})
+		reader.SetOffset(offset)
+		readers = append(readers, reader)
+	}
+
+	for _, reader := range readers {
+		b.wg.Add(1)
+		go b.dispatchObserved(reader)
+	}
+	return nil
+}
+
+func (b *KafkaEventBus) lastOffset(partition kafka.Partition) (int64, error) {
+	ctx, cancel := context.WithTimeout(b.ctx, kafkaTimeout)
+	defer cancel()
+
+	leader := net.JoinHostPort(partition.Leader.Host, strconv.Itoa(partition.Leader.Port))
+	conn, err := kafka.DialLeader(ctx, "tcp", leader, partition.Topic, partition.ID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to connect to the leader of %s/%d: %v", partition.Topic, partition.ID, err)
+	}
+	defer conn.Close()
+
+	offset, err := conn.ReadLastOffset()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read the last offset of %s/%d: %v", partition.Topic, partition.ID, err)
+	}
+	return offset, nil
+}
+
+func (b *KafkaEventBus) dispatchObserved(reader *kafka.Reader) {
+	defer b.wg.Done()
+	defer reader.Close()
+
+	for {
+		msg, err := reader.ReadMessage(b.ctx)
+		if err != nil {
+			if b.ctx.Err() != nil {
+				return
+			}
+			fmt.Printf("[Kafka] Failed to read observed events: %v\n", err)
+			if !b.wait(kafkaRetryDelay) {
+				return
+			}
+			continue
+		}
+
+		var env domain.EventEnvelope
+		if err := json.Unmarshal(msg.Value, &env); err != nil {
+			fmt.Printf("[Kafka] Failed to unmarshal observed event: %v\n", err)
+			continue
+		}
+
+		for _, observer := range b.observers.handlers(env.Name) {
+			if err := observer(&env); err != nil {
+				fmt.Printf("[Kafka] Observer failed for event %s: %v\n", env.Name, err)
+			}
+		}
+	}
+}
+
+// Close stops consuming, waiting for the handlers in progress, and flushes the writer
+func (b *KafkaEventBus) Close() error {
+	b.cancel()
+	b.wg.Wait()
+
+	if err := b.writer.Close(); err != nil {
+		return fmt.Errorf("failed to close writer: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package eventbus
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ercancavusoglu/messaging/internal/config"
+	"github.com/ercancavusoglu/messaging/internal/domain"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newUnconnectedKafkaBus returns a bus without brokers, enough for naming and encoding
+func newUnconnectedKafkaBus() *KafkaEventBus {
+	return &KafkaEventBus{settings: config.Kafka{TopicPrefix: "messaging", GroupID: "messaging"}}
+}
+
+func TestKafkaEventBus_Topics(t *testing.T) {
+	bus := newUnconnectedKafkaBus()
+
+	assert.Equal(t, []string{"messaging.events", "messaging.events.critical", "messaging.events.normal", "messaging.events.bulk"}, bus.eventTopics())
+
+	bulk := domain.NewMessageQueuedEvent(&domain.Message{ID: 1, Priority: domain.PriorityBulk})
+	assert.Equal(t, "messaging.events.bulk", bus.topicFor(bulk))
+
+	// Önceliği olmayan eski mesajlar normal konuya gitmeli
+	legacy := domain.NewMessageQueuedEvent(&domain.Message{ID: 2})
+	assert.Equal(t, "messaging.events.normal", bus.topicFor(legacy))
+
+	sent := domain.NewMessageSentEvent(&domain.Message{ID: 3, Priority: domain.PriorityCritical}, "msg_123", "client_one")
+	assert.Equal(t, "messaging.events", bus.topicFor(&sent))
+
+	delayTopic := bus.delayTopicName(30 * time.Second)
+	assert.Equal(t, "messaging.events.delay.30000", delayTopic)
+	assert.True(t, bus.isDelayTopic(delayTopic))
+	assert.False(t, bus.isDelayTopic("messaging.events.bulk"))
+
+	// Ölü mektup konusu gecikme konusu sayılmamalı
+	assert.Equal(t, "messaging.events.dead", bus.deadTopic())
+	assert.False(t, bus.isDelayTopic(bus.deadTopic()))
+}
+
+func TestKafkaMessage(t *testing.T) {
+	sent := domain.NewMessageSentEvent(&domain.Message{ID: 3}, "msg_123", "client_one")
+	env, err := newEnvelope(&sent)
+	require.NoError(t, err)
+
+	msg, err := kafkaMessage("messaging.events", env)
+	require.NoError(t, err)
+
+	// Aynı mesajın olayları aynı bölüme düşmeli
+	assert.Equal(t, []byte("3"), msg.Key)
+	assert.Equal(t, "messaging.events", msg.Topic)
+	assert.Equal(t, []kafka.Header{{Key: headerEvent, Value: []byte(domain.EventMessageSent)}}, msg.Headers)
+
+	var decoded domain.EventEnvelope
+	require.NoError(t, json.Unmarshal(msg.Value, &decoded))
+	assert.Equal(t, domain.EventMessageSent, decoded.Name)
+	assert.JSONEq(t, string(env.Data), string(decoded.Data))
+
+	// Kimliği olmayan olaylar bölümlere dağıtılır
+	env.AggregateID = ""
+	msg, err = kafkaMessage("messaging.events", env)
+	require.NoError(t, err)
+	assert.Nil(t, msg.Key)
+}
+
+func TestDelayMessage(t *testing.T) {
+	msg := kafka.Message{
+		Topic:   "messaging.events",
+		Key:     []byte("7"),
+		Value:   []byte(`{"name":"message.fallback"}`),
+		Headers: []kafka.Header{{Key: headerEvent, Value: []byte(domain.EventMessageFallback)}},
+	}
+	until := time.UnixMilli(1700000000000)
+
+	delayed := delayMessage(msg, "messaging.events.delay.30000", until)
+	assert.Equal(t, "messaging.events.delay.30000", delayed.Topic)
+	assert.Len(t, msg.Headers, 1)
+
+	relayed, due, err := relayedMessage(delayed)
+	require.NoError(t, err)
+	assert.Equal(t, msg, relayed)
+	assert.True(t, until.Equal(due))
+
+	_, _, err = relayedMessage(msg)
+	assert.Error(t, err)
+}
+
+func TestDeadLetterMessage(t *testing.T) {
+	msg := kafka.Message{
+		Topic:     "messaging.events.normal",
+		Partition: 2,
+		Offset:    41,
+		Key:       []byte("7"),
+		Value:     []byte(`{"name":"message.queued"}`),
+		Headers:   []kafka.Header{{Key: headerEvent, Value: []byte(domain.EventMessageQueued)}},
+	}
+
+	dead := deadLetterMessage(msg, "messaging.events.dead", errors.New("provider down"))
+
+	assert.Equal(t, "messaging.events.dead", dead.Topic)
+	assert.Equal(t, msg.Key, dead.Key)
+	assert.Equal(t, msg.Value, dead.Value)
+	assert.Equal(t, []kafka.Header{
+		{Key: headerEvent, Value: []byte(domain.EventMessageQueued)},
+		{Key: headerDeadTopic, Value: []byte("messaging.events.normal")},
+		{Key: headerDeadPartition, Value: []byte("2")},
+		{Key: headerDeadOffset, Value: []byte("41")},
+		{Key: headerDeadError, Value: []byte("provider down")},
+	}, dead.Headers)
+	// Orijinal mesajın başlıkları değişmemeli
+	assert.Len(t, msg.Headers, 1)
+}
@@ -0,0 +1,395 @@
+package eventbus
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ercancavusoglu/messaging/internal/domain"
+	"github.com/ercancavusoglu/messaging/internal/ports"
+)
+
+const (
+	// memoryRedeliveryDelay spaces out the redeliveries of an event a handler keeps rejecting
+	memoryRedeliveryDelay = 100 * time.Millisecond
+	// memoryDrainTimeout bounds how long Close waits for the queued events to be handled
+	memoryDrainTimeout = 10 * time.Second
+	// memoryDrainPoll is how often Close checks whether the queues are drained
+	memoryDrainPoll = 10 * time.Millisecond
+)
+
+var (
+	ErrEventBufferFull = errors.New("event buffer is full")
+	ErrEventBusClosed  = errors.New("event bus is closed")
+)
+
+// MemoryEventBus delivers events inside the process, for tests and for running the API and the workers
+// as one process without a broker. It keeps the semantics of RabbitMQEventBus:
+//
+//   - prioritized events wait in a queue per priority class and every other event in the shared queue,
+//     each consumed by a goroutine of its own once a handler is subscribed
+//   - an event is acknowledged once all its handlers succeeded, redelivered when one fails and dropped
+//     when nobody handles it
+//   - delayed events reach their queue once their delay has passed
+//   - observers get a copy of every event and cannot reject it
+//
+// Publishing is asynchronous; it fails with ErrEventBufferFull when the event's queue already holds
+// bufferSize events. Unlike a broker the bus is not durable: delayed events that are not due when the bus
+// is closed are lost, and so is everything still queued if the process stops without closing it.
+type MemoryEventBus struct {
+	handlers    *registry
+	observers   *registry
+	consumeOnce sync.Once
+	observeOnce sync.Once
+	// consuming and observing are set once the goroutines draining the queues were started
+	mu        sync.RWMutex
+	consuming bool
+	observing bool
+
+	shared     *memoryQueue
+	priorities map[domain.Priority]*memoryQueue
+	observed   *memoryQueue
+	consumers  sync.WaitGroup
+
+	timersMu sync.Mutex
+	timers   map[*time.Timer]struct{}
+	closed   bool
+}
+
+func NewMemoryEventBus(bufferSize int) *MemoryEventBus {
+	bus := &MemoryEventBus{
+		handlers:   newRegistry(),
+		observers:  newRegistry(),
+		shared:     newMemoryQueue(bufferSize),
+		priorities: make(map[domain.Priority]*memoryQueue),
+		observed:   newMemoryQueue(bufferSize),
+		timers:     make(map[*time.Timer]struct{}),
+	}
+	for _, priority := range domain.Priorities {
+		bus.priorities[priority] = newMemoryQueue(bufferSize)
+	}
+	return bus
+}
+
+func (b *MemoryEventBus) Publish(event ports.Event) error {
+	env, err := newEnvelope(event)
+	if err != nil {
+		return err
+	}
+
+	queue := b.queueFor(event)
+
+	if delayed, ok := event.(ports.DelayedEvent); ok && delayed.EventDelay() > 0 {
+		return b.publishLater(queue, env, delayed.EventDelay())
+	}
+
+	if err := queue.push(env, false); err != nil {
+		return fmt.Errorf("failed to publish event %s: %w", env.Name, err)
+	}
+	b.observe(env)
+
+	return nil
+}
+
+func (b *MemoryEventBus) queueFor(event ports.Event) *memoryQueue {
+	if prioritized, ok := event.(ports.PrioritizedEvent); ok {
+		if queue, ok := b.priorities[prioritized.EventPriority()]; ok {
+			return queue
+		}
+	}
+	return b.shared
+}
+
+// publishLater queues the event once its delay has passed. It was accepted when published, so it is
+// queued even if the queue has filled up in the meantime.
+func (b *MemoryEventBus) publishLater(queue *memoryQueue, env *domain.EventEnvelope, delay time.Duration) error {
+	b.timersMu.Lock()
+	defer b.timersMu.Unlock()
+
+	if b.closed {
+		return fmt.Errorf("failed to publish event %s: %w", env.Name, ErrEventBusClosed)
+	}
+
+	var timer *time.Timer
+	timer = time.AfterFunc(delay, func() {
+		b.timersMu.Lock()
+		delete(b.timers, timer)
+		b.timersMu.Unlock()
+
+		if err := queue.push(env, true); err != nil {
+			fmt.Printf("[MemoryBus] Dropping delayed event %s: %v\n", env.Name, err)
+			return
+		}
+		b.observe(env)
+	})
+	b.timers[timer] = struct{}{}
+
+	return nil
+}
+
+// observe copies the event to the observers; a copy that does not fit in the buffer is dropped
+func (b *MemoryEventBus) observe(env *domain.EventEnvelope) {
+	if !b.observers.has(env.Name) {
+		return
+	}
+	if err := b.observed.push(env, false); err != nil {
+		fmt.Printf("[MemoryBus] Dropping observed event %s: %v\n", env.Name, err)
+	}
+}
+
+func (b *MemoryEventBus) Subscribe(eventName string, handler ports.EventHandler) ports.Subscription {
+	subscription := b.handlers.add(eventName, handler)
+	b.consumeOnce.Do(b.startConsumers)
+	return subscription
+}
+
+// Observe only sees events published after it was called
+func (b *MemoryEventBus) Observe(eventName string, handler ports.EventHandler) error {
+	b.observers.add(eventName, handler)
+	b.observeOnce.Do(func() {
+		b.mu.Lock()
+		b.observing = true
+		b.mu.Unlock()
+
+		b.consumers.Add(1)
+		go b.consumeObserved()
+	})
+
+	return nil
+}
+
+func (b *MemoryEventBus) startConsumers() {
+	b.mu.Lock()
+	b.consuming = true
+	b.mu.Unlock()
+
+	queues := []*memoryQueue{b.shared}
+	for _, priority := range domain.Priorities {
+		queues = append(queues, b.priorities[priority])
+	}
+
+	for _, queue := range queues {
+		b.consumers.Add(1)
+		go b.consume(queue)
+	}
+}
+
+func (b *MemoryEventBus) consume(queue *memoryQueue) {
+	defer b.consumers.Done()
+
+	for {
+		env, ok := queue.pop()
+		if !ok {
+			return
+		}
+
+		if err := handle(b.handlers.handlers(env.Name), env); err != nil {
+			fmt.Printf("[MemoryBus] Handler failed for event %s: %v\n", env.Name, err)
+			queue.requeue(env)
+			time.Sleep(memoryRedeliveryDelay)
+			continue
+		}
+		queue.done()
+	}
+}
+
+func (b *MemoryEventBus) consumeObserved() {
+	defer b.consumers.Done()
+
+	for {
+		env, ok := b.observed.pop()
+		if !ok {
+			return
+		}
+
+		for _, observer := range b.observers.handlers(env.Name) {
+			if err := observer(env); err != nil {
+				fmt.Printf("[MemoryBus] Observer failed for event %s: %v\n", env.Name, err)
+			}
+		}
+		b.observed.done()
+	}
+}
+
+// Close waits, for at most memoryDrainTimeout, until every queued event has been handled, including the
+// events handlers publish meanwhile, and then stops accepting events. Stop the other publishers first, or
+// the bus may not drain in time. Delayed events that are not due yet are dropped.
+func (b *MemoryEventBus) Close() error {
+	b.timersMu.Lock()
+	if b.closed {
+		b.timersMu.Unlock()
+		return nil
+	}
+	b.closed = true
+	pendingTimers := 0
+	for timer := range b.timers {
+		if timer.Stop() {
+			pendingTimers++
+		}
+	}
+	b.timers = nil
+	b.timersMu.Unlock()
+
+	if pendingTimers > 0 {
+		fmt.Printf("[MemoryBus] Dropping %d delayed events\n", pendingTimers)
+	}
+
+	queues := []*memoryQueue{b.shared, b.observed}
+	for _, priority := range domain.Priorities {
+		queues = append(queues, b.priorities[priority])
+	}
+
+	drained := b.waitIdle(b.consumedQueues(), memoryDrainTimeout)
+	for _, queue := range queues {
+		queue.close()
+	}
+	if !drained {
+		for _, queue := range queues {
+			queue.discard()
+		}
+		return fmt.Errorf("event bus did not drain within %s", memoryDrainTimeout)
+	}
+
+	b.consumers.Wait()
+	return nil
+}
+
+// consumedQueues returns the queues being drained; events waiting in any other queue have nobody to
+// handle them
+func (b *MemoryEventBus) consumedQueues() []*memoryQueue {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	var queues []*memoryQueue
+	if b.consuming {
+		queues = append(queues, b.shared)
+		for _, priority := range domain.Priorities {
+			queues = append(queues, b.priorities[priority])
+		}
+	}
+	if b.observing {
+		queues = append(queues, b.observed)
+	}
+	return queues
+}
+
+// waitIdle reports whether every queue was empty, with no event being handled, before the timeout
+func (b *MemoryEventBus) waitIdle(queues []*memoryQueue, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for {
+		idle := true
+		for _, queue := range queues {
+			if !queue.idle() {
+				idle = false
+				break
+			}
+		}
+		if idle {
+			return true
+		}
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(memoryDrainPoll)
+	}
+}
+
+// memoryQueue is a bounded FIFO of events. Closing it rejects new events, while pop keeps returning
+// the queued ones until the queue is empty.
+type memoryQueue struct {
+	mu       sync.Mutex
+	ready    *sync.Cond
+	events   []*domain.EventEnvelope
+	capacity int
+	// handling counts the events popped and neither done nor requeued yet
+	handling int
+	closed   bool
+}
+
+func newMemoryQueue(capacity int) *memoryQueue {
+	queue := &memoryQueue{capacity: capacity}
+	queue.ready = sync.NewCond(&queue.mu)
+	return queue
+}
+
+// push appends the event; force ignores the capacity for events that were accepted before
+func (q *memoryQueue) push(env *domain.EventEnvelope, force bool) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return ErrEventBusClosed
+	}
+	if !force && len(q.events) >= q.capacity {
+		return ErrEventBufferFull
+	}
+
+	q.events = append(q.events, env)
+	q.ready.Signal()
+	return nil
+}
+
+// requeue puts a rejected event back at the head of the queue, as RabbitMQ does, even while draining
+func (q *memoryQueue) requeue(env *domain.EventEnvelope) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.events = append([]*domain.EventEnvelope{env}, q.events...)
+	q.handling--
+	q.ready.Signal()
+}
+
+// done acknowledges an event returned by pop
+func (q *memoryQueue) done() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.handling--
+}
+
+func (q *memoryQueue) idle() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return len(q.events) == 0 && q.handling == 0
+}
+
+// pop blocks until an event is queued; it returns false once the queue is closed and empty
+func (q *memoryQueue) pop() (*domain.EventEnvelope, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for len(q.events) == 0 && !q.closed {
+		q.ready.Wait()
+	}
+	if len(q.events) == 0 {
+		return nil, false
+	}
+
+	env := q.events[0]
+	q.events[0] = nil
+	q.events = q.events[1:]
+	q.handling++
+	return env, true
+}
+
+func (q *memoryQueue) close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.closed = true
+	q.ready.Broadcast()
+}
+
+// discard drops the events left after a drain timed out, so the consumers stop once their current
+// event is handled
+func (q *memoryQueue) discard() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(q.events) > 0 {
+		fmt.Printf("[MemoryBus] Dropping %d undelivered events\n", len(q.events))
+	}
+	q.events = nil
+}
@@ -0,0 +1,119 @@
+package eventbus
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ercancavusoglu/messaging/internal/domain"
+	"github.com/ercancavusoglu/messaging/internal/ports"
+	"github.com/stretchr/testify/assert"
+)
+
+type delayedMockEvent struct {
+	MockEvent
+	delay time.Duration
+}
+
+func (e *delayedMockEvent) EventDelay() time.Duration {
+	return e.delay
+}
+
+func newMockEvent(name string) *MockEvent {
+	return &MockEvent{name: name, occurredOn: time.Now(), aggregateID: "1"}
+}
+
+func receive(t *testing.T, events <-chan ports.Event) ports.Event {
+	t.Helper()
+
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(2 * time.Second):
+		t.Fatal("Event timeout")
+		return nil
+	}
+}
+
+func TestMemoryEventBus_BufferFull(t *testing.T) {
+	// Abone yokken tüketici başlamaz, kuyruk dolmalı
+	bus := NewMemoryEventBus(2)
+	defer bus.Close()
+
+	assert.NoError(t, bus.Publish(newMockEvent("test.event")))
+	assert.NoError(t, bus.Publish(newMockEvent("test.event")))
+
+	err := bus.Publish(newMockEvent("test.event"))
+	assert.ErrorIs(t, err, ErrEventBufferFull)
+
+	// Her öncelik sınıfının kendi kuyruğu vardır
+	err = bus.Publish(domain.NewMessageQueuedEvent(&domain.Message{ID: 1, Priority: domain.PriorityCritical}))
+	assert.NoError(t, err)
+}
+
+func TestMemoryEventBus_PriorityQueues(t *testing.T) {
+	bus := NewMemoryEventBus(10)
+	defer bus.Close()
+
+	bulk := domain.NewMessageQueuedEvent(&domain.Message{ID: 1, Priority: domain.PriorityBulk})
+	assert.Equal(t, bus.priorities[domain.PriorityBulk], bus.queueFor(bulk))
+
+	legacy := domain.NewMessageQueuedEvent(&domain.Message{ID: 2})
+	assert.Equal(t, bus.priorities[domain.PriorityNormal], bus.queueFor(legacy))
+
+	sent := domain.NewMessageSentEvent(&domain.Message{ID: 3, Priority: domain.PriorityCritical}, "msg_123", "client_one")
+	assert.Equal(t, bus.shared, bus.queueFor(&sent))
+
+	received := make(chan ports.Event, 1)
+	bus.Subscribe(domain.EventMessageQueued, func(event ports.Event) error {
+		received <- event
+		return nil
+	})
+
+	assert.NoError(t, bus.Publish(bulk))
+	envelope := receive(t, received).(*domain.EventEnvelope)
+	assert.Equal(t, domain.EventMessageQueued, envelope.Name)
+}
+
+func TestMemoryEventBus_CloseDrainsQueuedEvents(t *testing.T) {
+	bus := NewMemoryEventBus(100)
+
+	var mu sync.Mutex
+	handled := map[string]int{}
+	release := make(chan struct{})
+	bus.Subscribe("test.queued", func(event ports.Event) error {
+		<-release
+		mu.Lock()
+		handled["test.queued"]++
+		mu.Unlock()
+		// Boşaltma sırasında işleyicilerin yayınladığı olaylar da teslim edilmeli
+		return bus.Publish(newMockEvent("test.sent"))
+	})
+	bus.Subscribe("test.sent", func(event ports.Event) error {
+		mu.Lock()
+		handled["test.sent"]++
+		mu.Unlock()
+		return nil
+	})
+
+	for i := 0; i < 5; i++ {
+		assert.NoError(t, bus.Publish(newMockEvent("test.queued")))
+	}
+	// Ertelenmiş olay kapanışta düşürülür
+	assert.NoError(t, bus.Publish(&delayedMockEvent{MockEvent: *newMockEvent("test.queued"), delay: time.Hour}))
+
+	closed := make(chan error)
+	go func() {
+		closed <- bus.Close()
+	}()
+
+	close(release)
+	assert.NoError(t, <-closed)
+
+	mu.Lock()
+	assert.Equal(t, map[string]int{"test.queued": 5, "test.sent": 5}, handled)
+	mu.Unlock()
+
+	// Kapandıktan sonra yeni olay kabul edilmemeli
+	assert.ErrorIs(t, bus.Publish(newMockEvent("test.queued")), ErrEventBusClosed)
+}
@@ -3,8 +3,10 @@ package eventbus
 import (
 	"encoding/json"
 	"fmt"
+	"sync"
 	"time"
 
+	"github.com/ercancavusoglu/messaging/internal/config"
 	"github.com/ercancavusoglu/messaging/internal/domain"
 	"github.com/ercancavusoglu/messaging/internal/ports"
 
@@ -14,16 +16,39 @@ import (
 const (
 	exchangeName = "messaging.exchange"
 	queueName    = "messaging.queue"
+
+	// delayQueueIdle is how long a delay queue outlives its last delayed event before RabbitMQ removes it
+	delayQueueIdle = time.Hour
 )
 
+// RabbitMQEventBus delivers prioritized events (see ports.PrioritizedEvent) through one queue per priority
+// class and every other event through the shared queue. Each queue has its own consumer, so a backlog of
+// bulk messages never sits in front of a critical one. A consumer receives up to the configured prefetch of
+// deliveries at once and hands them to a fixed number of dispatch workers, so the events of one queue are
+// handled concurrently and may be acknowledged out of order.
+//
+// Delayed events (see ports.DelayedEvent) wait in a queue of their routing key and delay that nobody
+// consumes. The queue dead-letters them into the exchange with their routing key once the delay has
+// passed, so a delayed event survives a restart and reaches whichever worker is running by then.
+//
+// The queues are only consumed once a handler is subscribed, so a process that only publishes, such as the
+// API server, never takes events meant for the workers off the queues. A process that wants to watch the
+// events instead observes them (see Observe) through a queue of its own.
 type RabbitMQEventBus struct {
-	conn     *amqp.Connection
-	channel  *amqp.Channel
-	handlers map[string][]ports.EventHandler
+	conn        *amqp.Connection
+	channel     *amqp.Channel
+	workers     int
+	handlers    *registry
+	consumeOnce sync.Once
+
+	observeMu      sync.Mutex
+	observeChannel *amqp.Channel
+	observeQueue   string
+	observers      *registry
 }
 
-func NewRabbitMQEventBus(url string) (*RabbitMQEventBus, error) {
-	conn, err := amqp.Dial(url)
+func NewRabbitMQEventBus(settings config.RabbitMQ) (*RabbitMQEventBus, error) {
+	conn, err := amqp.Dial(settings.URL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to RabbitMQ: %v", err)
 	}
@@ -33,6 +58,11 @@ func NewRabbitMQEventBus(url string) (*RabbitMQEventBus, error) {
 		return nil, fmt.Errorf("failed to open channel: %v", err)
 	}
 
+	// The limit applies to each consumer started on the channel afterwards, i.e. to every queue on its own
+	if err := ch.Qos(settings.Prefetch, 0, false); err != nil {
+		return nil, fmt.Errorf("failed to set prefetch: %v", err)
+	}
+
 	err = ch.ExchangeDeclare(
 		exchangeName,
 		"topic",
@@ -58,9 +88,15 @@ func NewRabbitMQEventBus(url string) (*RabbitMQEventBus, error) {
 		return nil, fmt.Errorf("failed to declare queue: %v", err)
 	}
 
+	// Older deployments bound the shared queue to every routing key, which would also copy prioritized events into it
+	if err := ch.QueueUnbind(queueName, "#", exchangeName, nil); err != nil {
+		return nil, fmt.Errorf("failed to unbind queue: %v", err)
+	}
+
+	// Event names have two words ("message.sent"); prioritized events append the class as a third word
 	err = ch.QueueBind(
 		queueName,
-		"#",
+		"*.*",
 		exchangeName,
 		false,
 		nil,
@@ -69,20 +105,73 @@ func NewRabbitMQEventBus(url string) (*RabbitMQEventBus, error) {
 		return nil, fmt.Errorf("failed to bind queue: %v", err)
 	}
 
-	bus := &RabbitMQEventBus{
-		conn:     conn,
-		channel:  ch,
-		handlers: make(map[string][]ports.EventHandler),
+	for _, priority := range domain.Priorities {
+		if _, err := ch.QueueDeclare(priorityQueueName(priority), true, false, false, false, nil); err != nil {
+			return nil, fmt.Errorf("failed to declare %s queue: %v", priority, err)
+		}
+
+		if err := ch.QueueBind(priorityQueueName(priority), "*.*."+string(priority), exchangeName, false, nil); err != nil {
+			return nil, fmt.Errorf("failed to bind %s queue: %v", priority, err)
+		}
 	}
 
-	go bus.startConsumer()
+	bus := &RabbitMQEventBus{
+		conn:      conn,
+		channel:   ch,
+		workers:   settings.DispatchWorkers,
+		handlers:  newRegistry(),
+		observers: newRegistry(),
+	}
 
 	return bus, nil
 }
 
-func (b *RabbitMQEventBus) startConsumer() {
+func (b *RabbitMQEventBus) startConsumers() {
+	go b.startConsumer(queueName)
+	for _, priority := range domain.Priorities {
+		go b.startConsumer(priorityQueueName(priority))
+	}
+}
+
+func priorityQueueName(priority domain.Priority) string {
+	return queueName + "." + string(priority)
+}
+
+// routingKey appends the priority class to the event name of prioritized events
+func routingKey(event ports.Event) string {
+	if prioritized, ok := event.(ports.PrioritizedEvent); ok {
+		return event.EventName() + "." + string(prioritized.EventPriority())
+	}
+	return event.EventName()
+}
+
+// delayQueueName gives every delay of a routing key its own queue: a queue only expires the event at
+// its head, so a shorter delay must never wait behind a longer one
+func delayQueueName(key string, delay time.Duration) string {
+	return fmt.Sprintf("%s.delay.%s.%d", queueName, key, delay.Milliseconds())
+}
+
+func delayQueueArgs(key string, delay time.Duration) amqp.Table {
+	return amqp.Table{
+		"x-message-ttl":             delay.Milliseconds(),
+		"x-dead-letter-exchange":    exchangeName,
+		"x-dead-letter-routing-key": key,
+		"x-expires":                 (delay + delayQueueIdle).Milliseconds(),
+	}
+}
+
+// delayQueue declares the delay queue on every publish, which also renews its expiry
+func (b *RabbitMQEventBus) delayQueue(key string, delay time.Duration) (string, error) {
+	name := delayQueueName(key, delay)
+	if _, err := b.channel.QueueDeclare(name, true, false, false, false, delayQueueArgs(key, delay)); err != nil {
+		return "", fmt.Errorf("failed to declare delay queue: %v", err)
+	}
+	return name, nil
+}
+
+func (b *RabbitMQEventBus) startConsumer(queue string) {
 	msgs, err := b.channel.Consume(
-		queueName,
+		queue,
 		"",
 		false,
 		false,
@@ -91,67 +180,56 @@ func (b *RabbitMQEventBus) startConsumer() {
 		nil,
 	)
 	if err != nil {
-		fmt.Printf("[RabbitMQ] Failed to register consumer: %v\n", err)
+		fmt.Printf("[RabbitMQ] Failed to register consumer for %s: %v\n", queue, err)
 		return
 	}
 
-	for d := range msgs {
-		fmt.Printf("[RabbitMQ] Received message: %s\n", string(d.Body))
-
-		var env domain.EventEnvelope
-		if err := json.Unmarshal(d.Body, &env); err != nil {
-			fmt.Printf("[RabbitMQ] Failed to unmarshal event envelope: %v\n", err)
-			d.Reject(true)
-			continue
-		}
-
-		fmt.Printf("[RabbitMQ] Processing event: %s, AggregateID: %s\n", env.Name, env.AggregateID)
+	// The workers share the deliveries and stop once the channel is closed
+	for i := 0; i < b.workers; i++ {
+		go func() {
+			for d := range msgs {
+				b.dispatch(d)
+			}
+		}()
+	}
+}
 
-		handlers := b.handlers[env.Name]
-		if len(handlers) == 0 {
-			fmt.Printf("[RabbitMQ] No handlers found for event: %s\n", env.Name)
-			d.Ack(false)
-			continue
-		}
+func (b *RabbitMQEventBus) dispatch(d amqp.Delivery) {
+	fmt.Printf("[RabbitMQ] Received message: %s\n", string(d.Body))
 
-		var handlerError error
-		for _, handler := range handlers {
-			if err := handler(&env); err != nil {
-				fmt.Printf("[RabbitMQ] Handler failed for event %s: %v\n", env.Name, err)
-				handlerError = err
-				break
-			}
-		}
+	var env domain.EventEnvelope
+	if err := json.Unmarshal(d.Body, &env); err != nil {
+		fmt.Printf("[RabbitMQ] Failed to unmarshal event envelope: %v\n", err)
+		d.Reject(true)
+		return
+	}
 
-		if handlerError != nil {
-			d.Reject(true)
-			continue
-		}
+	fmt.Printf("[RabbitMQ] Processing event: %s, AggregateID: %s\n", env.Name, env.AggregateID)
 
+	handlers := b.handlers.handlers(env.Name)
+	if len(handlers) == 0 {
+		fmt.Printf("[RabbitMQ] No handlers found for event: %s\n", env.Name)
 		d.Ack(false)
-		fmt.Printf("[RabbitMQ] Successfully processed event: %s\n", env.Name)
+		return
+	}
+
+	if err := handle(handlers, &env); err != nil {
+		fmt.Printf("[RabbitMQ] Handler failed for event %s: %v\n", env.Name, err)
+		d.Reject(true)
+		return
 	}
+
+	d.Ack(false)
+	fmt.Printf("[RabbitMQ] Successfully processed event: %s\n", env.Name)
 }
 
 func (b *RabbitMQEventBus) Publish(event ports.Event) error {
-	data, err := json.Marshal(event)
+	env, err := newEnvelope(event)
 	if err != nil {
-		return fmt.Errorf("failed to marshal event: %v", err)
+		return err
 	}
 
-	fmt.Printf("[RabbitMQ] Event data: %s\n", string(data))
-
-	env := struct {
-		Name        string          `json:"name"`
-		OccurredOn  time.Time       `json:"occurred_on"`
-		AggregateID string          `json:"aggregate_id"`
-		Data        json.RawMessage `json:"data"`
-	}{
-		Name:        event.EventName(),
-		OccurredOn:  event.OccurredAt(),
-		AggregateID: event.GetAggregateID(),
-		Data:        data,
-	}
+	fmt.Printf("[RabbitMQ] Event data: %s\n", string(env.Data))
 
 	body, err := json.Marshal(env)
 	if err != nil {
@@ -160,9 +238,19 @@ func (b *RabbitMQEventBus) Publish(event ports.Event) error {
 
 	fmt.Printf("[RabbitMQ] Publishing event: %s\n", string(body))
 
+	// A delayed event goes through the default exchange, which routes by queue name, into its delay queue
+	exchange, key := exchangeName, routingKey(event)
+	if delayed, ok := event.(ports.DelayedEvent); ok && delayed.EventDelay() > 0 {
+		queue, err := b.delayQueue(key, delayed.EventDelay())
+		if err != nil {
+			return err
+		}
+		exchange, key = "", queue
+	}
+
 	err = b.channel.Publish(
-		exchangeName,
-		event.EventName(),
+		exchange,
+		key,
 		false,
 		false,
 		amqp.Publishing{
@@ -179,29 +267,93 @@ func (b *RabbitMQEventBus) Publish(event ports.Event) error {
 	return nil
 }
 
-func (b *RabbitMQEventBus) Subscribe(eventName string, handler ports.EventHandler) {
-	b.handlers[eventName] = append(b.handlers[eventName], handler)
+func (b *RabbitMQEventBus) Subscribe(eventName string, handler ports.EventHandler) ports.Subscription {
+	subscription := b.handlers.add(eventName, handler)
+	b.consumeOnce.Do(b.startConsumers)
+	return subscription
 }
 
-func (b *RabbitMQEventBus) Unsubscribe(eventName string, handler ports.EventHandler) {
-	handlers := b.handlers[eventName]
-	newHandlers := make([]ports.EventHandler, 0)
-	handlerPtr := fmt.Sprintf("%p", handler)
+// Observe copies the events into an exclusive queue that RabbitMQ deletes when the process disconnects, so
+// observing never takes an event from the workers and an observer that is gone leaves nothing behind.
+// Observed events are acknowledged on receipt: handler errors are only logged, and events published while
+// the process is down are missed.
+func (b *RabbitMQEventBus) Observe(eventName string, handler ports.EventHandler) error {
+	b.observeMu.Lock()
+	defer b.observeMu.Unlock()
+
+	if b.observeChannel == nil {
+		if err := b.startObserving(); err != nil {
+			return err
+		}
+	}
 
-	for _, h := range handlers {
-		if fmt.Sprintf("%p", h) != handlerPtr {
-			newHandlers = append(newHandlers, h)
+	if !b.observers.has(eventName) {
+		for _, key := range observedRoutingKeys(eventName) {
+			if err := b.observeChannel.QueueBind(b.observeQueue, key, exchangeName, false, nil); err != nil {
+				return fmt.Errorf("failed to bind observer queue: %v", err)
+			}
 		}
 	}
 
-	if len(newHandlers) == 0 {
-		delete(b.handlers, eventName)
-	} else {
-		b.handlers[eventName] = newHandlers
+	b.observers.add(eventName, handler)
+	return nil
+}
+
+// observedRoutingKeys matches the event both on its own and with the priority class appended
+func observedRoutingKeys(eventName string) []string {
+	return []string{eventName, eventName + ".*"}
+}
+
+// startObserving declares the observer queue on a channel of its own, so a failing bind cannot close the
+// channel the bus publishes on
+func (b *RabbitMQEventBus) startObserving() error {
+	ch, err := b.conn.Channel()
+	if err != nil {
+		return fmt.Errorf("failed to open observer channel: %v", err)
+	}
+
+	queue, err := ch.QueueDeclare("", false, true, true, false, nil)
+	if err != nil {
+		ch.Close()
+		return fmt.Errorf("failed to declare observer queue: %v", err)
+	}
+
+	msgs, err := ch.Consume(queue.Name, "", true, true, false, false, nil)
+	if err != nil {
+		ch.Close()
+		return fmt.Errorf("failed to consume observer queue: %v", err)
+	}
+
+	b.observeChannel = ch
+	b.observeQueue = queue.Name
+	go b.dispatchObserved(msgs)
+
+	return nil
+}
+
+func (b *RabbitMQEventBus) dispatchObserved(msgs <-chan amqp.Delivery) {
+	for d := range msgs {
+		var env domain.EventEnvelope
+		if err := json.Unmarshal(d.Body, &env); err != nil {
+			fmt.Printf("[RabbitMQ] Failed to unmarshal observed event: %v\n", err)
+			continue
+		}
+
+		for _, observer := range b.observers.handlers(env.Name) {
+			if err := observer(&env); err != nil {
+				fmt.Printf("[RabbitMQ] Observer failed for event %s: %v\n", env.Name, err)
+			}
+		}
 	}
 }
 
 func (b *RabbitMQEventBus) Close() error {
+	b.observeMu.Lock()
+	if b.observeChannel != nil {
+		b.observeChannel.Close()
+	}
+	b.observeMu.Unlock()
+
 	if err := b.channel.Close(); err != nil {
 		return fmt.Errorf("failed to close channel: %v", err)
 	}
@@ -0,0 +1,78 @@
+package eventbus
+
+import (
+	"sync"
+
+	"github.com/ercancavusoglu/messaging/internal/ports"
+)
+
+// registry holds the handlers subscribed to each event. Consumers take a snapshot of an event's handlers,
+// so subscribing and unsubscribing is safe while events are being delivered.
+type registry struct {
+	mu            sync.RWMutex
+	subscriptions map[string][]*subscription
+}
+
+// subscription is compared by identity, so each one only removes itself, even when the same handler or
+// another closure of the same function is subscribed too
+type subscription struct {
+	registry  *registry
+	eventName string
+	handler   ports.EventHandler
+}
+
+func newRegistry() *registry {
+	return &registry{subscriptions: make(map[string][]*subscription)}
+}
+
+func (r *registry) add(eventName string, handler ports.EventHandler) *subscription {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	sub := &subscription{registry: r, eventName: eventName, handler: handler}
+	r.subscriptions[eventName] = append(r.subscriptions[eventName], sub)
+	return sub
+}
+
+func (s *subscription) Unsubscribe() {
+	s.registry.remove(s)
+}
+
+func (r *registry) remove(sub *subscription) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	subscriptions := r.subscriptions[sub.eventName]
+	remaining := make([]*subscription, 0, len(subscriptions))
+	for _, s := range subscriptions {
+		if s != sub {
+			remaining = append(remaining, s)
+		}
+	}
+
+	if len(remaining) == 0 {
+		delete(r.subscriptions, sub.eventName)
+	} else {
+		r.subscriptions[sub.eventName] = remaining
+	}
+}
+
+// handlers returns the event's handlers in the order they were subscribed
+func (r *registry) handlers(eventName string) []ports.EventHandler {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	subscriptions := r.subscriptions[eventName]
+	handlers := make([]ports.EventHandler, len(subscriptions))
+	for i, sub := range subscriptions {
+		handlers[i] = sub.handler
+	}
+	return handlers
+}
+
+func (r *registry) has(eventName string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return len(r.subscriptions[eventName]) > 0
+}
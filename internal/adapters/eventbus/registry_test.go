@@ -0,0 +1,73 @@
+package eventbus
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/ercancavusoglu/messaging/internal/ports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_UnsubscribeRemovesOnlyItsHandler(t *testing.T) {
+	r := newRegistry()
+
+	// Aynı fonksiyon değişmezinden üretilen kapanışlar birbirinden ayırt edilmeli
+	var calls []string
+	newHandler := func(name string) ports.EventHandler {
+		return func(event ports.Event) error {
+			calls = append(calls, name)
+			return nil
+		}
+	}
+
+	first := r.add("message.sent", newHandler("first"))
+	r.add("message.sent", newHandler("second"))
+
+	first.Unsubscribe()
+	// İkinci çağrı etkisiz kalmalı
+	first.Unsubscribe()
+
+	handlers := r.handlers("message.sent")
+	require.Len(t, handlers, 1)
+	require.NoError(t, handlers[0](nil))
+	assert.Equal(t, []string{"second"}, calls)
+	assert.True(t, r.has("message.sent"))
+}
+
+func TestRegistry_RemovesEmptyEvents(t *testing.T) {
+	r := newRegistry()
+
+	subscription := r.add("message.sent", func(event ports.Event) error { return nil })
+	subscription.Unsubscribe()
+
+	assert.False(t, r.has("message.sent"))
+	assert.Empty(t, r.handlers("message.sent"))
+}
+
+func TestRegistry_ConcurrentSubscriptions(t *testing.T) {
+	r := newRegistry()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+		eventName := fmt.Sprintf("event.e%d", i%3)
+		go func() {
+			defer wg.Done()
+			subscription := r.add(eventName, func(event ports.Event) error { return nil })
+			subscription.Unsubscribe()
+		}()
+		go func() {
+			defer wg.Done()
+			for _, handler := range r.handlers(eventName) {
+				handler(nil)
+			}
+		}()
+	}
+	wg.Wait()
+
+	for i := 0; i < 3; i++ {
+		assert.False(t, r.has(fmt.Sprintf("event.e%d", i)))
+	}
+}
@@ -0,0 +1,114 @@
+package adapters
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/ercancavusoglu/messaging/internal/config"
+	"github.com/ercancavusoglu/messaging/internal/domain"
+	"github.com/ercancavusoglu/messaging/internal/ports"
+	"github.com/gorilla/mux"
+)
+
+// maxInboundBody bounds the body read before its signature is checked
+const maxInboundBody = 64 << 10
+
+var ErrInvalidInboundSignature = errors.New("missing or invalid signature")
+
+// InboundHandler accepts messages from providers that sign the body with their signing key, since the
+// keywords in them change the suppression list. Providers without a signing key cannot post messages.
+type InboundHandler struct {
+	inboundService ports.InboundService
+	mu             sync.RWMutex
+	signatures     map[string]config.ProviderInbound
+}
+
+type inboundMessageRequest struct {
+	From      string `json:"from"`
+	To        string `json:"to"`
+	Content   string `json:"content"`
+	MessageID string `json:"message_id"`
+}
+
+func NewInboundHandler(inboundService ports.InboundService, signatures map[string]config.ProviderInbound) *InboundHandler {
+	return &InboundHandler{
+		inboundService: inboundService,
+		signatures:     signatures,
+	}
+}
+
+// SetSignatures replaces the providers' signing keys, e.g. after a configuration reload
+func (h *InboundHandler) SetSignatures(signatures map[string]config.ProviderInbound) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.signatures = signatures
+}
+
+func (h *InboundHandler) ReceiveMessage(w http.ResponseWriter, r *http.Request) {
+	provider := mux.Vars(r)["provider"]
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxInboundBody))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %v", err))
+		return
+	}
+
+	if !h.verify(provider, r.Header, body) {
+		writeError(w, http.StatusUnauthorized, ErrInvalidInboundSignature)
+		return
+	}
+
+	var req inboundMessageRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %v", err))
+		return
+	}
+
+	if req.From == "" || req.Content == "" {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("from and content are required"))
+		return
+	}
+
+	msg := &domain.InboundMessage{
+		From:              req.From,
+		To:                req.To,
+		Content:           req.Content,
+		Provider:          provider,
+		ProviderMessageID: req.MessageID,
+	}
+
+	if err := h.inboundService.Receive(msg); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, msg)
+}
+
+// verify checks the hex HMAC-SHA256 signature of the body, with or without a "sha256=" prefix
+func (h *InboundHandler) verify(provider string, header http.Header, body []byte) bool {
+	h.mu.RLock()
+	signature, ok := h.signatures[provider]
+	h.mu.RUnlock()
+	if !ok {
+		return false
+	}
+
+	received, err := hex.DecodeString(strings.TrimPrefix(header.Get(signature.Header), "sha256="))
+	if err != nil || len(received) == 0 {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(signature.SigningKey))
+	mac.Write(body)
+	return hmac.Equal(received, mac.Sum(nil))
+}
@@ -0,0 +1,123 @@
+package adapters
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ercancavusoglu/messaging/internal/adapters/mocks"
+	"github.com/ercancavusoglu/messaging/internal/config"
+	"github.com/ercancavusoglu/messaging/internal/domain"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var testInboundSignatures = map[string]config.ProviderInbound{
+	"client_one": {SigningKey: "inbound-key", Header: "X-Signature"},
+}
+
+func signInbound(key, body string) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(body))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func newInboundRequest(provider, body, signature string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/inbound/"+provider, strings.NewReader(body))
+	if signature != "" {
+		req.Header.Set("X-Signature", signature)
+	}
+	return mux.SetURLVars(req, map[string]string{"provider": provider})
+}
+
+func TestInboundHandler_ReceiveMessage(t *testing.T) {
+	mockService := &mocks.MockInboundService{}
+	handler := NewInboundHandler(mockService, testInboundSignatures)
+
+	mockService.On("Receive", mock.MatchedBy(func(msg *domain.InboundMessage) bool {
+		return msg.From == "+905551234567" && msg.Content == "STOP" && msg.Provider == "client_one" && msg.ProviderMessageID == "mo_1"
+	})).Run(func(args mock.Arguments) {
+		args.Get(0).(*domain.InboundMessage).Keyword = domain.KeywordStop
+	}).Return(nil)
+
+	body := `{"from":"+905551234567","to":"3434","content":"STOP","message_id":"mo_1"}`
+	req := newInboundRequest("client_one", body, "sha256="+signInbound("inbound-key", body))
+	w := httptest.NewRecorder()
+
+	handler.ReceiveMessage(w, req)
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+
+	var response domain.InboundMessage
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Equal(t, domain.KeywordStop, response.Keyword)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestInboundHandler_ReceiveMessage_InvalidBody(t *testing.T) {
+	mockService := &mocks.MockInboundService{}
+	handler := NewInboundHandler(mockService, testInboundSignatures)
+
+	body := `{"from":""}`
+	w := httptest.NewRecorder()
+
+	handler.ReceiveMessage(w, newInboundRequest("client_one", body, signInbound("inbound-key", body)))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertNotCalled(t, "Receive", mock.Anything)
+}
+
+func TestInboundHandler_ReceiveMessage_RejectsUnsignedRequests(t *testing.T) {
+	body := `{"from":"+905551234567","content":"START"}`
+
+	tests := []struct {
+		name      string
+		provider  string
+		signature string
+	}{
+		{name: "missing signature", provider: "client_one"},
+		{name: "wrong key", provider: "client_one", signature: signInbound("guessed-key", body)},
+		{name: "not hex", provider: "client_one", signature: "sha256=zz"},
+		// İmza anahtarı olmayan sağlayıcı adına gelen mesaj kabul edilmemeli
+		{name: "provider without signing key", provider: "client_two", signature: signInbound("inbound-key", body)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mocks.MockInboundService{}
+			handler := NewInboundHandler(mockService, testInboundSignatures)
+			w := httptest.NewRecorder()
+
+			handler.ReceiveMessage(w, newInboundRequest(tt.provider, body, tt.signature))
+
+			assert.Equal(t, http.StatusUnauthorized, w.Code)
+			mockService.AssertNotCalled(t, "Receive", mock.Anything)
+		})
+	}
+}
+
+func TestInboundHandler_SetSignatures(t *testing.T) {
+	mockService := &mocks.MockInboundService{}
+	handler := NewInboundHandler(mockService, testInboundSignatures)
+	mockService.On("Receive", mock.AnythingOfType("*domain.InboundMessage")).Return(nil)
+
+	body := `{"from":"+905551234567","content":"HELP"}`
+	handler.SetSignatures(map[string]config.ProviderInbound{"client_one": {SigningKey: "rotated-key", Header: "X-Acme-Signature"}})
+
+	w := httptest.NewRecorder()
+	handler.ReceiveMessage(w, newInboundRequest("client_one", body, signInbound("inbound-key", body)))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	req := newInboundRequest("client_one", body, "")
+	req.Header.Set("X-Acme-Signature", signInbound("rotated-key", body))
+	w = httptest.NewRecorder()
+	handler.ReceiveMessage(w, req)
+	assert.Equal(t, http.StatusAccepted, w.Code)
+}
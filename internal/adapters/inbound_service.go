@@ -0,0 +1,60 @@
+package adapters
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ercancavusoglu/messaging/internal/domain"
+	"github.com/ercancavusoglu/messaging/internal/ports"
+)
+
+type inboundService struct {
+	repo         ports.InboundRepository
+	suppressions ports.SuppressionService
+	eventBus     ports.EventBus
+	keywords     *domain.KeywordMatcher
+}
+
+func NewInboundService(repo ports.InboundRepository, suppressions ports.SuppressionService, eventBus ports.EventBus, keywords *domain.KeywordMatcher) ports.InboundService {
+	return &inboundService{
+		repo:         repo,
+		suppressions: suppressions,
+		eventBus:     eventBus,
+		keywords:     keywords,
+	}
+}
+
+// Receive stores the inbound message, applies STOP/START keywords to the suppression list
+// and publishes a message.inbound event
+func (s *inboundService) Receive(msg *domain.InboundMessage) error {
+	if msg.ReceivedAt.IsZero() {
+		msg.ReceivedAt = time.Now()
+	}
+	msg.Keyword = s.keywords.Match(msg.Content)
+
+	if err := s.repo.Save(msg); err != nil {
+		return err
+	}
+
+	// Inbound replies reach the provider's number rather than a tenant, so they change the global list
+	switch msg.Keyword {
+	case domain.KeywordStop:
+		reason := fmt.Sprintf("%s reply via %s", strings.Fields(msg.Content)[0], msg.Provider)
+		if _, err := s.suppressions.Add(0, msg.From, reason, domain.SuppressionSourceInbound); err != nil {
+			return fmt.Errorf("failed to suppress recipient: %v", err)
+		}
+	case domain.KeywordStart:
+		if err := s.suppressions.Remove(0, msg.From); err != nil && !errors.Is(err, domain.ErrSuppressionNotFound) {
+			return fmt.Errorf("failed to unsuppress recipient: %v", err)
+		}
+	}
+
+	event := domain.NewMessageInboundEvent(msg)
+	if err := s.eventBus.Publish(&event); err != nil {
+		return fmt.Errorf("failed to publish inbound event: %v", err)
+	}
+
+	return nil
+}
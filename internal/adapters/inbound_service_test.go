@@ -0,0 +1,92 @@
+package adapters
+
+import (
+	"testing"
+
+	"github.com/ercancavusoglu/messaging/internal/adapters/mocks"
+	"github.com/ercancavusoglu/messaging/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestInboundService_Receive_Stop(t *testing.T) {
+	mockRepo := &mocks.MockInboundRepository{}
+	mockSuppressions := &mocks.MockSuppressionService{}
+	mockEventBus := &mocks.MockEventBus{}
+
+	service := NewInboundService(mockRepo, mockSuppressions, mockEventBus, domain.DefaultKeywordMatcher())
+
+	msg := &domain.InboundMessage{From: "+905551234567", Content: "iptal", Provider: "client_one"}
+
+	// Mock beklentileri
+	mockRepo.On("Save", msg).Return(nil)
+	mockSuppressions.On("Add", int64(0), "+905551234567", "iptal reply via client_one", domain.SuppressionSourceInbound).
+		Return(&domain.Suppression{Recipient: "+905551234567"}, nil)
+	mockEventBus.On("Publish", mock.AnythingOfType("*domain.MessageInboundEvent")).Return(nil)
+
+	err := service.Receive(msg)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.KeywordStop, msg.Keyword)
+	assert.False(t, msg.ReceivedAt.IsZero())
+
+	mockRepo.AssertExpectations(t)
+	mockSuppressions.AssertExpectations(t)
+	mockEventBus.AssertExpectations(t)
+}
+
+func TestInboundService_Receive_StartNotSuppressed(t *testing.T) {
+	mockRepo := &mocks.MockInboundRepository{}
+	mockSuppressions := &mocks.MockSuppressionService{}
+	mockEventBus := &mocks.MockEventBus{}
+
+	service := NewInboundService(mockRepo, mockSuppressions, mockEventBus, domain.DefaultKeywordMatcher())
+
+	msg := &domain.InboundMessage{From: "+905551234567", Content: "START", Provider: "client_one"}
+
+	mockRepo.On("Save", msg).Return(nil)
+	mockSuppressions.On("Remove", int64(0), "+905551234567").Return(domain.ErrSuppressionNotFound)
+	mockEventBus.On("Publish", mock.AnythingOfType("*domain.MessageInboundEvent")).Return(nil)
+
+	err := service.Receive(msg)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.KeywordStart, msg.Keyword)
+
+	mockSuppressions.AssertExpectations(t)
+	mockEventBus.AssertExpectations(t)
+}
+
+func TestInboundService_Receive_NoKeyword(t *testing.T) {
+	mockRepo := &mocks.MockInboundRepository{}
+	mockSuppressions := &mocks.MockSuppressionService{}
+	mockEventBus := &mocks.MockEventBus{}
+
+	service := NewInboundService(mockRepo, mockSuppressions, mockEventBus, domain.DefaultKeywordMatcher())
+
+	msg := &domain.InboundMessage{From: "+905551234567", Content: "Thanks!", Provider: "client_two"}
+
+	mockRepo.On("Save", msg).Return(nil)
+	mockEventBus.On("Publish", mock.AnythingOfType("*domain.MessageInboundEvent")).Return(nil)
+
+	err := service.Receive(msg)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.KeywordNone, msg.Keyword)
+
+	// Suppression listesine dokunulmamalı
+	mockSuppressions.AssertNotCalled(t, "Add", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockSuppressions.AssertNotCalled(t, "Remove", mock.Anything, mock.Anything)
+}
+
+func TestInboundService_Receive_SaveError(t *testing.T) {
+	mockRepo := &mocks.MockInboundRepository{}
+	mockSuppressions := &mocks.MockSuppressionService{}
+	mockEventBus := &mocks.MockEventBus{}
+
+	service := NewInboundService(mockRepo, mockSuppressions, mockEventBus, domain.DefaultKeywordMatcher())
+
+	msg := &domain.InboundMessage{From: "+905551234567", Content: "STOP", Provider: "client_one"}
+	mockRepo.On("Save", msg).Return(assert.AnError)
+
+	err := service.Receive(msg)
+	assert.Error(t, err)
+	mockEventBus.AssertNotCalled(t, "Publish", mock.Anything)
+}
@@ -1,75 +1,148 @@
 package adapters
 
 import (
-	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
-	"github.com/ercancavusoglu/messaging/internal/adapters/scheduler"
+	"github.com/ercancavusoglu/messaging/internal/domain"
 	"github.com/ercancavusoglu/messaging/internal/ports"
 )
 
 type MessageHandler struct {
-	messageService ports.MessageService
-	scheduler      *scheduler.SchedulerService
-	ctx            context.Context
-	cancel         context.CancelFunc
+	messageService  ports.MessageService
+	templateService ports.TemplateService
+	auditService    ports.AuditService
+	scheduler       ports.SchedulerController
+	rules           domain.MessageRules
 }
 
-func NewMessageHandler(messageService ports.MessageService, scheduler *scheduler.SchedulerService) *MessageHandler {
-	ctx, cancel := context.WithCancel(context.Background())
+// createMessageRequest carries either a literal content or a template reference with its variables.
+// Channel defaults to sms; a subject is only accepted for email. Fallback lists the channels tried in
+// turn while the message is not delivered.
+type createMessageRequest struct {
+	Channel    string                `json:"channel"`
+	To         string                `json:"to"`
+	Subject    string                `json:"subject"`
+	Content    string                `json:"content"`
+	TemplateID int64                 `json:"template_id"`
+	Locale     string                `json:"locale"`
+	Variables  map[string]string     `json:"variables"`
+	Priority   string                `json:"priority"`
+	Fallback   []domain.FallbackStep `json:"fallback"`
+}
+
+// schedulerIntervalRequest carries a Go duration such as "30s" or "1m"
+type schedulerIntervalRequest struct {
+	Interval string `json:"interval"`
+}
+
+func NewMessageHandler(messageService ports.MessageService, templateService ports.TemplateService, auditService ports.AuditService, scheduler ports.SchedulerController, rules domain.MessageRules) *MessageHandler {
 	return &MessageHandler{
-		messageService: messageService,
-		scheduler:      scheduler,
-		ctx:            ctx,
-		cancel:         cancel,
+		messageService:  messageService,
+		templateService: templateService,
+		auditService:    auditService,
+		scheduler:       scheduler,
+		rules:           rules,
 	}
 }
 
 func (h *MessageHandler) StartScheduler(w http.ResponseWriter, r *http.Request) {
-	if h.scheduler.IsRunning() {
+	err := h.scheduler.Start()
+	if errors.Is(err, domain.ErrSchedulerAlreadyRunning) {
+		recordAudit(h.auditService, r, domain.AuditActionSchedulerStart, "already running")
 		h.jsonResponse(w, http.StatusBadRequest, map[string]string{
 			"error": "Scheduler is already running",
 		})
 		return
 	}
-
-	fmt.Println("[StartScheduler] Scheduler is not running")
-	if err := h.scheduler.Start(h.ctx); err != nil {
+	if err != nil {
 		fmt.Printf("[StartScheduler] Error: %v\n", err)
+		recordAudit(h.auditService, r, domain.AuditActionSchedulerStart, err.Error())
 		h.jsonResponse(w, http.StatusInternalServerError, map[string]string{
 			"error": err.Error(),
 		})
 		return
 	}
 
+	recordAudit(h.auditService, r, domain.AuditActionSchedulerStart, "ok")
 	h.jsonResponse(w, http.StatusOK, map[string]string{
 		"message": "Scheduler started successfully",
 	})
 }
 
 func (h *MessageHandler) StopScheduler(w http.ResponseWriter, r *http.Request) {
-	if !h.scheduler.IsRunning() {
+	err := h.scheduler.Stop()
+	if errors.Is(err, domain.ErrSchedulerNotRunning) {
+		recordAudit(h.auditService, r, domain.AuditActionSchedulerStop, "not running")
 		h.jsonResponse(w, http.StatusBadRequest, map[string]string{
 			"error": "Scheduler is not running",
 		})
 		return
 	}
+	if err != nil {
+		fmt.Printf("[StopScheduler] Error: %v\n", err)
+		recordAudit(h.auditService, r, domain.AuditActionSchedulerStop, err.Error())
+		h.jsonResponse(w, http.StatusInternalServerError, map[string]string{
+			"error": err.Error(),
+		})
+		return
+	}
 
-	h.scheduler.Stop()
-	h.cancel()
-	ctx, cancel := context.WithCancel(context.Background())
-	h.ctx = ctx
-	h.cancel = cancel
-
+	recordAudit(h.auditService, r, domain.AuditActionSchedulerStop, "ok")
 	h.jsonResponse(w, http.StatusOK, map[string]string{
 		"message": "Scheduler stopped successfully",
 	})
 }
 
+func (h *MessageHandler) GetSchedulerStatus(w http.ResponseWriter, r *http.Request) {
+	status, err := h.scheduler.Status()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	h.jsonResponse(w, http.StatusOK, status)
+}
+
+func (h *MessageHandler) SetSchedulerInterval(w http.ResponseWriter, r *http.Request) {
+	var req schedulerIntervalRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %v", err))
+		return
+	}
+
+	interval, err := time.ParseDuration(req.Interval)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid interval: %q", req.Interval))
+		return
+	}
+
+	err = h.scheduler.SetInterval(interval)
+	if errors.Is(err, domain.ErrInvalidSchedulerInterval) {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+	if err != nil {
+		recordAudit(h.auditService, r, domain.AuditActionSchedulerInterval, err.Error())
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	recordAudit(h.auditService, r, domain.AuditActionSchedulerInterval, "ok: "+interval.String())
+	h.GetSchedulerStatus(w, r)
+}
+
 func (h *MessageHandler) GetMessages(w http.ResponseWriter, r *http.Request) {
-	messages, err := h.messageService.GetSendedMessages()
+	tenant, ok := TenantFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusForbidden, errMissingTenant)
+		return
+	}
+
+	messages, err := h.messageService.GetSendedMessages(tenant.ID)
 	if err != nil {
 		h.jsonResponse(w, http.StatusInternalServerError, map[string]string{
 			"error": err.Error(),
@@ -80,10 +153,71 @@ func (h *MessageHandler) GetMessages(w http.ResponseWriter, r *http.Request) {
 	h.jsonResponse(w, http.StatusOK, messages)
 }
 
-func (h *MessageHandler) jsonResponse(w http.ResponseWriter, status int, data interface{}) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-	if err := json.NewEncoder(w).Encode(data); err != nil {
-		fmt.Printf("[Handler] Error encoding response: %v\n", err)
+func (h *MessageHandler) CreateMessage(w http.ResponseWriter, r *http.Request) {
+	tenant, ok := TenantFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusForbidden, errMissingTenant)
+		return
+	}
+
+	var req createMessageRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %v", err))
+		return
+	}
+
+	if req.Content != "" && req.TemplateID != 0 {
+		writeError(w, http.StatusBadRequest, errors.New("content and template_id are mutually exclusive"))
+		return
+	}
+
+	content := req.Content
+	if req.TemplateID != 0 {
+		rendered, err := h.templateService.Render(tenant.ID, req.TemplateID, req.Locale, req.Variables)
+		if err != nil {
+			writeError(w, templateErrorStatus(err), err)
+			return
+		}
+		content = rendered
+	}
+
+	priority, err := domain.ParsePriority(req.Priority)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	channel, err := domain.ParseChannel(req.Channel)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	msg, err := domain.NewChannelMessage(channel, req.To, req.Subject, content, h.rules)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+	msg.Priority = priority
+
+	if err := msg.SetFallback(req.Fallback, h.rules); err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	err = h.messageService.CreateMessage(tenant, msg)
+	if errors.Is(err, domain.ErrQuotaExceeded) {
+		writeError(w, http.StatusTooManyRequests, err)
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
 	}
+
+	h.jsonResponse(w, http.StatusCreated, msg)
+}
+
+func (h *MessageHandler) jsonResponse(w http.ResponseWriter, status int, data interface{}) {
+	writeJSON(w, status, data)
 }
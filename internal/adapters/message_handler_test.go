@@ -4,37 +4,27 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/ercancavusoglu/messaging/internal/adapters/mocks"
-	"github.com/ercancavusoglu/messaging/internal/adapters/scheduler"
 	"github.com/ercancavusoglu/messaging/internal/domain"
-	"github.com/ercancavusoglu/messaging/internal/ports"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
-type mockLogger struct {
-	ports.Logger
-}
-
-func (m *mockLogger) Info(args ...interface{})                    {}
-func (m *mockLogger) Infof(format string, args ...interface{})    {}
-func (m *mockLogger) Error(args ...interface{})                   {}
-func (m *mockLogger) Errorf(format string, args ...interface{})   {}
-func (m *mockLogger) Warning(args ...interface{})                 {}
-func (m *mockLogger) Warningf(format string, args ...interface{}) {}
-
 func TestMessageHandler_GetMessages(t *testing.T) {
 	mockService := &mocks.MockMessageService{}
-	mockScheduler := scheduler.NewSchedulerService(mockService, time.Second, &mockLogger{})
+	mockScheduler := &mocks.MockSchedulerController{}
 
-	handler := NewMessageHandler(mockService, mockScheduler)
+	handler := NewMessageHandler(mockService, &mocks.MockTemplateService{}, &mocks.MockAuditService{}, mockScheduler, domain.DefaultMessageRules())
 
 	expectedMessages := []*domain.Message{createTestMessage()}
-	mockService.On("GetSendedMessages").Return(expectedMessages, nil)
+	mockService.On("GetSendedMessages", int64(7)).Return(expectedMessages, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/messages", nil)
+	req = req.WithContext(WithTenant(req.Context(), &domain.Tenant{ID: 7}))
 	w := httptest.NewRecorder()
 
 	handler.GetMessages(w, req)
@@ -56,15 +46,15 @@ func TestMessageHandler_GetMessages(t *testing.T) {
 
 func TestMessageHandler_StartScheduler_AlreadyRunning(t *testing.T) {
 	mockService := &mocks.MockMessageService{}
-	mockScheduler := scheduler.NewSchedulerService(mockService, time.Second, &mockLogger{})
+	mockScheduler := &mocks.MockSchedulerController{}
 
-	handler := NewMessageHandler(mockService, mockScheduler)
+	mockAudit := &mocks.MockAuditService{}
+	handler := NewMessageHandler(mockService, &mocks.MockTemplateService{}, mockAudit, mockScheduler, domain.DefaultMessageRules())
 
-	// Scheduler'ı başlatalım
-	go func() {
-		_ = mockScheduler.Start(handler.ctx)
-	}()
-	time.Sleep(time.Millisecond * 100) // Scheduler'ın başlaması için bekleyelim
+	mockScheduler.On("Start").Return(domain.ErrSchedulerAlreadyRunning)
+	mockAudit.On("Record", mock.MatchedBy(func(entry *domain.AuditEntry) bool {
+		return entry.Action == domain.AuditActionSchedulerStart && entry.Result == "already running"
+	})).Return(nil)
 
 	req := httptest.NewRequest(http.MethodPost, "/scheduler/start", nil)
 	w := httptest.NewRecorder()
@@ -78,14 +68,18 @@ func TestMessageHandler_StartScheduler_AlreadyRunning(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, "Scheduler is already running", response["error"])
 
-	mockScheduler.Stop()
+	mockAudit.AssertExpectations(t)
 }
 
 func TestMessageHandler_StopScheduler_NotRunning(t *testing.T) {
 	mockService := &mocks.MockMessageService{}
-	mockScheduler := scheduler.NewSchedulerService(mockService, time.Second, &mockLogger{})
+	mockScheduler := &mocks.MockSchedulerController{}
+
+	mockAudit := &mocks.MockAuditService{}
+	handler := NewMessageHandler(mockService, &mocks.MockTemplateService{}, mockAudit, mockScheduler, domain.DefaultMessageRules())
 
-	handler := NewMessageHandler(mockService, mockScheduler)
+	mockScheduler.On("Stop").Return(domain.ErrSchedulerNotRunning)
+	mockAudit.On("Record", mock.AnythingOfType("*domain.AuditEntry")).Return(nil)
 
 	req := httptest.NewRequest(http.MethodPost, "/scheduler/stop", nil)
 	w := httptest.NewRecorder()
@@ -98,4 +92,274 @@ func TestMessageHandler_StopScheduler_NotRunning(t *testing.T) {
 	err := json.NewDecoder(w.Body).Decode(&response)
 	assert.NoError(t, err)
 	assert.Equal(t, "Scheduler is not running", response["error"])
+	mockAudit.AssertExpectations(t)
+}
+
+func TestMessageHandler_StartStopScheduler_Audited(t *testing.T) {
+	mockService := &mocks.MockMessageService{}
+	mockScheduler := &mocks.MockSchedulerController{}
+
+	mockAudit := &mocks.MockAuditService{}
+	handler := NewMessageHandler(mockService, &mocks.MockTemplateService{}, mockAudit, mockScheduler, domain.DefaultMessageRules())
+
+	mockScheduler.On("Start").Return(nil)
+	mockScheduler.On("Stop").Return(nil)
+	for _, action := range []string{domain.AuditActionSchedulerStart, domain.AuditActionSchedulerStop} {
+		action := action
+		mockAudit.On("Record", mock.MatchedBy(func(entry *domain.AuditEntry) bool {
+			return entry.Action == action && entry.Actor == domain.AdminActor && entry.Result == "ok"
+		})).Return(nil).Once()
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/scheduler/start", nil)
+	req = req.WithContext(WithPrincipal(req.Context(), domain.AdminPrincipal()))
+	w := httptest.NewRecorder()
+
+	handler.StartScheduler(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	handler.StopScheduler(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	mockScheduler.AssertExpectations(t)
+	mockAudit.AssertExpectations(t)
+}
+
+func TestMessageHandler_GetSchedulerStatus(t *testing.T) {
+	mockScheduler := &mocks.MockSchedulerController{}
+	handler := NewMessageHandler(&mocks.MockMessageService{}, &mocks.MockTemplateService{}, &mocks.MockAuditService{}, mockScheduler, domain.DefaultMessageRules())
+
+	lastTick := time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)
+	mockScheduler.On("Status").Return(&domain.SchedulerStatus{
+		Running:           true,
+		Leader:            "api-1",
+		Interval:          5 * time.Second,
+		LastTickAt:        &lastTick,
+		LastTickDuration:  250 * time.Millisecond,
+		LastTickPublished: 12,
+		TotalPublished:    340,
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/scheduler/status", nil)
+	w := httptest.NewRecorder()
+
+	handler.GetSchedulerStatus(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	err := json.NewDecoder(w.Body).Decode(&response)
+	assert.NoError(t, err)
+	assert.Equal(t, true, response["running"])
+	assert.Equal(t, "5s", response["interval"])
+	assert.Equal(t, "250ms", response["last_tick_duration"])
+	assert.Equal(t, "2024-01-02T10:00:00Z", response["last_tick_at"])
+	assert.Equal(t, float64(12), response["last_tick_published"])
+	assert.Equal(t, float64(340), response["total_published"])
+}
+
+func TestMessageHandler_SetSchedulerInterval(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		setupMock      func(*mocks.MockSchedulerController, *mocks.MockAuditService)
+		expectedStatus int
+	}{
+		{
+			name: "Valid interval",
+			body: `{"interval":"30s"}`,
+			setupMock: func(s *mocks.MockSchedulerController, a *mocks.MockAuditService) {
+				s.On("SetInterval", 30*time.Second).Return(nil)
+				s.On("Status").Return(&domain.SchedulerStatus{Interval: 30 * time.Second}, nil)
+				a.On("Record", mock.MatchedBy(func(entry *domain.AuditEntry) bool {
+					return entry.Action == domain.AuditActionSchedulerInterval && entry.Result == "ok: 30s"
+				})).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Unparseable interval",
+			body:           `{"interval":"often"}`,
+			setupMock:      func(*mocks.MockSchedulerController, *mocks.MockAuditService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Out of range interval",
+			body: `{"interval":"10ms"}`,
+			setupMock: func(s *mocks.MockSchedulerController, a *mocks.MockAuditService) {
+				s.On("SetInterval", 10*time.Millisecond).Return(domain.ErrInvalidSchedulerInterval)
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockScheduler := &mocks.MockSchedulerController{}
+			mockAudit := &mocks.MockAuditService{}
+			handler := NewMessageHandler(&mocks.MockMessageService{}, &mocks.MockTemplateService{}, mockAudit, mockScheduler, domain.DefaultMessageRules())
+			tt.setupMock(mockScheduler, mockAudit)
+
+			req := httptest.NewRequest(http.MethodPut, "/scheduler/interval", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+
+			handler.SetSchedulerInterval(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockScheduler.AssertExpectations(t)
+			mockAudit.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMessageHandler_CreateMessage_FromTemplate(t *testing.T) {
+	mockService := &mocks.MockMessageService{}
+	mockTemplates := &mocks.MockTemplateService{}
+	mockScheduler := &mocks.MockSchedulerController{}
+
+	handler := NewMessageHandler(mockService, mockTemplates, &mocks.MockAuditService{}, mockScheduler, domain.DefaultMessageRules())
+
+	mockTemplates.On("Render", int64(7), int64(1), "tr", map[string]string{"code": "123456"}).Return("Kodunuz: 123456", nil)
+	tenant := &domain.Tenant{ID: 7}
+	mockService.On("CreateMessage", tenant, mock.MatchedBy(func(msg *domain.Message) bool {
+		return msg.To == "+905551234567" && msg.Content == "Kodunuz: 123456" && msg.Status == domain.StatusPending &&
+			msg.Priority == domain.PriorityCritical
+	})).Return(nil)
+
+	body := `{"to":"05551234567","template_id":1,"locale":"tr","variables":{"code":"123456"},"priority":"critical"}`
+	req := httptest.NewRequest(http.MethodPost, "/messages", strings.NewReader(body))
+	req = req.WithContext(WithTenant(req.Context(), tenant))
+	w := httptest.NewRecorder()
+
+	handler.CreateMessage(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	mockTemplates.AssertExpectations(t)
+	mockService.AssertExpectations(t)
+}
+
+func TestMessageHandler_CreateMessage_InvalidRecipient(t *testing.T) {
+	mockService := &mocks.MockMessageService{}
+	mockScheduler := &mocks.MockSchedulerController{}
+
+	handler := NewMessageHandler(mockService, &mocks.MockTemplateService{}, &mocks.MockAuditService{}, mockScheduler, domain.DefaultMessageRules())
+
+	req := httptest.NewRequest(http.MethodPost, "/messages", strings.NewReader(`{"to":"123","content":"Hello"}`))
+	req = req.WithContext(WithTenant(req.Context(), &domain.Tenant{ID: 7}))
+	w := httptest.NewRecorder()
+
+	handler.CreateMessage(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	mockService.AssertNotCalled(t, "CreateMessage", mock.Anything, mock.Anything)
+}
+
+func TestMessageHandler_CreateMessage_Email(t *testing.T) {
+	mockService := &mocks.MockMessageService{}
+	mockScheduler := &mocks.MockSchedulerController{}
+
+	handler := NewMessageHandler(mockService, &mocks.MockTemplateService{}, &mocks.MockAuditService{}, mockScheduler, domain.DefaultMessageRules())
+
+	tenant := &domain.Tenant{ID: 7}
+	mockService.On("CreateMessage", tenant, mock.MatchedBy(func(msg *domain.Message) bool {
+		return msg.Channel == domain.ChannelEmail && msg.To == "jane@example.com" && msg.Subject == "Siparişiniz"
+	})).Return(nil)
+
+	body := `{"channel":"email","to":"jane@Example.com","subject":"Siparişiniz","content":"Siparişiniz kargoda."}`
+	req := httptest.NewRequest(http.MethodPost, "/messages", strings.NewReader(body))
+	req = req.WithContext(WithTenant(req.Context(), tenant))
+	w := httptest.NewRecorder()
+
+	handler.CreateMessage(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestMessageHandler_CreateMessage_Fallback(t *testing.T) {
+	mockService := &mocks.MockMessageService{}
+
+	handler := NewMessageHandler(mockService, &mocks.MockTemplateService{}, &mocks.MockAuditService{}, &mocks.MockSchedulerController{}, domain.DefaultMessageRules())
+
+	tenant := &domain.Tenant{ID: 7}
+	mockService.On("CreateMessage", tenant, mock.MatchedBy(func(msg *domain.Message) bool {
+		return msg.Channel == domain.ChannelPush && len(msg.Fallback) == 2 &&
+			msg.Fallback[0] == domain.FallbackStep{Channel: domain.ChannelSMS, To: "+905551234567", WaitSeconds: 30}
+	})).Return(nil)
+
+	body := `{"channel":"push","to":"token-1","content":"Kodunuz: 123456","fallback":[
+		{"channel":"sms","to":"05551234567","wait_seconds":30},
+		{"channel":"email","to":"jane@example.com","subject":"Kod","wait_seconds":60}]}`
+	req := httptest.NewRequest(http.MethodPost, "/messages", strings.NewReader(body))
+	req = req.WithContext(WithTenant(req.Context(), tenant))
+	w := httptest.NewRecorder()
+
+	handler.CreateMessage(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestMessageHandler_CreateMessage_InvalidChannel(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{name: "unknown channel", body: `{"channel":"fax","to":"+905551234567","content":"Hello"}`},
+		{name: "subject on sms", body: `{"to":"+905551234567","subject":"Hi","content":"Hello"}`},
+		{name: "phone number on email", body: `{"channel":"email","to":"+905551234567","content":"Hello"}`},
+		{name: "invalid fallback recipient", body: `{"channel":"push","to":"token-1","content":"Hello","fallback":[{"channel":"sms","to":"123","wait_seconds":30}]}`},
+		{name: "fallback without wait", body: `{"channel":"push","to":"token-1","content":"Hello","fallback":[{"channel":"sms","to":"+905551234567"}]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mocks.MockMessageService{}
+			handler := NewMessageHandler(mockService, &mocks.MockTemplateService{}, &mocks.MockAuditService{}, &mocks.MockSchedulerController{}, domain.DefaultMessageRules())
+
+			req := httptest.NewRequest(http.MethodPost, "/messages", strings.NewReader(tt.body))
+			req = req.WithContext(WithTenant(req.Context(), &domain.Tenant{ID: 7}))
+			w := httptest.NewRecorder()
+
+			handler.CreateMessage(w, req)
+
+			assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+			mockService.AssertNotCalled(t, "CreateMessage", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestMessageHandler_CreateMessage_InvalidPriority(t *testing.T) {
+	mockService := &mocks.MockMessageService{}
+	mockScheduler := &mocks.MockSchedulerController{}
+
+	handler := NewMessageHandler(mockService, &mocks.MockTemplateService{}, &mocks.MockAuditService{}, mockScheduler, domain.DefaultMessageRules())
+
+	req := httptest.NewRequest(http.MethodPost, "/messages", strings.NewReader(`{"to":"+905551234567","content":"Hello","priority":"urgent"}`))
+	req = req.WithContext(WithTenant(req.Context(), &domain.Tenant{ID: 7}))
+	w := httptest.NewRecorder()
+
+	handler.CreateMessage(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	mockService.AssertNotCalled(t, "CreateMessage", mock.Anything, mock.Anything)
+}
+
+func TestMessageHandler_CreateMessage_QuotaExceeded(t *testing.T) {
+	mockService := &mocks.MockMessageService{}
+	mockScheduler := &mocks.MockSchedulerController{}
+
+	handler := NewMessageHandler(mockService, &mocks.MockTemplateService{}, &mocks.MockAuditService{}, mockScheduler, domain.DefaultMessageRules())
+
+	tenant := &domain.Tenant{ID: 7, DailyQuota: 10}
+	mockService.On("CreateMessage", tenant, mock.AnythingOfType("*domain.Message")).Return(domain.ErrQuotaExceeded)
+
+	req := httptest.NewRequest(http.MethodPost, "/messages", strings.NewReader(`{"to":"+905551234567","content":"Hello"}`))
+	req = req.WithContext(WithTenant(req.Context(), tenant))
+	w := httptest.NewRecorder()
+
+	handler.CreateMessage(w, req)
+
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
 }
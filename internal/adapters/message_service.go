@@ -1,6 +1,9 @@
 package adapters
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/ercancavusoglu/messaging/internal/domain"
 	"github.com/ercancavusoglu/messaging/internal/ports"
 )
@@ -21,15 +24,49 @@ func NewMessageService(repo ports.Repository, webhookClient ports.WebhookClient,
 	}
 }
 
-func (s *messageService) GetPendingMessages() ([]*domain.Message, error) {
-	return s.repo.GetByStatus(domain.StatusPending)
+func (s *messageService) GetPendingMessages(limit int) ([]*domain.Message, error) {
+	return s.repo.GetPendingMessages(limit)
+}
+
+func (s *messageService) GetSendedMessages(tenantID int64) ([]*domain.Message, error) {
+	return s.repo.ForTenant(tenantID).GetByStatus(domain.StatusSent)
 }
 
-func (s *messageService) GetSendedMessages() ([]*domain.Message, error) {
-	return s.repo.GetByStatus(domain.StatusSent)
+func (s *messageService) CreateMessage(tenant *domain.Tenant, msg *domain.Message) error {
+	repo := s.repo.ForTenant(tenant.ID)
+
+	if tenant.HasQuota() {
+		count, err := repo.CountCreatedSince(domain.QuotaDayStart(time.Now()))
+		if err != nil {
+			return fmt.Errorf("failed to check daily quota: %v", err)
+		}
+
+		if count >= tenant.DailyQuota {
+			return domain.ErrQuotaExceeded
+		}
+	}
+
+	msg.TenantID = tenant.ID
+	return repo.Create(msg)
 }
 
+// Publish hands a claimed message to the consumers; when that fails the message goes back to pending
+// so a later tick retries it
 func (s *messageService) Publish(msg *domain.Message) error {
-	s.eventBus.Publish(domain.NewMessageQueuedEvent(msg))
+	if err := s.eventBus.Publish(domain.NewMessageQueuedEvent(msg)); err != nil {
+		if updateErr := s.repo.UpdateStatus(msg.ID, domain.StatusPending, "", ""); updateErr != nil {
+			return fmt.Errorf("failed to publish message: %v (and failed to release it: %v)", err, updateErr)
+		}
+		return fmt.Errorf("failed to publish message: %v", err)
+	}
+
 	return nil
 }
+
+func (s *messageService) RequeueStale(visibilityTimeout time.Duration) (int, error) {
+	return s.repo.RequeueStale(visibilityTimeout)
+}
+
+func (s *messageService) CountInFlight() (int, error) {
+	return s.repo.CountInFlight()
+}
@@ -32,9 +32,9 @@ func TestMessageService_GetPendingMessages(t *testing.T) {
 	service := NewMessageService(mockRepo, mockWebhook, mockCache, mockEventBus)
 
 	expectedMessages := []*domain.Message{createTestMessage()}
-	mockRepo.On("GetByStatus", domain.StatusPending).Return(expectedMessages, nil)
+	mockRepo.On("GetPendingMessages", 50).Return(expectedMessages, nil)
 
-	messages, err := service.GetPendingMessages()
+	messages, err := service.GetPendingMessages(50)
 	assert.NoError(t, err)
 	assert.Equal(t, expectedMessages, messages)
 	mockRepo.AssertExpectations(t)
@@ -49,9 +49,10 @@ func TestMessageService_GetSendedMessages(t *testing.T) {
 	service := NewMessageService(mockRepo, mockWebhook, mockCache, mockEventBus)
 
 	expectedMessages := []*domain.Message{createTestMessage()}
+	mockRepo.On("ForTenant", int64(7)).Return()
 	mockRepo.On("GetByStatus", domain.StatusSent).Return(expectedMessages, nil)
 
-	messages, err := service.GetSendedMessages()
+	messages, err := service.GetSendedMessages(7)
 	assert.NoError(t, err)
 	assert.Equal(t, expectedMessages, messages)
 	mockRepo.AssertExpectations(t)
@@ -72,3 +73,59 @@ func TestMessageService_Publish(t *testing.T) {
 	assert.NoError(t, err)
 	mockEventBus.AssertExpectations(t)
 }
+
+func TestMessageService_Publish_ReleasesMessageOnFailure(t *testing.T) {
+	mockRepo := &mocks.MockRepository{}
+	mockWebhook := &mocks.MockWebhookClient{}
+	mockCache := &mocks.MockCache{}
+	mockEventBus := &mocks.MockEventBus{}
+
+	service := NewMessageService(mockRepo, mockWebhook, mockCache, mockEventBus)
+
+	msg := createTestMessage()
+	mockEventBus.On("Publish", mock.AnythingOfType("domain.MessageQueuedEvent")).Return(assert.AnError)
+	mockRepo.On("UpdateStatus", msg.ID, domain.StatusPending, "", "").Return(nil)
+
+	// Kuyruğa gönderilemeyen mesaj bir sonraki turda tekrar denenmek üzere pending'e dönmeli
+	err := service.Publish(msg)
+	assert.Error(t, err)
+	mockEventBus.AssertExpectations(t)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestMessageService_CreateMessage(t *testing.T) {
+	mockRepo := &mocks.MockRepository{}
+	mockWebhook := &mocks.MockWebhookClient{}
+	mockCache := &mocks.MockCache{}
+	mockEventBus := &mocks.MockEventBus{}
+
+	service := NewMessageService(mockRepo, mockWebhook, mockCache, mockEventBus)
+
+	msg := createTestMessage()
+	mockRepo.On("ForTenant", int64(7)).Return()
+	mockRepo.On("Create", msg).Return(nil)
+
+	err := service.CreateMessage(&domain.Tenant{ID: 7}, msg)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), msg.TenantID)
+
+	// Kota tanımlı değilse sayım yapılmamalı
+	mockRepo.AssertNotCalled(t, "CountCreatedSince", mock.Anything)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestMessageService_CreateMessage_QuotaExceeded(t *testing.T) {
+	mockRepo := &mocks.MockRepository{}
+	mockWebhook := &mocks.MockWebhookClient{}
+	mockCache := &mocks.MockCache{}
+	mockEventBus := &mocks.MockEventBus{}
+
+	service := NewMessageService(mockRepo, mockWebhook, mockCache, mockEventBus)
+
+	mockRepo.On("ForTenant", int64(7)).Return()
+	mockRepo.On("CountCreatedSince", mock.AnythingOfType("time.Time")).Return(100, nil)
+
+	err := service.CreateMessage(&domain.Tenant{ID: 7, DailyQuota: 100}, createTestMessage())
+	assert.ErrorIs(t, err, domain.ErrQuotaExceeded)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
@@ -0,0 +1,72 @@
+package mocks
+
+import (
+	"time"
+
+	"github.com/ercancavusoglu/messaging/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockCallbackRepository struct {
+	mock.Mock
+}
+
+func (m *MockCallbackRepository) Create(subscription *domain.CallbackSubscription) error {
+	args := m.Called(subscription)
+	return args.Error(0)
+}
+
+func (m *MockCallbackRepository) Get(tenantID, id int64) (*domain.CallbackSubscription, error) {
+	args := m.Called(tenantID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.CallbackSubscription), args.Error(1)
+}
+
+func (m *MockCallbackRepository) List(tenantID int64) ([]*domain.CallbackSubscription, error) {
+	args := m.Called(tenantID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.CallbackSubscription), args.Error(1)
+}
+
+func (m *MockCallbackRepository) Delete(tenantID, id int64) error {
+	args := m.Called(tenantID, id)
+	return args.Error(0)
+}
+
+func (m *MockCallbackRepository) ListForEvent(tenantID int64, eventName string) ([]*domain.CallbackSubscription, error) {
+	args := m.Called(tenantID, eventName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.CallbackSubscription), args.Error(1)
+}
+
+func (m *MockCallbackRepository) CreateDelivery(delivery *domain.CallbackDelivery) error {
+	args := m.Called(delivery)
+	return args.Error(0)
+}
+
+func (m *MockCallbackRepository) ClaimDueDeliveries(limit int, lease time.Duration) ([]*domain.CallbackDelivery, error) {
+	args := m.Called(limit, lease)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.CallbackDelivery), args.Error(1)
+}
+
+func (m *MockCallbackRepository) UpdateDelivery(delivery *domain.CallbackDelivery) error {
+	args := m.Called(delivery)
+	return args.Error(0)
+}
+
+func (m *MockCallbackRepository) ListDeliveries(tenantID, subscriptionID int64, limit int) ([]*domain.CallbackDelivery, error) {
+	args := m.Called(tenantID, subscriptionID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.CallbackDelivery), args.Error(1)
+}
@@ -0,0 +1,52 @@
+package mocks
+
+import (
+	"github.com/ercancavusoglu/messaging/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockCallbackService struct {
+	mock.Mock
+}
+
+func (m *MockCallbackService) Create(tenantID int64, subscription *domain.CallbackSubscription) error {
+	args := m.Called(tenantID, subscription)
+	return args.Error(0)
+}
+
+func (m *MockCallbackService) Get(tenantID, id int64) (*domain.CallbackSubscription, error) {
+	args := m.Called(tenantID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.CallbackSubscription), args.Error(1)
+}
+
+func (m *MockCallbackService) List(tenantID int64) ([]*domain.CallbackSubscription, error) {
+	args := m.Called(tenantID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.CallbackSubscription), args.Error(1)
+}
+
+func (m *MockCallbackService) Delete(tenantID, id int64) error {
+	args := m.Called(tenantID, id)
+	return args.Error(0)
+}
+
+func (m *MockCallbackService) Deliveries(tenantID, id int64) ([]*domain.CallbackDelivery, error) {
+	args := m.Called(tenantID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.CallbackDelivery), args.Error(1)
+}
+
+func (m *MockCallbackService) Ping(tenantID, id int64) (*domain.CallbackDelivery, error) {
+	args := m.Called(tenantID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.CallbackDelivery), args.Error(1)
+}
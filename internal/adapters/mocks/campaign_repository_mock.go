@@ -0,0 +1,46 @@
+package mocks
+
+import (
+	"github.com/ercancavusoglu/messaging/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockCampaignRepository struct {
+	mock.Mock
+}
+
+func (m *MockCampaignRepository) Create(campaign *domain.Campaign, messages []*domain.Message, dailyQuota int) error {
+	args := m.Called(campaign, messages, dailyQuota)
+	return args.Error(0)
+}
+
+func (m *MockCampaignRepository) Get(tenantID, id int64) (*domain.Campaign, error) {
+	args := m.Called(tenantID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Campaign), args.Error(1)
+}
+
+func (m *MockCampaignRepository) List(tenantID int64) ([]*domain.Campaign, error) {
+	args := m.Called(tenantID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Campaign), args.Error(1)
+}
+
+func (m *MockCampaignRepository) UpdateStatus(campaign *domain.Campaign, from domain.CampaignStatus) error {
+	args := m.Called(campaign, from)
+	return args.Error(0)
+}
+
+func (m *MockCampaignRepository) CancelPending(campaign *domain.Campaign) (int, error) {
+	args := m.Called(campaign)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockCampaignRepository) ApplyProgress(campaignID, messageID int64, eventName string, delta domain.CampaignProgress) error {
+	args := m.Called(campaignID, messageID, eventName, delta)
+	return args.Error(0)
+}
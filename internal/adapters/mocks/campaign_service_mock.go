@@ -0,0 +1,56 @@
+package mocks
+
+import (
+	"github.com/ercancavusoglu/messaging/internal/domain"
+	"github.com/ercancavusoglu/messaging/internal/ports"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockCampaignService struct {
+	mock.Mock
+}
+
+func (m *MockCampaignService) Create(tenant *domain.Tenant, campaign *domain.Campaign) error {
+	args := m.Called(tenant, campaign)
+	return args.Error(0)
+}
+
+func (m *MockCampaignService) Get(tenantID, id int64) (*domain.Campaign, error) {
+	args := m.Called(tenantID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Campaign), args.Error(1)
+}
+
+func (m *MockCampaignService) List(tenantID int64) ([]*domain.Campaign, error) {
+	args := m.Called(tenantID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Campaign), args.Error(1)
+}
+
+func (m *MockCampaignService) Pause(tenantID, id int64) (*domain.Campaign, error) {
+	return m.transition(m.Called(tenantID, id))
+}
+
+func (m *MockCampaignService) Resume(tenantID, id int64) (*domain.Campaign, error) {
+	return m.transition(m.Called(tenantID, id))
+}
+
+func (m *MockCampaignService) Cancel(tenantID, id int64) (*domain.Campaign, error) {
+	return m.transition(m.Called(tenantID, id))
+}
+
+func (m *MockCampaignService) HandleEvent(event ports.Event) error {
+	args := m.Called(event)
+	return args.Error(0)
+}
+
+func (m *MockCampaignService) transition(args mock.Arguments) (*domain.Campaign, error) {
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Campaign), args.Error(1)
+}
@@ -14,10 +14,11 @@ func (m *MockEventBus) Publish(event ports.Event) error {
 	return args.Error(0)
 }
 
-func (m *MockEventBus) Subscribe(eventName string, handler ports.EventHandler) {
-	m.Called(eventName, handler)
-}
-
-func (m *MockEventBus) Unsubscribe(eventName string, handler ports.EventHandler) {
-	m.Called(eventName, handler)
+// Subscribe returns the subscription given to Return, if any
+func (m *MockEventBus) Subscribe(eventName string, handler ports.EventHandler) ports.Subscription {
+	args := m.Called(eventName, handler)
+	if len(args) == 0 || args.Get(0) == nil {
+		return nil
+	}
+	return args.Get(0).(ports.Subscription)
 }
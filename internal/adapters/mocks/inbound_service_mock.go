@@ -0,0 +1,15 @@
+package mocks
+
+import (
+	"github.com/ercancavusoglu/messaging/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockInboundService struct {
+	mock.Mock
+}
+
+func (m *MockInboundService) Receive(message *domain.InboundMessage) error {
+	args := m.Called(message)
+	return args.Error(0)
+}
@@ -1,6 +1,8 @@
 package mocks
 
 import (
+	"time"
+
 	"github.com/ercancavusoglu/messaging/internal/domain"
 	"github.com/stretchr/testify/mock"
 )
@@ -9,22 +11,27 @@ type MockMessageService struct {
 	mock.Mock
 }
 
-func (m *MockMessageService) GetPendingMessages() ([]*domain.Message, error) {
-	args := m.Called()
+func (m *MockMessageService) GetPendingMessages(limit int) ([]*domain.Message, error) {
+	args := m.Called(limit)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]*domain.Message), args.Error(1)
 }
 
-func (m *MockMessageService) GetSendedMessages() ([]*domain.Message, error) {
-	args := m.Called()
+func (m *MockMessageService) GetSendedMessages(tenantID int64) ([]*domain.Message, error) {
+	args := m.Called(tenantID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]*domain.Message), args.Error(1)
 }
 
+func (m *MockMessageService) CreateMessage(tenant *domain.Tenant, msg *domain.Message) error {
+	args := m.Called(tenant, msg)
+	return args.Error(0)
+}
+
 func (m *MockMessageService) QueueMessage(msg *domain.Message) error {
 	args := m.Called(msg)
 	return args.Error(0)
@@ -42,3 +49,13 @@ func (m *MockMessageService) Publish(msg *domain.Message) error {
 	args := m.Called(msg)
 	return args.Error(0)
 }
+
+func (m *MockMessageService) RequeueStale(visibilityTimeout time.Duration) (int, error) {
+	args := m.Called(visibilityTimeout)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockMessageService) CountInFlight() (int, error) {
+	args := m.Called()
+	return args.Int(0), args.Error(1)
+}
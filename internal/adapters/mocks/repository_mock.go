@@ -1,7 +1,10 @@
 package mocks
 
 import (
+	"time"
+
 	"github.com/ercancavusoglu/messaging/internal/domain"
+	"github.com/ercancavusoglu/messaging/internal/ports"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -9,19 +12,73 @@ type MockRepository struct {
 	mock.Mock
 }
 
+// ForTenant records the tenant and returns the same mock so expectations stay in one place
+func (m *MockRepository) ForTenant(tenantID int64) ports.Repository {
+	m.Called(tenantID)
+	return m
+}
+
+func (m *MockRepository) CountCreatedSince(since time.Time) (int, error) {
+	args := m.Called(since)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockRepository) CountByStatus() (map[domain.MessageStatus]int, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[domain.MessageStatus]int), args.Error(1)
+}
+
+func (m *MockRepository) RequeueFailed(since time.Time) (int, error) {
+	args := m.Called(since)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockRepository) TakeQueued(id int64) (bool, error) {
+	args := m.Called(id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRepository) CountInFlight() (int, error) {
+	args := m.Called()
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockRepository) RequeueStale(visibilityTimeout time.Duration) (int, error) {
+	args := m.Called(visibilityTimeout)
+	return args.Int(0), args.Error(1)
+}
+
 func (m *MockRepository) Save(message *domain.Message) error {
 	args := m.Called(message)
 	return args.Error(0)
 }
 
-func (m *MockRepository) GetPendingMessages() ([]*domain.Message, error) {
-	args := m.Called()
+func (m *MockRepository) Create(message *domain.Message) error {
+	args := m.Called(message)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetPendingMessages(limit int) ([]*domain.Message, error) {
+	args := m.Called(limit)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]*domain.Message), args.Error(1)
 }
 
+func (m *MockRepository) MarkDelivered(id int64) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockRepository) CreateFallback(message *domain.Message) error {
+	args := m.Called(message)
+	return args.Error(0)
+}
+
 func (m *MockRepository) UpdateStatus(id int64, status domain.MessageStatus, messageID string, provider string) error {
 	args := m.Called(id, status, messageID, provider)
 	return args.Error(0)
@@ -35,6 +92,14 @@ func (m *MockRepository) GetByStatus(status domain.MessageStatus) ([]*domain.Mes
 	return args.Get(0).([]*domain.Message), args.Error(1)
 }
 
+func (m *MockRepository) GetByProviderMessageID(provider, messageID string) (*domain.Message, error) {
+	args := m.Called(provider, messageID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Message), args.Error(1)
+}
+
 func (m *MockRepository) UpdateMessageID(id int64, messageID string) error {
 	args := m.Called(id, messageID)
 	return args.Error(0)
@@ -0,0 +1,40 @@
+package mocks
+
+import (
+	"time"
+
+	"github.com/ercancavusoglu/messaging/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockSchedulerController struct {
+	mock.Mock
+}
+
+func (m *MockSchedulerController) Start() error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+func (m *MockSchedulerController) Stop() error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+func (m *MockSchedulerController) IsRunning() bool {
+	args := m.Called()
+	return args.Bool(0)
+}
+
+func (m *MockSchedulerController) Status() (*domain.SchedulerStatus, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.SchedulerStatus), args.Error(1)
+}
+
+func (m *MockSchedulerController) SetInterval(interval time.Duration) error {
+	args := m.Called(interval)
+	return args.Error(0)
+}
@@ -0,0 +1,61 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/ercancavusoglu/messaging/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockSchedulerState struct {
+	mock.Mock
+}
+
+func (m *MockSchedulerState) SetDesiredRunning(ctx context.Context, running bool) error {
+	args := m.Called(ctx, running)
+	return args.Error(0)
+}
+
+func (m *MockSchedulerState) DesiredRunning(ctx context.Context) (bool, error) {
+	args := m.Called(ctx)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockSchedulerState) MarkActive(ctx context.Context, holder string, ttl time.Duration) error {
+	args := m.Called(ctx, holder, ttl)
+	return args.Error(0)
+}
+
+func (m *MockSchedulerState) ClearActive(ctx context.Context, holder string) error {
+	args := m.Called(ctx, holder)
+	return args.Error(0)
+}
+
+func (m *MockSchedulerState) ActiveHolder(ctx context.Context) (string, error) {
+	args := m.Called(ctx)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockSchedulerState) SaveStatus(ctx context.Context, status domain.SchedulerStatus) error {
+	args := m.Called(ctx, status)
+	return args.Error(0)
+}
+
+func (m *MockSchedulerState) Status(ctx context.Context) (*domain.SchedulerStatus, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.SchedulerStatus), args.Error(1)
+}
+
+func (m *MockSchedulerState) SetInterval(ctx context.Context, interval time.Duration) error {
+	args := m.Called(ctx, interval)
+	return args.Error(0)
+}
+
+func (m *MockSchedulerState) Interval(ctx context.Context) (time.Duration, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(time.Duration), args.Error(1)
+}
@@ -0,0 +1,43 @@
+package mocks
+
+import (
+	"github.com/ercancavusoglu/messaging/internal/domain"
+	"github.com/ercancavusoglu/messaging/internal/ports"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockSuppressionRepository struct {
+	mock.Mock
+}
+
+// ForTenant records the tenant and returns the same mock so expectations stay in one place
+func (m *MockSuppressionRepository) ForTenant(tenantID int64) ports.SuppressionRepository {
+	m.Called(tenantID)
+	return m
+}
+
+func (m *MockSuppressionRepository) Add(suppression *domain.Suppression) error {
+	args := m.Called(suppression)
+	return args.Error(0)
+}
+
+func (m *MockSuppressionRepository) Remove(recipient string) error {
+	args := m.Called(recipient)
+	return args.Error(0)
+}
+
+func (m *MockSuppressionRepository) Get(recipient string) (*domain.Suppression, error) {
+	args := m.Called(recipient)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Suppression), args.Error(1)
+}
+
+func (m *MockSuppressionRepository) List() ([]*domain.Suppression, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Suppression), args.Error(1)
+}
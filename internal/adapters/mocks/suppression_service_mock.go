@@ -0,0 +1,56 @@
+package mocks
+
+import (
+	"io"
+
+	"github.com/ercancavusoglu/messaging/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockSuppressionService struct {
+	mock.Mock
+}
+
+func (m *MockSuppressionService) IsSuppressed(tenantID int64, recipient string) (bool, error) {
+	args := m.Called(tenantID, recipient)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockSuppressionService) Add(tenantID int64, recipient, reason, source string) (*domain.Suppression, error) {
+	args := m.Called(tenantID, recipient, reason, source)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Suppression), args.Error(1)
+}
+
+func (m *MockSuppressionService) Remove(tenantID int64, recipient string) error {
+	args := m.Called(tenantID, recipient)
+	return args.Error(0)
+}
+
+func (m *MockSuppressionService) Get(tenantID int64, recipient string) (*domain.Suppression, error) {
+	args := m.Called(tenantID, recipient)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Suppression), args.Error(1)
+}
+
+func (m *MockSuppressionService) List(tenantID int64) ([]*domain.Suppression, error) {
+	args := m.Called(tenantID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Suppression), args.Error(1)
+}
+
+func (m *MockSuppressionService) Import(tenantID int64, r io.Reader) (int, error) {
+	args := m.Called(tenantID, r)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockSuppressionService) Export(tenantID int64, w io.Writer) error {
+	args := m.Called(tenantID, w)
+	return args.Error(0)
+}
@@ -0,0 +1,48 @@
+package mocks
+
+import (
+	"github.com/ercancavusoglu/messaging/internal/domain"
+	"github.com/ercancavusoglu/messaging/internal/ports"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockTemplateRepository struct {
+	mock.Mock
+}
+
+// ForTenant records the tenant and returns the same mock so expectations stay in one place
+func (m *MockTemplateRepository) ForTenant(tenantID int64) ports.TemplateRepository {
+	m.Called(tenantID)
+	return m
+}
+
+func (m *MockTemplateRepository) Create(template *domain.Template) error {
+	args := m.Called(template)
+	return args.Error(0)
+}
+
+func (m *MockTemplateRepository) Update(template *domain.Template) error {
+	args := m.Called(template)
+	return args.Error(0)
+}
+
+func (m *MockTemplateRepository) Delete(id int64) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockTemplateRepository) Get(id int64) (*domain.Template, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Template), args.Error(1)
+}
+
+func (m *MockTemplateRepository) List() ([]*domain.Template, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Template), args.Error(1)
+}
@@ -0,0 +1,46 @@
+package mocks
+
+import (
+	"github.com/ercancavusoglu/messaging/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockTemplateService struct {
+	mock.Mock
+}
+
+func (m *MockTemplateService) Create(tenantID int64, template *domain.Template) error {
+	args := m.Called(tenantID, template)
+	return args.Error(0)
+}
+
+func (m *MockTemplateService) Update(tenantID int64, template *domain.Template) error {
+	args := m.Called(tenantID, template)
+	return args.Error(0)
+}
+
+func (m *MockTemplateService) Delete(tenantID, id int64) error {
+	args := m.Called(tenantID, id)
+	return args.Error(0)
+}
+
+func (m *MockTemplateService) Get(tenantID, id int64) (*domain.Template, error) {
+	args := m.Called(tenantID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Template), args.Error(1)
+}
+
+func (m *MockTemplateService) List(tenantID int64) ([]*domain.Template, error) {
+	args := m.Called(tenantID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Template), args.Error(1)
+}
+
+func (m *MockTemplateService) Render(tenantID, id int64, locale string, variables map[string]string) (string, error) {
+	args := m.Called(tenantID, id, locale, variables)
+	return args.String(0), args.Error(1)
+}
@@ -0,0 +1,49 @@
+package mocks
+
+import (
+	"github.com/ercancavusoglu/messaging/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockTenantService struct {
+	mock.Mock
+}
+
+func (m *MockTenantService) Create(tenant *domain.Tenant) (string, error) {
+	args := m.Called(tenant)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockTenantService) Update(tenant *domain.Tenant) error {
+	args := m.Called(tenant)
+	return args.Error(0)
+}
+
+func (m *MockTenantService) RotateAPIKey(id int64) (string, error) {
+	args := m.Called(id)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockTenantService) Get(id int64) (*domain.Tenant, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Tenant), args.Error(1)
+}
+
+func (m *MockTenantService) List() ([]*domain.Tenant, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Tenant), args.Error(1)
+}
+
+func (m *MockTenantService) Authenticate(apiKey string) (*domain.Tenant, error) {
+	args := m.Called(apiKey)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Tenant), args.Error(1)
+}
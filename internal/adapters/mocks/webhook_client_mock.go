@@ -9,8 +9,8 @@ type MockWebhookClient struct {
 	mock.Mock
 }
 
-func (m *MockWebhookClient) SendMessage(to, content string) (*domain.WebhookResponse, error) {
-	args := m.Called(to, content)
+func (m *MockWebhookClient) SendMessage(msg *domain.Message) (*domain.WebhookResponse, error) {
+	args := m.Called(msg)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -2,6 +2,7 @@ package cache
 
 import (
 	"context"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-redis/redis/v8"
@@ -14,17 +15,24 @@ type RedisClient interface {
 
 type RedisAdapter struct {
 	client RedisClient
+	ttl    atomic.Int64
 }
 
-func NewRedisAdapter(client RedisClient) *RedisAdapter {
-	return &RedisAdapter{
-		client: client,
-	}
+// NewRedisAdapter stores every value for ttl
+func NewRedisAdapter(client RedisClient, ttl time.Duration) *RedisAdapter {
+	adapter := &RedisAdapter{client: client}
+	adapter.SetTTL(ttl)
+	return adapter
+}
+
+// SetTTL changes the expiration of values stored from now on
+func (r *RedisAdapter) SetTTL(ttl time.Duration) {
+	r.ttl.Store(int64(ttl))
 }
 
 func (r *RedisAdapter) Set(key string, value interface{}) error {
 	ctx := context.Background()
-	return r.client.Set(ctx, key, value, 24*time.Hour).Err()
+	return r.client.Set(ctx, key, value, time.Duration(r.ttl.Load())).Err()
 }
 
 func (r *RedisAdapter) Get(key string) (interface{}, error) {
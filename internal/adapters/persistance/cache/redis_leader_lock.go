@@ -0,0 +1,88 @@
+package cache
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// acquireScript renews the lease when the caller already holds it and otherwise takes it only if it is free
+var acquireScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+if redis.call("SET", KEYS[1], ARGV[1], "NX", "PX", ARGV[2]) then
+	return 1
+end
+return 0
+`)
+
+// releaseScript deletes the key only when it still holds the caller's value, so an expired lease
+// taken over by another replica is never released by the previous holder
+var releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+type RedisLeaderLock struct {
+	client redis.Cmdable
+	key    string
+	id     string
+	ttl    time.Duration
+}
+
+// NewRedisLeaderLock creates a lease on key that expires after ttl unless renewed by Acquire
+func NewRedisLeaderLock(client redis.Cmdable, key string, ttl time.Duration) (*RedisLeaderLock, error) {
+	id, err := newHolderID()
+	if err != nil {
+		return nil, err
+	}
+
+	return &RedisLeaderLock{
+		client: client,
+		key:    key,
+		id:     id,
+		ttl:    ttl,
+	}, nil
+}
+
+func (l *RedisLeaderLock) Acquire(ctx context.Context) (bool, error) {
+	held, err := acquireScript.Run(ctx, l.client, []string{l.key}, l.id, l.ttl.Milliseconds()).Int()
+	if err != nil {
+		return false, fmt.Errorf("failed to acquire leader lock: %v", err)
+	}
+	return held == 1, nil
+}
+
+func (l *RedisLeaderLock) Release(ctx context.Context) error {
+	if err := releaseScript.Run(ctx, l.client, []string{l.key}, l.id).Err(); err != nil {
+		return fmt.Errorf("failed to release leader lock: %v", err)
+	}
+	return nil
+}
+
+func (l *RedisLeaderLock) ID() string {
+	return l.id
+}
+
+// newHolderID identifies this process in lock values and logs, e.g. "api-7d9f-4c2a1b3e"
+func newHolderID() (string, error) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+
+	buf := make([]byte, 4)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate lock holder id: %v", err)
+	}
+
+	return hostname + "-" + hex.EncodeToString(buf), nil
+}
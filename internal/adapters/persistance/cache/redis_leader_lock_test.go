@@ -0,0 +1,85 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+)
+
+func newMiniredisClient(t *testing.T) (*miniredis.Miniredis, *redis.Client) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return server, client
+}
+
+func TestRedisLeaderLock_SingleLeader(t *testing.T) {
+	server, client := newMiniredisClient(t)
+	ctx := context.Background()
+
+	first, err := NewRedisLeaderLock(client, "scheduler:leader", 3*time.Second)
+	assert.NoError(t, err)
+	second, err := NewRedisLeaderLock(client, "scheduler:leader", 3*time.Second)
+	assert.NoError(t, err)
+	assert.NotEqual(t, first.ID(), second.ID())
+
+	held, err := first.Acquire(ctx)
+	assert.NoError(t, err)
+	assert.True(t, held)
+
+	held, err = second.Acquire(ctx)
+	assert.NoError(t, err)
+	assert.False(t, held)
+
+	// Lider süresi dolmadan yenilerse liderliği korumalı
+	server.FastForward(2 * time.Second)
+	held, err = first.Acquire(ctx)
+	assert.NoError(t, err)
+	assert.True(t, held)
+
+	server.FastForward(2 * time.Second)
+	held, err = second.Acquire(ctx)
+	assert.NoError(t, err)
+	assert.False(t, held)
+}
+
+func TestRedisLeaderLock_ExpiredLeaseIsTakenOver(t *testing.T) {
+	server, client := newMiniredisClient(t)
+	ctx := context.Background()
+
+	first, _ := NewRedisLeaderLock(client, "scheduler:leader", 3*time.Second)
+	second, _ := NewRedisLeaderLock(client, "scheduler:leader", 3*time.Second)
+
+	held, _ := first.Acquire(ctx)
+	assert.True(t, held)
+
+	server.FastForward(4 * time.Second)
+
+	held, err := second.Acquire(ctx)
+	assert.NoError(t, err)
+	assert.True(t, held)
+
+	// Eski lider, başkasına geçmiş kilidi silmemeli
+	assert.NoError(t, first.Release(ctx))
+	held, _ = first.Acquire(ctx)
+	assert.False(t, held)
+}
+
+func TestRedisLeaderLock_Release(t *testing.T) {
+	_, client := newMiniredisClient(t)
+	ctx := context.Background()
+
+	first, _ := NewRedisLeaderLock(client, "scheduler:leader", time.Minute)
+	second, _ := NewRedisLeaderLock(client, "scheduler:leader", time.Minute)
+
+	first.Acquire(ctx)
+	assert.NoError(t, first.Release(ctx))
+
+	held, err := second.Acquire(ctx)
+	assert.NoError(t, err)
+	assert.True(t, held)
+}
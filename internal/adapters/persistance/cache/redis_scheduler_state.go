@@ -0,0 +1,132 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ercancavusoglu/messaging/internal/domain"
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	schedulerDesiredKey  = "scheduler:desired"
+	schedulerActiveKey   = "scheduler:active"
+	schedulerStatusKey   = "scheduler:status"
+	schedulerIntervalKey = "scheduler:interval"
+)
+
+type RedisSchedulerState struct {
+	client         redis.Cmdable
+	defaultRunning bool
+}
+
+// NewRedisSchedulerState stores the scheduler state in Redis; defaultRunning applies until
+// someone starts or stops the scheduler for the first time
+func NewRedisSchedulerState(client redis.Cmdable, defaultRunning bool) *RedisSchedulerState {
+	return &RedisSchedulerState{
+		client:         client,
+		defaultRunning: defaultRunning,
+	}
+}
+
+func (s *RedisSchedulerState) SetDesiredRunning(ctx context.Context, running bool) error {
+	value := "0"
+	if running {
+		value = "1"
+	}
+
+	if err := s.client.Set(ctx, schedulerDesiredKey, value, 0).Err(); err != nil {
+		return fmt.Errorf("failed to set scheduler state: %v", err)
+	}
+	return nil
+}
+
+func (s *RedisSchedulerState) DesiredRunning(ctx context.Context) (bool, error) {
+	value, err := s.client.Get(ctx, schedulerDesiredKey).Result()
+	if errors.Is(err, redis.Nil) {
+		return s.defaultRunning, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get scheduler state: %v", err)
+	}
+	return value == "1", nil
+}
+
+func (s *RedisSchedulerState) MarkActive(ctx context.Context, holder string, ttl time.Duration) error {
+	if err := s.client.Set(ctx, schedulerActiveKey, holder, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to mark scheduler active: %v", err)
+	}
+	return nil
+}
+
+func (s *RedisSchedulerState) ClearActive(ctx context.Context, holder string) error {
+	if err := releaseScript.Run(ctx, s.client, []string{schedulerActiveKey}, holder).Err(); err != nil {
+		return fmt.Errorf("failed to clear scheduler active mark: %v", err)
+	}
+	return nil
+}
+
+func (s *RedisSchedulerState) ActiveHolder(ctx context.Context) (string, error) {
+	holder, err := s.client.Get(ctx, schedulerActiveKey).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get active scheduler: %v", err)
+	}
+	return holder, nil
+}
+
+func (s *RedisSchedulerState) SaveStatus(ctx context.Context, status domain.SchedulerStatus) error {
+	data, err := json.Marshal(status)
+	if err != nil {
+		return fmt.Errorf("failed to marshal scheduler status: %v", err)
+	}
+
+	if err := s.client.Set(ctx, schedulerStatusKey, data, 0).Err(); err != nil {
+		return fmt.Errorf("failed to save scheduler status: %v", err)
+	}
+	return nil
+}
+
+func (s *RedisSchedulerState) Status(ctx context.Context) (*domain.SchedulerStatus, error) {
+	data, err := s.client.Get(ctx, schedulerStatusKey).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scheduler status: %v", err)
+	}
+
+	var status domain.SchedulerStatus
+	if err := json.Unmarshal(data, &status); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal scheduler status: %v", err)
+	}
+	return &status, nil
+}
+
+func (s *RedisSchedulerState) SetInterval(ctx context.Context, interval time.Duration) error {
+	if err := s.client.Set(ctx, schedulerIntervalKey, interval.String(), 0).Err(); err != nil {
+		return fmt.Errorf("failed to set scheduler interval: %v", err)
+	}
+	return nil
+}
+
+func (s *RedisSchedulerState) Interval(ctx context.Context) (time.Duration, error) {
+	value, err := s.client.Get(ctx, schedulerIntervalKey).Result()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get scheduler interval: %v", err)
+	}
+
+	interval, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid scheduler interval %q: %v", value, err)
+	}
+	return interval, nil
+}
@@ -0,0 +1,100 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ercancavusoglu/messaging/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedisSchedulerState_DesiredRunning(t *testing.T) {
+	_, client := newMiniredisClient(t)
+	ctx := context.Background()
+
+	state := NewRedisSchedulerState(client, true)
+
+	// Henüz kimse başlatıp durdurmadıysa varsayılan değer kullanılmalı
+	running, err := state.DesiredRunning(ctx)
+	assert.NoError(t, err)
+	assert.True(t, running)
+
+	assert.NoError(t, state.SetDesiredRunning(ctx, false))
+
+	// Aynı Redis'i kullanan başka bir replika da aynı durumu görmeli
+	running, err = NewRedisSchedulerState(client, true).DesiredRunning(ctx)
+	assert.NoError(t, err)
+	assert.False(t, running)
+}
+
+func TestRedisSchedulerState_ActiveHolder(t *testing.T) {
+	server, client := newMiniredisClient(t)
+	ctx := context.Background()
+
+	state := NewRedisSchedulerState(client, true)
+
+	holder, err := state.ActiveHolder(ctx)
+	assert.NoError(t, err)
+	assert.Empty(t, holder)
+
+	assert.NoError(t, state.MarkActive(ctx, "api-1", 3*time.Second))
+	holder, _ = state.ActiveHolder(ctx)
+	assert.Equal(t, "api-1", holder)
+
+	// Başka bir replika işareti silememeli
+	assert.NoError(t, state.ClearActive(ctx, "api-2"))
+	holder, _ = state.ActiveHolder(ctx)
+	assert.Equal(t, "api-1", holder)
+
+	// Lider çökerse işaret kendiliğinden düşmeli
+	server.FastForward(4 * time.Second)
+	holder, _ = state.ActiveHolder(ctx)
+	assert.Empty(t, holder)
+}
+
+func TestRedisSchedulerState_Status(t *testing.T) {
+	_, client := newMiniredisClient(t)
+	ctx := context.Background()
+
+	state := NewRedisSchedulerState(client, true)
+
+	status, err := state.Status(ctx)
+	assert.NoError(t, err)
+	assert.Nil(t, status)
+
+	lastTick := time.Now().UTC().Truncate(time.Second)
+	assert.NoError(t, state.SaveStatus(ctx, domain.SchedulerStatus{
+		Interval:          5 * time.Second,
+		LastTickAt:        &lastTick,
+		LastTickDuration:  120 * time.Millisecond,
+		LastTickPublished: 3,
+		TotalPublished:    42,
+		LastError:         "failed to publish message 7: timeout",
+	}))
+
+	status, err = state.Status(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 5*time.Second, status.Interval)
+	assert.Equal(t, 120*time.Millisecond, status.LastTickDuration)
+	assert.True(t, lastTick.Equal(*status.LastTickAt))
+	assert.Equal(t, int64(42), status.TotalPublished)
+	assert.Equal(t, "failed to publish message 7: timeout", status.LastError)
+}
+
+func TestRedisSchedulerState_Interval(t *testing.T) {
+	_, client := newMiniredisClient(t)
+	ctx := context.Background()
+
+	state := NewRedisSchedulerState(client, true)
+
+	// Ayarlanmamışsa sıfır dönmeli, yapılandırılan varsayılan kullanılır
+	interval, err := state.Interval(ctx)
+	assert.NoError(t, err)
+	assert.Zero(t, interval)
+
+	assert.NoError(t, state.SetInterval(ctx, 30*time.Second))
+	interval, err = state.Interval(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 30*time.Second, interval)
+}
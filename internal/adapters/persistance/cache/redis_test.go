@@ -26,7 +26,7 @@ func TestRedisAdapter_Integration(t *testing.T) {
 	assert.NoError(t, err)
 
 	// Redis adapter
-	adapter := NewRedisAdapter(client)
+	adapter := NewRedisAdapter(client, time.Hour)
 
 	// Test verileri
 	key := "test_key"
@@ -71,12 +71,13 @@ func TestRedisAdapter_Set(t *testing.T) {
 		mockSet: func(ctx context.Context, k string, v interface{}, expiration time.Duration) *redis.StatusCmd {
 			assert.Equal(t, key, k)
 			assert.Equal(t, value, v)
+			assert.Equal(t, time.Hour, expiration)
 			return redis.NewStatusCmd(ctx)
 		},
 	}
 
 	// Redis adapter
-	adapter := NewRedisAdapter(mockClient)
+	adapter := NewRedisAdapter(mockClient, time.Hour)
 
 	// Set
 	err := adapter.Set(key, value)
@@ -99,7 +100,7 @@ func TestRedisAdapter_Get(t *testing.T) {
 	}
 
 	// Redis adapter
-	adapter := NewRedisAdapter(mockClient)
+	adapter := NewRedisAdapter(mockClient, time.Hour)
 
 	// Get
 	result, err := adapter.Get(key)
@@ -122,7 +123,7 @@ func TestRedisAdapter_Get_NotFound(t *testing.T) {
 	}
 
 	// Redis adapter
-	adapter := NewRedisAdapter(mockClient)
+	adapter := NewRedisAdapter(mockClient, time.Hour)
 
 	// Get
 	_, err := adapter.Get(key)
@@ -0,0 +1,62 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/ercancavusoglu/messaging/internal/domain"
+)
+
+type AuditRepository struct {
+	db *sql.DB
+}
+
+func NewAuditRepository(db *sql.DB) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+func (r *AuditRepository) Create(entry *domain.AuditEntry) error {
+	query := `
+		INSERT INTO audit_logs (actor, action, result, remote_addr)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at
+	`
+	err := r.db.QueryRow(query, entry.Actor, entry.Action, entry.Result, entry.RemoteAddr).
+		Scan(&entry.ID, &entry.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create audit entry: %v", err)
+	}
+
+	return nil
+}
+
+// List returns the most recent entries first
+func (r *AuditRepository) List(limit int) ([]*domain.AuditEntry, error) {
+	query := `
+		SELECT id, actor, action, result, COALESCE(remote_addr, ''), created_at
+		FROM audit_logs
+		ORDER BY created_at DESC, id DESC
+		LIMIT $1
+	`
+
+	rows, err := r.db.Query(query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit entries: %v", err)
+	}
+	defer rows.Close()
+
+	var entries []*domain.AuditEntry
+	for rows.Next() {
+		entry := &domain.AuditEntry{}
+		if err := rows.Scan(&entry.ID, &entry.Actor, &entry.Action, &entry.Result, &entry.RemoteAddr, &entry.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan audit entry: %v", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating audit entries: %v", err)
+	}
+
+	return entries, nil
+}
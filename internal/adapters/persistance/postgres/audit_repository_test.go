@@ -0,0 +1,55 @@
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ercancavusoglu/messaging/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditRepository_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewAuditRepository(db)
+
+	now := time.Now()
+	entry := &domain.AuditEntry{Actor: "admin", Action: domain.AuditActionSchedulerStop, Result: "ok", RemoteAddr: "10.0.0.1:5000"}
+
+	// Mock beklentileri
+	mock.ExpectQuery("INSERT INTO audit_logs").
+		WithArgs("admin", domain.AuditActionSchedulerStop, "ok", "10.0.0.1:5000").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(1, now))
+
+	err = repo.Create(entry)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), entry.ID)
+	assert.Equal(t, now, entry.CreatedAt)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAuditRepository_List(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewAuditRepository(db)
+
+	now := time.Now()
+	mock.ExpectQuery("SELECT (.+) FROM audit_logs").
+		WithArgs(50).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "actor", "action", "result", "remote_addr", "created_at"}).
+			AddRow(2, "tenant:7", domain.AuditActionSchedulerStart, "ok", "", now).
+			AddRow(1, "admin", domain.AuditActionSchedulerStop, "scheduler is not running", "10.0.0.1:5000", now))
+
+	entries, err := repo.List(50)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "tenant:7", entries[0].Actor)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
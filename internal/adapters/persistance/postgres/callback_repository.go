@@ -0,0 +1,221 @@
+package postgres
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ercancavusoglu/messaging/internal/domain"
+)
+
+type CallbackRepository struct {
+	db *sql.DB
+}
+
+func NewCallbackRepository(db *sql.DB) *CallbackRepository {
+	return &CallbackRepository{db: db}
+}
+
+const selectCallbackSubscriptions = `
+	SELECT id, tenant_id, url, events, secret, created_at
+	FROM callback_subscriptions
+`
+
+const callbackDeliveryColumns = `id, subscription_id, tenant_id, COALESCE(message_id, 0), event, payload, status, attempts,
+	response_status, last_error, next_attempt_at, created_at, delivered_at`
+
+func (r *CallbackRepository) Create(subscription *domain.CallbackSubscription) error {
+	events, err := json.Marshal(subscription.Events)
+	if err != nil {
+		return fmt.Errorf("failed to encode callback events: %v", err)
+	}
+
+	query := `
+		INSERT INTO callback_subscriptions (tenant_id, url, events, secret)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at
+	`
+	err = r.db.QueryRow(query, subscription.TenantID, subscription.URL, events, subscription.Secret).
+		Scan(&subscription.ID, &subscription.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create callback subscription: %v", err)
+	}
+
+	return nil
+}
+
+func (r *CallbackRepository) Get(tenantID, id int64) (*domain.CallbackSubscription, error) {
+	subscription, err := scanCallbackSubscription(r.db.QueryRow(selectCallbackSubscriptions+` WHERE tenant_id = $1 AND id = $2`, tenantID, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrCallbackNotFound
+	}
+	return subscription, err
+}
+
+func (r *CallbackRepository) List(tenantID int64) ([]*domain.CallbackSubscription, error) {
+	return r.list(selectCallbackSubscriptions+` WHERE tenant_id = $1 ORDER BY id ASC`, tenantID)
+}
+
+// ListForEvent matches the event name against the JSON array of events
+func (r *CallbackRepository) ListForEvent(tenantID int64, eventName string) ([]*domain.CallbackSubscription, error) {
+	return r.list(selectCallbackSubscriptions+` WHERE tenant_id = $1 AND events ? $2 ORDER BY id ASC`, tenantID, eventName)
+}
+
+// Delete also removes the subscription's delivery log
+func (r *CallbackRepository) Delete(tenantID, id int64) error {
+	result, err := r.db.Exec(`DELETE FROM callback_subscriptions WHERE tenant_id = $1 AND id = $2`, tenantID, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete callback subscription: %v", err)
+	}
+
+	return expectAffected(result, domain.ErrCallbackNotFound)
+}
+
+func (r *CallbackRepository) CreateDelivery(delivery *domain.CallbackDelivery) error {
+	query := `
+		INSERT INTO callback_deliveries (subscription_id, tenant_id, message_id, event, payload, status, attempts,
+			response_status, last_error, next_attempt_at, delivered_at)
+		VALUES ($1, $2, NULLIF($3, 0), $4, $5, $6, $7, $8, $9, $10, $11)
+		ON CONFLICT (subscription_id, message_id, event) DO NOTHING
+		RETURNING id, created_at
+	`
+	err := r.db.QueryRow(query, delivery.SubscriptionID, delivery.TenantID, delivery.MessageID, delivery.Event,
+		[]byte(delivery.Payload), delivery.Status, delivery.Attempts, delivery.ResponseStatus, delivery.LastError,
+		delivery.NextAttemptAt, delivery.DeliveredAt).
+		Scan(&delivery.ID, &delivery.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create callback delivery: %v", err)
+	}
+
+	return nil
+}
+
+// ClaimDueDeliveries moves next_attempt_at of the claimed deliveries past the lease; the attempt's
+// outcome then sets it again. A dispatcher that dies mid-attempt leaves them to be retried after the lease.
+func (r *CallbackRepository) ClaimDueDeliveries(limit int, lease time.Duration) ([]*domain.CallbackDelivery, error) {
+	query := `
+		UPDATE callback_deliveries SET next_attempt_at = NOW() + $2 * INTERVAL '1 millisecond'
+		WHERE id IN (
+			SELECT id FROM callback_deliveries
+			WHERE status = 'pending' AND next_attempt_at <= NOW()
+			ORDER BY next_attempt_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + callbackDeliveryColumns
+
+	rows, err := r.db.Query(query, limit, lease.Milliseconds())
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim callback deliveries: %v", err)
+	}
+	defer rows.Close()
+
+	return scanCallbackDeliveries(rows)
+}
+
+func (r *CallbackRepository) UpdateDelivery(delivery *domain.CallbackDelivery) error {
+	query := `
+		UPDATE callback_deliveries
+		SET status = $1, attempts = $2, response_status = $3, last_error = $4, next_attempt_at = $5, delivered_at = $6
+		WHERE id = $7
+	`
+	result, err := r.db.Exec(query, delivery.Status, delivery.Attempts, delivery.ResponseStatus, delivery.LastError,
+		delivery.NextAttemptAt, delivery.DeliveredAt, delivery.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update callback delivery: %v", err)
+	}
+
+	return expectAffected(result, domain.ErrCallbackNotFound)
+}
+
+func (r *CallbackRepository) ListDeliveries(tenantID, subscriptionID int64, limit int) ([]*domain.CallbackDelivery, error) {
+	query := `SELECT ` + callbackDeliveryColumns + `
+		FROM callback_deliveries
+		WHERE tenant_id = $1 AND subscription_id = $2
+		ORDER BY id DESC
+		LIMIT $3`
+
+	rows, err := r.db.Query(query, tenantID, subscriptionID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list callback deliveries: %v", err)
+	}
+	defer rows.Close()
+
+	return scanCallbackDeliveries(rows)
+}
+
+func (r *CallbackRepository) list(query string, args ...interface{}) ([]*domain.CallbackSubscription, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list callback subscriptions: %v", err)
+	}
+	defer rows.Close()
+
+	var subscriptions []*domain.CallbackSubscription
+	for rows.Next() {
+		subscription, err := scanCallbackSubscription(rows)
+		if err != nil {
+			return nil, err
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating callback subscriptions: %v", err)
+	}
+
+	return subscriptions, nil
+}
+
+func scanCallbackSubscription(row rowScanner) (*domain.CallbackSubscription, error) {
+	subscription := &domain.CallbackSubscription{}
+	var events []byte
+
+	err := row.Scan(&subscription.ID, &subscription.TenantID, &subscription.URL, &events, &subscription.Secret, &subscription.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan callback subscription: %v", err)
+	}
+
+	if err := json.Unmarshal(events, &subscription.Events); err != nil {
+		return nil, fmt.Errorf("failed to decode callback events: %v", err)
+	}
+
+	return subscription, nil
+}
+
+func scanCallbackDeliveries(rows *sql.Rows) ([]*domain.CallbackDelivery, error) {
+	var deliveries []*domain.CallbackDelivery
+	for rows.Next() {
+		delivery := &domain.CallbackDelivery{}
+		var payload []byte
+		var deliveredAt sql.NullTime
+
+		err := rows.Scan(&delivery.ID, &delivery.SubscriptionID, &delivery.TenantID, &delivery.MessageID, &delivery.Event,
+			&payload, &delivery.Status, &delivery.Attempts, &delivery.ResponseStatus, &delivery.LastError,
+			&delivery.NextAttemptAt, &delivery.CreatedAt, &deliveredAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan callback delivery: %v", err)
+		}
+
+		delivery.Payload = payload
+		if deliveredAt.Valid {
+			delivery.DeliveredAt = &deliveredAt.Time
+		}
+
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating callback deliveries: %v", err)
+	}
+
+	return deliveries, nil
+}
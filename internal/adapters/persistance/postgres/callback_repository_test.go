@@ -0,0 +1,162 @@
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ercancavusoglu/messaging/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	callbackSubscriptionColumns = []string{"id", "tenant_id", "url", "events", "secret", "created_at"}
+	callbackDeliveryColumnNames = []string{"id", "subscription_id", "tenant_id", "message_id", "event", "payload", "status", "attempts",
+		"response_status", "last_error", "next_attempt_at", "created_at", "delivered_at"}
+)
+
+func TestCallbackRepository_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewCallbackRepository(db)
+
+	subscription := &domain.CallbackSubscription{
+		TenantID: 7,
+		URL:      "https://hooks.example.com/sms",
+		Events:   []string{domain.EventMessageSent, domain.EventMessageFailed},
+		Secret:   "whsec_abc",
+	}
+
+	mock.ExpectQuery("INSERT INTO callback_subscriptions").
+		WithArgs(int64(7), "https://hooks.example.com/sms", []byte(`["message.sent","message.failed"]`), "whsec_abc").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(3, time.Now()))
+
+	err = repo.Create(subscription)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), subscription.ID)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCallbackRepository_ListForEvent(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewCallbackRepository(db)
+
+	mock.ExpectQuery(`SELECT (.+) FROM callback_subscriptions WHERE tenant_id = \$1 AND events \? \$2`).
+		WithArgs(int64(7), domain.EventMessageSent).
+		WillReturnRows(sqlmock.NewRows(callbackSubscriptionColumns).
+			AddRow(3, 7, "https://hooks.example.com/sms", []byte(`["message.sent"]`), "whsec_abc", time.Now()))
+
+	subscriptions, err := repo.ListForEvent(7, domain.EventMessageSent)
+	assert.NoError(t, err)
+	assert.Len(t, subscriptions, 1)
+	assert.Equal(t, []string{domain.EventMessageSent}, subscriptions[0].Events)
+	assert.Equal(t, "whsec_abc", subscriptions[0].Secret)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCallbackRepository_Get_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewCallbackRepository(db)
+
+	mock.ExpectQuery("SELECT (.+) FROM callback_subscriptions WHERE tenant_id").
+		WithArgs(int64(7), int64(9)).
+		WillReturnRows(sqlmock.NewRows(callbackSubscriptionColumns))
+
+	subscription, err := repo.Get(7, 9)
+	assert.Nil(t, subscription)
+	assert.ErrorIs(t, err, domain.ErrCallbackNotFound)
+}
+
+func TestCallbackRepository_Delete_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewCallbackRepository(db)
+
+	// Başka bir kiracının aboneliği silinemez
+	mock.ExpectExec("DELETE FROM callback_subscriptions").
+		WithArgs(int64(7), int64(3)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.Delete(7, 3)
+	assert.ErrorIs(t, err, domain.ErrCallbackNotFound)
+}
+
+func TestCallbackRepository_CreateDelivery_Duplicate(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewCallbackRepository(db)
+
+	delivery, _ := domain.NewCallbackDelivery(&domain.CallbackSubscription{ID: 3, TenantID: 7}, 11,
+		domain.MessageUpdate{Event: domain.EventMessageSent})
+
+	// Aynı olay yeniden işlendiğinde ikinci kayıt oluşturulmaz
+	mock.ExpectQuery("INSERT INTO callback_deliveries (.+) ON CONFLICT").
+		WithArgs(int64(3), int64(7), int64(11), domain.EventMessageSent, []byte(delivery.Payload), domain.CallbackPending,
+			0, 0, "", sqlmock.AnyArg(), nil).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}))
+
+	err = repo.CreateDelivery(delivery)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), delivery.ID)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCallbackRepository_ClaimDueDeliveries(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewCallbackRepository(db)
+
+	now := time.Now()
+	mock.ExpectQuery(`UPDATE callback_deliveries SET next_attempt_at (.+) FOR UPDATE SKIP LOCKED`).
+		WithArgs(50, int64(60000)).
+		WillReturnRows(sqlmock.NewRows(callbackDeliveryColumnNames).
+			AddRow(5, 3, 7, 11, domain.EventMessageSent, []byte(`{"event":"message.sent"}`), "pending", 2, 503, "unexpected status 503", now, now, nil))
+
+	deliveries, err := repo.ClaimDueDeliveries(50, time.Minute)
+	assert.NoError(t, err)
+	assert.Len(t, deliveries, 1)
+	assert.Equal(t, int64(11), deliveries[0].MessageID)
+	assert.Equal(t, 2, deliveries[0].Attempts)
+	assert.JSONEq(t, `{"event":"message.sent"}`, string(deliveries[0].Payload))
+	assert.Nil(t, deliveries[0].DeliveredAt)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCallbackRepository_UpdateDelivery(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewCallbackRepository(db)
+
+	now := time.Now()
+	delivery := &domain.CallbackDelivery{ID: 5, Status: domain.CallbackPending}
+	delivery.Succeed(200, now)
+
+	mock.ExpectExec("UPDATE callback_deliveries").
+		WithArgs(domain.CallbackDelivered, 1, 200, "", sqlmock.AnyArg(), &now, int64(5)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = repo.UpdateDelivery(delivery)
+	assert.NoError(t, err)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
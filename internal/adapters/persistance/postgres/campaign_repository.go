@@ -0,0 +1,264 @@
+package postgres
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ercancavusoglu/messaging/internal/domain"
+)
+
+type CampaignRepository struct {
+	db *sql.DB
+}
+
+func NewCampaignRepository(db *sql.DB) *CampaignRepository {
+	return &CampaignRepository{db: db}
+}
+
+// checkDailyQuota locks the tenant row until the transaction ends, so concurrent campaigns of the tenant
+// count the messages one after the other and cannot pass the quota together
+func checkDailyQuota(tx *sql.Tx, tenantID int64, adding, dailyQuota int) error {
+	if _, err := tx.Exec(`SELECT id FROM tenants WHERE id = $1 FOR UPDATE`, tenantID); err != nil {
+		return fmt.Errorf("failed to lock tenant: %v", err)
+	}
+
+	var count int
+	query := `SELECT COUNT(*) FROM messages WHERE tenant_id = $1 AND created_at >= $2`
+	if err := tx.QueryRow(query, tenantID, domain.QuotaDayStart(time.Now())).Scan(&count); err != nil {
+		return fmt.Errorf("failed to check daily quota: %v", err)
+	}
+
+	if count+adding > dailyQuota {
+		return domain.ErrQuotaExceeded
+	}
+	return nil
+}
+
+const selectCampaigns = `
+	SELECT id, tenant_id, name, template_id, locale, variables, audience_size, priority, scheduled_at, status,
+		pending, sent, failed, delivered, created_at, updated_at
+	FROM campaigns
+`
+
+func (r *CampaignRepository) Create(campaign *domain.Campaign, messages []*domain.Message, dailyQuota int) error {
+	variables := campaign.Variables
+	if variables == nil {
+		variables = map[string]string{}
+	}
+
+	variablesJSON, err := json.Marshal(variables)
+	if err != nil {
+		return fmt.Errorf("failed to encode campaign variables: %v", err)
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	if dailyQuota > 0 {
+		if err := checkDailyQuota(tx, campaign.TenantID, len(messages), dailyQuota); err != nil {
+			return err
+		}
+	}
+
+	query := `
+		INSERT INTO campaigns (tenant_id, name, template_id, locale, variables, audience_size, priority, scheduled_at, status, pending)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id, created_at, updated_at
+	`
+	err = tx.QueryRow(query, campaign.TenantID, campaign.Name, campaign.TemplateID, campaign.Locale, variablesJSON,
+		campaign.AudienceSize, campaign.Priority, campaign.ScheduledAt, campaign.Status, campaign.Progress.Pending).
+		Scan(&campaign.ID, &campaign.CreatedAt, &campaign.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create campaign: %v", err)
+	}
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO messages (tenant_id, campaign_id, recipient, content, encoding, segments, priority, message_status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, created_at
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare campaign messages: %v", err)
+	}
+	defer stmt.Close()
+
+	for _, msg := range messages {
+		msg.TenantID = campaign.TenantID
+		msg.CampaignID = campaign.ID
+		msg.Priority = campaign.Priority
+
+		if err := stmt.QueryRow(msg.TenantID, msg.CampaignID, msg.To, msg.Content, msg.Encoding, msg.Segments, msg.Priority, msg.Status).
+			Scan(&msg.ID, &msg.CreatedAt); err != nil {
+			return fmt.Errorf("failed to create campaign message: %v", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	return nil
+}
+
+func (r *CampaignRepository) Get(tenantID, id int64) (*domain.Campaign, error) {
+	campaign, err := scanCampaign(r.db.QueryRow(selectCampaigns+` WHERE id = $1 AND tenant_id = $2`, id, tenantID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrCampaignNotFound
+	}
+	return campaign, err
+}
+
+func (r *CampaignRepository) List(tenantID int64) ([]*domain.Campaign, error) {
+	rows, err := r.db.Query(selectCampaigns+` WHERE tenant_id = $1 ORDER BY id ASC`, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list campaigns: %v", err)
+	}
+	defer rows.Close()
+
+	var campaigns []*domain.Campaign
+	for rows.Next() {
+		campaign, err := scanCampaign(rows)
+		if err != nil {
+			return nil, err
+		}
+		campaigns = append(campaigns, campaign)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating campaigns: %v", err)
+	}
+
+	return campaigns, nil
+}
+
+func (r *CampaignRepository) UpdateStatus(campaign *domain.Campaign, from domain.CampaignStatus) error {
+	query := `
+		UPDATE campaigns SET status = $1, updated_at = NOW()
+		WHERE id = $2 AND tenant_id = $3 AND status = $4
+		RETURNING updated_at
+	`
+	err := r.db.QueryRow(query, campaign.Status, campaign.ID, campaign.TenantID, from).Scan(&campaign.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		// The campaign exists, it was loaded before, so someone else changed its status in the meantime
+		return fmt.Errorf("%w: campaign is no longer %s", domain.ErrInvalidCampaignTransition, from)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update campaign status: %v", err)
+	}
+
+	return nil
+}
+
+func (r *CampaignRepository) CancelPending(campaign *domain.Campaign) (int, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(
+		`UPDATE messages SET message_status = 'cancelled' WHERE campaign_id = $1 AND message_status = 'pending'`,
+		campaign.ID,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to cancel campaign messages: %v", err)
+	}
+
+	cancelled, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %v", err)
+	}
+
+	query := `
+		UPDATE campaigns SET pending = GREATEST(pending - $1, 0), updated_at = NOW()
+		WHERE id = $2
+		RETURNING pending, updated_at
+	`
+	if err := tx.QueryRow(query, cancelled, campaign.ID).Scan(&campaign.Progress.Pending, &campaign.UpdatedAt); err != nil {
+		return 0, fmt.Errorf("failed to update campaign progress: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	return int(cancelled), nil
+}
+
+func (r *CampaignRepository) ApplyProgress(campaignID, messageID int64, eventName string, delta domain.CampaignProgress) error {
+	query := `
+		WITH recorded AS (
+			INSERT INTO campaign_message_events (campaign_id, message_id, event)
+			VALUES ($1, $2, $3)
+			ON CONFLICT DO NOTHING
+			RETURNING campaign_id
+		)
+		UPDATE campaigns SET
+			pending = GREATEST(pending + $4, 0),
+			sent = sent + $5,
+			failed = failed + $6,
+			delivered = delivered + $7,
+			status = CASE
+				WHEN status IN ('scheduled', 'running') AND pending + $4 <= 0 THEN 'completed'
+				WHEN status = 'scheduled' THEN 'running'
+				ELSE status
+			END,
+			updated_at = NOW()
+		FROM recorded
+		WHERE campaigns.id = recorded.campaign_id
+	`
+	_, err := r.db.Exec(query, campaignID, messageID, eventName, delta.Pending, delta.Sent, delta.Failed, delta.Delivered)
+	if err != nil {
+		return fmt.Errorf("failed to update campaign progress: %v", err)
+	}
+
+	return nil
+}
+
+func scanCampaign(row rowScanner) (*domain.Campaign, error) {
+	campaign := &domain.Campaign{}
+	var variables []byte
+	var scheduledAt sql.NullTime
+
+	err := row.Scan(
+		&campaign.ID,
+		&campaign.TenantID,
+		&campaign.Name,
+		&campaign.TemplateID,
+		&campaign.Locale,
+		&variables,
+		&campaign.AudienceSize,
+		&campaign.Priority,
+		&scheduledAt,
+		&campaign.Status,
+		&campaign.Progress.Pending,
+		&campaign.Progress.Sent,
+		&campaign.Progress.Failed,
+		&campaign.Progress.Delivered,
+		&campaign.CreatedAt,
+		&campaign.UpdatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan campaign: %v", err)
+	}
+
+	if len(variables) > 0 {
+		if err := json.Unmarshal(variables, &campaign.Variables); err != nil {
+			return nil, fmt.Errorf("failed to decode campaign variables: %v", err)
+		}
+	}
+	if scheduledAt.Valid {
+		campaign.ScheduledAt = &scheduledAt.Time
+	}
+
+	return campaign, nil
+}
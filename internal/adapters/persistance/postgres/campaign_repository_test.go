@@ -0,0 +1,171 @@
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ercancavusoglu/messaging/internal/domain"
+	"github.com/ercancavusoglu/messaging/internal/domain/valueobject"
+	"github.com/stretchr/testify/assert"
+)
+
+var campaignColumns = []string{
+	"id", "tenant_id", "name", "template_id", "locale", "variables", "audience_size", "priority", "scheduled_at", "status",
+	"pending", "sent", "failed", "delivered", "created_at", "updated_at",
+}
+
+func TestCampaignRepository_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewCampaignRepository(db)
+
+	now := time.Now()
+	campaign := &domain.Campaign{
+		TenantID:     7,
+		Name:         "Spring sale",
+		TemplateID:   1,
+		AudienceSize: 1,
+		Priority:     domain.PriorityBulk,
+		Status:       domain.CampaignRunning,
+		Progress:     domain.CampaignProgress{Pending: 1},
+	}
+	msg := &domain.Message{To: "+905551234567", Content: "İndirim %20", Encoding: valueobject.EncodingGSM7, Segments: 1, Status: domain.StatusPending}
+
+	// Mock beklentileri
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO campaigns").
+		WithArgs(int64(7), "Spring sale", int64(1), "", []byte(`{}`), 1, domain.PriorityBulk, nil, domain.CampaignRunning, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at"}).AddRow(4, now, now))
+	mock.ExpectPrepare("INSERT INTO messages")
+	mock.ExpectQuery("INSERT INTO messages").
+		WithArgs(int64(7), int64(4), "+905551234567", "İndirim %20", valueobject.EncodingGSM7, 1, domain.PriorityBulk, domain.StatusPending).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(90, now))
+	mock.ExpectCommit()
+
+	err = repo.Create(campaign, []*domain.Message{msg}, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), campaign.ID)
+	assert.Equal(t, int64(4), msg.CampaignID)
+	assert.Equal(t, int64(90), msg.ID)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCampaignRepository_Create_DailyQuota(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewCampaignRepository(db)
+
+	campaign := &domain.Campaign{TenantID: 7, Name: "Spring sale", TemplateID: 1, AudienceSize: 2, Status: domain.CampaignRunning}
+	messages := []*domain.Message{{To: "+905551234567"}, {To: "+905557654321"}}
+
+	// Tenant satırı kilitlenmeli ki eşzamanlı kampanyalar kotayı birlikte aşamasın
+	mock.ExpectBegin()
+	mock.ExpectExec(`SELECT id FROM tenants WHERE id = \$1 FOR UPDATE`).
+		WithArgs(int64(7)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM messages WHERE tenant_id = \$1 AND created_at >= \$2`).
+		WithArgs(int64(7), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(99))
+	mock.ExpectRollback()
+
+	err = repo.Create(campaign, messages, 100)
+	assert.ErrorIs(t, err, domain.ErrQuotaExceeded)
+	assert.Zero(t, campaign.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCampaignRepository_Get(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewCampaignRepository(db)
+
+	now := time.Now()
+	mock.ExpectQuery("SELECT (.+) FROM campaigns").
+		WithArgs(int64(4), int64(7)).
+		WillReturnRows(sqlmock.NewRows(campaignColumns).
+			AddRow(4, 7, "Spring sale", 1, "tr", []byte(`{"discount":"20"}`), 100, "bulk", nil, "running", 40, 55, 5, 50, now, now))
+
+	campaign, err := repo.Get(7, 4)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.CampaignRunning, campaign.Status)
+	assert.Equal(t, map[string]string{"discount": "20"}, campaign.Variables)
+	assert.Equal(t, domain.CampaignProgress{Pending: 40, Sent: 55, Failed: 5, Delivered: 50}, campaign.Progress)
+	assert.Nil(t, campaign.ScheduledAt)
+
+	// Başka bir tenant'ın kampanyası bulunamamalı
+	mock.ExpectQuery("SELECT (.+) FROM campaigns").
+		WithArgs(int64(4), int64(8)).
+		WillReturnRows(sqlmock.NewRows(campaignColumns))
+
+	_, err = repo.Get(8, 4)
+	assert.ErrorIs(t, err, domain.ErrCampaignNotFound)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCampaignRepository_UpdateStatus_Conflict(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewCampaignRepository(db)
+
+	mock.ExpectQuery("UPDATE campaigns SET status").
+		WithArgs(domain.CampaignPaused, int64(4), int64(7), domain.CampaignRunning).
+		WillReturnRows(sqlmock.NewRows([]string{"updated_at"}))
+
+	err = repo.UpdateStatus(&domain.Campaign{ID: 4, TenantID: 7, Status: domain.CampaignPaused}, domain.CampaignRunning)
+	assert.ErrorIs(t, err, domain.ErrInvalidCampaignTransition)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCampaignRepository_CancelPending(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewCampaignRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE messages SET message_status = 'cancelled'").
+		WithArgs(int64(4)).
+		WillReturnResult(sqlmock.NewResult(0, 30))
+	mock.ExpectQuery("UPDATE campaigns SET pending").
+		WithArgs(int64(30), int64(4)).
+		WillReturnRows(sqlmock.NewRows([]string{"pending", "updated_at"}).AddRow(2, time.Now()))
+	mock.ExpectCommit()
+
+	campaign := &domain.Campaign{ID: 4, Progress: domain.CampaignProgress{Pending: 32}}
+	cancelled, err := repo.CancelPending(campaign)
+	assert.NoError(t, err)
+	assert.Equal(t, 30, cancelled)
+	assert.Equal(t, 2, campaign.Progress.Pending)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCampaignRepository_ApplyProgress(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewCampaignRepository(db)
+
+	mock.ExpectExec("INSERT INTO campaign_message_events (.+) ON CONFLICT DO NOTHING").
+		WithArgs(int64(4), int64(90), domain.EventMessageSent, -1, 1, 0, 0).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = repo.ApplyProgress(4, 90, domain.EventMessageSent, domain.CampaignProgress{Pending: -1, Sent: 1})
+	assert.NoError(t, err)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,38 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/ercancavusoglu/messaging/internal/domain"
+)
+
+type InboundRepository struct {
+	db *sql.DB
+}
+
+func NewInboundRepository(db *sql.DB) *InboundRepository {
+	return &InboundRepository{db: db}
+}
+
+func (r *InboundRepository) Save(message *domain.InboundMessage) error {
+	query := `
+		INSERT INTO inbound_messages (sender, recipient, content, provider, provider_message_id, keyword, received_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`
+	err := r.db.QueryRow(query,
+		message.From,
+		message.To,
+		message.Content,
+		message.Provider,
+		sql.NullString{String: message.ProviderMessageID, Valid: message.ProviderMessageID != ""},
+		sql.NullString{String: string(message.Keyword), Valid: message.Keyword != domain.KeywordNone},
+		message.ReceivedAt,
+	).Scan(&message.ID)
+	if err != nil {
+		return fmt.Errorf("failed to save inbound message: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,58 @@
+package postgres
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ercancavusoglu/messaging/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInboundRepository_Save(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewInboundRepository(db)
+
+	now := time.Now()
+	message := &domain.InboundMessage{
+		From:              "+905551234567",
+		To:                "3434",
+		Content:           "STOP",
+		Provider:          "client_one",
+		ProviderMessageID: "mo_123",
+		Keyword:           domain.KeywordStop,
+		ReceivedAt:        now,
+	}
+
+	// Mock beklentileri
+	mock.ExpectQuery("INSERT INTO inbound_messages").
+		WithArgs("+905551234567", "3434", "STOP", "client_one",
+			sql.NullString{String: "mo_123", Valid: true},
+			sql.NullString{String: "stop", Valid: true},
+			now).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(9))
+
+	err = repo.Save(message)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(9), message.ID)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInboundRepository_Save_Error(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewInboundRepository(db)
+
+	mock.ExpectQuery("INSERT INTO inbound_messages").WillReturnError(assert.AnError)
+
+	err = repo.Save(&domain.InboundMessage{From: "+905551234567", Content: "hello"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to save inbound message")
+}
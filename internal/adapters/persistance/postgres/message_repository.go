@@ -2,28 +2,162 @@ package postgres
 
 import (
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/ercancavusoglu/messaging/internal/domain"
+	"github.com/ercancavusoglu/messaging/internal/domain/valueobject"
+	"github.com/ercancavusoglu/messaging/internal/ports"
 )
 
+const messageColumns = `id, COALESCE(tenant_id, 0), COALESCE(campaign_id, 0), recipient, content, encoding, segments, priority, message_status, message_id, provider, created_at, sent_at, channel, subject, COALESCE(parent_id, 0), delivered_at, fallback`
+
+const selectMessages = `
+	SELECT ` + messageColumns + `
+	FROM messages
+`
+
+// claimPendingMessages takes at most $1 pending messages and marks them queued in one statement.
+// Higher priority classes fill the batch first. Within a class every tenant, and every campaign of a
+// tenant, contributes its oldest messages in turns, so the batch holds the first message of each group,
+// then the second of each, and so on; a 500k message campaign cannot starve the others. Messages of
+// campaigns that are paused, cancelled or not due yet are left alone. SKIP LOCKED lets concurrent
+// claims take disjoint batches instead of waiting on each other.
+const claimPendingMessages = `
+	WITH heads AS (
+		SELECT h.id, h.created_at, g.rank,
+			ROW_NUMBER() OVER (PARTITION BY g.priority, g.tenant, g.campaign ORDER BY h.created_at, h.id) AS turn
+		FROM (
+			SELECT DISTINCT priority, COALESCE(tenant_id, 0) AS tenant, COALESCE(campaign_id, 0) AS campaign,
+				CASE priority WHEN 'critical' THEN 0 WHEN 'normal' THEN 1 ELSE 2 END AS rank
+			FROM messages WHERE message_status = 'pending'%s
+		) g
+		CROSS JOIN LATERAL (
+			SELECT id, created_at FROM messages
+			WHERE message_status = 'pending' AND priority = g.priority
+				AND COALESCE(tenant_id, 0) = g.tenant AND COALESCE(campaign_id, 0) = g.campaign
+			ORDER BY created_at, id
+			LIMIT $1
+		) h
+		WHERE g.campaign = 0 OR EXISTS (
+			SELECT 1 FROM campaigns c
+			WHERE c.id = g.campaign
+				AND (c.status = 'running' OR (c.status = 'scheduled' AND c.scheduled_at <= NOW()))
+		)
+	), batch AS (
+		SELECT m.id, heads.rank, heads.turn
+		FROM messages m
+		JOIN heads ON heads.id = m.id
+		WHERE m.message_status = 'pending'
+		ORDER BY heads.rank, heads.turn, heads.created_at, heads.id
+		LIMIT $1
+		FOR UPDATE OF m SKIP LOCKED
+	), claimed AS (
+		UPDATE messages SET message_status = 'queued', claimed_at = NOW()
+		FROM batch
+		WHERE messages.id = batch.id
+		RETURNING messages.*, batch.rank, batch.turn
+	)
+	SELECT ` + messageColumns + `
+	FROM claimed
+	ORDER BY rank, turn, created_at, id
+`
+
+// MessageRepository is unscoped when tenantID is zero, which only internal jobs such as the
+// scheduler and consumer use. Repositories returned by ForTenant filter every query by tenant.
 type MessageRepository struct {
-	db *sql.DB
+	db       *sql.DB
+	tenantID int64
 }
 
 func NewMessageRepository(db *sql.DB) *MessageRepository {
 	return &MessageRepository{db: db}
 }
 
+func (r *MessageRepository) ForTenant(tenantID int64) ports.Repository {
+	return &MessageRepository{db: r.db, tenantID: tenantID}
+}
+
+func (r *MessageRepository) Create(msg *domain.Message) error {
+	if r.tenantID != 0 {
+		msg.TenantID = r.tenantID
+	}
+
+	query := `
+		INSERT INTO messages (tenant_id, campaign_id, recipient, content, encoding, segments, priority, message_status, channel, subject, parent_id, fallback)
+		VALUES (NULLIF($1, 0), NULLIF($2, 0), $3, $4, $5, $6, $7, $8, $9, $10, NULLIF($11, 0), $12)
+		RETURNING id, created_at
+	`
+	args, err := messageArgs(msg)
+	if err != nil {
+		return err
+	}
+
+	if err := r.db.QueryRow(query, args...).Scan(&msg.ID, &msg.CreatedAt); err != nil {
+		return fmt.Errorf("failed to create message: %v", err)
+	}
+
+	return nil
+}
+
+// CreateFallback inserts the message only while its parent is undelivered; the unique parent_id index
+// turns a second fallback of the same parent, e.g. from a redelivered event, into a no-op
+func (r *MessageRepository) CreateFallback(msg *domain.Message) error {
+	if r.tenantID != 0 {
+		msg.TenantID = r.tenantID
+	}
+
+	query := `
+		INSERT INTO messages (tenant_id, campaign_id, recipient, content, encoding, segments, priority, message_status, channel, subject, parent_id, fallback)
+		SELECT NULLIF($1, 0), NULLIF($2, 0), $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
+		WHERE EXISTS (SELECT 1 FROM messages WHERE id = $11 AND delivered_at IS NULL)
+		ON CONFLICT (parent_id) DO NOTHING
+		RETURNING id, created_at
+	`
+	args, err := messageArgs(msg)
+	if err != nil {
+		return err
+	}
+
+	err = r.db.QueryRow(query, args...).Scan(&msg.ID, &msg.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.ErrFallbackNotNeeded
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create fallback message: %v", err)
+	}
+
+	return nil
+}
+
+// messageArgs fills in the defaults and returns the values of the columns Create inserts
+func messageArgs(msg *domain.Message) ([]interface{}, error) {
+	msg.Priority = msg.Priority.OrDefault()
+	msg.Channel = msg.Channel.OrDefault()
+
+	fallback := msg.Fallback
+	if fallback == nil {
+		fallback = []domain.FallbackStep{}
+	}
+	fallbackJSON, err := json.Marshal(fallback)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode message fallback: %v", err)
+	}
+
+	return []interface{}{msg.TenantID, msg.CampaignID, msg.To, msg.Content, msg.Encoding, msg.Segments, msg.Priority,
+		msg.Status, msg.Channel, msg.Subject, msg.ParentID, fallbackJSON}, nil
+}
+
 func (r *MessageRepository) UpdateStatus(id int64, status domain.MessageStatus, messageID string, provider string) error {
 	log.Printf("[MessageRepository] Updating message status [id: %d, status: %s, messageID: %s, provider: %s]", id, status, messageID, provider)
 	query := `
 		UPDATE messages 
 		SET message_status = $1::varchar, message_id = $2, provider = $3, sent_at = CASE WHEN $1::varchar = 'sent' THEN NOW() ELSE sent_at END
-		WHERE id = $4
-	`
-	result, err := r.db.Exec(query, status, messageID, provider, id)
+		WHERE id = $4` + r.tenantFilter(5)
+	result, err := r.db.Exec(query, r.scope(status, messageID, provider, id)...)
 	if err != nil {
 		return fmt.Errorf("failed to update message status: %v", err)
 	}
@@ -41,97 +175,230 @@ func (r *MessageRepository) UpdateStatus(id int64, status domain.MessageStatus,
 	return nil
 }
 
-func (r *MessageRepository) GetPendingMessages() ([]*domain.Message, error) {
-	query := `
-		SELECT id, recipient, content, message_status, message_id, provider, created_at, sent_at
-		FROM messages
-		WHERE message_status = $1
-		ORDER BY created_at ASC
-		LIMIT $2
-	`
+// GetPendingMessages claims up to limit pending messages, marking them queued so the next call does
+// not return them again. Callers that fail to hand a message over must set it back to pending; one
+// that is lost anyway is returned by RequeueStale.
+func (r *MessageRepository) GetPendingMessages(limit int) ([]*domain.Message, error) {
+	query := fmt.Sprintf(claimPendingMessages, r.tenantFilter(2))
 
-	rows, err := r.db.Query(query, domain.StatusPending)
+	rows, err := r.db.Query(query, r.scope(limit)...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get pending messages: %v", err)
 	}
 	defer rows.Close()
 
-	var messages []*domain.Message
-	for rows.Next() {
-		msg := &domain.Message{}
-		var sentAt sql.NullTime
-		var messageID sql.NullString
-		var provider sql.NullString
+	return scanMessages(rows)
+}
 
-		err := rows.Scan(
-			&msg.ID,
-			&msg.To,
-			&msg.Content,
-			&msg.Status,
-			&messageID,
-			&provider,
-			&msg.CreatedAt,
-			&sentAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan message: %v", err)
-		}
+func (r *MessageRepository) GetByStatus(status domain.MessageStatus) ([]*domain.Message, error) {
+	query := selectMessages + `WHERE message_status = $1` + r.tenantFilter(2) + ` ORDER BY created_at ASC`
 
-		if messageID.Valid {
-			msg.MessageID = messageID.String
-		}
-		if provider.Valid {
-			msg.Provider = provider.String
-		}
-		if sentAt.Valid {
-			msg.SentAt = &sentAt.Time
+	rows, err := r.db.Query(query, r.scope(status)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get messages by status: %v", err)
+	}
+	defer rows.Close()
+
+	return scanMessages(rows)
+}
+
+func (r *MessageRepository) GetByProviderMessageID(provider, messageID string) (*domain.Message, error) {
+	query := selectMessages + `WHERE provider = $1 AND message_id = $2` + r.tenantFilter(3) + ` ORDER BY id DESC LIMIT 1`
+
+	rows, err := r.db.Query(query, r.scope(provider, messageID)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get message by provider message id: %v", err)
+	}
+	defer rows.Close()
+
+	messages, err := scanMessages(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(messages) == 0 {
+		return nil, domain.ErrMessageNotFound
+	}
+
+	return messages[0], nil
+}
+
+// MarkDelivered keeps the time of the first delivery receipt
+func (r *MessageRepository) MarkDelivered(id int64) error {
+	query := `UPDATE messages SET delivered_at = NOW() WHERE id = $1 AND delivered_at IS NULL` + r.tenantFilter(2)
+
+	if _, err := r.db.Exec(query, r.scope(id)...); err != nil {
+		return fmt.Errorf("failed to mark message delivered: %v", err)
+	}
+
+	return nil
+}
+
+// CountCreatedSince counts the messages created after since, used to enforce daily tenant quotas
+func (r *MessageRepository) CountCreatedSince(since time.Time) (int, error) {
+	query := `SELECT COUNT(*) FROM messages WHERE created_at >= $1` + r.tenantFilter(2)
+
+	var count int
+	if err := r.db.QueryRow(query, r.scope(since)...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count messages: %v", err)
+	}
+
+	return count, nil
+}
+
+func (r *MessageRepository) CountByStatus() (map[domain.MessageStatus]int, error) {
+	query := `SELECT message_status, COUNT(*) FROM messages WHERE TRUE` + r.tenantFilter(1) + ` GROUP BY message_status`
+
+	rows, err := r.db.Query(query, r.scope()...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count messages by status: %v", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[domain.MessageStatus]int)
+	for rows.Next() {
+		var status domain.MessageStatus
+		var count int
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan message count: %v", err)
 		}
+		counts[status] = count
+	}
 
-		messages = append(messages, msg)
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating message counts: %v", err)
 	}
 
-	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating messages: %v", err)
+	return counts, nil
+}
+
+// TakeQueued moves a queued message to sending and reports whether this call did so. Only one consumer
+// can take a message, so a copy published twice, e.g. after RequeueStale, is not sent twice.
+func (r *MessageRepository) TakeQueued(id int64) (bool, error) {
+	query := `UPDATE messages SET message_status = 'sending' WHERE id = $1 AND message_status = 'queued'` + r.tenantFilter(2)
+
+	result, err := r.db.Exec(query, r.scope(id)...)
+	if err != nil {
+		return false, fmt.Errorf("failed to take message: %v", err)
 	}
 
-	return messages, nil
+	taken, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %v", err)
+	}
+
+	return taken == 1, nil
 }
 
-func (r *MessageRepository) GetByStatus(status domain.MessageStatus) ([]*domain.Message, error) {
+// CountInFlight counts the messages claimed by the scheduler that no consumer has settled yet
+func (r *MessageRepository) CountInFlight() (int, error) {
+	query := `SELECT COUNT(*) FROM messages WHERE message_status IN ('queued', 'sending')` + r.tenantFilter(1)
+
+	var count int
+	if err := r.db.QueryRow(query, r.scope()...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count messages in flight: %v", err)
+	}
+
+	return count, nil
+}
+
+// RequeueStale moves messages that were claimed longer than visibilityTimeout ago and are still queued
+// back to pending, e.g. after the consumer crashed before taking them. Messages a consumer took are
+// sending and stay untouched, since they may have reached their provider already.
+func (r *MessageRepository) RequeueStale(visibilityTimeout time.Duration) (int, error) {
 	query := `
-		SELECT id, recipient, content, message_status, message_id, provider, created_at, sent_at
-		FROM messages
-		WHERE message_status = $1
-		ORDER BY created_at ASC
-	`
+		UPDATE messages SET message_status = 'pending'
+		WHERE message_status = 'queued' AND claimed_at < NOW() - make_interval(secs => $1)` + r.tenantFilter(2)
 
-	rows, err := r.db.Query(query, status)
+	result, err := r.db.Exec(query, r.scope(visibilityTimeout.Seconds())...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get messages by status: %v", err)
+		return 0, fmt.Errorf("failed to requeue stale messages: %v", err)
 	}
-	defer rows.Close()
 
+	requeued, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %v", err)
+	}
+
+	return int(requeued), nil
+}
+
+// RequeueFailed leaves campaign messages alone: their campaign progress already counts them as failed
+func (r *MessageRepository) RequeueFailed(since time.Time) (int, error) {
+	query := `
+		UPDATE messages SET message_status = 'pending', message_id = NULL, provider = NULL
+		WHERE message_status = 'failed' AND campaign_id IS NULL AND created_at >= $1` + r.tenantFilter(2)
+
+	result, err := r.db.Exec(query, r.scope(since)...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to requeue failed messages: %v", err)
+	}
+
+	requeued, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %v", err)
+	}
+
+	return int(requeued), nil
+}
+
+// tenantFilter returns the tenant condition using placeholder $n, or nothing for an unscoped repository
+func (r *MessageRepository) tenantFilter(n int) string {
+	if r.tenantID == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" AND tenant_id = $%d", n)
+}
+
+// scope appends the tenant id to the query arguments to match tenantFilter
+func (r *MessageRepository) scope(args ...interface{}) []interface{} {
+	if r.tenantID == 0 {
+		return args
+	}
+	return append(args, r.tenantID)
+}
+
+func scanMessages(rows *sql.Rows) ([]*domain.Message, error) {
 	var messages []*domain.Message
 	for rows.Next() {
 		msg := &domain.Message{}
 		var sentAt sql.NullTime
 		var messageID sql.NullString
 		var provider sql.NullString
+		var encoding sql.NullString
+		var segments sql.NullInt64
+		var deliveredAt sql.NullTime
+		var fallback []byte
 
 		err := rows.Scan(
 			&msg.ID,
+			&msg.TenantID,
+			&msg.CampaignID,
 			&msg.To,
 			&msg.Content,
+			&encoding,
+			&segments,
+			&msg.Priority,
 			&msg.Status,
 			&messageID,
 			&provider,
 			&msg.CreatedAt,
 			&sentAt,
+			&msg.Channel,
+			&msg.Subject,
+			&msg.ParentID,
+			&deliveredAt,
+			&fallback,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan message: %v", err)
 		}
 
+		if encoding.Valid {
+			msg.Encoding = valueobject.Encoding(encoding.String)
+		}
+		if segments.Valid {
+			msg.Segments = int(segments.Int64)
+		}
 		if messageID.Valid {
 			msg.MessageID = messageID.String
 		}
@@ -141,11 +408,19 @@ func (r *MessageRepository) GetByStatus(status domain.MessageStatus) ([]*domain.
 		if sentAt.Valid {
 			msg.SentAt = &sentAt.Time
 		}
+		if deliveredAt.Valid {
+			msg.DeliveredAt = &deliveredAt.Time
+		}
+		if len(fallback) > 0 {
+			if err := json.Unmarshal(fallback, &msg.Fallback); err != nil {
+				return nil, fmt.Errorf("failed to decode message fallback: %v", err)
+			}
+		}
 
 		messages = append(messages, msg)
 	}
 
-	if err = rows.Err(); err != nil {
+	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("error iterating messages: %v", err)
 	}
 
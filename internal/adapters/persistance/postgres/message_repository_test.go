@@ -7,9 +7,39 @@ import (
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/ercancavusoglu/messaging/internal/domain"
+	"github.com/ercancavusoglu/messaging/internal/domain/valueobject"
 	"github.com/stretchr/testify/assert"
 )
 
+func TestMessageRepository_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewMessageRepository(db)
+
+	now := time.Now()
+	msg := &domain.Message{
+		To:       "+905551234567",
+		Content:  "Test message",
+		Encoding: valueobject.EncodingGSM7,
+		Segments: 1,
+		Status:   domain.StatusPending,
+	}
+
+	// Mock beklentileri
+	mock.ExpectQuery("INSERT INTO messages").
+		WithArgs(msg.TenantID, msg.CampaignID, msg.To, msg.Content, msg.Encoding, msg.Segments, domain.PriorityNormal, msg.Status, domain.ChannelSMS, "", int64(0), []byte("[]")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(10, now))
+
+	err = repo.Create(msg)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(10), msg.ID)
+	assert.Equal(t, now, msg.CreatedAt)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestMessageRepository_UpdateStatus(t *testing.T) {
 	// Mock DB oluştur
 	db, mock, err := sqlmock.New()
@@ -75,17 +105,17 @@ func TestMessageRepository_GetPendingMessages(t *testing.T) {
 
 	// Test verileri
 	now := time.Now()
-	rows := sqlmock.NewRows([]string{"id", "recipient", "content", "message_status", "message_id", "provider", "created_at", "sent_at"}).
-		AddRow(1, "+905551234567", "Test message 1", domain.StatusPending, sql.NullString{}, sql.NullString{}, now, sql.NullTime{}).
-		AddRow(2, "+905551234568", "Test message 2", domain.StatusPending, sql.NullString{}, sql.NullString{}, now, sql.NullTime{})
+	rows := sqlmock.NewRows([]string{"id", "tenant_id", "campaign_id", "recipient", "content", "encoding", "segments", "priority", "message_status", "message_id", "provider", "created_at", "sent_at", "channel", "subject", "parent_id", "delivered_at", "fallback"}).
+		AddRow(1, 0, 0, "+905551234567", "Test message 1", "gsm7", 1, domain.PriorityNormal, domain.StatusQueued, sql.NullString{}, sql.NullString{}, now, sql.NullTime{}, domain.ChannelSMS, "", 0, sql.NullTime{}, []byte("[]")).
+		AddRow(2, 0, 0, "+905551234568", "Test message 2", sql.NullString{}, sql.NullInt64{}, domain.PriorityBulk, domain.StatusQueued, sql.NullString{}, sql.NullString{}, now, sql.NullTime{}, domain.ChannelSMS, "", 0, sql.NullTime{}, []byte("[]"))
 
 	// Mock beklentileri
-	mock.ExpectQuery("SELECT (.+) FROM messages").
-		WithArgs(domain.StatusPending).
+	mock.ExpectQuery("UPDATE messages SET message_status = 'queued', claimed_at = NOW\\(\\)").
+		WithArgs(50).
 		WillReturnRows(rows)
 
 	// Test
-	messages, err := repo.GetPendingMessages()
+	messages, err := repo.GetPendingMessages(50)
 	assert.NoError(t, err)
 	assert.Len(t, messages, 2)
 
@@ -93,16 +123,41 @@ func TestMessageRepository_GetPendingMessages(t *testing.T) {
 	assert.Equal(t, int64(1), messages[0].ID)
 	assert.Equal(t, "+905551234567", messages[0].To)
 	assert.Equal(t, "Test message 1", messages[0].Content)
-	assert.Equal(t, domain.StatusPending, messages[0].Status)
+	assert.Equal(t, valueobject.EncodingGSM7, messages[0].Encoding)
+	assert.Equal(t, 1, messages[0].Segments)
+	assert.Equal(t, domain.PriorityNormal, messages[0].Priority)
+	assert.Equal(t, domain.StatusQueued, messages[0].Status)
 	assert.Empty(t, messages[0].MessageID)
 	assert.Empty(t, messages[0].Provider)
 	assert.Equal(t, now, messages[0].CreatedAt)
 	assert.Nil(t, messages[0].SentAt)
 
+	// Encoding bilgisi olmayan eski kayıtlar
+	assert.Empty(t, messages[1].Encoding)
+	assert.Zero(t, messages[1].Segments)
+
 	// Mock beklentilerinin karşılandığını kontrol et
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestMessageRepository_GetPendingMessages_ForTenant(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewMessageRepository(db).ForTenant(7)
+
+	mock.ExpectQuery("tenant_id = \\$2").
+		WithArgs(50, int64(7)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "tenant_id", "campaign_id", "recipient", "content", "encoding", "segments", "priority", "message_status", "message_id", "provider", "created_at", "sent_at", "channel", "subject", "parent_id", "delivered_at", "fallback"}))
+
+	messages, err := repo.GetPendingMessages(50)
+	assert.NoError(t, err)
+	assert.Empty(t, messages)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestMessageRepository_GetByStatus(t *testing.T) {
 	// Mock DB oluştur
 	db, mock, err := sqlmock.New()
@@ -114,9 +169,9 @@ func TestMessageRepository_GetByStatus(t *testing.T) {
 	// Test verileri
 	now := time.Now()
 	sentAt := now.Add(time.Hour)
-	rows := sqlmock.NewRows([]string{"id", "recipient", "content", "message_status", "message_id", "provider", "created_at", "sent_at"}).
-		AddRow(1, "+905551234567", "Test message 1", domain.StatusSent, "msg_123", "client_one", now, sentAt).
-		AddRow(2, "+905551234568", "Test message 2", domain.StatusSent, "msg_124", "client_two", now, sentAt)
+	rows := sqlmock.NewRows([]string{"id", "tenant_id", "campaign_id", "recipient", "content", "encoding", "segments", "priority", "message_status", "message_id", "provider", "created_at", "sent_at", "channel", "subject", "parent_id", "delivered_at", "fallback"}).
+		AddRow(1, 0, 0, "+905551234567", "Test message 1", "gsm7", 1, domain.PriorityNormal, domain.StatusSent, "msg_123", "client_one", now, sentAt, domain.ChannelSMS, "", 0, sql.NullTime{}, []byte("[]")).
+		AddRow(2, 0, 0, "+905551234568", "Test message 2", "ucs2", 2, domain.PriorityBulk, domain.StatusSent, "msg_124", "client_two", now, sentAt, domain.ChannelSMS, "", 0, sql.NullTime{}, []byte("[]"))
 
 	// Mock beklentileri
 	mock.ExpectQuery("SELECT (.+) FROM messages").
@@ -153,7 +208,7 @@ func TestMessageRepository_GetByStatus_NoRows(t *testing.T) {
 	// Mock beklentileri
 	mock.ExpectQuery("SELECT (.+) FROM messages").
 		WithArgs(domain.StatusSent).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "recipient", "content", "message_status", "message_id", "provider", "created_at", "sent_at"}))
+		WillReturnRows(sqlmock.NewRows([]string{"id", "tenant_id", "campaign_id", "recipient", "content", "encoding", "segments", "priority", "message_status", "message_id", "provider", "created_at", "sent_at", "channel", "subject", "parent_id", "delivered_at", "fallback"}))
 
 	// Test
 	messages, err := repo.GetByStatus(domain.StatusSent)
@@ -163,3 +218,223 @@ func TestMessageRepository_GetByStatus_NoRows(t *testing.T) {
 	// Mock beklentilerinin karşılandığını kontrol et
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestMessageRepository_ForTenant_ScopesQueries(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewMessageRepository(db).ForTenant(7)
+
+	now := time.Now()
+	mock.ExpectQuery(`SELECT (.+) FROM messages WHERE message_status = \$1 AND tenant_id = \$2`).
+		WithArgs(domain.StatusSent, int64(7)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "tenant_id", "campaign_id", "recipient", "content", "encoding", "segments", "priority", "message_status", "message_id", "provider", "created_at", "sent_at", "channel", "subject", "parent_id", "delivered_at", "fallback"}).
+			AddRow(1, 7, 0, "+905551234567", "Test message 1", "gsm7", 1, domain.PriorityNormal, domain.StatusSent, "msg_123", "client_one", now, now, domain.ChannelSMS, "", 0, sql.NullTime{}, []byte("[]")))
+	mock.ExpectExec(`UPDATE messages (.+) AND tenant_id = \$5`).
+		WithArgs(domain.StatusFailed, "", "", int64(1), int64(7)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("INSERT INTO messages").
+		WithArgs(int64(7), int64(0), "+905551234567", "Test message", valueobject.EncodingGSM7, 1, domain.PriorityNormal, domain.StatusPending, domain.ChannelSMS, "", int64(0), []byte("[]")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(2, now))
+
+	messages, err := repo.GetByStatus(domain.StatusSent)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), messages[0].TenantID)
+
+	assert.NoError(t, repo.UpdateStatus(1, domain.StatusFailed, "", ""))
+
+	// Tenant'a ait repository başka bir tenant adına mesaj oluşturamamalı
+	msg := &domain.Message{TenantID: 99, To: "+905551234567", Content: "Test message", Encoding: valueobject.EncodingGSM7, Segments: 1, Status: domain.StatusPending}
+	assert.NoError(t, repo.Create(msg))
+	assert.Equal(t, int64(7), msg.TenantID)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMessageRepository_CountCreatedSince(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewMessageRepository(db).ForTenant(7).(*MessageRepository)
+
+	since := time.Date(2024, 2, 24, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM messages WHERE created_at >= \$1 AND tenant_id = \$2`).
+		WithArgs(since, int64(7)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(42))
+
+	count, err := repo.CountCreatedSince(since)
+	assert.NoError(t, err)
+	assert.Equal(t, 42, count)
+}
+
+func TestMessageRepository_CountByStatus(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewMessageRepository(db)
+
+	mock.ExpectQuery(`SELECT message_status, COUNT\(\*\) FROM messages WHERE TRUE GROUP BY message_status`).
+		WillReturnRows(sqlmock.NewRows([]string{"message_status", "count"}).
+			AddRow("pending", 12).
+			AddRow("failed", 3))
+
+	counts, err := repo.CountByStatus()
+	assert.NoError(t, err)
+	assert.Equal(t, map[domain.MessageStatus]int{domain.StatusPending: 12, domain.StatusFailed: 3}, counts)
+}
+
+func TestMessageRepository_RequeueFailed(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewMessageRepository(db).ForTenant(7)
+
+	since := time.Date(2024, 2, 24, 0, 0, 0, 0, time.UTC)
+	mock.ExpectExec(`UPDATE messages SET message_status = 'pending', message_id = NULL, provider = NULL\s+WHERE message_status = 'failed' AND campaign_id IS NULL AND created_at >= \$1 AND tenant_id = \$2`).
+		WithArgs(since, int64(7)).
+		WillReturnResult(sqlmock.NewResult(0, 5))
+
+	requeued, err := repo.RequeueFailed(since)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, requeued)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMessageRepository_RequeueStale(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewMessageRepository(db)
+
+	// Zaman aşımını geçen queued mesajlar pending'e dönmeli
+	mock.ExpectExec(`UPDATE messages SET message_status = 'pending'\s+WHERE message_status = 'queued' AND claimed_at < NOW\(\) - make_interval\(secs => \$1\)`).
+		WithArgs(float64(600)).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	requeued, err := repo.RequeueStale(10 * time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, requeued)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMessageRepository_TakeQueued(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewMessageRepository(db)
+
+	mock.ExpectExec(`UPDATE messages SET message_status = 'sending' WHERE id = \$1 AND message_status = 'queued'`).
+		WithArgs(int64(5)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	// Başka bir tüketicinin aldığı mesaj ikinci kez alınamamalı
+	mock.ExpectExec(`UPDATE messages SET message_status = 'sending' WHERE id = \$1 AND message_status = 'queued'`).
+		WithArgs(int64(5)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	taken, err := repo.TakeQueued(5)
+	assert.NoError(t, err)
+	assert.True(t, taken)
+
+	taken, err = repo.TakeQueued(5)
+	assert.NoError(t, err)
+	assert.False(t, taken)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMessageRepository_CountInFlight(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewMessageRepository(db)
+
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM messages WHERE message_status IN \('queued', 'sending'\)`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(42))
+
+	count, err := repo.CountInFlight()
+	assert.NoError(t, err)
+	assert.Equal(t, 42, count)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMessageRepository_GetByProviderMessageID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewMessageRepository(db)
+
+	now := time.Now()
+	rows := sqlmock.NewRows([]string{"id", "tenant_id", "campaign_id", "recipient", "content", "encoding", "segments", "priority", "message_status", "message_id", "provider", "created_at", "sent_at", "channel", "subject", "parent_id", "delivered_at", "fallback"}).
+		AddRow(3, 7, 0, "+905551234567", "Test message", "gsm7", 1, domain.PriorityNormal, domain.StatusSent, "sim-1", "carrier", now, now, domain.ChannelSMS, "", 0, sql.NullTime{},
+			[]byte(`[{"channel": "email", "to": "jane@example.com", "wait_seconds": 60}]`))
+
+	mock.ExpectQuery("SELECT (.+) FROM messages\\s+WHERE provider = \\$1 AND message_id = \\$2").
+		WithArgs("carrier", "sim-1").
+		WillReturnRows(rows)
+
+	msg, err := repo.GetByProviderMessageID("carrier", "sim-1")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), msg.ID)
+	assert.Equal(t, int64(7), msg.TenantID)
+	assert.Equal(t, []domain.FallbackStep{{Channel: domain.ChannelEmail, To: "jane@example.com", WaitSeconds: 60}}, msg.Fallback)
+
+	// Bilinmeyen ID için ErrMessageNotFound dönmeli
+	mock.ExpectQuery("SELECT (.+) FROM messages").
+		WithArgs("carrier", "unknown").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, err = repo.GetByProviderMessageID("carrier", "unknown")
+	assert.ErrorIs(t, err, domain.ErrMessageNotFound)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMessageRepository_CreateFallback(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewMessageRepository(db)
+
+	msg, err := domain.NewChannelMessage(domain.ChannelEmail, "jane@example.com", "Kod", "Kodunuz: 123456", domain.DefaultMessageRules())
+	assert.NoError(t, err)
+	msg.TenantID = 7
+	msg.ParentID = 3
+
+	now := time.Now()
+	mock.ExpectQuery("INSERT INTO messages (.+) SELECT (.+) WHERE EXISTS (.+) ON CONFLICT \\(parent_id\\) DO NOTHING").
+		WithArgs(int64(7), int64(0), "jane@example.com", "Kodunuz: 123456", valueobject.Encoding(""), 0, domain.PriorityNormal, domain.StatusPending, domain.ChannelEmail, "Kod", int64(3), []byte("[]")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(11, now))
+
+	assert.NoError(t, repo.CreateFallback(msg))
+	assert.Equal(t, int64(11), msg.ID)
+
+	// Ebeveyn teslim edildiyse ya da zaten bir yedeği varsa satır eklenmez
+	mock.ExpectQuery("INSERT INTO messages").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}))
+
+	assert.ErrorIs(t, repo.CreateFallback(msg), domain.ErrFallbackNotNeeded)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMessageRepository_MarkDelivered(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewMessageRepository(db)
+
+	mock.ExpectExec("UPDATE messages SET delivered_at = NOW\\(\\) WHERE id = \\$1 AND delivered_at IS NULL").
+		WithArgs(int64(3)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, repo.MarkDelivered(3))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
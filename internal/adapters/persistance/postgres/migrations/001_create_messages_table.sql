@@ -1,11 +0,0 @@
--- Create Messages Table
-CREATE TABLE IF NOT EXISTS messages (
-    id SERIAL PRIMARY KEY,
-    recipient VARCHAR(15) NOT NULL,
-    content VARCHAR(160) NOT NULL,
-    status VARCHAR(20) NOT NULL DEFAULT 'pending',
-    message_id VARCHAR(36),
-    provider VARCHAR(50),
-    sent_at TIMESTAMP,
-    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
-); 
\ No newline at end of file
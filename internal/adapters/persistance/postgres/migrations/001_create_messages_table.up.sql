@@ -0,0 +1,20 @@
+-- Create Messages Table
+CREATE TABLE IF NOT EXISTS messages (
+    id SERIAL PRIMARY KEY,
+    recipient VARCHAR(15) NOT NULL,
+    content VARCHAR(160) NOT NULL,
+    message_status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    message_id VARCHAR(36),
+    provider VARCHAR(50),
+    sent_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Databases created from the first version of this file have a status column the code never used
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'messages' AND column_name = 'status')
+        AND NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'messages' AND column_name = 'message_status') THEN
+        ALTER TABLE messages RENAME COLUMN status TO message_status;
+    END IF;
+END $$;
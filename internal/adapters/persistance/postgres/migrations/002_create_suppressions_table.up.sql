@@ -0,0 +1,8 @@
+-- Create Suppressions Table
+CREATE TABLE IF NOT EXISTS suppressions (
+    id SERIAL PRIMARY KEY,
+    recipient VARCHAR(20) NOT NULL UNIQUE,
+    reason VARCHAR(255) NOT NULL DEFAULT '',
+    source VARCHAR(50) NOT NULL DEFAULT 'manual',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
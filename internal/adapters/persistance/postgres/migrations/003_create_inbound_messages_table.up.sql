@@ -0,0 +1,13 @@
+-- Create Inbound Messages Table
+CREATE TABLE IF NOT EXISTS inbound_messages (
+    id SERIAL PRIMARY KEY,
+    sender VARCHAR(20) NOT NULL,
+    recipient VARCHAR(20) NOT NULL DEFAULT '',
+    content VARCHAR(1600) NOT NULL,
+    provider VARCHAR(50) NOT NULL,
+    provider_message_id VARCHAR(64),
+    keyword VARCHAR(20),
+    received_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_inbound_messages_sender ON inbound_messages (sender);
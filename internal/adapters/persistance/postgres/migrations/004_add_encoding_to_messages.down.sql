@@ -0,0 +1,3 @@
+-- content stays TEXT: concatenated messages would no longer fit into VARCHAR(160)
+ALTER TABLE messages DROP COLUMN IF EXISTS segments;
+ALTER TABLE messages DROP COLUMN IF EXISTS encoding;
@@ -0,0 +1,4 @@
+-- Store SMS encoding and segment count; concatenated messages no longer fit into 160 characters
+ALTER TABLE messages ALTER COLUMN content TYPE TEXT;
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS encoding VARCHAR(10);
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS segments SMALLINT;
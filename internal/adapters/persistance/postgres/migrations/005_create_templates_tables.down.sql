@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS template_variants;
+DROP TABLE IF EXISTS templates;
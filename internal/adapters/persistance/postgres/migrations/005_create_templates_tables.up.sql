@@ -0,0 +1,15 @@
+-- Create Templates Tables
+CREATE TABLE IF NOT EXISTS templates (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    default_locale VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS template_variants (
+    template_id INTEGER NOT NULL REFERENCES templates (id) ON DELETE CASCADE,
+    locale VARCHAR(20) NOT NULL,
+    body TEXT NOT NULL,
+    PRIMARY KEY (template_id, locale)
+);
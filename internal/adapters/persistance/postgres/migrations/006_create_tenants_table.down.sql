@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_messages_tenant_created_at;
+ALTER TABLE messages DROP COLUMN IF EXISTS tenant_id;
+DROP TABLE IF EXISTS tenants;
@@ -0,0 +1,15 @@
+-- Create Tenants Table
+CREATE TABLE IF NOT EXISTS tenants (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    api_key_hash CHAR(64) NOT NULL UNIQUE,
+    daily_quota INTEGER NOT NULL DEFAULT 0,
+    provider_credentials JSONB NOT NULL DEFAULT '{}',
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Messages created before tenants existed keep a NULL tenant and are only visible to internal jobs
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS tenant_id INTEGER REFERENCES tenants (id);
+
+CREATE INDEX IF NOT EXISTS idx_messages_tenant_created_at ON messages (tenant_id, created_at);
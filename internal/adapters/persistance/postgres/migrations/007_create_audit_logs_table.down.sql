@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS audit_logs;
+ALTER TABLE tenants DROP COLUMN IF EXISTS scopes;
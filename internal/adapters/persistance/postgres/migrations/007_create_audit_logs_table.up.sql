@@ -0,0 +1,15 @@
+-- Tenant API key scopes; existing tenants keep the default tenant scopes
+ALTER TABLE tenants ADD COLUMN IF NOT EXISTS scopes JSONB NOT NULL
+    DEFAULT '["messages:read", "messages:write", "templates:write", "suppressions:write"]';
+
+-- Create Audit Logs Table
+CREATE TABLE IF NOT EXISTS audit_logs (
+    id SERIAL PRIMARY KEY,
+    actor VARCHAR(100) NOT NULL,
+    action VARCHAR(100) NOT NULL,
+    result VARCHAR(255) NOT NULL,
+    remote_addr VARCHAR(100),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_logs_created_at ON audit_logs (created_at);
@@ -0,0 +1,4 @@
+-- The scheduler claims the oldest pending messages of every tenant in turns; without this index each
+-- tick would sort the whole backlog
+CREATE INDEX IF NOT EXISTS idx_messages_pending ON messages (COALESCE(tenant_id, 0), created_at, id)
+    WHERE message_status = 'pending';
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_messages_pending;
+ALTER TABLE messages DROP COLUMN IF EXISTS priority;
+
+CREATE INDEX IF NOT EXISTS idx_messages_pending ON messages (COALESCE(tenant_id, 0), created_at, id)
+    WHERE message_status = 'pending';
@@ -0,0 +1,7 @@
+-- Priority class of a message: critical (e.g. OTP), normal or bulk (campaigns)
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS priority VARCHAR(10) NOT NULL DEFAULT 'normal';
+
+-- Pending messages are now claimed per priority class and tenant
+DROP INDEX IF EXISTS idx_messages_pending;
+CREATE INDEX IF NOT EXISTS idx_messages_pending ON messages (priority, COALESCE(tenant_id, 0), created_at, id)
+    WHERE message_status = 'pending';
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_messages_pending;
+DROP TABLE IF EXISTS campaign_message_events;
+ALTER TABLE messages DROP COLUMN IF EXISTS campaign_id;
+DROP TABLE IF EXISTS campaigns;
+
+CREATE INDEX IF NOT EXISTS idx_messages_pending ON messages (priority, COALESCE(tenant_id, 0), created_at, id)
+    WHERE message_status = 'pending';
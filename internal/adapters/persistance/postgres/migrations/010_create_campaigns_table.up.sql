@@ -0,0 +1,38 @@
+-- Create Campaigns Table
+CREATE TABLE IF NOT EXISTS campaigns (
+    id SERIAL PRIMARY KEY,
+    tenant_id INTEGER NOT NULL REFERENCES tenants (id),
+    name VARCHAR(100) NOT NULL,
+    template_id INTEGER NOT NULL REFERENCES templates (id),
+    locale VARCHAR(20) NOT NULL DEFAULT '',
+    variables JSONB NOT NULL DEFAULT '{}',
+    audience_size INTEGER NOT NULL,
+    priority VARCHAR(10) NOT NULL DEFAULT 'bulk',
+    scheduled_at TIMESTAMP,
+    status VARCHAR(20) NOT NULL,
+    pending INTEGER NOT NULL DEFAULT 0,
+    sent INTEGER NOT NULL DEFAULT 0,
+    failed INTEGER NOT NULL DEFAULT 0,
+    delivered INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_campaigns_tenant_id ON campaigns (tenant_id);
+
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS campaign_id INTEGER REFERENCES campaigns (id);
+
+-- Events are delivered at least once; a progress counter only moves the first time an event is seen
+CREATE TABLE IF NOT EXISTS campaign_message_events (
+    campaign_id INTEGER NOT NULL REFERENCES campaigns (id),
+    message_id INTEGER NOT NULL,
+    event VARCHAR(50) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (message_id, event)
+);
+
+-- Campaign messages form their own fairness group next to the tenant's individual messages
+DROP INDEX IF EXISTS idx_messages_pending;
+CREATE INDEX IF NOT EXISTS idx_messages_pending
+    ON messages (priority, COALESCE(tenant_id, 0), COALESCE(campaign_id, 0), created_at, id)
+    WHERE message_status = 'pending';
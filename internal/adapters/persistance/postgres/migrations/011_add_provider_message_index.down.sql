@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_messages_provider_message_id;
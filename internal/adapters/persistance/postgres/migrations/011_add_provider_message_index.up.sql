@@ -0,0 +1,3 @@
+-- Delivery receipts identify a message by the provider and the message ID the provider returned
+CREATE INDEX IF NOT EXISTS idx_messages_provider_message_id ON messages (provider, message_id)
+    WHERE message_id IS NOT NULL;
@@ -0,0 +1,3 @@
+-- recipient and message_id stay wide: non-SMS rows would no longer fit the old sizes
+ALTER TABLE messages DROP COLUMN IF EXISTS subject;
+ALTER TABLE messages DROP COLUMN IF EXISTS channel;
@@ -0,0 +1,8 @@
+-- Messages go out through sms, email, push or whatsapp; rows written before are SMS
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS channel VARCHAR(16) NOT NULL DEFAULT 'sms';
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS subject TEXT NOT NULL DEFAULT '';
+
+-- Email addresses and push tokens do not fit the phone number sized columns, nor do SMTP Message-IDs
+ALTER TABLE messages ALTER COLUMN recipient TYPE TEXT;
+ALTER TABLE messages ALTER COLUMN message_id TYPE VARCHAR(255);
+ALTER TABLE suppressions ALTER COLUMN recipient TYPE TEXT;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_messages_parent_id;
+
+ALTER TABLE messages DROP COLUMN IF EXISTS delivered_at;
+ALTER TABLE messages DROP COLUMN IF EXISTS fallback;
+ALTER TABLE messages DROP COLUMN IF EXISTS parent_id;
@@ -0,0 +1,6 @@
+-- A fallback message points at the message it follows; each message falls back at most once
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS parent_id INTEGER REFERENCES messages (id);
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS fallback JSONB NOT NULL DEFAULT '[]';
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS delivered_at TIMESTAMP;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_messages_parent_id ON messages (parent_id);
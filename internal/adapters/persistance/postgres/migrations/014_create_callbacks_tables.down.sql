@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS callback_deliveries;
+DROP TABLE IF EXISTS callback_subscriptions;
@@ -0,0 +1,36 @@
+-- Create Callback Subscriptions Table
+CREATE TABLE IF NOT EXISTS callback_subscriptions (
+    id SERIAL PRIMARY KEY,
+    tenant_id INTEGER NOT NULL REFERENCES tenants (id),
+    url TEXT NOT NULL,
+    events JSONB NOT NULL DEFAULT '[]',
+    secret VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_callback_subscriptions_tenant_id ON callback_subscriptions (tenant_id);
+
+-- Every event sent to a subscription, which doubles as the retry queue of the dispatcher
+CREATE TABLE IF NOT EXISTS callback_deliveries (
+    id SERIAL PRIMARY KEY,
+    subscription_id INTEGER NOT NULL REFERENCES callback_subscriptions (id) ON DELETE CASCADE,
+    tenant_id INTEGER NOT NULL REFERENCES tenants (id),
+    message_id INTEGER,
+    event VARCHAR(50) NOT NULL,
+    payload JSONB NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    response_status INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    next_attempt_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    delivered_at TIMESTAMP
+);
+
+-- Events are delivered at least once; a message event is only logged once per subscription
+CREATE UNIQUE INDEX IF NOT EXISTS idx_callback_deliveries_event
+    ON callback_deliveries (subscription_id, message_id, event);
+
+CREATE INDEX IF NOT EXISTS idx_callback_deliveries_due
+    ON callback_deliveries (next_attempt_at)
+    WHERE status = 'pending';
@@ -0,0 +1,8 @@
+-- Fails while two tenants share a template name or a suppressed recipient, instead of dropping either row
+DROP INDEX IF EXISTS idx_suppressions_tenant_recipient;
+ALTER TABLE suppressions DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE suppressions ADD CONSTRAINT suppressions_recipient_key UNIQUE (recipient);
+
+DROP INDEX IF EXISTS idx_templates_tenant_name;
+ALTER TABLE templates DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE templates ADD CONSTRAINT templates_name_key UNIQUE (name);
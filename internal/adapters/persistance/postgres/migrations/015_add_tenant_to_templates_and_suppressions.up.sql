@@ -0,0 +1,11 @@
+-- Templates and suppressions created before they were tenant scoped keep a NULL tenant: such templates
+-- are only visible to internal jobs and such suppressions, like inbound STOP replies, apply to every tenant
+ALTER TABLE templates ADD COLUMN IF NOT EXISTS tenant_id INTEGER REFERENCES tenants (id);
+ALTER TABLE suppressions ADD COLUMN IF NOT EXISTS tenant_id INTEGER REFERENCES tenants (id);
+
+-- Names and recipients are unique within a tenant instead of across all tenants
+ALTER TABLE templates DROP CONSTRAINT IF EXISTS templates_name_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_templates_tenant_name ON templates (COALESCE(tenant_id, 0), name);
+
+ALTER TABLE suppressions DROP CONSTRAINT IF EXISTS suppressions_recipient_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_suppressions_tenant_recipient ON suppressions (COALESCE(tenant_id, 0), recipient);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_messages_queued_claimed_at;
+ALTER TABLE messages DROP COLUMN IF EXISTS claimed_at;
@@ -0,0 +1,9 @@
+-- A message stays queued from its claim until the consumer settles it; one that is still queued after the
+-- visibility timeout was lost on the way, e.g. by a crash, and goes back to pending
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS claimed_at TIMESTAMP;
+
+-- Messages queued before the column existed get the full timeout from now on
+UPDATE messages SET claimed_at = NOW() WHERE message_status = 'queued' AND claimed_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_messages_queued_claimed_at ON messages (claimed_at)
+    WHERE message_status = 'queued';
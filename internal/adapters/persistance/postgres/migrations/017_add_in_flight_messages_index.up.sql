@@ -0,0 +1,3 @@
+-- The scheduler counts the claimed messages no consumer has settled yet before every claim
+CREATE INDEX IF NOT EXISTS idx_messages_in_flight ON messages (message_status)
+    WHERE message_status IN ('queued', 'sending');
@@ -0,0 +1,2 @@
+-- The revoked scopes are not restored: which tenants held them is not recorded
+SELECT 1;
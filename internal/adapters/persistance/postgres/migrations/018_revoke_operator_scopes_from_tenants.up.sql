@@ -0,0 +1,3 @@
+-- scheduler:admin and audit:read act on every tenant, so only the operator key may hold them
+UPDATE tenants SET scopes = scopes - 'scheduler:admin' - 'audit:read'
+    WHERE scopes ? 'scheduler:admin' OR scopes ? 'audit:read';
@@ -0,0 +1,9 @@
+// Package migrations embeds the versioned schema migrations into the binary.
+//
+// Every version has a NNN_name.up.sql file and a NNN_name.down.sql file that reverts it.
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS
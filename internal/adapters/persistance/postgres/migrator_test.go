@@ -0,0 +1,201 @@
+package postgres
+
+import (
+	"regexp"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ercancavusoglu/messaging/internal/adapters/persistance/postgres/migrations"
+	"github.com/stretchr/testify/assert"
+)
+
+func testMigrations() fstest.MapFS {
+	return fstest.MapFS{
+		"001_create_things.up.sql":   {Data: []byte("CREATE TABLE things (id SERIAL PRIMARY KEY);")},
+		"001_create_things.down.sql": {Data: []byte("DROP TABLE things;")},
+		"002_add_name.up.sql":        {Data: []byte("ALTER TABLE things ADD COLUMN name TEXT;")},
+		"002_add_name.down.sql":      {Data: []byte("ALTER TABLE things DROP COLUMN name;")},
+		"README.md":                  {Data: []byte("ignored")},
+	}
+}
+
+func expectMigrationLock(mock sqlmock.Sqlmock) {
+	mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_lock($1)")).WithArgs(migrationLockKey).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func expectAppliedVersions(mock sqlmock.Sqlmock, versions ...int) {
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT to_regclass('schema_migrations') IS NOT NULL")).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+	rows := sqlmock.NewRows([]string{"version", "applied_at"})
+	for _, version := range versions {
+		rows.AddRow(version, time.Now())
+	}
+	mock.ExpectQuery("SELECT version, applied_at FROM schema_migrations").WillReturnRows(rows)
+}
+
+func TestLoadMigrations(t *testing.T) {
+	loaded, err := LoadMigrations(testMigrations())
+	assert.NoError(t, err)
+	assert.Len(t, loaded, 2)
+	assert.Equal(t, 1, loaded[0].Version)
+	assert.Equal(t, "create_things", loaded[0].Name)
+	assert.Equal(t, "DROP TABLE things;", loaded[0].Down)
+
+	// Down dosyası olmayan migration reddedilmeli
+	source := testMigrations()
+	delete(source, "002_add_name.down.sql")
+	_, err = LoadMigrations(source)
+	assert.Error(t, err)
+}
+
+func TestLoadMigrations_Embedded(t *testing.T) {
+	loaded, err := LoadMigrations(migrations.FS)
+	assert.NoError(t, err)
+
+	// Versiyonlar boşluksuz ilerlemeli
+	for i, migration := range loaded {
+		assert.Equal(t, i+1, migration.Version, migration.Name)
+	}
+}
+
+func TestMigrator_Up(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	migrator, err := NewMigrator(db, testMigrations())
+	assert.NoError(t, err)
+
+	expectMigrationLock(mock)
+	expectAppliedVersions(mock, 1)
+	mock.ExpectBegin()
+	mock.ExpectExec("ALTER TABLE things ADD COLUMN name TEXT").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO schema_migrations").WithArgs(2, "add_name").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_unlock($1)")).WithArgs(migrationLockKey).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	applied, err := migrator.Up(0)
+	assert.NoError(t, err)
+	assert.Len(t, applied, 1)
+	assert.Equal(t, 2, applied[0].Version)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_Up_FailedMigrationRollsBack(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	migrator, err := NewMigrator(db, testMigrations())
+	assert.NoError(t, err)
+
+	expectMigrationLock(mock)
+	expectAppliedVersions(mock)
+	mock.ExpectBegin()
+	mock.ExpectExec("CREATE TABLE things").WillReturnError(assert.AnError)
+	mock.ExpectRollback()
+	mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_unlock($1)")).WithArgs(migrationLockKey).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	applied, err := migrator.Up(0)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "migration 001_create_things failed")
+	assert.Empty(t, applied)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_Down(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	migrator, err := NewMigrator(db, testMigrations())
+	assert.NoError(t, err)
+
+	expectMigrationLock(mock)
+	expectAppliedVersions(mock, 1, 2)
+	mock.ExpectBegin()
+	mock.ExpectExec("ALTER TABLE things DROP COLUMN name").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("DELETE FROM schema_migrations").WithArgs(2).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_unlock($1)")).WithArgs(migrationLockKey).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	reverted, err := migrator.Down(1)
+	assert.NoError(t, err)
+	assert.Len(t, reverted, 1)
+	assert.Equal(t, 2, reverted[0].Version)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_Down_UnknownVersion(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	migrator, err := NewMigrator(db, testMigrations())
+	assert.NoError(t, err)
+
+	expectMigrationLock(mock)
+	expectAppliedVersions(mock, 1, 2, 3)
+	mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_unlock($1)")).WithArgs(migrationLockKey).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	_, err = migrator.Down(1)
+	assert.ErrorIs(t, err, ErrSchemaTooNew)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_CheckVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		versions []int
+		wantErr  error
+	}{
+		{name: "Up to date", versions: []int{1, 2}},
+		{name: "Outdated", versions: []int{1}, wantErr: ErrSchemaOutdated},
+		{name: "Newer release", versions: []int{1, 2, 3}, wantErr: ErrSchemaTooNew},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+
+			migrator, err := NewMigrator(db, testMigrations())
+			assert.NoError(t, err)
+
+			expectAppliedVersions(mock, tt.versions...)
+
+			err = migrator.CheckVersion()
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestMigrator_CheckVersion_NeverMigrated(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	migrator, err := NewMigrator(db, testMigrations())
+	assert.NoError(t, err)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT to_regclass('schema_migrations') IS NOT NULL")).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+	err = migrator.CheckVersion()
+	assert.ErrorIs(t, err, ErrSchemaOutdated)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,125 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/ercancavusoglu/messaging/internal/domain"
+	"github.com/ercancavusoglu/messaging/internal/ports"
+)
+
+// SuppressionRepository holds the global suppressions, stored without a tenant, when tenantID is zero.
+// Repositories returned by ForTenant only see and create the suppressions of the tenant.
+type SuppressionRepository struct {
+	db       *sql.DB
+	tenantID int64
+}
+
+func NewSuppressionRepository(db *sql.DB) *SuppressionRepository {
+	return &SuppressionRepository{db: db}
+}
+
+func (r *SuppressionRepository) ForTenant(tenantID int64) ports.SuppressionRepository {
+	return &SuppressionRepository{db: r.db, tenantID: tenantID}
+}
+
+func (r *SuppressionRepository) Add(suppression *domain.Suppression) error {
+	suppression.TenantID = r.tenantID
+
+	query := `
+		INSERT INTO suppressions (tenant_id, recipient, reason, source)
+		VALUES (NULLIF($1, 0), $2, $3, $4)
+		ON CONFLICT ((COALESCE(tenant_id, 0)), recipient) DO UPDATE SET reason = EXCLUDED.reason, source = EXCLUDED.source
+		RETURNING id, created_at
+	`
+	err := r.db.QueryRow(query, r.tenantID, suppression.Recipient, suppression.Reason, suppression.Source).
+		Scan(&suppression.ID, &suppression.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to add suppression: %v", err)
+	}
+
+	return nil
+}
+
+func (r *SuppressionRepository) Remove(recipient string) error {
+	result, err := r.db.Exec(`DELETE FROM suppressions WHERE COALESCE(tenant_id, 0) = $1 AND recipient = $2`, r.tenantID, recipient)
+	if err != nil {
+		return fmt.Errorf("failed to remove suppression: %v", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %v", err)
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrSuppressionNotFound
+	}
+
+	return nil
+}
+
+func (r *SuppressionRepository) Get(recipient string) (*domain.Suppression, error) {
+	query := `
+		SELECT id, COALESCE(tenant_id, 0), recipient, reason, source, created_at
+		FROM suppressions
+		WHERE COALESCE(tenant_id, 0) = $1 AND recipient = $2
+	`
+
+	suppression := &domain.Suppression{}
+	err := r.db.QueryRow(query, r.tenantID, recipient).Scan(
+		&suppression.ID,
+		&suppression.TenantID,
+		&suppression.Recipient,
+		&suppression.Reason,
+		&suppression.Source,
+		&suppression.CreatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrSuppressionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get suppression: %v", err)
+	}
+
+	return suppression, nil
+}
+
+func (r *SuppressionRepository) List() ([]*domain.Suppression, error) {
+	query := `
+		SELECT id, COALESCE(tenant_id, 0), recipient, reason, source, created_at
+		FROM suppressions
+		WHERE COALESCE(tenant_id, 0) = $1
+		ORDER BY created_at ASC
+	`
+
+	rows, err := r.db.Query(query, r.tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list suppressions: %v", err)
+	}
+	defer rows.Close()
+
+	var suppressions []*domain.Suppression
+	for rows.Next() {
+		suppression := &domain.Suppression{}
+		if err := rows.Scan(
+			&suppression.ID,
+			&suppression.TenantID,
+			&suppression.Recipient,
+			&suppression.Reason,
+			&suppression.Source,
+			&suppression.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan suppression: %v", err)
+		}
+
+		suppressions = append(suppressions, suppression)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating suppressions: %v", err)
+	}
+
+	return suppressions, nil
+}
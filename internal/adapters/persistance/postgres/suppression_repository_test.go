@@ -0,0 +1,156 @@
+package postgres
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ercancavusoglu/messaging/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSuppressionRepository_Add(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewSuppressionRepository(db)
+
+	now := time.Now()
+	suppression := &domain.Suppression{
+		Recipient: "+905551234567",
+		Reason:    "STOP reply",
+		Source:    domain.SuppressionSourceManual,
+	}
+
+	// Mock beklentileri
+	mock.ExpectQuery("INSERT INTO suppressions").
+		WithArgs(int64(0), suppression.Recipient, suppression.Reason, suppression.Source).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(7, now))
+
+	err = repo.Add(suppression)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), suppression.ID)
+	assert.Equal(t, now, suppression.CreatedAt)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSuppressionRepository_Remove_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewSuppressionRepository(db)
+
+	mock.ExpectExec("DELETE FROM suppressions").
+		WithArgs(int64(0), "+905551234567").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.Remove("+905551234567")
+	assert.ErrorIs(t, err, domain.ErrSuppressionNotFound)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSuppressionRepository_Get(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewSuppressionRepository(db)
+
+	now := time.Now()
+	mock.ExpectQuery("SELECT (.+) FROM suppressions").
+		WithArgs(int64(0), "+905551234567").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "tenant_id", "recipient", "reason", "source", "created_at"}).
+			AddRow(1, 0, "+905551234567", "STOP reply", "manual", now))
+
+	suppression, err := repo.Get("+905551234567")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), suppression.ID)
+	assert.Equal(t, "+905551234567", suppression.Recipient)
+	assert.Equal(t, "STOP reply", suppression.Reason)
+	assert.Equal(t, "manual", suppression.Source)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSuppressionRepository_Get_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewSuppressionRepository(db)
+
+	mock.ExpectQuery("SELECT (.+) FROM suppressions").
+		WithArgs(int64(0), "+905551234567").
+		WillReturnError(sql.ErrNoRows)
+
+	suppression, err := repo.Get("+905551234567")
+	assert.Nil(t, suppression)
+	assert.ErrorIs(t, err, domain.ErrSuppressionNotFound)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSuppressionRepository_List(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewSuppressionRepository(db)
+
+	now := time.Now()
+	mock.ExpectQuery("SELECT (.+) FROM suppressions").
+		WithArgs(int64(0)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "tenant_id", "recipient", "reason", "source", "created_at"}).
+			AddRow(1, 0, "+905551234567", "", "manual", now).
+			AddRow(2, 0, "+905551234568", "", "import", now))
+
+	suppressions, err := repo.List()
+	assert.NoError(t, err)
+	assert.Len(t, suppressions, 2)
+	assert.Equal(t, "+905551234568", suppressions[1].Recipient)
+	assert.Equal(t, "import", suppressions[1].Source)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSuppressionRepository_ForTenant(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewSuppressionRepository(db).ForTenant(7)
+
+	now := time.Now()
+	suppression := &domain.Suppression{Recipient: "+905551234567", Source: domain.SuppressionSourceManual}
+
+	mock.ExpectQuery("INSERT INTO suppressions").
+		WithArgs(int64(7), suppression.Recipient, "", suppression.Source).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(7, now))
+
+	assert.NoError(t, repo.Add(suppression))
+	assert.Equal(t, int64(7), suppression.TenantID)
+
+	// Başka bir tenant'ın veya global listenin kaydı silinemez
+	mock.ExpectExec(`DELETE FROM suppressions WHERE COALESCE\(tenant_id, 0\) = \$1 AND recipient = \$2`).
+		WithArgs(int64(7), "+905551234568").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.ErrorIs(t, repo.Remove("+905551234568"), domain.ErrSuppressionNotFound)
+
+	mock.ExpectQuery(`SELECT (.+) FROM suppressions WHERE COALESCE\(tenant_id, 0\) = \$1`).
+		WithArgs(int64(7)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "tenant_id", "recipient", "reason", "source", "created_at"}).
+			AddRow(7, 7, "+905551234567", "", "manual", now))
+
+	suppressions, err := repo.List()
+	assert.NoError(t, err)
+	assert.Len(t, suppressions, 1)
+	assert.Equal(t, int64(7), suppressions[0].TenantID)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,217 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/ercancavusoglu/messaging/internal/domain"
+	"github.com/ercancavusoglu/messaging/internal/ports"
+)
+
+// TemplateRepository is unscoped when tenantID is zero, which only internal jobs use. Repositories
+// returned by ForTenant filter every query by tenant.
+type TemplateRepository struct {
+	db       *sql.DB
+	tenantID int64
+}
+
+func NewTemplateRepository(db *sql.DB) *TemplateRepository {
+	return &TemplateRepository{db: db}
+}
+
+func (r *TemplateRepository) ForTenant(tenantID int64) ports.TemplateRepository {
+	return &TemplateRepository{db: r.db, tenantID: tenantID}
+}
+
+func (r *TemplateRepository) Create(template *domain.Template) error {
+	if r.tenantID != 0 {
+		template.TenantID = r.tenantID
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO templates (tenant_id, name, default_locale)
+		VALUES (NULLIF($1, 0), $2, $3)
+		RETURNING id, created_at, updated_at
+	`
+	if err := tx.QueryRow(query, template.TenantID, template.Name, template.DefaultLocale).
+		Scan(&template.ID, &template.CreatedAt, &template.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to create template: %v", err)
+	}
+
+	if err := insertVariants(tx, template); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	return nil
+}
+
+func (r *TemplateRepository) Update(template *domain.Template) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE templates
+		SET name = $1, default_locale = $2, updated_at = NOW()
+		WHERE id = $3` + r.tenantFilter("tenant_id", 4) + `
+		RETURNING COALESCE(tenant_id, 0), created_at, updated_at
+	`
+	err = tx.QueryRow(query, r.scope(template.Name, template.DefaultLocale, template.ID)...).
+		Scan(&template.TenantID, &template.CreatedAt, &template.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.ErrTemplateNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update template: %v", err)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM template_variants WHERE template_id = $1`, template.ID); err != nil {
+		return fmt.Errorf("failed to delete template variants: %v", err)
+	}
+
+	if err := insertVariants(tx, template); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	return nil
+}
+
+func (r *TemplateRepository) Delete(id int64) error {
+	result, err := r.db.Exec(`DELETE FROM templates WHERE id = $1`+r.tenantFilter("tenant_id", 2), r.scope(id)...)
+	if err != nil {
+		return fmt.Errorf("failed to delete template: %v", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %v", err)
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrTemplateNotFound
+	}
+
+	return nil
+}
+
+func (r *TemplateRepository) Get(id int64) (*domain.Template, error) {
+	templates, err := r.query(`WHERE t.id = $1`+r.tenantFilter("t.tenant_id", 2), r.scope(id)...)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(templates) == 0 {
+		return nil, domain.ErrTemplateNotFound
+	}
+
+	return templates[0], nil
+}
+
+func (r *TemplateRepository) List() ([]*domain.Template, error) {
+	if r.tenantID == 0 {
+		return r.query("")
+	}
+	return r.query(`WHERE t.tenant_id = $1`, r.tenantID)
+}
+
+func (r *TemplateRepository) query(where string, args ...interface{}) ([]*domain.Template, error) {
+	query := `
+		SELECT t.id, COALESCE(t.tenant_id, 0), t.name, t.default_locale, t.created_at, t.updated_at, v.locale, v.body
+		FROM templates t
+		LEFT JOIN template_variants v ON v.template_id = t.id
+		` + where + `
+		ORDER BY t.id ASC
+	`
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get templates: %v", err)
+	}
+	defer rows.Close()
+
+	byID := make(map[int64]*domain.Template)
+	for rows.Next() {
+		var tmpl domain.Template
+		var locale, body sql.NullString
+
+		if err := rows.Scan(&tmpl.ID, &tmpl.TenantID, &tmpl.Name, &tmpl.DefaultLocale, &tmpl.CreatedAt, &tmpl.UpdatedAt, &locale, &body); err != nil {
+			return nil, fmt.Errorf("failed to scan template: %v", err)
+		}
+
+		existing, ok := byID[tmpl.ID]
+		if !ok {
+			tmpl.Variants = make(map[string]string)
+			existing = &tmpl
+			byID[tmpl.ID] = existing
+		}
+
+		if locale.Valid {
+			existing.Variants[locale.String] = body.String
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating templates: %v", err)
+	}
+
+	templates := make([]*domain.Template, 0, len(byID))
+	for _, tmpl := range byID {
+		templates = append(templates, tmpl)
+	}
+	sort.Slice(templates, func(i, j int) bool { return templates[i].ID < templates[j].ID })
+
+	return templates, nil
+}
+
+// tenantFilter returns the tenant condition on column using placeholder $n, or nothing for an unscoped repository
+func (r *TemplateRepository) tenantFilter(column string, n int) string {
+	if r.tenantID == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" AND %s = $%d", column, n)
+}
+
+// scope appends the tenant id to the query arguments to match tenantFilter
+func (r *TemplateRepository) scope(args ...interface{}) []interface{} {
+	if r.tenantID == 0 {
+		return args
+	}
+	return append(args, r.tenantID)
+}
+
+func insertVariants(tx *sql.Tx, template *domain.Template) error {
+	locales := make([]string, 0, len(template.Variants))
+	for locale := range template.Variants {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+
+	for _, locale := range locales {
+		if _, err := tx.Exec(
+			`INSERT INTO template_variants (template_id, locale, body) VALUES ($1, $2, $3)`,
+			template.ID, locale, template.Variants[locale],
+		); err != nil {
+			return fmt.Errorf("failed to insert template variant %s: %v", locale, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,192 @@
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ercancavusoglu/messaging/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+var templateColumns = []string{"id", "tenant_id", "name", "default_locale", "created_at", "updated_at", "locale", "body"}
+
+func TestTemplateRepository_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewTemplateRepository(db)
+
+	now := time.Now()
+	tmpl := &domain.Template{
+		Name:          "otp",
+		DefaultLocale: "en",
+		Variants:      map[string]string{"en": "Your code is {{code}}"},
+	}
+
+	// Mock beklentileri
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO templates").
+		WithArgs(int64(0), "otp", "en").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at"}).AddRow(3, now, now))
+	mock.ExpectExec("INSERT INTO template_variants").
+		WithArgs(int64(3), "en", "Your code is {{code}}").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err = repo.Create(tmpl)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), tmpl.ID)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTemplateRepository_Update_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewTemplateRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("UPDATE templates").
+		WithArgs("otp", "en", int64(3)).
+		WillReturnRows(sqlmock.NewRows([]string{"tenant_id", "created_at", "updated_at"}))
+	mock.ExpectRollback()
+
+	err = repo.Update(&domain.Template{ID: 3, Name: "otp", DefaultLocale: "en"})
+	assert.ErrorIs(t, err, domain.ErrTemplateNotFound)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTemplateRepository_Get(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewTemplateRepository(db)
+
+	now := time.Now()
+	mock.ExpectQuery("SELECT (.+) FROM templates").
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows(templateColumns).
+			AddRow(1, 0, "otp", "en", now, now, "en", "Your code is {{code}}").
+			AddRow(1, 0, "otp", "en", now, now, "tr", "Kodunuz {{code}}"))
+
+	tmpl, err := repo.Get(1)
+	assert.NoError(t, err)
+	assert.Equal(t, "otp", tmpl.Name)
+	assert.Equal(t, map[string]string{"en": "Your code is {{code}}", "tr": "Kodunuz {{code}}"}, tmpl.Variants)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTemplateRepository_Get_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewTemplateRepository(db)
+
+	mock.ExpectQuery("SELECT (.+) FROM templates").
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows(templateColumns))
+
+	tmpl, err := repo.Get(1)
+	assert.Nil(t, tmpl)
+	assert.ErrorIs(t, err, domain.ErrTemplateNotFound)
+}
+
+func TestTemplateRepository_List(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewTemplateRepository(db)
+
+	now := time.Now()
+	mock.ExpectQuery("SELECT (.+) FROM templates").
+		WillReturnRows(sqlmock.NewRows(templateColumns).
+			AddRow(1, 0, "otp", "en", now, now, "en", "Your code is {{code}}").
+			AddRow(2, 0, "shipping", "tr", now, now, nil, nil))
+
+	templates, err := repo.List()
+	assert.NoError(t, err)
+	assert.Len(t, templates, 2)
+	assert.Equal(t, "shipping", templates[1].Name)
+	assert.Empty(t, templates[1].Variants)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTemplateRepository_Delete(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewTemplateRepository(db)
+
+	mock.ExpectExec("DELETE FROM templates").
+		WithArgs(int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.Delete(1)
+	assert.ErrorIs(t, err, domain.ErrTemplateNotFound)
+}
+
+func TestTemplateRepository_ForTenant(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewTemplateRepository(db).ForTenant(7)
+
+	now := time.Now()
+	tmpl := &domain.Template{Name: "otp", DefaultLocale: "en"}
+
+	// Oluşturulan şablon tenant'a ait olmalı
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO templates").
+		WithArgs(int64(7), "otp", "en").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at"}).AddRow(3, now, now))
+	mock.ExpectCommit()
+
+	assert.NoError(t, repo.Create(tmpl))
+	assert.Equal(t, int64(7), tmpl.TenantID)
+
+	// Başka bir tenant'ın şablonu bulunamaz
+	mock.ExpectQuery(`SELECT (.+) FROM templates (.+) WHERE t.id = \$1 AND t.tenant_id = \$2`).
+		WithArgs(int64(4), int64(7)).
+		WillReturnRows(sqlmock.NewRows(templateColumns))
+
+	_, err = repo.Get(4)
+	assert.ErrorIs(t, err, domain.ErrTemplateNotFound)
+
+	mock.ExpectQuery(`SELECT (.+) FROM templates (.+) WHERE t.tenant_id = \$1`).
+		WithArgs(int64(7)).
+		WillReturnRows(sqlmock.NewRows(templateColumns).AddRow(3, 7, "otp", "en", now, now, nil, nil))
+
+	templates, err := repo.List()
+	assert.NoError(t, err)
+	assert.Len(t, templates, 1)
+	assert.Equal(t, int64(7), templates[0].TenantID)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`UPDATE templates (.+) WHERE id = \$3 AND tenant_id = \$4`).
+		WithArgs("otp", "en", int64(4), int64(7)).
+		WillReturnRows(sqlmock.NewRows([]string{"tenant_id", "created_at", "updated_at"}))
+	mock.ExpectRollback()
+
+	err = repo.Update(&domain.Template{ID: 4, Name: "otp", DefaultLocale: "en"})
+	assert.ErrorIs(t, err, domain.ErrTemplateNotFound)
+
+	mock.ExpectExec(`DELETE FROM templates WHERE id = \$1 AND tenant_id = \$2`).
+		WithArgs(int64(4), int64(7)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.ErrorIs(t, repo.Delete(4), domain.ErrTemplateNotFound)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,178 @@
+package postgres
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/ercancavusoglu/messaging/internal/domain"
+)
+
+type TenantRepository struct {
+	db *sql.DB
+}
+
+func NewTenantRepository(db *sql.DB) *TenantRepository {
+	return &TenantRepository{db: db}
+}
+
+const selectTenants = `
+	SELECT id, name, api_key_hash, daily_quota, provider_credentials, scopes, active, created_at
+	FROM tenants
+`
+
+func (r *TenantRepository) Create(tenant *domain.Tenant) error {
+	credentials, scopes, err := marshalTenantJSON(tenant)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO tenants (name, api_key_hash, daily_quota, provider_credentials, scopes, active)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`
+	err = r.db.QueryRow(query, tenant.Name, tenant.APIKeyHash, tenant.DailyQuota, credentials, scopes, tenant.Active).
+		Scan(&tenant.ID, &tenant.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create tenant: %v", err)
+	}
+
+	return nil
+}
+
+func (r *TenantRepository) Update(tenant *domain.Tenant) error {
+	credentials, scopes, err := marshalTenantJSON(tenant)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE tenants
+		SET name = $1, daily_quota = $2, provider_credentials = $3, scopes = $4, active = $5
+		WHERE id = $6
+	`
+	result, err := r.db.Exec(query, tenant.Name, tenant.DailyQuota, credentials, scopes, tenant.Active, tenant.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update tenant: %v", err)
+	}
+
+	return expectAffected(result, domain.ErrTenantNotFound)
+}
+
+func (r *TenantRepository) UpdateAPIKeyHash(id int64, hash string) error {
+	result, err := r.db.Exec(`UPDATE tenants SET api_key_hash = $1 WHERE id = $2`, hash, id)
+	if err != nil {
+		return fmt.Errorf("failed to update tenant api key: %v", err)
+	}
+
+	return expectAffected(result, domain.ErrTenantNotFound)
+}
+
+func (r *TenantRepository) Get(id int64) (*domain.Tenant, error) {
+	return r.getOne(selectTenants+` WHERE id = $1`, id)
+}
+
+func (r *TenantRepository) GetByAPIKeyHash(hash string) (*domain.Tenant, error) {
+	return r.getOne(selectTenants+` WHERE api_key_hash = $1`, hash)
+}
+
+func (r *TenantRepository) List() ([]*domain.Tenant, error) {
+	rows, err := r.db.Query(selectTenants + ` ORDER BY id ASC`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tenants: %v", err)
+	}
+	defer rows.Close()
+
+	var tenants []*domain.Tenant
+	for rows.Next() {
+		tenant, err := scanTenant(rows)
+		if err != nil {
+			return nil, err
+		}
+		tenants = append(tenants, tenant)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating tenants: %v", err)
+	}
+
+	return tenants, nil
+}
+
+func (r *TenantRepository) getOne(query string, arg interface{}) (*domain.Tenant, error) {
+	tenant, err := scanTenant(r.db.QueryRow(query, arg))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrTenantNotFound
+	}
+	return tenant, err
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanTenant(row rowScanner) (*domain.Tenant, error) {
+	tenant := &domain.Tenant{}
+	var credentials, scopes []byte
+
+	err := row.Scan(&tenant.ID, &tenant.Name, &tenant.APIKeyHash, &tenant.DailyQuota, &credentials, &scopes, &tenant.Active, &tenant.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan tenant: %v", err)
+	}
+
+	if len(credentials) > 0 {
+		if err := json.Unmarshal(credentials, &tenant.ProviderCredentials); err != nil {
+			return nil, fmt.Errorf("failed to decode provider credentials: %v", err)
+		}
+	}
+
+	if len(scopes) > 0 {
+		if err := json.Unmarshal(scopes, &tenant.Scopes); err != nil {
+			return nil, fmt.Errorf("failed to decode scopes: %v", err)
+		}
+	}
+
+	return tenant, nil
+}
+
+func marshalTenantJSON(tenant *domain.Tenant) ([]byte, []byte, error) {
+	credentials := tenant.ProviderCredentials
+	if credentials == nil {
+		credentials = map[string]domain.ProviderCredential{}
+	}
+
+	credentialsJSON, err := json.Marshal(credentials)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode provider credentials: %v", err)
+	}
+
+	scopes := tenant.Scopes
+	if scopes == nil {
+		scopes = []domain.Scope{}
+	}
+
+	scopesJSON, err := json.Marshal(scopes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode scopes: %v", err)
+	}
+
+	return credentialsJSON, scopesJSON, nil
+}
+
+func expectAffected(result sql.Result, notFound error) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %v", err)
+	}
+
+	if rowsAffected == 0 {
+		return notFound
+	}
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ercancavusoglu/messaging/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+var tenantColumns = []string{"id", "name", "api_key_hash", "daily_quota", "provider_credentials", "scopes", "active", "created_at"}
+
+func TestTenantRepository_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewTenantRepository(db)
+
+	now := time.Now()
+	tenant := &domain.Tenant{
+		Name:                "payments",
+		APIKeyHash:          "hash",
+		DailyQuota:          1000,
+		ProviderCredentials: map[string]domain.ProviderCredential{"client_one": {URL: "https://sms.example.com", Token: "secret"}},
+		Scopes:              []domain.Scope{domain.ScopeMessagesRead},
+		Active:              true,
+	}
+
+	// Mock beklentileri
+	mock.ExpectQuery("INSERT INTO tenants").
+		WithArgs("payments", "hash", 1000, []byte(`{"client_one":{"url":"https://sms.example.com","token":"secret"}}`), []byte(`["messages:read"]`), true).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(4, now))
+
+	err = repo.Create(tenant)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), tenant.ID)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTenantRepository_GetByAPIKeyHash(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewTenantRepository(db)
+
+	now := time.Now()
+	mock.ExpectQuery("SELECT (.+) FROM tenants WHERE api_key_hash").
+		WithArgs("hash").
+		WillReturnRows(sqlmock.NewRows(tenantColumns).
+			AddRow(4, "payments", "hash", 1000, []byte(`{"client_one":{"url":"https://sms.example.com","token":"secret"}}`), []byte(`["messages:read","scheduler:admin"]`), true, now))
+
+	tenant, err := repo.GetByAPIKeyHash("hash")
+	assert.NoError(t, err)
+	assert.Equal(t, "payments", tenant.Name)
+	assert.Equal(t, "https://sms.example.com", tenant.ProviderCredentials["client_one"].URL)
+	assert.Equal(t, []domain.Scope{domain.ScopeMessagesRead, domain.ScopeSchedulerAdmin}, tenant.Scopes)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTenantRepository_Get_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewTenantRepository(db)
+
+	mock.ExpectQuery("SELECT (.+) FROM tenants WHERE id").
+		WithArgs(int64(9)).
+		WillReturnRows(sqlmock.NewRows(tenantColumns))
+
+	tenant, err := repo.Get(9)
+	assert.Nil(t, tenant)
+	assert.ErrorIs(t, err, domain.ErrTenantNotFound)
+}
+
+func TestTenantRepository_UpdateAPIKeyHash_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewTenantRepository(db)
+
+	mock.ExpectExec("UPDATE tenants SET api_key_hash").
+		WithArgs("new-hash", int64(9)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.UpdateAPIKeyHash(9, "new-hash")
+	assert.ErrorIs(t, err, domain.ErrTenantNotFound)
+}
@@ -0,0 +1,106 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ercancavusoglu/messaging/internal/adapters/consumer"
+	"github.com/ercancavusoglu/messaging/internal/adapters/eventbus"
+	"github.com/ercancavusoglu/messaging/internal/adapters/mocks"
+	"github.com/ercancavusoglu/messaging/internal/adapters/scheduler"
+	"github.com/ercancavusoglu/messaging/internal/adapters/webhook"
+	"github.com/ercancavusoglu/messaging/internal/config"
+	"github.com/ercancavusoglu/messaging/internal/domain"
+	"github.com/ercancavusoglu/messaging/internal/ports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// Zamanlayıcıdan sağlayıcıya kadar tüm akış, RabbitMQ olmadan bellek içi olay yolu üzerinden çalışmalı
+func TestPipeline_SchedulerToWebhook_MemoryEventBus(t *testing.T) {
+	requests := make(chan map[string]string, 1)
+	provider := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		requests <- body
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(domain.WebhookResponse{MessageID: "msg_123", Message: "Accepted"})
+	}))
+	defer provider.Close()
+
+	logger := &mocks.MockLogger{}
+	for _, method := range []string{"Info", "Infof", "Warn", "Warnf", "Error", "Errorf"} {
+		logger.On(method, mock.Anything, mock.Anything).Maybe()
+		logger.On(method, mock.Anything).Maybe()
+	}
+
+	msg := &domain.Message{ID: 1, To: "+905551234567", Content: "Hello", Status: domain.StatusQueued}
+
+	repo := &mocks.MockRepository{}
+	repo.On("RequeueStale", time.Minute).Return(0, nil)
+	repo.On("GetPendingMessages", 10).Return([]*domain.Message{msg}, nil).Once()
+	repo.On("GetPendingMessages", 10).Return([]*domain.Message{}, nil)
+	repo.On("TakeQueued", int64(1)).Return(true, nil)
+	repo.On("UpdateStatus", int64(1), domain.StatusSent, "msg_123", "client_one").Return(nil).Once()
+
+	cache := &mocks.MockCache{}
+	cache.On("Set", "message:1", nil).Return(nil)
+
+	suppressions := &mocks.MockSuppressionService{}
+	suppressions.On("IsSuppressed", msg.TenantID, msg.To).Return(false, nil)
+
+	bus := eventbus.NewMemoryEventBus(10)
+
+	spec := config.Webhook{URLOne: provider.URL, TokenOne: "test-api-key"}.ProviderList()[0]
+	clients := webhook.ChannelClients{
+		domain.ChannelSMS: webhook.NewRetryableWebhookClient([]ports.WebhookClient{webhook.NewHTTPProvider(spec)}, 1),
+	}
+	messageConsumer := consumer.NewConsumer(webhook.NewTenantClientResolver(clients, nil, nil, 1), repo, cache, bus, suppressions, consumer.WorkerShares{Shared: 1}, domain.DefaultMessageRules(), logger)
+	require.NoError(t, messageConsumer.Start())
+
+	sent := make(chan *domain.MessageUpdate, 1)
+	bus.Subscribe(domain.EventMessageSent, func(event ports.Event) error {
+		update, err := domain.NewMessageUpdate(event.(*domain.EventEnvelope))
+		if err != nil {
+			return err
+		}
+		sent <- update
+		return nil
+	})
+
+	messageScheduler := scheduler.NewSchedulerService(NewMessageService(repo, nil, cache, bus), 10*time.Millisecond, 10, time.Minute, logger)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go messageScheduler.Start(ctx)
+
+	select {
+	case body := <-requests:
+		assert.Equal(t, "+905551234567", body["to"])
+		assert.Equal(t, "Hello", body["content"])
+	case <-time.After(5 * time.Second):
+		t.Fatal("Provider was not called")
+	}
+
+	select {
+	case update := <-sent:
+		assert.Equal(t, int64(1), update.Message.ID)
+		assert.Equal(t, domain.StatusSent, update.Message.Status)
+		assert.Equal(t, "msg_123", update.Message.MessageID)
+		assert.Equal(t, "client_one", update.Message.Provider)
+	case <-time.After(5 * time.Second):
+		t.Fatal("message.sent event timeout")
+	}
+
+	messageScheduler.Stop()
+	assert.NoError(t, bus.Close())
+	messageConsumer.Stop()
+
+	repo.AssertExpectations(t)
+	cache.AssertExpectations(t)
+}
@@ -1,17 +1,82 @@
 package adapters
 
 import (
-	"github.com/gorilla/mux"
 	"net/http"
+
+	"github.com/ercancavusoglu/messaging/internal/domain"
+	"github.com/ercancavusoglu/messaging/internal/ports"
+	"github.com/gorilla/mux"
 )
 
-func NewRouter(messageHandler *MessageHandler) http.Handler {
+func NewRouter(
+	messageHandler *MessageHandler,
+	suppressionHandler *SuppressionHandler,
+	inboundHandler *InboundHandler,
+	templateHandler *TemplateHandler,
+	campaignHandler *CampaignHandler,
+	callbackHandler *CallbackHandler,
+	streamHandler *StreamHandler,
+	tenantHandler *TenantHandler,
+	auditHandler *AuditHandler,
+	tenantService ports.TenantService,
+	adminAPIKey string,
+) http.Handler {
 	router := mux.NewRouter()
 
+	// Providers call the inbound webhook directly, so instead of an API key it requires their signature
+	router.HandleFunc("/api/v1/inbound/{provider}", inboundHandler.ReceiveMessage).Methods("POST")
+
 	api := router.PathPrefix("/api/v1").Subrouter()
-	api.HandleFunc("/messages", messageHandler.GetMessages).Methods("GET")
-	api.HandleFunc("/scheduler/start", messageHandler.StartScheduler).Methods("GET")
-	api.HandleFunc("/scheduler/stop", messageHandler.StopScheduler).Methods("GET")
+	api.Use(SameOriginMiddleware, AuthMiddleware(tenantService, adminAPIKey))
+
+	// handle registers a route that requires the given API key scope
+	handle := func(path, method string, scope domain.Scope, handler http.HandlerFunc) {
+		api.Handle(path, RequireScope(scope)(handler)).Methods(method)
+	}
+
+	handle("/messages", "GET", domain.ScopeMessagesRead, messageHandler.GetMessages)
+	handle("/messages", "POST", domain.ScopeMessagesWrite, messageHandler.CreateMessage)
+	handle("/messages/stream", "GET", domain.ScopeMessagesRead, streamHandler.StreamMessages)
+	handle("/scheduler/start", "POST", domain.ScopeSchedulerAdmin, messageHandler.StartScheduler)
+	handle("/scheduler/stop", "POST", domain.ScopeSchedulerAdmin, messageHandler.StopScheduler)
+	handle("/scheduler/status", "GET", domain.ScopeSchedulerAdmin, messageHandler.GetSchedulerStatus)
+	handle("/scheduler/interval", "PUT", domain.ScopeSchedulerAdmin, messageHandler.SetSchedulerInterval)
+
+	handle("/suppressions", "GET", domain.ScopeMessagesRead, suppressionHandler.ListSuppressions)
+	handle("/suppressions", "POST", domain.ScopeSuppressionsWrite, suppressionHandler.AddSuppression)
+	handle("/suppressions/export", "GET", domain.ScopeMessagesRead, suppressionHandler.ExportSuppressions)
+	handle("/suppressions/import", "POST", domain.ScopeSuppressionsWrite, suppressionHandler.ImportSuppressions)
+	handle("/suppressions/{recipient}", "GET", domain.ScopeMessagesRead, suppressionHandler.GetSuppression)
+	handle("/suppressions/{recipient}", "DELETE", domain.ScopeSuppressionsWrite, suppressionHandler.RemoveSuppression)
+
+	handle("/templates", "GET", domain.ScopeMessagesRead, templateHandler.ListTemplates)
+	handle("/templates", "POST", domain.ScopeTemplatesWrite, templateHandler.CreateTemplate)
+	handle("/templates/{id}", "GET", domain.ScopeMessagesRead, templateHandler.GetTemplate)
+	handle("/templates/{id}", "PUT", domain.ScopeTemplatesWrite, templateHandler.UpdateTemplate)
+	handle("/templates/{id}", "DELETE", domain.ScopeTemplatesWrite, templateHandler.DeleteTemplate)
+	handle("/templates/{id}/render", "POST", domain.ScopeMessagesRead, templateHandler.RenderTemplate)
+
+	handle("/campaigns", "GET", domain.ScopeMessagesRead, campaignHandler.ListCampaigns)
+	handle("/campaigns", "POST", domain.ScopeCampaignsWrite, campaignHandler.CreateCampaign)
+	handle("/campaigns/{id}", "GET", domain.ScopeMessagesRead, campaignHandler.GetCampaign)
+	handle("/campaigns/{id}/pause", "POST", domain.ScopeCampaignsWrite, campaignHandler.PauseCampaign)
+	handle("/campaigns/{id}/resume", "POST", domain.ScopeCampaignsWrite, campaignHandler.ResumeCampaign)
+	handle("/campaigns/{id}/cancel", "POST", domain.ScopeCampaignsWrite, campaignHandler.CancelCampaign)
+
+	handle("/callbacks", "GET", domain.ScopeMessagesRead, callbackHandler.ListCallbacks)
+	handle("/callbacks", "POST", domain.ScopeCallbacksWrite, callbackHandler.CreateCallback)
+	handle("/callbacks/{id}", "GET", domain.ScopeMessagesRead, callbackHandler.GetCallback)
+	handle("/callbacks/{id}", "DELETE", domain.ScopeCallbacksWrite, callbackHandler.DeleteCallback)
+	handle("/callbacks/{id}/deliveries", "GET", domain.ScopeMessagesRead, callbackHandler.ListDeliveries)
+	handle("/callbacks/{id}/ping", "POST", domain.ScopeCallbacksWrite, callbackHandler.PingCallback)
+
+	handle("/tenants", "GET", domain.ScopeTenantsAdmin, tenantHandler.ListTenants)
+	handle("/tenants", "POST", domain.ScopeTenantsAdmin, tenantHandler.CreateTenant)
+	handle("/tenants/{id}", "GET", domain.ScopeTenantsAdmin, tenantHandler.GetTenant)
+	handle("/tenants/{id}", "PUT", domain.ScopeTenantsAdmin, tenantHandler.UpdateTenant)
+	handle("/tenants/{id}/rotate-key", "POST", domain.ScopeTenantsAdmin, tenantHandler.RotateAPIKey)
+
+	handle("/audit-logs", "GET", domain.ScopeAuditRead, auditHandler.ListAuditLogs)
 
 	return router
 }
@@ -0,0 +1,317 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ercancavusoglu/messaging/internal/domain"
+	"github.com/ercancavusoglu/messaging/internal/ports"
+)
+
+// controlTimeout bounds the shared state calls made on behalf of an HTTP request
+const controlTimeout = 5 * time.Second
+
+// Coordinator makes sure exactly one replica runs the scheduler ticker. Every replica competes for the
+// leader lease; the leader runs its local SchedulerService while the shared desired state says so.
+// Start, Stop and IsRunning act on the shared state and therefore apply cluster-wide.
+type Coordinator struct {
+	scheduler *SchedulerService
+	lock      ports.LeaderLock
+	state     ports.SchedulerState
+	lease     time.Duration
+	logger    ports.Logger
+
+	leader      atomic.Bool
+	localCancel context.CancelFunc
+	// localDone is closed once the local scheduler's Start has returned
+	localDone chan struct{}
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func NewCoordinator(scheduler *SchedulerService, lock ports.LeaderLock, state ports.SchedulerState, lease time.Duration, logger ports.Logger) *Coordinator {
+	c := &Coordinator{
+		scheduler: scheduler,
+		lock:      lock,
+		state:     state,
+		lease:     lease,
+		logger:    logger,
+	}
+	scheduler.OnTick(c.saveStatus)
+	return c
+}
+
+// Run competes for leadership until ctx is cancelled or Close is called. The lease is renewed three
+// times per lease period so a healthy leader never loses it.
+func (c *Coordinator) Run(ctx context.Context) {
+	c.mu.Lock()
+	ctx, c.cancel = context.WithCancel(ctx)
+	c.done = make(chan struct{})
+	done := c.done
+	c.mu.Unlock()
+	defer close(done)
+
+	c.logger.Infof("[Coordinator] Starting as %s", c.lock.ID())
+	ticker := time.NewTicker(c.lease / 3)
+	defer ticker.Stop()
+
+	c.reconcile(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			c.resign()
+			return
+		case <-ticker.C:
+			c.reconcile(ctx)
+		}
+	}
+}
+
+// Close stops the local ticker, releases the lease so another replica can take over immediately and
+// waits for Run to return
+func (c *Coordinator) Close() {
+	c.mu.Lock()
+	cancel, done := c.cancel, c.done
+	c.mu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+	cancel()
+	<-done
+}
+
+func (c *Coordinator) Start() error {
+	ctx, cancel := context.WithTimeout(context.Background(), controlTimeout)
+	defer cancel()
+
+	running, err := c.state.DesiredRunning(ctx)
+	if err != nil {
+		return err
+	}
+	if running {
+		return domain.ErrSchedulerAlreadyRunning
+	}
+
+	return c.state.SetDesiredRunning(ctx, true)
+}
+
+func (c *Coordinator) Stop() error {
+	ctx, cancel := context.WithTimeout(context.Background(), controlTimeout)
+	defer cancel()
+
+	running, err := c.state.DesiredRunning(ctx)
+	if err != nil {
+		return err
+	}
+	if !running {
+		return domain.ErrSchedulerNotRunning
+	}
+
+	return c.state.SetDesiredRunning(ctx, false)
+}
+
+// IsRunning reports whether any replica is currently running the ticker
+func (c *Coordinator) IsRunning() bool {
+	ctx, cancel := context.WithTimeout(context.Background(), controlTimeout)
+	defer cancel()
+
+	holder, err := c.state.ActiveHolder(ctx)
+	if err != nil {
+		c.logger.Errorf("[Coordinator] Failed to read scheduler state: %v", err)
+		return false
+	}
+	return holder != ""
+}
+
+// Status combines the statistics saved by the leader with the live cluster state
+func (c *Coordinator) Status() (*domain.SchedulerStatus, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), controlTimeout)
+	defer cancel()
+
+	return ReadStatus(ctx, c.state, c.scheduler.Interval())
+}
+
+// ReadStatus returns the cluster-wide scheduler status from the shared state without taking part in the
+// leader election; defaultInterval is reported while no interval was set through the API
+func ReadStatus(ctx context.Context, state ports.SchedulerState, defaultInterval time.Duration) (*domain.SchedulerStatus, error) {
+	status, err := state.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if status == nil {
+		status = &domain.SchedulerStatus{}
+	}
+
+	holder, err := state.ActiveHolder(ctx)
+	if err != nil {
+		return nil, err
+	}
+	status.Running = holder != ""
+	status.Leader = holder
+	if !status.Running {
+		status.NextTickAt = nil
+	}
+
+	status.Interval, err = state.Interval(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if status.Interval == 0 {
+		status.Interval = defaultInterval
+	}
+
+	return status, nil
+}
+
+// SetInterval changes the tick interval for the whole cluster; the leader applies it on its next renewal
+func (c *Coordinator) SetInterval(interval time.Duration) error {
+	if err := domain.ValidateSchedulerInterval(interval); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), controlTimeout)
+	defer cancel()
+
+	if err := c.state.SetInterval(ctx, interval); err != nil {
+		return err
+	}
+
+	if c.IsLeader() {
+		c.scheduler.SetInterval(interval)
+	}
+	return nil
+}
+
+func (c *Coordinator) IsLeader() bool {
+	return c.leader.Load()
+}
+
+func (c *Coordinator) reconcile(ctx context.Context) {
+	leader, err := c.lock.Acquire(ctx)
+	if err != nil {
+		// Without a confirmed lease another replica may take over, so stop ticking rather than risk running twice
+		c.logger.Errorf("[Coordinator] %v", err)
+		leader = false
+	}
+
+	if was := c.leader.Swap(leader); was != leader {
+		if leader {
+			c.logger.Infof("[Coordinator] %s became leader", c.lock.ID())
+		} else {
+			c.logger.Warnf("[Coordinator] %s lost leadership", c.lock.ID())
+		}
+	}
+
+	if !leader {
+		c.stopLocal()
+		return
+	}
+
+	desired, err := c.state.DesiredRunning(ctx)
+	if err != nil {
+		c.logger.Errorf("[Coordinator] Failed to read desired state, keeping current state: %v", err)
+		desired = c.localCancel != nil
+	}
+
+	if !desired {
+		c.stopLocal()
+		if err := c.state.ClearActive(ctx, c.lock.ID()); err != nil {
+			c.logger.Errorf("[Coordinator] %v", err)
+		}
+		return
+	}
+
+	if interval, err := c.state.Interval(ctx); err != nil {
+		c.logger.Errorf("[Coordinator] %v", err)
+	} else if interval > 0 {
+		c.scheduler.SetInterval(interval)
+	}
+
+	if c.localCancel == nil {
+		c.restoreTotals(ctx)
+	}
+	c.startLocal()
+	if err := c.state.MarkActive(ctx, c.lock.ID(), c.lease); err != nil {
+		c.logger.Errorf("[Coordinator] %v", err)
+	}
+}
+
+// restoreTotals carries the cumulative counters over from the previous leader
+func (c *Coordinator) restoreTotals(ctx context.Context) {
+	previous, err := c.state.Status(ctx)
+	if err != nil {
+		c.logger.Errorf("[Coordinator] Failed to restore scheduler status: %v", err)
+		return
+	}
+	if previous != nil {
+		c.scheduler.RestoreTotals(*previous)
+	}
+}
+
+func (c *Coordinator) saveStatus(status domain.SchedulerStatus) {
+	ctx, cancel := context.WithTimeout(context.Background(), controlTimeout)
+	defer cancel()
+
+	status.Leader = c.lock.ID()
+	if err := c.state.SaveStatus(ctx, status); err != nil {
+		c.logger.Errorf("[Coordinator] %v", err)
+	}
+}
+
+func (c *Coordinator) startLocal() {
+	if c.localCancel != nil {
+		return
+	}
+
+	c.logger.Info("[Coordinator] Starting local scheduler")
+	ctx, cancel := context.WithCancel(context.Background())
+	c.localCancel = cancel
+	done := make(chan struct{})
+	c.localDone = done
+
+	go func() {
+		defer close(done)
+		if err := c.scheduler.Start(ctx); err != nil && !errors.Is(err, context.Canceled) {
+			c.logger.Errorf("[Coordinator] Scheduler error: %v", err)
+		}
+	}()
+}
+
+func (c *Coordinator) stopLocal() {
+	if c.localCancel == nil {
+		return
+	}
+
+	c.logger.Info("[Coordinator] Stopping local scheduler")
+	c.scheduler.Stop()
+	c.localCancel()
+	// Wait for the old run to return so it cannot touch the state of the next one
+	<-c.localDone
+	c.localCancel = nil
+	c.localDone = nil
+}
+
+func (c *Coordinator) resign() {
+	c.stopLocal()
+
+	if !c.leader.Swap(false) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), controlTimeout)
+	defer cancel()
+
+	if err := c.state.ClearActive(ctx, c.lock.ID()); err != nil {
+		c.logger.Errorf("[Coordinator] %v", err)
+	}
+	if err := c.lock.Release(ctx); err != nil {
+		c.logger.Errorf("[Coordinator] %v", err)
+	}
+	c.logger.Infof("[Coordinator] %s released leadership", c.lock.ID())
+}
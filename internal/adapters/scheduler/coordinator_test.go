@@ -0,0 +1,167 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/ercancavusoglu/messaging/internal/adapters/mocks"
+	"github.com/ercancavusoglu/messaging/internal/adapters/persistance/cache"
+	"github.com/ercancavusoglu/messaging/internal/domain"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const testLease = 150 * time.Millisecond
+
+func newTestCoordinator(t *testing.T, client *redis.Client) *Coordinator {
+	lock, err := cache.NewRedisLeaderLock(client, "scheduler:leader", testLease)
+	assert.NoError(t, err)
+
+	local := NewSchedulerService(&mocks.MockMessageService{}, time.Hour, 100, time.Minute, &mockLogger{})
+	return NewCoordinator(local, lock, cache.NewRedisSchedulerState(client, true), testLease, &mockLogger{})
+}
+
+// runningReplicas counts the replicas whose local ticker is running
+func runningReplicas(coordinators ...*Coordinator) int {
+	count := 0
+	for _, c := range coordinators {
+		if c.scheduler.IsRunning() {
+			count++
+		}
+	}
+	return count
+}
+
+func TestCoordinator_SingleLeaderRunsScheduler(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+
+	first := newTestCoordinator(t, client)
+	second := newTestCoordinator(t, client)
+
+	go first.Run(context.Background())
+	go second.Run(context.Background())
+	defer first.Close()
+	defer second.Close()
+
+	assert.Eventually(t, func() bool { return runningReplicas(first, second) == 1 }, time.Second, 10*time.Millisecond)
+	assert.NotEqual(t, first.IsLeader(), second.IsLeader())
+	assert.True(t, first.IsRunning())
+	assert.True(t, second.IsRunning())
+
+	// Birkaç yenileme döngüsünden sonra da tek replika çalışmalı
+	time.Sleep(2 * testLease)
+	assert.Equal(t, 1, runningReplicas(first, second))
+}
+
+func TestCoordinator_StopAndStartApplyClusterWide(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+
+	first := newTestCoordinator(t, client)
+	second := newTestCoordinator(t, client)
+
+	go first.Run(context.Background())
+	defer first.Close()
+	assert.Eventually(t, first.IsLeader, time.Second, 10*time.Millisecond)
+
+	go second.Run(context.Background())
+	defer second.Close()
+
+	// Lider olmayan replika üzerinden durdurma
+	assert.NoError(t, second.Stop())
+	assert.ErrorIs(t, second.Stop(), domain.ErrSchedulerNotRunning)
+	assert.Eventually(t, func() bool { return !first.scheduler.IsRunning() }, time.Second, 10*time.Millisecond)
+	assert.False(t, second.IsRunning())
+
+	assert.NoError(t, second.Start())
+	assert.ErrorIs(t, first.Start(), domain.ErrSchedulerAlreadyRunning)
+	assert.Eventually(t, first.scheduler.IsRunning, time.Second, 10*time.Millisecond)
+	assert.Eventually(t, second.IsRunning, time.Second, 10*time.Millisecond)
+}
+
+func TestCoordinator_FailoverOnClose(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+
+	first := newTestCoordinator(t, client)
+	second := newTestCoordinator(t, client)
+
+	go first.Run(context.Background())
+	assert.Eventually(t, first.scheduler.IsRunning, time.Second, 10*time.Millisecond)
+
+	go second.Run(context.Background())
+	defer second.Close()
+
+	first.Close()
+	assert.False(t, first.scheduler.IsRunning())
+
+	assert.Eventually(t, second.scheduler.IsRunning, time.Second, 10*time.Millisecond)
+	assert.True(t, second.IsLeader())
+}
+
+func TestCoordinator_StopsLocalSchedulerWhenLeaseCannotBeRenewed(t *testing.T) {
+	mockLock := &mocks.MockLeaderLock{}
+	mockState := &mocks.MockSchedulerState{}
+	local := NewSchedulerService(&mocks.MockMessageService{}, time.Hour, 100, time.Minute, &mockLogger{})
+
+	coordinator := NewCoordinator(local, mockLock, mockState, testLease, &mockLogger{})
+
+	mockLock.On("ID").Return("api-1")
+	mockLock.On("Acquire", mock.Anything).Return(true, nil).Once()
+	mockState.On("DesiredRunning", mock.Anything).Return(true, nil)
+	mockState.On("MarkActive", mock.Anything, "api-1", testLease).Return(nil)
+	mockState.On("Interval", mock.Anything).Return(time.Duration(0), nil)
+	mockState.On("Status", mock.Anything).Return(nil, nil)
+
+	coordinator.reconcile(context.Background())
+	assert.Eventually(t, local.IsRunning, time.Second, 10*time.Millisecond)
+
+	// Redis erişilemez hale geldi
+	mockLock.On("Acquire", mock.Anything).Return(false, assert.AnError)
+
+	coordinator.reconcile(context.Background())
+	assert.False(t, local.IsRunning())
+	assert.False(t, coordinator.IsLeader())
+}
+
+func TestCoordinator_StatusAndInterval(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+
+	first := newTestCoordinator(t, client)
+	second := newTestCoordinator(t, client)
+
+	go first.Run(context.Background())
+	defer first.Close()
+	assert.Eventually(t, first.scheduler.IsRunning, time.Second, 10*time.Millisecond)
+
+	status, err := second.Status()
+	assert.NoError(t, err)
+	assert.True(t, status.Running)
+	assert.Equal(t, first.lock.ID(), status.Leader)
+	assert.Equal(t, time.Hour, status.Interval)
+
+	assert.ErrorIs(t, second.SetInterval(time.Millisecond), domain.ErrInvalidSchedulerInterval)
+
+	// Lider olmayan replika üzerinden değiştirilen aralık lidere de uygulanmalı
+	assert.NoError(t, second.SetInterval(30*time.Second))
+	assert.Eventually(t, func() bool { return first.scheduler.Interval() == 30*time.Second }, time.Second, 10*time.Millisecond)
+
+	status, err = second.Status()
+	assert.NoError(t, err)
+	assert.Equal(t, 30*time.Second, status.Interval)
+
+	assert.NoError(t, second.Stop())
+	assert.Eventually(t, func() bool {
+		status, err := second.Status()
+		return err == nil && !status.Running && status.NextTickAt == nil
+	}, time.Second, 10*time.Millisecond)
+}
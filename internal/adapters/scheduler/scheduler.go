@@ -11,26 +11,37 @@ import (
 	"github.com/ercancavusoglu/messaging/internal/ports"
 )
 
-type SchedulerStatus struct {
-	IsRunning bool
-	LastRun   time.Time
-}
-
 type SchedulerService struct {
 	messageService ports.MessageService
 	interval       time.Duration
-	running        atomic.Bool
-	stopChan       chan struct{}
-	mu             sync.Mutex
-	logger         ports.Logger
+	batchSize      int
+	// maxInFlight caps the claimed messages the consumers have not settled yet; zero means no cap
+	maxInFlight  int
+	running      atomic.Bool
+	stopChan     chan struct{}
+	intervalChan chan time.Duration
+	mu           sync.Mutex
+	logger       ports.Logger
+
+	// visibilityTimeout is how long a claimed message may stay queued before it is claimed again
+	visibilityTimeout time.Duration
+
+	statusMu sync.Mutex
+	status   domain.SchedulerStatus
+	onTick   func(domain.SchedulerStatus)
 }
 
-func NewSchedulerService(messageService ports.MessageService, interval time.Duration, logger ports.Logger) *SchedulerService {
+// NewSchedulerService publishes at most batchSize pending messages per tick, and first returns the
+// messages that stayed queued longer than visibilityTimeout to pending
+func NewSchedulerService(messageService ports.MessageService, interval time.Duration, batchSize int, visibilityTimeout time.Duration, logger ports.Logger) *SchedulerService {
 	return &SchedulerService{
-		messageService: messageService,
-		interval:       interval,
-		stopChan:       make(chan struct{}),
-		logger:         logger,
+		messageService:    messageService,
+		interval:          interval,
+		batchSize:         batchSize,
+		visibilityTimeout: visibilityTimeout,
+		stopChan:          make(chan struct{}),
+		intervalChan:      make(chan time.Duration, 1),
+		logger:            logger,
 	}
 }
 
@@ -40,49 +51,63 @@ func (s *SchedulerService) Start(ctx context.Context) error {
 		s.mu.Unlock()
 		return fmt.Errorf("scheduler is already running")
 	}
-	s.stopChan = make(chan struct{})
+	// The run owns this stop channel; a later run gets its own, so this one can never stop it
+	stop := make(chan struct{})
+	s.stopChan = stop
+	select {
+	case <-s.intervalChan:
+	default:
+	}
+	interval := s.interval
 	s.mu.Unlock()
 
 	s.logger.Info("[Scheduler] Starting...")
-	ticker := time.NewTicker(s.interval)
+	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
+	s.scheduleNextTick(interval)
+	defer s.finish(stop)
 
 	s.logger.Info("[Scheduler] Ticker started")
 	for {
 		select {
 		case <-ctx.Done():
 			s.logger.Info("[Scheduler] Stopping due to context cancellation")
-			s.running.Store(false)
 			return ctx.Err()
-		case <-s.stopChan:
+		case <-stop:
 			s.logger.Info("[Scheduler] Stop signal received")
-			s.running.Store(false)
 			return nil
+		case interval = <-s.intervalChan:
+			s.logger.Infof("[Scheduler] Interval changed to %s", interval)
+			ticker.Reset(interval)
+			s.scheduleNextTick(interval)
 		case <-ticker.C:
-			if !s.running.Load() {
+			// Stop may race with the ticker; a stopped run must not tick again
+			select {
+			case <-stop:
 				continue
+			default:
 			}
 
-			messages, err := s.messageService.GetPendingMessages()
-			if err != nil {
-				s.logger.Errorf("[Scheduler] Error getting pending messages: %v", err)
-				continue
-			}
-
-			s.logger.Infof("[Scheduler] Found %d pending messages", len(messages))
-			for _, msg := range messages {
-				if msg.Status != domain.StatusPending {
-					continue
-				}
-				s.logger.Infof("[Scheduler] Publishing message ID: %d, Content: %s", msg.ID, msg.Content)
-				if err := s.messageService.Publish(msg); err != nil {
-					s.logger.Errorf("[Scheduler] Error publishing message: %v", err)
-				}
-			}
+			s.tick()
+			s.scheduleNextTick(interval)
+			s.notify()
 		}
 	}
 }
 
+// finish marks the run that owns stop as ended. A run that was stopped and replaced by a new Start leaves
+// the new run's state alone.
+func (s *SchedulerService) finish(stop chan struct{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.stopChan != stop {
+		return
+	}
+	s.running.Store(false)
+	s.scheduleNextTick(0)
+}
+
 func (s *SchedulerService) Stop() {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -97,3 +122,174 @@ func (s *SchedulerService) Stop() {
 func (s *SchedulerService) IsRunning() bool {
 	return s.running.Load()
 }
+
+// SetInterval changes the tick interval; a running ticker picks it up immediately
+func (s *SchedulerService) SetInterval(interval time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if interval == s.interval {
+		return
+	}
+	s.interval = interval
+
+	// Only the latest value matters, so replace a change the ticker has not consumed yet
+	select {
+	case <-s.intervalChan:
+	default:
+	}
+	s.intervalChan <- interval
+}
+
+// SetBatchSize changes the number of messages claimed per tick, starting with the next tick
+func (s *SchedulerService) SetBatchSize(batchSize int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.batchSize = batchSize
+}
+
+// SetMaxInFlight limits a tick to claiming what is left of maxInFlight after the messages still queued or
+// sending, so the claims follow what the consumers drain instead of piling up in the broker
+func (s *SchedulerService) SetMaxInFlight(maxInFlight int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.maxInFlight = maxInFlight
+}
+
+func (s *SchedulerService) Interval() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.interval
+}
+
+// OnTick registers a function called with the current status after every tick
+func (s *SchedulerService) OnTick(fn func(domain.SchedulerStatus)) {
+	s.statusMu.Lock()
+	defer s.statusMu.Unlock()
+	s.onTick = fn
+}
+
+// Status returns the run statistics collected by this instance
+func (s *SchedulerService) Status() domain.SchedulerStatus {
+	s.statusMu.Lock()
+	status := s.status
+	s.statusMu.Unlock()
+
+	status.Running = s.IsRunning()
+	status.Interval = s.Interval()
+	return status
+}
+
+// RestoreTotals continues the cumulative counters from a previous status, e.g. the one saved by the former leader
+func (s *SchedulerService) RestoreTotals(previous domain.SchedulerStatus) {
+	s.statusMu.Lock()
+	defer s.statusMu.Unlock()
+
+	s.status.TotalTicks = previous.TotalTicks
+	s.status.TotalPublished = previous.TotalPublished
+	s.status.TotalFailed = previous.TotalFailed
+	if s.status.LastTickAt == nil {
+		s.status.LastTickAt = previous.LastTickAt
+		s.status.LastTickDuration = previous.LastTickDuration
+		s.status.LastTickPublished = previous.LastTickPublished
+		s.status.LastTickFailed = previous.LastTickFailed
+	}
+	if s.status.LastErrorAt == nil {
+		s.status.LastError = previous.LastError
+		s.status.LastErrorAt = previous.LastErrorAt
+	}
+}
+
+func (s *SchedulerService) tick() {
+	startedAt := time.Now()
+	published, failed := 0, 0
+	var lastErr error
+
+	s.mu.Lock()
+	batchSize := s.batchSize
+	maxInFlight := s.maxInFlight
+	s.mu.Unlock()
+
+	requeued, err := s.messageService.RequeueStale(s.visibilityTimeout)
+	if err != nil {
+		s.logger.Errorf("[Scheduler] Error requeueing stale messages: %v", err)
+		lastErr = fmt.Errorf("failed to requeue stale messages: %v", err)
+	} else if requeued > 0 {
+		s.logger.Infof("[Scheduler] Requeued %d messages that stayed queued longer than %s", requeued, s.visibilityTimeout)
+	}
+
+	if maxInFlight > 0 {
+		inFlight, err := s.messageService.CountInFlight()
+		if err != nil {
+			s.logger.Errorf("[Scheduler] Error counting messages in flight: %v", err)
+			lastErr = fmt.Errorf("failed to count messages in flight: %v", err)
+			// Without the count the tick cannot tell how much room is left, so it claims nothing
+			batchSize = 0
+		} else if room := maxInFlight - inFlight; room < batchSize {
+			batchSize = room
+			if room <= 0 {
+				s.logger.Infof("[Scheduler] %d messages are still in flight, claiming none", inFlight)
+			}
+		}
+	}
+
+	var messages []*domain.Message
+	if batchSize > 0 {
+		messages, err = s.messageService.GetPendingMessages(batchSize)
+		if err != nil {
+			s.logger.Errorf("[Scheduler] Error getting pending messages: %v", err)
+			lastErr = fmt.Errorf("failed to get pending messages: %v", err)
+		}
+	}
+
+	s.logger.Infof("[Scheduler] Found %d pending messages", len(messages))
+	for _, msg := range messages {
+		s.logger.Infof("[Scheduler] Publishing message ID: %d, Content: %s", msg.ID, msg.Content)
+		if err := s.messageService.Publish(msg); err != nil {
+			s.logger.Errorf("[Scheduler] Error publishing message: %v", err)
+			lastErr = fmt.Errorf("failed to publish message %d: %v", msg.ID, err)
+			failed++
+			continue
+		}
+		published++
+	}
+
+	s.statusMu.Lock()
+	defer s.statusMu.Unlock()
+
+	s.status.LastTickAt = &startedAt
+	s.status.LastTickDuration = time.Since(startedAt)
+	s.status.LastTickPublished = published
+	s.status.LastTickFailed = failed
+	s.status.TotalTicks++
+	s.status.TotalPublished += int64(published)
+	s.status.TotalFailed += int64(failed)
+	if lastErr != nil {
+		now := time.Now()
+		s.status.LastError = lastErr.Error()
+		s.status.LastErrorAt = &now
+	}
+}
+
+// scheduleNextTick records when the ticker fires next; zero clears it once the ticker stops
+func (s *SchedulerService) scheduleNextTick(interval time.Duration) {
+	s.statusMu.Lock()
+	defer s.statusMu.Unlock()
+
+	if interval == 0 {
+		s.status.NextTickAt = nil
+		return
+	}
+	next := time.Now().Add(interval)
+	s.status.NextTickAt = &next
+}
+
+func (s *SchedulerService) notify() {
+	s.statusMu.Lock()
+	fn := s.onTick
+	s.statusMu.Unlock()
+
+	if fn != nil {
+		fn(s.Status())
+	}
+}
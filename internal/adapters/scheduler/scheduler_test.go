@@ -2,6 +2,7 @@ package scheduler
 
 import (
 	"context"
+	"sync"
 	"testing"
 	"time"
 
@@ -9,6 +10,7 @@ import (
 	"github.com/ercancavusoglu/messaging/internal/domain"
 	"github.com/ercancavusoglu/messaging/internal/ports"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 type mockLogger struct {
@@ -21,6 +23,8 @@ func (m *mockLogger) Error(args ...interface{})                   {}
 func (m *mockLogger) Errorf(format string, args ...interface{})   {}
 func (m *mockLogger) Warning(args ...interface{})                 {}
 func (m *mockLogger) Warningf(format string, args ...interface{}) {}
+func (m *mockLogger) Warn(args ...interface{})                    {}
+func (m *mockLogger) Warnf(format string, args ...interface{})    {}
 
 func createTestMessage() *domain.Message {
 	return &domain.Message{
@@ -38,12 +42,13 @@ func TestSchedulerService_Start_Success(t *testing.T) {
 	mockService := &mocks.MockMessageService{}
 	logger := &mockLogger{}
 
-	scheduler := NewSchedulerService(mockService, 100*time.Millisecond, logger)
+	scheduler := NewSchedulerService(mockService, 100*time.Millisecond, 100, time.Minute, logger)
 
 	// Mock beklentileri
 	msg := createTestMessage()
 	messages := []*domain.Message{msg}
-	mockService.On("GetPendingMessages").Return(messages, nil)
+	mockService.On("RequeueStale", time.Minute).Return(0, nil)
+	mockService.On("GetPendingMessages", 100).Return(messages, nil)
 	mockService.On("Publish", msg).Return(nil)
 
 	// Scheduler'ı başlat
@@ -68,7 +73,7 @@ func TestSchedulerService_Start_AlreadyRunning(t *testing.T) {
 	mockService := &mocks.MockMessageService{}
 	logger := &mockLogger{}
 
-	scheduler := NewSchedulerService(mockService, 100*time.Millisecond, logger)
+	scheduler := NewSchedulerService(mockService, 100*time.Millisecond, 100, time.Minute, logger)
 
 	// İlk başlatma
 	ctx1, cancel1 := context.WithCancel(context.Background())
@@ -92,7 +97,7 @@ func TestSchedulerService_Stop(t *testing.T) {
 	mockService := &mocks.MockMessageService{}
 	logger := &mockLogger{}
 
-	scheduler := NewSchedulerService(mockService, 100*time.Millisecond, logger)
+	scheduler := NewSchedulerService(mockService, 100*time.Millisecond, 100, time.Minute, logger)
 
 	// Scheduler'ı başlat
 	ctx, cancel := context.WithCancel(context.Background())
@@ -108,11 +113,40 @@ func TestSchedulerService_Stop(t *testing.T) {
 	assert.False(t, scheduler.IsRunning())
 }
 
+func TestSchedulerService_RestartIsNotDisabledByPreviousRun(t *testing.T) {
+	scheduler := NewSchedulerService(&mocks.MockMessageService{}, time.Hour, 100, time.Minute, &mockLogger{})
+
+	firstCtx, cancelFirst := context.WithCancel(context.Background())
+	firstDone := make(chan struct{})
+	go func() {
+		defer close(firstDone)
+		_ = scheduler.Start(firstCtx)
+	}()
+	assert.Eventually(t, scheduler.IsRunning, time.Second, time.Millisecond)
+
+	// Liderlik dalgalanması: eski çalışma çıkmadan yenisi başlar
+	scheduler.Stop()
+	secondCtx, cancelSecond := context.WithCancel(context.Background())
+	defer cancelSecond()
+	go func() {
+		_ = scheduler.Start(secondCtx)
+	}()
+	assert.Eventually(t, scheduler.IsRunning, time.Second, time.Millisecond)
+
+	// Eski çalışmanın bağlam üzerinden çıkması yenisini durdurmamalı
+	cancelFirst()
+	<-firstDone
+	assert.True(t, scheduler.IsRunning())
+
+	scheduler.Stop()
+	assert.False(t, scheduler.IsRunning())
+}
+
 func TestSchedulerService_IsRunning(t *testing.T) {
 	mockService := &mocks.MockMessageService{}
 	logger := &mockLogger{}
 
-	scheduler := NewSchedulerService(mockService, 100*time.Millisecond, logger)
+	scheduler := NewSchedulerService(mockService, 100*time.Millisecond, 100, time.Minute, logger)
 
 	// Başlangıçta çalışmıyor olmalı
 	assert.False(t, scheduler.IsRunning())
@@ -135,3 +169,143 @@ func TestSchedulerService_IsRunning(t *testing.T) {
 	// Tekrar çalışmıyor olmalı
 	assert.False(t, scheduler.IsRunning())
 }
+
+func TestSchedulerService_Status(t *testing.T) {
+	mockService := &mocks.MockMessageService{}
+	logger := &mockLogger{}
+
+	scheduler := NewSchedulerService(mockService, 50*time.Millisecond, 100, time.Minute, logger)
+
+	sent, failed := createTestMessage(), createTestMessage()
+	failed.ID = 124
+	mockService.On("RequeueStale", time.Minute).Return(0, nil)
+	mockService.On("GetPendingMessages", 100).Return([]*domain.Message{sent, failed}, nil)
+	mockService.On("Publish", sent).Return(nil)
+	mockService.On("Publish", failed).Return(assert.AnError)
+
+	ticks := make(chan domain.SchedulerStatus, 10)
+	scheduler.OnTick(func(status domain.SchedulerStatus) { ticks <- status })
+
+	// Başlamadan önce istatistik olmamalı
+	status := scheduler.Status()
+	assert.False(t, status.Running)
+	assert.Nil(t, status.LastTickAt)
+	assert.Nil(t, status.NextTickAt)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		_ = scheduler.Start(ctx)
+	}()
+
+	select {
+	case status = <-ticks:
+	case <-time.After(time.Second):
+		t.Fatal("scheduler did not tick")
+	}
+
+	assert.True(t, status.Running)
+	assert.Equal(t, 50*time.Millisecond, status.Interval)
+	assert.NotNil(t, status.LastTickAt)
+	assert.NotNil(t, status.NextTickAt)
+	assert.Equal(t, 1, status.LastTickPublished)
+	assert.Equal(t, 1, status.LastTickFailed)
+	assert.Equal(t, int64(1), status.TotalTicks)
+	assert.Contains(t, status.LastError, "failed to publish message 124")
+
+	scheduler.Stop()
+	assert.Eventually(t, func() bool { return scheduler.Status().NextTickAt == nil }, time.Second, 10*time.Millisecond)
+}
+
+func TestSchedulerService_SetInterval(t *testing.T) {
+	mockService := &mocks.MockMessageService{}
+	logger := &mockLogger{}
+
+	scheduler := NewSchedulerService(mockService, time.Hour, 100, time.Minute, logger)
+	mockService.On("RequeueStale", time.Minute).Return(0, nil)
+	mockService.On("GetPendingMessages", 100).Return([]*domain.Message{}, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		_ = scheduler.Start(ctx)
+	}()
+	assert.Eventually(t, scheduler.IsRunning, time.Second, 10*time.Millisecond)
+
+	// Çalışan ticker yeniden başlatılmadan yeni aralığı kullanmalı
+	scheduler.SetInterval(20 * time.Millisecond)
+	assert.Eventually(t, func() bool { return scheduler.Status().TotalTicks >= 2 }, time.Second, 10*time.Millisecond)
+
+	scheduler.Stop()
+}
+
+func TestSchedulerService_RequeuesStaleMessagesBeforeClaiming(t *testing.T) {
+	mockService := &mocks.MockMessageService{}
+	logger := &mockLogger{}
+
+	scheduler := NewSchedulerService(mockService, 50*time.Millisecond, 100, time.Minute, logger)
+
+	// Sahiplenilip takılı kalan mesajlar aynı tick'te tekrar sahiplenilebilmeli
+	var mu sync.Mutex
+	var calls []string
+	record := func(call string) func(mock.Arguments) {
+		return func(mock.Arguments) {
+			mu.Lock()
+			defer mu.Unlock()
+			calls = append(calls, call)
+		}
+	}
+	mockService.On("RequeueStale", time.Minute).Return(3, nil).Run(record("requeue")).Once()
+	mockService.On("RequeueStale", time.Minute).Return(0, assert.AnError).Run(record("requeue"))
+	mockService.On("GetPendingMessages", 100).Return([]*domain.Message{}, nil).Run(record("claim"))
+
+	ticks := make(chan domain.SchedulerStatus, 10)
+	scheduler.OnTick(func(status domain.SchedulerStatus) { ticks <- status })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		_ = scheduler.Start(ctx)
+	}()
+
+	var status domain.SchedulerStatus
+	for i := 0; i < 2; i++ {
+		select {
+		case status = <-ticks:
+		case <-time.After(time.Second):
+			t.Fatal("scheduler did not tick")
+		}
+	}
+	scheduler.Stop()
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{"requeue", "claim", "requeue", "claim"}, calls[:4])
+	assert.Contains(t, status.LastError, "failed to requeue stale messages")
+}
+
+func TestSchedulerService_ClaimsOnlyWhatIsLeftOfMaxInFlight(t *testing.T) {
+	mockService := &mocks.MockMessageService{}
+
+	scheduler := NewSchedulerService(mockService, time.Second, 100, time.Minute, &mockLogger{})
+	scheduler.SetMaxInFlight(250)
+
+	mockService.On("RequeueStale", time.Minute).Return(0, nil)
+
+	// Tüketicilerin henüz bitirmediği 180 mesaj varken yalnızca 70 yer kalır
+	mockService.On("CountInFlight").Return(180, nil).Once()
+	mockService.On("GetPendingMessages", 70).Return([]*domain.Message{}, nil).Once()
+	scheduler.tick()
+
+	// Sınır doluysa hiç mesaj sahiplenilmemeli
+	mockService.On("CountInFlight").Return(250, nil).Once()
+	scheduler.tick()
+
+	// Sayım başarısızsa da sahiplenme yapılmamalı
+	mockService.On("CountInFlight").Return(0, assert.AnError).Once()
+	scheduler.tick()
+
+	mockService.AssertNumberOfCalls(t, "GetPendingMessages", 1)
+	assert.Contains(t, scheduler.Status().LastError, "failed to count messages in flight")
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,538 @@
+package smpp
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ercancavusoglu/messaging/internal/config"
+	"github.com/ercancavusoglu/messaging/internal/domain"
+	"github.com/ercancavusoglu/messaging/internal/domain/valueobject"
+)
+
+const (
+	defaultTimeout        = 10 * time.Second
+	defaultEnquireLink    = 30 * time.Second
+	defaultWindow         = 10
+	defaultReconnectDelay = 5 * time.Second
+
+	// maxParts is the most parts the concatenation header can number; the content was already checked
+	// against the segment limit when the message was created
+	maxParts = 255
+)
+
+var ErrClientClosed = errors.New("smpp client is closed")
+
+// Client sends messages to an SMSC over an SMPP 3.4 session described by a config.Provider, so it
+// plugs into RetryableWebhookClient like the HTTP providers. It keeps one session bound in the
+// background, re-binding after ReconnectDelay when the session is lost, and reports the delivery
+// receipts of a transceiver session to onReceipt. A long message is tracked by its first part's ID.
+type Client struct {
+	spec      config.Provider
+	onReceipt func(domain.DeliveryReceipt)
+	window    chan struct{}
+	sequence  uint32
+	reference uint32
+
+	mu      sync.Mutex
+	session *session
+	bound   chan struct{}
+	lastErr error
+	closed  bool
+	sending sync.WaitGroup
+
+	closing chan struct{}
+	done    chan struct{}
+}
+
+// NewClient fills in the defaults of the settings the spec leaves empty and starts binding
+func NewClient(spec config.Provider, onReceipt func(domain.DeliveryReceipt)) *Client {
+	if spec.Timeout == 0 {
+		spec.Timeout = defaultTimeout
+	}
+	if spec.SMPP.Bind == "" {
+		spec.SMPP.Bind = config.BindTransceiver
+	}
+	if spec.SMPP.EnquireLink == 0 {
+		spec.SMPP.EnquireLink = defaultEnquireLink
+	}
+	if spec.SMPP.Window == 0 {
+		spec.SMPP.Window = defaultWindow
+	}
+	if spec.SMPP.ReconnectDelay == 0 {
+		spec.SMPP.ReconnectDelay = defaultReconnectDelay
+	}
+
+	c := &Client{
+		spec:      spec,
+		onReceipt: onReceipt,
+		window:    make(chan struct{}, spec.SMPP.Window),
+		bound:     make(chan struct{}),
+		lastErr:   errors.New("not bound yet"),
+		closing:   make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	go c.run()
+
+	return c
+}
+
+func (c *Client) Name() string {
+	return c.spec.Name
+}
+
+// SendMessage submits the content in as many parts as it needs, waiting up to the provider timeout
+// for a bound session and a free window slot
+func (c *Client) SendMessage(msg *domain.Message) (*domain.WebhookResponse, error) {
+	log.Printf("[SMPP] Sending message through %s [to: %s]", c.spec.Name, msg.To)
+
+	mc, err := valueobject.NewMessageContent(msg.Content, maxParts)
+	if err != nil {
+		return nil, fmt.Errorf("invalid message content: %v", err)
+	}
+
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return nil, ErrClientClosed
+	}
+	c.sending.Add(1)
+	c.mu.Unlock()
+	defer c.sending.Done()
+
+	deadline := time.NewTimer(c.spec.Timeout)
+	defer deadline.Stop()
+
+	s, err := c.waitSession(deadline.C)
+	if err != nil {
+		return nil, err
+	}
+
+	messages := c.shortMessages(msg.To, mc)
+	var firstID string
+	for i, message := range messages {
+		messageID, err := c.submit(s, message, deadline.C)
+		if err != nil {
+			return nil, fmt.Errorf("failed to submit part %d of %d: %v", i+1, len(messages), err)
+		}
+		if i == 0 {
+			firstID = messageID
+		}
+	}
+
+	log.Printf("[SMPP] Message accepted by %s [id: %s, parts: %d]", c.spec.Name, firstID, len(messages))
+
+	return &domain.WebhookResponse{
+		MessageID: firstID,
+		Provider:  c.spec.Name,
+	}, nil
+}
+
+// shortMessages splits the content into submit_sm messages, prefixing each part of a long message
+// with the concatenation header: IEI 0x00, a reference shared by the parts, the part count and number
+func (c *Client) shortMessages(to string, mc *valueobject.MessageContent) []shortMessage {
+	template := shortMessage{
+		sourceAddr:      c.spec.SMPP.SourceAddr,
+		destinationAddr: strings.TrimPrefix(to, "+"),
+		dataCoding:      codingDefault,
+	}
+	if mc.Encoding() == valueobject.EncodingUCS2 {
+		template.dataCoding = codingUCS2
+	}
+	if c.spec.SMPP.Bind == config.BindTransceiver {
+		template.registeredDelivery = 1
+	}
+
+	parts := mc.Parts()
+	if len(parts) == 1 {
+		template.message = parts[0]
+		return []shortMessage{template}
+	}
+
+	reference := byte(atomic.AddUint32(&c.reference, 1))
+	messages := make([]shortMessage, len(parts))
+	for i, part := range parts {
+		message := template
+		message.esmClass = esmUDHI
+		message.message = append([]byte{0x05, 0x00, 0x03, reference, byte(len(parts)), byte(i + 1)}, part...)
+		messages[i] = message
+	}
+	return messages
+}
+
+func (c *Client) submit(s *session, message shortMessage, deadline <-chan time.Time) (string, error) {
+	select {
+	case c.window <- struct{}{}:
+	case <-deadline:
+		return "", errors.New("timed out waiting for a free window slot")
+	}
+	defer func() { <-c.window }()
+
+	resp, err := s.request(pdu{commandID: cmdSubmitSM, sequence: c.nextSequence(), body: message.marshal()}, deadline)
+	if err != nil {
+		return "", err
+	}
+	if resp.status != StatusOK {
+		return "", fmt.Errorf("submit_sm rejected with status 0x%08X", resp.status)
+	}
+
+	r := &bodyReader{data: resp.body}
+	messageID := r.cstring()
+	if r.err != nil || messageID == "" {
+		return "", errors.New("submit_sm_resp carries no message ID")
+	}
+	return messageID, nil
+}
+
+func (c *Client) waitSession(deadline <-chan time.Time) (*session, error) {
+	for {
+		c.mu.Lock()
+		s, bound := c.session, c.bound
+		c.mu.Unlock()
+
+		if s != nil {
+			return s, nil
+		}
+
+		select {
+		case <-bound:
+		case <-c.closing:
+			return nil, ErrClientClosed
+		case <-deadline:
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			return nil, fmt.Errorf("not bound to %s: %v", c.spec.Name, c.lastErr)
+		}
+	}
+}
+
+func (c *Client) nextSequence() uint32 {
+	// Sequence numbers run from 1 to 0x7FFFFFFF
+	return atomic.AddUint32(&c.sequence, 1)%0x7FFFFFFF + 1
+}
+
+// Close stops reconnecting, waits for the messages being sent and unbinds the session
+func (c *Client) Close() error {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return nil
+	}
+	c.closed = true
+	c.mu.Unlock()
+
+	c.sending.Wait()
+	close(c.closing)
+
+	c.mu.Lock()
+	s := c.session
+	c.mu.Unlock()
+
+	if s != nil {
+		deadline := time.NewTimer(c.spec.Timeout)
+		if _, err := s.request(pdu{commandID: cmdUnbind, sequence: c.nextSequence()}, deadline.C); err != nil {
+			log.Printf("[SMPP] Failed to unbind from %s: %v", c.spec.Name, err)
+		}
+		deadline.Stop()
+		s.close()
+	}
+
+	<-c.done
+	return nil
+}
+
+// run keeps a session bound until the client is closed
+func (c *Client) run() {
+	defer close(c.done)
+
+	for {
+		s, err := c.bind()
+		if err != nil {
+			log.Printf("[SMPP] Failed to bind to %s: %v", c.spec.Name, err)
+			c.mu.Lock()
+			c.lastErr = err
+			c.mu.Unlock()
+		} else {
+			c.mu.Lock()
+			select {
+			case <-c.closing:
+				// Close has already looked for a session to unbind
+				c.mu.Unlock()
+				s.close()
+				return
+			default:
+			}
+			log.Printf("[SMPP] Bound to %s as %s", c.spec.Name, c.spec.SMPP.Bind)
+			c.session = s
+			close(c.bound)
+			c.mu.Unlock()
+
+			go c.keepAlive(s)
+			err = c.readLoop(s)
+
+			c.mu.Lock()
+			c.session = nil
+			c.bound = make(chan struct{})
+			c.lastErr = fmt.Errorf("session lost: %v", err)
+			c.mu.Unlock()
+		}
+
+		select {
+		case <-c.closing:
+			return
+		default:
+		}
+		log.Printf("[SMPP] Session with %s ended: %v; reconnecting in %s", c.spec.Name, err, c.spec.SMPP.ReconnectDelay)
+
+		select {
+		case <-c.closing:
+			return
+		case <-time.After(c.spec.SMPP.ReconnectDelay):
+		}
+	}
+}
+
+func (c *Client) bind() (*session, error) {
+	conn, err := net.DialTimeout("tcp", c.spec.SMPP.Addr, c.spec.Timeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect: %v", err)
+	}
+
+	commandID := cmdBindTransceiver
+	if c.spec.SMPP.Bind == config.BindTransmitter {
+		commandID = cmdBindTransmitter
+	}
+
+	request := pdu{
+		commandID: commandID,
+		sequence:  c.nextSequence(),
+		body:      bindBody(c.spec.SMPP.SystemID, c.spec.SMPP.Password, c.spec.SMPP.SystemType),
+	}
+
+	conn.SetDeadline(time.Now().Add(c.spec.Timeout))
+	if _, err := conn.Write(request.marshal()); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to send bind: %v", err)
+	}
+
+	resp, err := readPDU(conn)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to read bind response: %v", err)
+	}
+	if resp.commandID != commandID|responseBit || resp.sequence != request.sequence {
+		conn.Close()
+		return nil, fmt.Errorf("unexpected response 0x%08X to bind", resp.commandID)
+	}
+	if resp.status != StatusOK {
+		conn.Close()
+		return nil, fmt.Errorf("bind rejected with status 0x%08X", resp.status)
+	}
+	conn.SetDeadline(time.Time{})
+
+	return newSession(conn), nil
+}
+
+// readLoop hands responses to their requests and answers the SMSC's requests until the session ends
+func (c *Client) readLoop(s *session) error {
+	defer s.close()
+
+	for {
+		p, err := readPDU(s.conn)
+		if err != nil {
+			return err
+		}
+
+		if p.isResponse() {
+			s.resolve(p)
+			continue
+		}
+
+		switch p.commandID {
+		case cmdEnquireLink:
+			s.write(pdu{commandID: cmdEnquireLink | responseBit, sequence: p.sequence})
+		case cmdDeliverSM:
+			// deliver_sm_resp carries an empty message_id
+			s.write(pdu{commandID: cmdDeliverSM | responseBit, sequence: p.sequence, body: []byte{0}})
+			c.handleDeliver(p)
+		case cmdUnbind:
+			s.write(pdu{commandID: cmdUnbind | responseBit, sequence: p.sequence})
+			return errors.New("unbound by the SMSC")
+		default:
+			s.write(pdu{commandID: cmdGenericNack, status: StatusInvalidCmdID, sequence: p.sequence})
+		}
+	}
+}
+
+func (c *Client) handleDeliver(p pdu) {
+	message, err := parseShortMessage(p.body)
+	if err != nil {
+		log.Printf("[SMPP] Invalid deliver_sm from %s: %v", c.spec.Name, err)
+		return
+	}
+
+	if message.esmClass&esmDeliveryReceipt == 0 {
+		log.Printf("[SMPP] Ignoring mobile originated message from %s [from: %s]", c.spec.Name, message.sourceAddr)
+		return
+	}
+
+	receipt, err := parseReceipt(message)
+	if err != nil {
+		log.Printf("[SMPP] Invalid delivery receipt from %s: %v", c.spec.Name, err)
+		return
+	}
+	receipt.Provider = c.spec.Name
+
+	if c.onReceipt != nil {
+		go c.onReceipt(receipt)
+	}
+}
+
+// keepAlive sends enquire_link every interval and ends the session when one goes unanswered
+func (c *Client) keepAlive(s *session) {
+	ticker := time.NewTicker(c.spec.SMPP.EnquireLink)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.closed:
+			return
+		case <-ticker.C:
+			deadline := time.NewTimer(c.spec.SMPP.EnquireLink)
+			_, err := s.request(pdu{commandID: cmdEnquireLink, sequence: c.nextSequence()}, deadline.C)
+			deadline.Stop()
+			if err != nil {
+				log.Printf("[SMPP] enquire_link to %s failed: %v", c.spec.Name, err)
+				s.close()
+				return
+			}
+		}
+	}
+}
+
+// messageStates maps the message_state TLV to receipt statuses
+var messageStates = map[byte]string{
+	2: domain.ReceiptDelivered,
+	3: domain.ReceiptExpired,
+	4: domain.ReceiptDeleted,
+	5: domain.ReceiptUndelivered,
+	7: domain.ReceiptUnknown,
+	8: domain.ReceiptRejected,
+}
+
+// parseReceipt reads a delivery receipt from the receipted_message_id and message_state TLVs, falling
+// back to the "id:... stat:... err:..." text SMSCs put in the short message
+func parseReceipt(message shortMessage) (domain.DeliveryReceipt, error) {
+	text := string(message.message)
+	receipt := domain.DeliveryReceipt{
+		MessageID: receiptField(text, "id"),
+		Status:    receiptField(text, "stat"),
+	}
+	if code := receiptField(text, "err"); code != "" && strings.Trim(code, "0") != "" {
+		receipt.Error = "error " + code
+	}
+
+	if id, ok := message.params[tagReceiptedMessageID]; ok {
+		receipt.MessageID = strings.TrimRight(string(id), "\x00")
+	}
+	if state, ok := message.params[tagMessageState]; ok && len(state) == 1 {
+		if status, known := messageStates[state[0]]; known {
+			receipt.Status = status
+		}
+	}
+
+	if receipt.MessageID == "" || receipt.Status == "" {
+		return domain.DeliveryReceipt{}, fmt.Errorf("no message ID or status in %q", text)
+	}
+	return receipt, nil
+}
+
+func receiptField(text, name string) string {
+	for _, field := range strings.Fields(text) {
+		if value, ok := strings.CutPrefix(field, name+":"); ok {
+			return value
+		}
+	}
+	return ""
+}
+
+// session is one bound connection; requests wait for the response carrying their sequence number
+type session struct {
+	conn      net.Conn
+	writeMu   sync.Mutex
+	pendingMu sync.Mutex
+	pending   map[uint32]chan pdu
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+func newSession(conn net.Conn) *session {
+	return &session{
+		conn:    conn,
+		pending: make(map[uint32]chan pdu),
+		closed:  make(chan struct{}),
+	}
+}
+
+func (s *session) write(p pdu) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	_, err := s.conn.Write(p.marshal())
+	return err
+}
+
+func (s *session) request(p pdu, deadline <-chan time.Time) (pdu, error) {
+	response := make(chan pdu, 1)
+	s.pendingMu.Lock()
+	s.pending[p.sequence] = response
+	s.pendingMu.Unlock()
+
+	defer func() {
+		s.pendingMu.Lock()
+		delete(s.pending, p.sequence)
+		s.pendingMu.Unlock()
+	}()
+
+	if err := s.write(p); err != nil {
+		s.close()
+		return pdu{}, fmt.Errorf("failed to write PDU: %v", err)
+	}
+
+	select {
+	case resp := <-response:
+		if resp.commandID == cmdGenericNack {
+			return pdu{}, fmt.Errorf("request rejected with generic_nack status 0x%08X", resp.status)
+		}
+		return resp, nil
+	case <-s.closed:
+		return pdu{}, errors.New("session closed")
+	case <-deadline:
+		return pdu{}, errors.New("timed out waiting for a response")
+	}
+}
+
+func (s *session) resolve(p pdu) {
+	s.pendingMu.Lock()
+	response, ok := s.pending[p.sequence]
+	s.pendingMu.Unlock()
+
+	if ok {
+		select {
+		case response <- p:
+		default:
+		}
+	}
+}
+
+func (s *session) close() {
+	s.closeOnce.Do(func() {
+		close(s.closed)
+		s.conn.Close()
+	})
+}
@@ -0,0 +1,262 @@
+package smpp
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ercancavusoglu/messaging/internal/adapters/webhook"
+	"github.com/ercancavusoglu/messaging/internal/config"
+	"github.com/ercancavusoglu/messaging/internal/domain"
+	"github.com/ercancavusoglu/messaging/internal/ports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSimulator(t *testing.T) *Simulator {
+	simulator, err := NewSimulator("acme", "secret")
+	require.NoError(t, err)
+	t.Cleanup(func() { simulator.Close() })
+	return simulator
+}
+
+func carrier(simulator *Simulator) config.Provider {
+	return config.Provider{
+		Name:    "carrier",
+		Type:    config.TypeSMPP,
+		Timeout: 2 * time.Second,
+		SMPP: config.ProviderSMPP{
+			Addr:           simulator.Addr(),
+			SystemID:       "acme",
+			Password:       "secret",
+			SourceAddr:     "ACME",
+			ReconnectDelay: 50 * time.Millisecond,
+		},
+	}
+}
+
+func newClient(t *testing.T, spec config.Provider, onReceipt func(domain.DeliveryReceipt)) *Client {
+	client := NewClient(spec, onReceipt)
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func sms(to, content string) *domain.Message {
+	return &domain.Message{Channel: domain.ChannelSMS, To: to, Content: content}
+}
+
+func TestClient_SendMessage(t *testing.T) {
+	simulator := newSimulator(t)
+	client := newClient(t, carrier(simulator), nil)
+
+	response, err := client.SendMessage(sms("+905551234567", "Merhaba"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "sim-1", response.MessageID)
+	assert.Equal(t, "carrier", response.Provider)
+
+	submissions := simulator.Submissions()
+	require.Len(t, submissions, 1)
+	assert.Equal(t, "ACME", submissions[0].Source)
+	assert.Equal(t, "905551234567", submissions[0].Destination)
+	assert.Equal(t, codingDefault, submissions[0].DataCoding)
+	assert.Equal(t, byte(1), submissions[0].RegisteredDelivery)
+	assert.Equal(t, []byte("Merhaba"), submissions[0].Message)
+}
+
+func TestClient_LongMessageIsConcatenated(t *testing.T) {
+	simulator := newSimulator(t)
+	client := newClient(t, carrier(simulator), nil)
+
+	// 80 UCS-2 karakter iki parçaya bölünmeli
+	content := strings.Repeat("ş", 80)
+	response, err := client.SendMessage(sms("+905551234567", content))
+	require.NoError(t, err)
+	assert.Equal(t, "sim-1", response.MessageID)
+
+	submissions := simulator.Submissions()
+	require.Len(t, submissions, 2)
+	for i, submission := range submissions {
+		assert.Equal(t, esmUDHI, submission.ESMClass)
+		assert.Equal(t, codingUCS2, submission.DataCoding)
+
+		udh := submission.Message[:6]
+		assert.Equal(t, []byte{0x05, 0x00, 0x03}, udh[:3])
+		assert.Equal(t, submissions[0].Message[3], udh[3], "parts share the reference")
+		assert.Equal(t, byte(2), udh[4])
+		assert.Equal(t, byte(i+1), udh[5])
+	}
+	assert.Len(t, submissions[0].Message[6:], 67*2)
+	assert.Len(t, submissions[1].Message[6:], 13*2)
+}
+
+func TestClient_BindRejected(t *testing.T) {
+	simulator := newSimulator(t)
+	spec := carrier(simulator)
+	spec.SMPP.Password = "wrong"
+	spec.Timeout = 200 * time.Millisecond
+	client := newClient(t, spec, nil)
+
+	_, err := client.SendMessage(sms("+905551234567", "Merhaba"))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "bind rejected with status 0x0000000E")
+	assert.Equal(t, 0, simulator.Binds())
+}
+
+func TestClient_SubmitRejected(t *testing.T) {
+	simulator := newSimulator(t)
+	simulator.SetSubmitStatus(StatusThrottled)
+	client := newClient(t, carrier(simulator), nil)
+
+	_, err := client.SendMessage(sms("+905551234567", "Merhaba"))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "submit_sm rejected with status 0x00000058")
+}
+
+func TestClient_Window(t *testing.T) {
+	simulator := newSimulator(t)
+	simulator.SetResponseDelay(50 * time.Millisecond)
+	spec := carrier(simulator)
+	spec.SMPP.Window = 2
+	client := newClient(t, spec, nil)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.SendMessage(sms("+905551234567", "Merhaba"))
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	assert.Len(t, simulator.Submissions(), 6)
+	assert.Equal(t, 2, simulator.MaxOutstanding())
+}
+
+func TestClient_Reconnect(t *testing.T) {
+	simulator := newSimulator(t)
+	client := newClient(t, carrier(simulator), nil)
+
+	_, err := client.SendMessage(sms("+905551234567", "Merhaba"))
+	require.NoError(t, err)
+
+	simulator.DropConnections()
+
+	// Bağlantı koptuktan sonra istemci yeniden bağlanmalı
+	require.Eventually(t, func() bool { return simulator.Binds() == 2 }, time.Second, 10*time.Millisecond)
+	_, err = client.SendMessage(sms("+905551234567", "Tekrar merhaba"))
+	require.NoError(t, err)
+	assert.Equal(t, 2, simulator.Binds())
+}
+
+func TestClient_EnquireLinkTimeoutReconnects(t *testing.T) {
+	simulator := newSimulator(t)
+	spec := carrier(simulator)
+	spec.SMPP.EnquireLink = 20 * time.Millisecond
+	newClient(t, spec, nil)
+
+	require.Eventually(t, func() bool { return simulator.EnquireLinks() >= 2 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, 1, simulator.Binds())
+
+	simulator.SetSilent(true)
+
+	require.Eventually(t, func() bool { return simulator.Binds() >= 2 }, time.Second, 10*time.Millisecond)
+}
+
+func TestClient_DeliveryReceipts(t *testing.T) {
+	simulator := newSimulator(t)
+	simulator.SetReceiptStatus(domain.ReceiptDelivered)
+
+	receipts := make(chan domain.DeliveryReceipt, 2)
+	client := newClient(t, carrier(simulator), func(receipt domain.DeliveryReceipt) {
+		receipts <- receipt
+	})
+
+	_, err := client.SendMessage(sms("+905551234567", "Merhaba"))
+	require.NoError(t, err)
+
+	select {
+	case receipt := <-receipts:
+		assert.Equal(t, domain.DeliveryReceipt{Provider: "carrier", MessageID: "sim-1", Status: domain.ReceiptDelivered}, receipt)
+	case <-time.After(time.Second):
+		t.Fatal("no delivery receipt")
+	}
+
+	simulator.SendReceipt("sim-1", domain.ReceiptUndelivered)
+
+	select {
+	case receipt := <-receipts:
+		assert.Equal(t, domain.ReceiptUndelivered, receipt.Status)
+		assert.False(t, receipt.Delivered())
+	case <-time.After(time.Second):
+		t.Fatal("no delivery receipt")
+	}
+}
+
+func TestClient_TransmitterDoesNotRequestReceipts(t *testing.T) {
+	simulator := newSimulator(t)
+	spec := carrier(simulator)
+	spec.SMPP.Bind = config.BindTransmitter
+	client := newClient(t, spec, nil)
+
+	_, err := client.SendMessage(sms("+905551234567", "Merhaba"))
+	require.NoError(t, err)
+
+	assert.Equal(t, byte(0), simulator.Submissions()[0].RegisteredDelivery)
+}
+
+func TestClient_CloseUnbinds(t *testing.T) {
+	simulator := newSimulator(t)
+	client := NewClient(carrier(simulator), nil)
+
+	_, err := client.SendMessage(sms("+905551234567", "Merhaba"))
+	require.NoError(t, err)
+
+	require.NoError(t, client.Close())
+
+	_, err = client.SendMessage(sms("+905551234567", "Merhaba"))
+	assert.ErrorIs(t, err, ErrClientClosed)
+	assert.Equal(t, 1, simulator.Binds())
+}
+
+func TestClient_FailsOverInRetryableClient(t *testing.T) {
+	simulator := newSimulator(t)
+	simulator.SetSubmitStatus(StatusSystemError)
+	backup := newSimulator(t)
+
+	spec := carrier(backup)
+	spec.Name = "backup"
+	retryable := webhook.NewRetryableWebhookClient([]ports.WebhookClient{
+		newClient(t, carrier(simulator), nil),
+		newClient(t, spec, nil),
+	}, 2)
+
+	response, err := retryable.SendMessage(sms("+905551234567", "Merhaba"))
+	require.NoError(t, err)
+	assert.Equal(t, "backup", response.Provider)
+}
+
+func TestParseReceipt(t *testing.T) {
+	receipt, err := parseReceipt(shortMessage{
+		message: []byte("id:7f3a sub:001 dlvrd:000 submit date:2410191200 done date:2410191201 stat:UNDELIV err:034 text:Merhaba"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, domain.DeliveryReceipt{MessageID: "7f3a", Status: domain.ReceiptUndelivered, Error: "error 034"}, receipt)
+
+	// TLV'ler metne göre önceliklidir
+	receipt, err = parseReceipt(shortMessage{
+		message: []byte("id:1 stat:ENROUTE"),
+		params:  map[uint16][]byte{tagReceiptedMessageID: []byte("abc\x00"), tagMessageState: {2}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "abc", receipt.MessageID)
+	assert.True(t, receipt.Delivered())
+
+	_, err = parseReceipt(shortMessage{message: []byte("hello")})
+	assert.Error(t, err)
+}
@@ -0,0 +1,256 @@
+package smpp
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Command IDs of the SMPP 3.4 operations the client and the simulator use; a response sets the high bit
+const (
+	cmdGenericNack     uint32 = 0x80000000
+	cmdBindTransmitter uint32 = 0x00000002
+	cmdBindTransceiver uint32 = 0x00000009
+	cmdSubmitSM        uint32 = 0x00000004
+	cmdDeliverSM       uint32 = 0x00000005
+	cmdUnbind          uint32 = 0x00000006
+	cmdEnquireLink     uint32 = 0x00000015
+	responseBit        uint32 = 0x80000000
+	headerLength              = 16
+	maxPDULength              = 64 * 1024
+)
+
+// Command statuses
+const (
+	StatusOK            uint32 = 0x00000000
+	StatusInvalidCmdID  uint32 = 0x00000003
+	StatusSystemError   uint32 = 0x00000008
+	StatusInvalidPasswd uint32 = 0x0000000E
+	StatusThrottled     uint32 = 0x00000058
+)
+
+// Optional parameter tags read from deliver_sm
+const (
+	tagReceiptedMessageID uint16 = 0x001E
+	tagMessageState       uint16 = 0x0427
+)
+
+// esm_class bits
+const (
+	esmUDHI            byte = 0x40
+	esmDeliveryReceipt byte = 0x04
+)
+
+// Data codings of submit_sm
+const (
+	codingDefault byte = 0x00
+	codingUCS2    byte = 0x08
+)
+
+// pdu is a decoded SMPP protocol data unit; the body is kept raw and parsed by the command's reader
+type pdu struct {
+	commandID uint32
+	status    uint32
+	sequence  uint32
+	body      []byte
+}
+
+func (p pdu) isResponse() bool {
+	return p.commandID&responseBit != 0
+}
+
+func (p pdu) marshal() []byte {
+	out := make([]byte, headerLength, headerLength+len(p.body))
+	binary.BigEndian.PutUint32(out[0:], uint32(headerLength+len(p.body)))
+	binary.BigEndian.PutUint32(out[4:], p.commandID)
+	binary.BigEndian.PutUint32(out[8:], p.status)
+	binary.BigEndian.PutUint32(out[12:], p.sequence)
+	return append(out, p.body...)
+}
+
+func readPDU(r io.Reader) (pdu, error) {
+	header := make([]byte, headerLength)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return pdu{}, err
+	}
+
+	length := binary.BigEndian.Uint32(header[0:])
+	if length < headerLength || length > maxPDULength {
+		return pdu{}, fmt.Errorf("invalid PDU length %d", length)
+	}
+
+	p := pdu{
+		commandID: binary.BigEndian.Uint32(header[4:]),
+		status:    binary.BigEndian.Uint32(header[8:]),
+		sequence:  binary.BigEndian.Uint32(header[12:]),
+		body:      make([]byte, length-headerLength),
+	}
+	if _, err := io.ReadFull(r, p.body); err != nil {
+		return pdu{}, err
+	}
+	return p, nil
+}
+
+// bodyWriter appends the mandatory parameters of a PDU body
+type bodyWriter struct {
+	bytes.Buffer
+}
+
+func (w *bodyWriter) cstring(value string) {
+	w.WriteString(value)
+	w.WriteByte(0)
+}
+
+func (w *bodyWriter) octets(value []byte) {
+	w.WriteByte(byte(len(value)))
+	w.Write(value)
+}
+
+// bodyReader reads the mandatory parameters of a PDU body, remembering the first error
+type bodyReader struct {
+	data []byte
+	err  error
+}
+
+var errShortBody = errors.New("PDU body is truncated")
+
+func (r *bodyReader) cstring() string {
+	if r.err != nil {
+		return ""
+	}
+	end := bytes.IndexByte(r.data, 0)
+	if end == -1 {
+		r.err = errShortBody
+		return ""
+	}
+	value := string(r.data[:end])
+	r.data = r.data[end+1:]
+	return value
+}
+
+func (r *bodyReader) byte() byte {
+	if r.err != nil {
+		return 0
+	}
+	if len(r.data) == 0 {
+		r.err = errShortBody
+		return 0
+	}
+	value := r.data[0]
+	r.data = r.data[1:]
+	return value
+}
+
+func (r *bodyReader) octets() []byte {
+	length := int(r.byte())
+	if r.err != nil {
+		return nil
+	}
+	if len(r.data) < length {
+		r.err = errShortBody
+		return nil
+	}
+	value := r.data[:length]
+	r.data = r.data[length:]
+	return value
+}
+
+// tlvs reads the optional parameters left after the mandatory ones
+func (r *bodyReader) tlvs() map[uint16][]byte {
+	params := make(map[uint16][]byte)
+	for r.err == nil && len(r.data) >= 4 {
+		tag := binary.BigEndian.Uint16(r.data[0:])
+		length := int(binary.BigEndian.Uint16(r.data[2:]))
+		if len(r.data) < 4+length {
+			r.err = errShortBody
+			break
+		}
+		params[tag] = r.data[4 : 4+length]
+		r.data = r.data[4+length:]
+	}
+	return params
+}
+
+func bindBody(systemID, password, systemType string) []byte {
+	var w bodyWriter
+	w.cstring(systemID)
+	w.cstring(password)
+	w.cstring(systemType)
+	w.WriteByte(0x34) // interface_version 3.4
+	w.WriteByte(0)    // addr_ton
+	w.WriteByte(0)    // addr_npi
+	w.cstring("")     // address_range
+	return w.Bytes()
+}
+
+// shortMessage holds the parameters of submit_sm and deliver_sm this package uses
+type shortMessage struct {
+	sourceAddr         string
+	destinationAddr    string
+	esmClass           byte
+	registeredDelivery byte
+	dataCoding         byte
+	message            []byte
+	params             map[uint16][]byte
+}
+
+func (m shortMessage) marshal() []byte {
+	var w bodyWriter
+	w.cstring("") // service_type
+	w.WriteByte(0)
+	w.WriteByte(0)
+	w.cstring(m.sourceAddr)
+	w.WriteByte(1) // dest_addr_ton: international
+	w.WriteByte(1) // dest_addr_npi: ISDN
+	w.cstring(m.destinationAddr)
+	w.WriteByte(m.esmClass)
+	w.WriteByte(0) // protocol_id
+	w.WriteByte(0) // priority_flag
+	w.cstring("")  // schedule_delivery_time
+	w.cstring("")  // validity_period
+	w.WriteByte(m.registeredDelivery)
+	w.WriteByte(0) // replace_if_present_flag
+	w.WriteByte(m.dataCoding)
+	w.WriteByte(0) // sm_default_msg_id
+	w.octets(m.message)
+
+	for tag, value := range m.params {
+		var header [4]byte
+		binary.BigEndian.PutUint16(header[0:], tag)
+		binary.BigEndian.PutUint16(header[2:], uint16(len(value)))
+		w.Write(header[:])
+		w.Write(value)
+	}
+	return w.Bytes()
+}
+
+func parseShortMessage(body []byte) (shortMessage, error) {
+	r := &bodyReader{data: body}
+	var m shortMessage
+
+	r.cstring() // service_type
+	r.byte()
+	r.byte()
+	m.sourceAddr = r.cstring()
+	r.byte()
+	r.byte()
+	m.destinationAddr = r.cstring()
+	m.esmClass = r.byte()
+	r.byte()
+	r.byte()
+	r.cstring()
+	r.cstring()
+	m.registeredDelivery = r.byte()
+	r.byte()
+	m.dataCoding = r.byte()
+	r.byte()
+	m.message = r.octets()
+	m.params = r.tlvs()
+
+	if r.err != nil {
+		return shortMessage{}, r.err
+	}
+	return m, nil
+}
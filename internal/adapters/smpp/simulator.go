@@ -0,0 +1,322 @@
+package smpp
+
+import (
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/ercancavusoglu/messaging/internal/domain"
+)
+
+// Submission is a submit_sm received by the Simulator
+type Submission struct {
+	MessageID          string
+	Source             string
+	Destination        string
+	ESMClass           byte
+	DataCoding         byte
+	RegisteredDelivery byte
+	Message            []byte
+}
+
+// Simulator is a local SMSC for tests and development. It accepts binds with its credentials, answers
+// submit_sm with message IDs sim-1, sim-2, ... and enquire_link, and sends delivery receipts to
+// transceiver sessions.
+type Simulator struct {
+	systemID string
+	password string
+	listener net.Listener
+
+	mu             sync.Mutex
+	conns          map[*simConn]bool
+	submissions    []Submission
+	nextID         int
+	sequence       uint32
+	delay          time.Duration
+	submitStatus   uint32
+	receiptStatus  string
+	silent         bool
+	binds          int
+	enquireLinks   int
+	outstanding    int
+	maxOutstanding int
+
+	wg sync.WaitGroup
+}
+
+type simConn struct {
+	net.Conn
+	writeMu     sync.Mutex
+	transceiver bool
+}
+
+func (c *simConn) write(p pdu) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	_, err := c.Write(p.marshal())
+	return err
+}
+
+// NewSimulator starts a simulator on a free local port
+func NewSimulator(systemID, password string) (*Simulator, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen: %v", err)
+	}
+
+	s := &Simulator{
+		systemID: systemID,
+		password: password,
+		listener: listener,
+		conns:    make(map[*simConn]bool),
+	}
+
+	s.wg.Add(1)
+	go s.accept()
+
+	return s, nil
+}
+
+// Addr is the host:port to bind to
+func (s *Simulator) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// SetResponseDelay delays every submit_sm_resp, e.g. to keep submits outstanding
+func (s *Simulator) SetResponseDelay(delay time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.delay = delay
+}
+
+// SetSubmitStatus makes the simulator answer submit_sm with the status, e.g. StatusThrottled
+func (s *Simulator) SetSubmitStatus(status uint32) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.submitStatus = status
+}
+
+// SetReceiptStatus makes the simulator send a receipt with the status, e.g. DELIVRD, after every
+// accepted submit; an empty status turns automatic receipts off
+func (s *Simulator) SetReceiptStatus(status string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.receiptStatus = status
+}
+
+// SetSilent stops the simulator from answering enquire_link, as a hung SMSC would
+func (s *Simulator) SetSilent(silent bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.silent = silent
+}
+
+func (s *Simulator) Submissions() []Submission {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Submission(nil), s.submissions...)
+}
+
+// Binds counts the successful binds
+func (s *Simulator) Binds() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.binds
+}
+
+func (s *Simulator) EnquireLinks() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.enquireLinks
+}
+
+// MaxOutstanding is the largest number of submits awaiting a response at once
+func (s *Simulator) MaxOutstanding() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.maxOutstanding
+}
+
+// SendReceipt sends a delivery receipt for the message to every transceiver session
+func (s *Simulator) SendReceipt(messageID, status string) {
+	s.mu.Lock()
+	conns := make([]*simConn, 0, len(s.conns))
+	for conn := range s.conns {
+		if conn.transceiver {
+			conns = append(conns, conn)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, conn := range conns {
+		s.sendReceipt(conn, messageID, status)
+	}
+}
+
+// DropConnections closes every session without unbinding, as a network failure would
+func (s *Simulator) DropConnections() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for conn := range s.conns {
+		conn.Close()
+	}
+}
+
+func (s *Simulator) Close() error {
+	err := s.listener.Close()
+	s.DropConnections()
+	s.wg.Wait()
+	return err
+}
+
+func (s *Simulator) accept() {
+	defer s.wg.Done()
+
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		s.wg.Add(1)
+		go s.serve(&simConn{Conn: conn})
+	}
+}
+
+func (s *Simulator) serve(conn *simConn) {
+	defer s.wg.Done()
+	defer conn.Close()
+
+	s.mu.Lock()
+	s.conns[conn] = true
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+	}()
+
+	bound := false
+	for {
+		p, err := readPDU(conn)
+		if err != nil {
+			return
+		}
+
+		switch p.commandID {
+		case cmdBindTransmitter, cmdBindTransceiver:
+			r := &bodyReader{data: p.body}
+			systemID, password := r.cstring(), r.cstring()
+
+			status := StatusOK
+			if systemID != s.systemID || password != s.password {
+				status = StatusInvalidPasswd
+			}
+			conn.write(pdu{commandID: p.commandID | responseBit, status: status, sequence: p.sequence, body: []byte("simulator\x00")})
+			if status != StatusOK {
+				return
+			}
+
+			bound = true
+			s.mu.Lock()
+			s.binds++
+			conn.transceiver = p.commandID == cmdBindTransceiver
+			s.mu.Unlock()
+		case cmdSubmitSM:
+			if !bound {
+				conn.write(pdu{commandID: cmdGenericNack, status: StatusInvalidCmdID, sequence: p.sequence})
+				continue
+			}
+			s.wg.Add(1)
+			go s.submit(conn, p)
+		case cmdEnquireLink:
+			s.mu.Lock()
+			s.enquireLinks++
+			silent := s.silent
+			s.mu.Unlock()
+			if !silent {
+				conn.write(pdu{commandID: cmdEnquireLink | responseBit, sequence: p.sequence})
+			}
+		case cmdUnbind:
+			conn.write(pdu{commandID: cmdUnbind | responseBit, sequence: p.sequence})
+			return
+		case cmdDeliverSM | responseBit:
+		default:
+			conn.write(pdu{commandID: cmdGenericNack, status: StatusInvalidCmdID, sequence: p.sequence})
+		}
+	}
+}
+
+func (s *Simulator) submit(conn *simConn, p pdu) {
+	defer s.wg.Done()
+
+	message, err := parseShortMessage(p.body)
+
+	s.mu.Lock()
+	s.outstanding++
+	if s.outstanding > s.maxOutstanding {
+		s.maxOutstanding = s.outstanding
+	}
+	delay, status, receiptStatus, transceiver := s.delay, s.submitStatus, s.receiptStatus, conn.transceiver
+	if err != nil {
+		status = StatusSystemError
+	}
+
+	var messageID string
+	if status == StatusOK {
+		s.nextID++
+		messageID = fmt.Sprintf("sim-%d", s.nextID)
+		s.submissions = append(s.submissions, Submission{
+			MessageID:          messageID,
+			Source:             message.sourceAddr,
+			Destination:        message.destinationAddr,
+			ESMClass:           message.esmClass,
+			DataCoding:         message.dataCoding,
+			RegisteredDelivery: message.registeredDelivery,
+			Message:            append([]byte(nil), message.message...),
+		})
+	}
+	s.mu.Unlock()
+
+	time.Sleep(delay)
+
+	s.mu.Lock()
+	s.outstanding--
+	s.mu.Unlock()
+
+	var body []byte
+	if status == StatusOK {
+		body = append([]byte(messageID), 0)
+	}
+	conn.write(pdu{commandID: cmdSubmitSM | responseBit, status: status, sequence: p.sequence, body: body})
+
+	if status == StatusOK && receiptStatus != "" && transceiver && message.registeredDelivery != 0 {
+		s.sendReceipt(conn, messageID, receiptStatus)
+	}
+}
+
+// sendReceipt sends the receipt text SMSCs commonly use; the receipted_message_id TLV is left out so
+// clients have to read the text
+func (s *Simulator) sendReceipt(conn *simConn, messageID, status string) {
+	delivered := "000"
+	if status == domain.ReceiptDelivered {
+		delivered = "001"
+	}
+	date := time.Now().Format("0601021504")
+
+	s.mu.Lock()
+	s.sequence++
+	sequence := s.sequence
+	s.mu.Unlock()
+
+	receipt := shortMessage{
+		esmClass: esmDeliveryReceipt,
+		message: []byte(fmt.Sprintf("id:%s sub:001 dlvrd:%s submit date:%s done date:%s stat:%s err:000 text:",
+			messageID, delivered, date, date, status)),
+	}
+	conn.write(pdu{commandID: cmdDeliverSM, sequence: sequence, body: receipt.marshal()})
+}
@@ -0,0 +1,177 @@
+package stream
+
+import (
+	"sync"
+
+	"github.com/ercancavusoglu/messaging/internal/domain"
+	"github.com/ercancavusoglu/messaging/internal/ports"
+)
+
+// subscriptionBuffer is how many updates a subscriber may fall behind before it is dropped
+const subscriptionBuffer = 64
+
+// Events are the message events the stream carries
+var Events = []string{domain.EventMessageQueued, domain.EventMessageSent, domain.EventMessageFailed}
+
+// Filter selects the updates a subscriber receives; zero fields match everything
+type Filter struct {
+	// TenantID limits the stream to one tenant's messages
+	TenantID  int64
+	Statuses  []domain.MessageStatus
+	Provider  string
+	MessageID int64
+}
+
+func (f Filter) Matches(update *domain.MessageUpdate) bool {
+	msg := update.Message
+	if f.TenantID != 0 && msg.TenantID != f.TenantID {
+		return false
+	}
+	if f.MessageID != 0 && msg.ID != f.MessageID {
+		return false
+	}
+	if f.Provider != "" && msg.Provider != f.Provider {
+		return false
+	}
+	if len(f.Statuses) == 0 {
+		return true
+	}
+	for _, status := range f.Statuses {
+		if msg.Status == status {
+			return true
+		}
+	}
+	return false
+}
+
+// Subscription receives the updates matching its filter until it is closed
+type Subscription struct {
+	filter  Filter
+	updates chan *domain.MessageUpdate
+	done    chan struct{}
+	// overflowed is set when the subscriber fell too far behind and was dropped
+	overflowed bool
+}
+
+func (s *Subscription) Updates() <-chan *domain.MessageUpdate {
+	return s.updates
+}
+
+// Done is closed when the hub drops the subscription: because it fell behind, see Overflowed, or
+// because the hub was closed
+func (s *Subscription) Done() <-chan struct{} {
+	return s.done
+}
+
+// Overflowed may only be read once Done is closed
+func (s *Subscription) Overflowed() bool {
+	return s.overflowed
+}
+
+// Hub fans message events out to the live stream's subscribers. Publishing never blocks on a subscriber:
+// one that is a full buffer behind is dropped, so a slow dashboard cannot hold up the others, and can
+// reconnect and reload what it missed.
+type Hub struct {
+	mu            sync.Mutex
+	subscriptions map[*Subscription]struct{}
+	closed        bool
+	logger        ports.Logger
+}
+
+func NewHub(logger ports.Logger) *Hub {
+	return &Hub{
+		subscriptions: make(map[*Subscription]struct{}),
+		logger:        logger,
+	}
+}
+
+func (h *Hub) Subscribe(filter Filter) *Subscription {
+	subscription := &Subscription{
+		filter:  filter,
+		updates: make(chan *domain.MessageUpdate, subscriptionBuffer),
+		done:    make(chan struct{}),
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		close(subscription.done)
+		return subscription
+	}
+	h.subscriptions[subscription] = struct{}{}
+
+	return subscription
+}
+
+func (h *Hub) Unsubscribe(subscription *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.drop(subscription)
+}
+
+// HandleEvent is observed on the event bus. It never fails: the stream is best effort.
+func (h *Hub) HandleEvent(e ports.Event) error {
+	envelope, ok := e.(*domain.EventEnvelope)
+	if !ok {
+		h.logger.Errorf("[Stream] Unexpected event type %T", e)
+		return nil
+	}
+
+	update, err := domain.NewMessageUpdate(envelope)
+	if err != nil {
+		h.logger.Errorf("[Stream] Failed to decode event: %v", err)
+		return nil
+	}
+
+	h.Publish(update)
+	return nil
+}
+
+// Publish hands the update to every matching subscriber
+func (h *Hub) Publish(update *domain.MessageUpdate) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for subscription := range h.subscriptions {
+		if !subscription.filter.Matches(update) {
+			continue
+		}
+
+		select {
+		case subscription.updates <- update:
+		default:
+			h.logger.Warnf("[Stream] Dropping a subscriber %d updates behind", subscriptionBuffer)
+			subscription.overflowed = true
+			h.drop(subscription)
+		}
+	}
+}
+
+// Close drops every subscription, ending their streams, e.g. so the HTTP server can shut down
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+	for subscription := range h.subscriptions {
+		h.drop(subscription)
+	}
+}
+
+// Subscribers returns the number of open subscriptions
+func (h *Hub) Subscribers() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return len(h.subscriptions)
+}
+
+func (h *Hub) drop(subscription *Subscription) {
+	if _, ok := h.subscriptions[subscription]; !ok {
+		return
+	}
+	delete(h.subscriptions, subscription)
+	close(subscription.done)
+}
@@ -0,0 +1,106 @@
+package stream
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ercancavusoglu/messaging/internal/domain"
+	"github.com/ercancavusoglu/messaging/internal/ports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockLogger struct {
+	ports.Logger
+}
+
+func (m *mockLogger) Errorf(format string, args ...interface{}) {}
+func (m *mockLogger) Warnf(format string, args ...interface{})  {}
+
+func update(id, tenantID int64, status domain.MessageStatus, provider string) *domain.MessageUpdate {
+	return &domain.MessageUpdate{
+		Event:   "message." + string(status),
+		Message: &domain.Message{ID: id, TenantID: tenantID, Status: status, Provider: provider},
+	}
+}
+
+func TestFilter_Matches(t *testing.T) {
+	sent := update(11, 7, domain.StatusSent, "client_one")
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   bool
+	}{
+		{name: "everything", filter: Filter{}, want: true},
+		{name: "own tenant", filter: Filter{TenantID: 7}, want: true},
+		{name: "other tenant", filter: Filter{TenantID: 8}, want: false},
+		{name: "status", filter: Filter{Statuses: []domain.MessageStatus{domain.StatusFailed, domain.StatusSent}}, want: true},
+		{name: "other status", filter: Filter{Statuses: []domain.MessageStatus{domain.StatusFailed}}, want: false},
+		{name: "provider", filter: Filter{Provider: "client_one"}, want: true},
+		{name: "other provider", filter: Filter{Provider: "client_two"}, want: false},
+		{name: "message", filter: Filter{MessageID: 11}, want: true},
+		{name: "other message", filter: Filter{MessageID: 12}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.filter.Matches(sent))
+		})
+	}
+}
+
+func TestHub_HandleEvent(t *testing.T) {
+	hub := NewHub(&mockLogger{})
+	subscription := hub.Subscribe(Filter{TenantID: 7})
+
+	event := domain.NewMessageSentEvent(&domain.Message{ID: 11, TenantID: 7, Status: domain.StatusQueued}, "prov-1", "client_one")
+	data, err := json.Marshal(&event)
+	require.NoError(t, err)
+
+	err = hub.HandleEvent(&domain.EventEnvelope{Name: domain.EventMessageSent, Data: data})
+	assert.NoError(t, err)
+
+	got := <-subscription.Updates()
+	assert.Equal(t, domain.StatusSent, got.Message.Status)
+	assert.Equal(t, "client_one", got.Message.Provider)
+
+	// Bozuk olaylar akışı durdurmamalı
+	assert.NoError(t, hub.HandleEvent(&domain.EventEnvelope{Name: domain.EventMessageSent, Data: []byte("{")}))
+}
+
+func TestHub_DropsSlowSubscribers(t *testing.T) {
+	hub := NewHub(&mockLogger{})
+	slow := hub.Subscribe(Filter{})
+	other := hub.Subscribe(Filter{MessageID: 1})
+
+	for i := int64(1); i <= subscriptionBuffer+1; i++ {
+		hub.Publish(update(i, 7, domain.StatusSent, ""))
+	}
+
+	select {
+	case <-slow.Done():
+		assert.True(t, slow.Overflowed())
+	default:
+		t.Fatal("slow subscriber was not dropped")
+	}
+
+	// Yavaş abone diğerlerini etkilememeli
+	assert.Len(t, other.Updates(), 1)
+	assert.Equal(t, 1, hub.Subscribers())
+}
+
+func TestHub_Close(t *testing.T) {
+	hub := NewHub(&mockLogger{})
+	subscription := hub.Subscribe(Filter{})
+
+	hub.Close()
+
+	<-subscription.Done()
+	assert.False(t, subscription.Overflowed())
+	assert.Equal(t, 0, hub.Subscribers())
+
+	late := hub.Subscribe(Filter{})
+	<-late.Done()
+	hub.Unsubscribe(late)
+}
@@ -0,0 +1,146 @@
+package adapters
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ercancavusoglu/messaging/internal/adapters/stream"
+	"github.com/ercancavusoglu/messaging/internal/domain"
+)
+
+const (
+	// streamHeartbeat keeps idle streams open through proxies and notices clients that went away
+	streamHeartbeat = 15 * time.Second
+	// streamWriteTimeout disconnects a client that stops reading instead of blocking on it
+	streamWriteTimeout = 10 * time.Second
+	// streamRetry is the reconnect delay suggested to EventSource clients, in milliseconds
+	streamRetry = 3000
+)
+
+var streamStatuses = map[string]domain.MessageStatus{
+	string(domain.StatusQueued): domain.StatusQueued,
+	string(domain.StatusSent):   domain.StatusSent,
+	string(domain.StatusFailed): domain.StatusFailed,
+}
+
+type StreamHandler struct {
+	hub       *stream.Hub
+	heartbeat time.Duration
+}
+
+func NewStreamHandler(hub *stream.Hub) *StreamHandler {
+	return &StreamHandler{
+		hub:       hub,
+		heartbeat: streamHeartbeat,
+	}
+}
+
+// StreamMessages streams message status changes as Server-Sent Events, one "message.queued",
+// "message.sent" or "message.failed" event per change with the update as its JSON data. Tenants only see
+// their own messages; the operator sees every tenant's unless tenant_id is given. A client that falls
+// too far behind gets an "overflow" event and is disconnected.
+func (h *StreamHandler) StreamMessages(w http.ResponseWriter, r *http.Request) {
+	principal, ok := PrincipalFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, errMissingAPIKey)
+		return
+	}
+
+	filter, err := streamFilter(r, principal)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	controller := http.NewResponseController(w)
+	write := func(frame string) error {
+		// Not every ResponseWriter supports deadlines; such a writer simply has none
+		controller.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+		if _, err := fmt.Fprint(w, frame); err != nil {
+			return err
+		}
+		return controller.Flush()
+	}
+
+	subscription := h.hub.Subscribe(filter)
+	defer h.hub.Unsubscribe(subscription)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	if err := write(fmt.Sprintf("retry: %d\n\n", streamRetry)); err != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(h.heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-subscription.Done():
+			if subscription.Overflowed() {
+				write("event: overflow\ndata: {}\n\n")
+			}
+			return
+		case update := <-subscription.Updates():
+			data, err := json.Marshal(update)
+			if err != nil {
+				fmt.Printf("[Handler] Error encoding stream update: %v\n", err)
+				continue
+			}
+			if err := write(fmt.Sprintf("event: %s\ndata: %s\n\n", update.Event, data)); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if err := write(": heartbeat\n\n"); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// streamFilter reads the status (comma separated), provider, message_id and, for the operator, tenant_id
+// query parameters
+func streamFilter(r *http.Request, principal *domain.Principal) (stream.Filter, error) {
+	query := r.URL.Query()
+	filter := stream.Filter{Provider: query.Get("provider")}
+
+	if principal.Tenant != nil {
+		filter.TenantID = principal.Tenant.ID
+	} else if value := query.Get("tenant_id"); value != "" {
+		id, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || id <= 0 {
+			return filter, fmt.Errorf("invalid tenant_id: %q", value)
+		}
+		filter.TenantID = id
+	}
+
+	if value := query.Get("message_id"); value != "" {
+		id, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || id <= 0 {
+			return filter, fmt.Errorf("invalid message_id: %q", value)
+		}
+		filter.MessageID = id
+	}
+
+	if value := query.Get("status"); value != "" {
+		for _, name := range strings.Split(value, ",") {
+			status, ok := streamStatuses[strings.TrimSpace(name)]
+			if !ok {
+				return filter, fmt.Errorf("invalid status %q: must be queued, sent or failed", name)
+			}
+			filter.Statuses = append(filter.Statuses, status)
+		}
+	}
+
+	return filter, nil
+}
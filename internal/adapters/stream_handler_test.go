@@ -0,0 +1,122 @@
+package adapters
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ercancavusoglu/messaging/internal/adapters/mocks"
+	"github.com/ercancavusoglu/messaging/internal/adapters/stream"
+	"github.com/ercancavusoglu/messaging/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newStreamRequest(ctx context.Context, query string, principal *domain.Principal) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/messages/stream"+query, nil)
+	return req.WithContext(WithPrincipal(ctx, principal))
+}
+
+// serveStream runs the handler until publish has run and the stream has had time to write
+func serveStream(t *testing.T, handler *StreamHandler, hub *stream.Hub, req *http.Request, publish func()) *httptest.ResponseRecorder {
+	ctx, cancel := context.WithCancel(req.Context())
+	w := httptest.NewRecorder()
+	done := make(chan struct{})
+
+	go func() {
+		handler.StreamMessages(w, req.WithContext(ctx))
+		close(done)
+	}()
+
+	require.Eventually(t, func() bool { return hub.Subscribers() == 1 }, time.Second, time.Millisecond)
+	publish()
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	<-done
+
+	return w
+}
+
+func TestStreamHandler_StreamMessages(t *testing.T) {
+	hub := stream.NewHub(&mocks.MockLogger{})
+	handler := NewStreamHandler(hub)
+
+	req := newStreamRequest(context.Background(), "?status=sent,failed", domain.TenantPrincipal(&domain.Tenant{ID: 7}))
+	w := serveStream(t, handler, hub, req, func() {
+		hub.Publish(&domain.MessageUpdate{Event: domain.EventMessageSent, Message: &domain.Message{ID: 11, TenantID: 7, Status: domain.StatusSent}})
+		// Başka kiracının ve filtre dışındaki durumların olayları akışa düşmemeli
+		hub.Publish(&domain.MessageUpdate{Event: domain.EventMessageSent, Message: &domain.Message{ID: 12, TenantID: 8, Status: domain.StatusSent}})
+		hub.Publish(&domain.MessageUpdate{Event: domain.EventMessageQueued, Message: &domain.Message{ID: 13, TenantID: 7, Status: domain.StatusQueued}})
+	})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+
+	body := w.Body.String()
+	assert.True(t, strings.HasPrefix(body, "retry: 3000\n\n"))
+	assert.Contains(t, body, "event: message.sent\ndata: {\"event\":\"message.sent\"")
+	assert.Contains(t, body, `"id":11`)
+	assert.NotContains(t, body, `"id":12`)
+	assert.NotContains(t, body, `"id":13`)
+	assert.Equal(t, 0, hub.Subscribers())
+}
+
+func TestStreamHandler_Heartbeat(t *testing.T) {
+	hub := stream.NewHub(&mocks.MockLogger{})
+	handler := NewStreamHandler(hub)
+	handler.heartbeat = 10 * time.Millisecond
+
+	req := newStreamRequest(context.Background(), "", domain.AdminPrincipal())
+	w := serveStream(t, handler, hub, req, func() {})
+
+	assert.Contains(t, w.Body.String(), ": heartbeat\n\n")
+}
+
+func TestStreamHandler_EndsWhenHubCloses(t *testing.T) {
+	hub := stream.NewHub(&mocks.MockLogger{})
+	handler := NewStreamHandler(hub)
+
+	w := httptest.NewRecorder()
+	req := newStreamRequest(context.Background(), "", domain.AdminPrincipal())
+	done := make(chan struct{})
+
+	go func() {
+		handler.StreamMessages(w, req)
+		close(done)
+	}()
+
+	// Sunucu kapanırken açık akışlar beklenmeden sonlandırılmalı
+	require.Eventually(t, func() bool { return hub.Subscribers() == 1 }, time.Second, time.Millisecond)
+	hub.Close()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("stream did not end when the hub closed")
+	}
+	assert.NotContains(t, w.Body.String(), "event: overflow")
+}
+
+func TestStreamHandler_InvalidFilter(t *testing.T) {
+	handler := NewStreamHandler(stream.NewHub(&mocks.MockLogger{}))
+
+	tests := []string{"?status=pending", "?message_id=abc", "?tenant_id=-1"}
+	for _, query := range tests {
+		w := httptest.NewRecorder()
+		handler.StreamMessages(w, newStreamRequest(context.Background(), query, domain.AdminPrincipal()))
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
+
+func TestStreamFilter_TenantCannotWidenScope(t *testing.T) {
+	req := newStreamRequest(context.Background(), "?tenant_id=8&provider=client_one", domain.TenantPrincipal(&domain.Tenant{ID: 7}))
+
+	filter, err := streamFilter(req, domain.TenantPrincipal(&domain.Tenant{ID: 7}))
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), filter.TenantID)
+	assert.Equal(t, "client_one", filter.Provider)
+}
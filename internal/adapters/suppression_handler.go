@@ -0,0 +1,164 @@
+package adapters
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/ercancavusoglu/messaging/internal/domain"
+	"github.com/ercancavusoglu/messaging/internal/ports"
+	"github.com/gorilla/mux"
+)
+
+type SuppressionHandler struct {
+	suppressionService ports.SuppressionService
+}
+
+type addSuppressionRequest struct {
+	Recipient string `json:"recipient"`
+	Reason    string `json:"reason"`
+}
+
+func NewSuppressionHandler(suppressionService ports.SuppressionService) *SuppressionHandler {
+	return &SuppressionHandler{
+		suppressionService: suppressionService,
+	}
+}
+
+func (h *SuppressionHandler) ListSuppressions(w http.ResponseWriter, r *http.Request) {
+	tenantID, ok := suppressionTenantID(r)
+	if !ok {
+		writeError(w, http.StatusForbidden, errMissingTenant)
+		return
+	}
+
+	suppressions, err := h.suppressionService.List(tenantID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, suppressions)
+}
+
+func (h *SuppressionHandler) GetSuppression(w http.ResponseWriter, r *http.Request) {
+	tenantID, ok := suppressionTenantID(r)
+	if !ok {
+		writeError(w, http.StatusForbidden, errMissingTenant)
+		return
+	}
+
+	suppression, err := h.suppressionService.Get(tenantID, mux.Vars(r)["recipient"])
+	if errors.Is(err, domain.ErrSuppressionNotFound) {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, suppression)
+}
+
+func (h *SuppressionHandler) AddSuppression(w http.ResponseWriter, r *http.Request) {
+	tenantID, ok := suppressionTenantID(r)
+	if !ok {
+		writeError(w, http.StatusForbidden, errMissingTenant)
+		return
+	}
+
+	var req addSuppressionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %v", err))
+		return
+	}
+
+	if req.Recipient == "" {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("recipient is required"))
+		return
+	}
+
+	suppression, err := h.suppressionService.Add(tenantID, req.Recipient, req.Reason, domain.SuppressionSourceManual)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, suppression)
+}
+
+func (h *SuppressionHandler) RemoveSuppression(w http.ResponseWriter, r *http.Request) {
+	tenantID, ok := suppressionTenantID(r)
+	if !ok {
+		writeError(w, http.StatusForbidden, errMissingTenant)
+		return
+	}
+
+	err := h.suppressionService.Remove(tenantID, mux.Vars(r)["recipient"])
+	if errors.Is(err, domain.ErrSuppressionNotFound) {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *SuppressionHandler) ImportSuppressions(w http.ResponseWriter, r *http.Request) {
+	tenantID, ok := suppressionTenantID(r)
+	if !ok {
+		writeError(w, http.StatusForbidden, errMissingTenant)
+		return
+	}
+
+	imported, err := h.suppressionService.Import(tenantID, r.Body)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]interface{}{
+			"error":    err.Error(),
+			"imported": imported,
+		})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]int{
+		"imported": imported,
+	})
+}
+
+func (h *SuppressionHandler) ExportSuppressions(w http.ResponseWriter, r *http.Request) {
+	tenantID, ok := suppressionTenantID(r)
+	if !ok {
+		writeError(w, http.StatusForbidden, errMissingTenant)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := h.suppressionService.Export(tenantID, &buf); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", `attachment; filename="suppressions.csv"`)
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+}
+
+// suppressionTenantID returns the tenant whose suppressions the request manages; the admin key manages
+// the global list that applies to every tenant
+func suppressionTenantID(r *http.Request) (int64, bool) {
+	principal, ok := PrincipalFromContext(r.Context())
+	if !ok {
+		return 0, false
+	}
+	if principal.Tenant == nil {
+		return 0, principal.Actor == domain.AdminActor
+	}
+	return principal.Tenant.ID, true
+}
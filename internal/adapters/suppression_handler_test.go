@@ -0,0 +1,105 @@
+package adapters
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ercancavusoglu/messaging/internal/adapters/mocks"
+	"github.com/ercancavusoglu/messaging/internal/domain"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSuppressionHandler_AddSuppression(t *testing.T) {
+	mockService := &mocks.MockSuppressionService{}
+	handler := NewSuppressionHandler(mockService)
+
+	suppression := &domain.Suppression{ID: 1, Recipient: "+905551234567", Reason: "STOP", Source: domain.SuppressionSourceManual}
+	mockService.On("Add", int64(7), "+905551234567", "STOP", domain.SuppressionSourceManual).Return(suppression, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/suppressions", strings.NewReader(`{"recipient":"+905551234567","reason":"STOP"}`))
+	req = req.WithContext(WithTenant(req.Context(), &domain.Tenant{ID: 7}))
+	w := httptest.NewRecorder()
+
+	handler.AddSuppression(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var response domain.Suppression
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Equal(t, "+905551234567", response.Recipient)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestSuppressionHandler_AddSuppression_MissingRecipient(t *testing.T) {
+	mockService := &mocks.MockSuppressionService{}
+	handler := NewSuppressionHandler(mockService)
+
+	req := httptest.NewRequest(http.MethodPost, "/suppressions", strings.NewReader(`{"reason":"STOP"}`))
+	req = req.WithContext(WithTenant(req.Context(), &domain.Tenant{ID: 7}))
+	w := httptest.NewRecorder()
+
+	handler.AddSuppression(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertNotCalled(t, "Add", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSuppressionHandler_RemoveSuppression_NotFound(t *testing.T) {
+	mockService := &mocks.MockSuppressionService{}
+	handler := NewSuppressionHandler(mockService)
+
+	mockService.On("Remove", int64(7), "+905551234567").Return(domain.ErrSuppressionNotFound)
+
+	req := httptest.NewRequest(http.MethodDelete, "/suppressions/+905551234567", nil)
+	req = req.WithContext(WithTenant(req.Context(), &domain.Tenant{ID: 7}))
+	req = mux.SetURLVars(req, map[string]string{"recipient": "+905551234567"})
+	w := httptest.NewRecorder()
+
+	handler.RemoveSuppression(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestSuppressionHandler_ExportSuppressions(t *testing.T) {
+	mockService := &mocks.MockSuppressionService{}
+	handler := NewSuppressionHandler(mockService)
+
+	mockService.On("Export", int64(7), mock.Anything).Run(func(args mock.Arguments) {
+		w := args.Get(1).(interface{ WriteString(string) (int, error) })
+		w.WriteString("recipient,reason,source,created_at\n")
+	}).Return(nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/suppressions/export", nil)
+	req = req.WithContext(WithTenant(req.Context(), &domain.Tenant{ID: 7}))
+	w := httptest.NewRecorder()
+
+	handler.ExportSuppressions(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/csv", w.Header().Get("Content-Type"))
+	assert.Equal(t, "recipient,reason,source,created_at\n", w.Body.String())
+}
+
+func TestSuppressionHandler_AdminManagesGlobalList(t *testing.T) {
+	mockService := &mocks.MockSuppressionService{}
+	handler := NewSuppressionHandler(mockService)
+
+	mockService.On("Remove", int64(0), "+905551234567").Return(nil)
+
+	req := httptest.NewRequest(http.MethodDelete, "/suppressions/+905551234567", nil)
+	req = mux.SetURLVars(req, map[string]string{"recipient": "+905551234567"})
+	req = req.WithContext(WithPrincipal(req.Context(), domain.AdminPrincipal()))
+	w := httptest.NewRecorder()
+
+	handler.RemoveSuppression(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,193 @@
+package adapters
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/ercancavusoglu/messaging/internal/domain"
+	"github.com/ercancavusoglu/messaging/internal/domain/valueobject"
+	"github.com/ercancavusoglu/messaging/internal/ports"
+)
+
+const (
+	suppressedCacheValue    = "1"
+	notSuppressedCacheValue = "0"
+)
+
+var suppressionCSVHeader = []string{"recipient", "reason", "source", "created_at"}
+
+type suppressionService struct {
+	repo  ports.SuppressionRepository
+	cache ports.Cache
+	// defaultRegion is used to parse recipients written in national format
+	defaultRegion string
+}
+
+func NewSuppressionService(repo ports.SuppressionRepository, cache ports.Cache, defaultRegion string) ports.SuppressionService {
+	return &suppressionService{
+		repo:          repo,
+		cache:         cache,
+		defaultRegion: defaultRegion,
+	}
+}
+
+// IsSuppressed checks the tenant's own suppressions and then the global ones
+func (s *suppressionService) IsSuppressed(tenantID int64, recipient string) (bool, error) {
+	recipient = s.normalizeRecipient(recipient)
+	if tenantID != 0 {
+		suppressed, err := s.listed(tenantID, recipient)
+		if err != nil || suppressed {
+			return suppressed, err
+		}
+	}
+
+	return s.listed(0, recipient)
+}
+
+// listed answers from the cache when possible and falls back to the repository, caching both
+// positive and negative lookups of every list separately
+func (s *suppressionService) listed(tenantID int64, recipient string) (bool, error) {
+	key := suppressionCacheKey(tenantID, recipient)
+	if cached, err := s.cache.Get(key); err == nil && cached != nil {
+		return fmt.Sprint(cached) == suppressedCacheValue, nil
+	}
+
+	_, err := s.repo.ForTenant(tenantID).Get(recipient)
+	if errors.Is(err, domain.ErrSuppressionNotFound) {
+		_ = s.cache.Set(key, notSuppressedCacheValue)
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	_ = s.cache.Set(key, suppressedCacheValue)
+	return true, nil
+}
+
+func (s *suppressionService) Add(tenantID int64, recipient, reason, source string) (*domain.Suppression, error) {
+	recipient = s.normalizeRecipient(recipient)
+	if recipient == "" {
+		return nil, fmt.Errorf("recipient cannot be empty")
+	}
+	if source == "" {
+		source = domain.SuppressionSourceManual
+	}
+
+	suppression := &domain.Suppression{
+		Recipient: recipient,
+		Reason:    reason,
+		Source:    source,
+	}
+	if err := s.repo.ForTenant(tenantID).Add(suppression); err != nil {
+		return nil, err
+	}
+
+	_ = s.cache.Set(suppressionCacheKey(tenantID, recipient), suppressedCacheValue)
+	return suppression, nil
+}
+
+func (s *suppressionService) Remove(tenantID int64, recipient string) error {
+	recipient = s.normalizeRecipient(recipient)
+	if err := s.repo.ForTenant(tenantID).Remove(recipient); err != nil {
+		return err
+	}
+
+	_ = s.cache.Set(suppressionCacheKey(tenantID, recipient), notSuppressedCacheValue)
+	return nil
+}
+
+func (s *suppressionService) Get(tenantID int64, recipient string) (*domain.Suppression, error) {
+	return s.repo.ForTenant(tenantID).Get(s.normalizeRecipient(recipient))
+}
+
+func (s *suppressionService) List(tenantID int64) ([]*domain.Suppression, error) {
+	return s.repo.ForTenant(tenantID).List()
+}
+
+// Import reads recipient,reason,source rows; the header row and the reason/source columns are optional
+func (s *suppressionService) Import(tenantID int64, r io.Reader) (int, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	imported := 0
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return imported, fmt.Errorf("failed to read csv line %d: %v", line, err)
+		}
+
+		if line == 1 && strings.EqualFold(strings.TrimSpace(record[0]), suppressionCSVHeader[0]) {
+			continue
+		}
+
+		reason, source := "", domain.SuppressionSourceImport
+		if len(record) > 1 {
+			reason = record[1]
+		}
+		if len(record) > 2 && strings.TrimSpace(record[2]) != "" {
+			source = strings.TrimSpace(record[2])
+		}
+
+		if _, err := s.Add(tenantID, record[0], reason, source); err != nil {
+			return imported, fmt.Errorf("failed to import csv line %d: %v", line, err)
+		}
+		imported++
+	}
+
+	return imported, nil
+}
+
+func (s *suppressionService) Export(tenantID int64, w io.Writer) error {
+	suppressions, err := s.List(tenantID)
+	if err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(suppressionCSVHeader); err != nil {
+		return fmt.Errorf("failed to write csv header: %v", err)
+	}
+
+	for _, suppression := range suppressions {
+		if err := writer.Write([]string{
+			suppression.Recipient,
+			suppression.Reason,
+			suppression.Source,
+			suppression.CreatedAt.Format(time.RFC3339),
+		}); err != nil {
+			return fmt.Errorf("failed to write csv row: %v", err)
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// suppressionCacheKey keys the list of the tenant, where zero is the global list
+func suppressionCacheKey(tenantID int64, recipient string) string {
+	return fmt.Sprintf("suppression:%d:%s", tenantID, recipient)
+}
+
+// normalizeRecipient converts phone numbers to E.164 so that "0555 123 45 67" and "+905551234567"
+// share a single suppression entry, and lowercases the domain of email addresses the same way messages
+// store them. Anything else, such as a device token, is kept as is.
+func (s *suppressionService) normalizeRecipient(recipient string) string {
+	recipient = strings.TrimSpace(recipient)
+	if phone, err := valueobject.NewPhoneNumber(recipient, s.defaultRegion); err == nil {
+		return phone.String()
+	}
+	if address, err := valueobject.NewEmailAddress(recipient); err == nil {
+		return address.String()
+	}
+
+	return recipient
+}
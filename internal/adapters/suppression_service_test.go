@@ -0,0 +1,198 @@
+package adapters
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ercancavusoglu/messaging/internal/adapters/mocks"
+	"github.com/ercancavusoglu/messaging/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSuppressionService_IsSuppressed_CacheHit(t *testing.T) {
+	mockRepo := &mocks.MockSuppressionRepository{}
+	mockCache := &mocks.MockCache{}
+
+	service := NewSuppressionService(mockRepo, mockCache, "TR")
+
+	mockCache.On("Get", "suppression:7:+905551234567").Return("1", nil)
+
+	suppressed, err := service.IsSuppressed(7, "+905551234567")
+	assert.NoError(t, err)
+	assert.True(t, suppressed)
+
+	// Cache'te bulunduğu için repository'e gidilmemeli
+	mockRepo.AssertNotCalled(t, "Get", mock.Anything)
+	mockCache.AssertExpectations(t)
+}
+
+func TestSuppressionService_IsSuppressed_CacheMiss(t *testing.T) {
+	mockRepo := &mocks.MockSuppressionRepository{}
+	mockCache := &mocks.MockCache{}
+
+	service := NewSuppressionService(mockRepo, mockCache, "TR")
+
+	// Ne tenant'ın kendi listesinde ne de global listede var
+	mockCache.On("Get", "suppression:7:+905551234567").Return(nil, assert.AnError)
+	mockCache.On("Get", "suppression:0:+905551234567").Return(nil, assert.AnError)
+	mockRepo.On("ForTenant", int64(7)).Return()
+	mockRepo.On("ForTenant", int64(0)).Return()
+	mockRepo.On("Get", "+905551234567").Return(nil, domain.ErrSuppressionNotFound)
+	mockCache.On("Set", "suppression:7:+905551234567", "0").Return(nil)
+	mockCache.On("Set", "suppression:0:+905551234567", "0").Return(nil)
+
+	suppressed, err := service.IsSuppressed(7, "+905551234567")
+	assert.NoError(t, err)
+	assert.False(t, suppressed)
+
+	mockRepo.AssertExpectations(t)
+	mockCache.AssertExpectations(t)
+}
+
+func TestSuppressionService_IsSuppressed_RepositoryError(t *testing.T) {
+	mockRepo := &mocks.MockSuppressionRepository{}
+	mockCache := &mocks.MockCache{}
+
+	service := NewSuppressionService(mockRepo, mockCache, "TR")
+
+	mockCache.On("Get", "suppression:7:+905551234567").Return(nil, assert.AnError)
+	mockRepo.On("ForTenant", int64(7)).Return()
+	mockRepo.On("Get", "+905551234567").Return(nil, assert.AnError)
+
+	_, err := service.IsSuppressed(7, "+905551234567")
+	assert.Error(t, err)
+	mockCache.AssertNotCalled(t, "Set", mock.Anything, mock.Anything)
+}
+
+func TestSuppressionService_IsSuppressed_GlobalList(t *testing.T) {
+	mockRepo := &mocks.MockSuppressionRepository{}
+	mockCache := &mocks.MockCache{}
+
+	service := NewSuppressionService(mockRepo, mockCache, "TR")
+
+	// STOP yanıtı gibi global kayıtlar her tenant için geçerli
+	mockCache.On("Get", "suppression:7:+905551234567").Return("0", nil)
+	mockCache.On("Get", "suppression:0:+905551234567").Return("1", nil)
+
+	suppressed, err := service.IsSuppressed(7, "+905551234567")
+	assert.NoError(t, err)
+	assert.True(t, suppressed)
+
+	mockCache.AssertExpectations(t)
+}
+
+func TestSuppressionService_Add(t *testing.T) {
+	mockRepo := &mocks.MockSuppressionRepository{}
+	mockCache := &mocks.MockCache{}
+
+	service := NewSuppressionService(mockRepo, mockCache, "TR")
+
+	mockRepo.On("ForTenant", int64(7)).Return()
+	mockRepo.On("Add", mock.MatchedBy(func(s *domain.Suppression) bool {
+		return s.Recipient == "+905551234567" && s.Source == domain.SuppressionSourceManual
+	})).Return(nil)
+	mockCache.On("Set", "suppression:7:+905551234567", "1").Return(nil)
+
+	suppression, err := service.Add(7, " +905551234567 ", "customer request", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "+905551234567", suppression.Recipient)
+	assert.Equal(t, "customer request", suppression.Reason)
+
+	mockRepo.AssertExpectations(t)
+	mockCache.AssertExpectations(t)
+}
+
+func TestSuppressionService_Remove(t *testing.T) {
+	mockRepo := &mocks.MockSuppressionRepository{}
+	mockCache := &mocks.MockCache{}
+
+	service := NewSuppressionService(mockRepo, mockCache, "TR")
+
+	mockRepo.On("ForTenant", int64(7)).Return()
+	mockRepo.On("Remove", "+905551234567").Return(nil)
+	mockCache.On("Set", "suppression:7:+905551234567", "0").Return(nil)
+
+	err := service.Remove(7, "+905551234567")
+	assert.NoError(t, err)
+
+	mockRepo.AssertExpectations(t)
+	mockCache.AssertExpectations(t)
+}
+
+func TestSuppressionService_Import(t *testing.T) {
+	mockRepo := &mocks.MockSuppressionRepository{}
+	mockCache := &mocks.MockCache{}
+
+	service := NewSuppressionService(mockRepo, mockCache, "TR")
+
+	mockRepo.On("ForTenant", int64(7)).Return()
+	mockRepo.On("Add", mock.AnythingOfType("*domain.Suppression")).Return(nil)
+	mockCache.On("Set", mock.Anything, "1").Return(nil)
+
+	csvData := "recipient,reason,source\n+905551234567,STOP reply,\n+905551234568,,audit\n"
+	imported, err := service.Import(7, strings.NewReader(csvData))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, imported)
+	mockRepo.AssertNumberOfCalls(t, "ForTenant", 2)
+
+	added := mockRepo.Calls[1].Arguments.Get(0).(*domain.Suppression)
+	assert.Equal(t, "+905551234567", added.Recipient)
+	assert.Equal(t, "STOP reply", added.Reason)
+	assert.Equal(t, domain.SuppressionSourceImport, added.Source)
+
+	added = mockRepo.Calls[3].Arguments.Get(0).(*domain.Suppression)
+	assert.Equal(t, "audit", added.Source)
+}
+
+func TestSuppressionService_Export(t *testing.T) {
+	mockRepo := &mocks.MockSuppressionRepository{}
+	mockCache := &mocks.MockCache{}
+
+	service := NewSuppressionService(mockRepo, mockCache, "TR")
+
+	createdAt := time.Date(2024, 2, 24, 1, 15, 39, 0, time.UTC)
+	mockRepo.On("ForTenant", int64(7)).Return()
+	mockRepo.On("List").Return([]*domain.Suppression{
+		{ID: 1, Recipient: "+905551234567", Reason: "STOP reply", Source: "manual", CreatedAt: createdAt},
+	}, nil)
+
+	var buf bytes.Buffer
+	err := service.Export(7, &buf)
+	assert.NoError(t, err)
+	assert.Equal(t, "recipient,reason,source,created_at\n+905551234567,STOP reply,manual,2024-02-24T01:15:39Z\n", buf.String())
+}
+
+func TestSuppressionService_NormalizesPhoneNumbers(t *testing.T) {
+	mockRepo := &mocks.MockSuppressionRepository{}
+	mockCache := &mocks.MockCache{}
+
+	service := NewSuppressionService(mockRepo, mockCache, "TR")
+
+	mockCache.On("Get", "suppression:0:+905551234567").Return("1", nil)
+
+	// Ulusal formattaki numara E.164 formatına çevrilmeli
+	suppressed, err := service.IsSuppressed(0, "0555 123 45 67")
+	assert.NoError(t, err)
+	assert.True(t, suppressed)
+
+	mockCache.AssertExpectations(t)
+}
+
+func TestSuppressionService_NormalizesEmailAddresses(t *testing.T) {
+	mockRepo := &mocks.MockSuppressionRepository{}
+	mockCache := &mocks.MockCache{}
+
+	service := NewSuppressionService(mockRepo, mockCache, "TR")
+
+	mockCache.On("Get", "suppression:0:jane@example.com").Return("1", nil)
+
+	// Alan adı büyük harfle yazılsa da aynı kayıt bulunmalı
+	suppressed, err := service.IsSuppressed(0, " jane@Example.COM ")
+	assert.NoError(t, err)
+	assert.True(t, suppressed)
+
+	mockCache.AssertExpectations(t)
+}
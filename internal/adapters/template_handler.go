@@ -0,0 +1,193 @@
+package adapters
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/ercancavusoglu/messaging/internal/domain"
+	"github.com/ercancavusoglu/messaging/internal/domain/valueobject"
+	"github.com/ercancavusoglu/messaging/internal/ports"
+	"github.com/gorilla/mux"
+)
+
+type TemplateHandler struct {
+	templateService ports.TemplateService
+}
+
+type renderTemplateRequest struct {
+	Locale    string            `json:"locale"`
+	Variables map[string]string `json:"variables"`
+}
+
+func NewTemplateHandler(templateService ports.TemplateService) *TemplateHandler {
+	return &TemplateHandler{
+		templateService: templateService,
+	}
+}
+
+func (h *TemplateHandler) ListTemplates(w http.ResponseWriter, r *http.Request) {
+	tenant, ok := TenantFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusForbidden, errMissingTenant)
+		return
+	}
+
+	templates, err := h.templateService.List(tenant.ID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, templates)
+}
+
+func (h *TemplateHandler) GetTemplate(w http.ResponseWriter, r *http.Request) {
+	tenant, ok := TenantFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusForbidden, errMissingTenant)
+		return
+	}
+
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	template, err := h.templateService.Get(tenant.ID, id)
+	if err != nil {
+		writeError(w, templateErrorStatus(err), err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, template)
+}
+
+func (h *TemplateHandler) CreateTemplate(w http.ResponseWriter, r *http.Request) {
+	tenant, ok := TenantFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusForbidden, errMissingTenant)
+		return
+	}
+
+	var template domain.Template
+	if err := json.NewDecoder(r.Body).Decode(&template); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %v", err))
+		return
+	}
+
+	if err := h.templateService.Create(tenant.ID, &template); err != nil {
+		writeError(w, templateErrorStatus(err), err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, template)
+}
+
+func (h *TemplateHandler) UpdateTemplate(w http.ResponseWriter, r *http.Request) {
+	tenant, ok := TenantFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusForbidden, errMissingTenant)
+		return
+	}
+
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var template domain.Template
+	if err := json.NewDecoder(r.Body).Decode(&template); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %v", err))
+		return
+	}
+	template.ID = id
+
+	if err := h.templateService.Update(tenant.ID, &template); err != nil {
+		writeError(w, templateErrorStatus(err), err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, template)
+}
+
+func (h *TemplateHandler) DeleteTemplate(w http.ResponseWriter, r *http.Request) {
+	tenant, ok := TenantFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusForbidden, errMissingTenant)
+		return
+	}
+
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := h.templateService.Delete(tenant.ID, id); err != nil {
+		writeError(w, templateErrorStatus(err), err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *TemplateHandler) RenderTemplate(w http.ResponseWriter, r *http.Request) {
+	tenant, ok := TenantFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusForbidden, errMissingTenant)
+		return
+	}
+
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var req renderTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %v", err))
+		return
+	}
+
+	content, err := h.templateService.Render(tenant.ID, id, req.Locale, req.Variables)
+	if err != nil {
+		writeError(w, templateErrorStatus(err), err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{
+		"content": content,
+	})
+}
+
+// templateErrorStatus maps template and content validation errors to client errors
+func templateErrorStatus(err error) int {
+	var missingErr *domain.MissingVariablesError
+	switch {
+	case errors.Is(err, domain.ErrTemplateNotFound):
+		return http.StatusNotFound
+	case errors.As(err, &missingErr),
+		errors.Is(err, domain.ErrTemplateNameRequired),
+		errors.Is(err, domain.ErrTemplateLocaleNotFound),
+		errors.Is(err, domain.ErrTemplateVariantEmpty),
+		errors.Is(err, valueobject.ErrEmptyContent),
+		errors.Is(err, valueobject.ErrContentTooLong):
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func pathID(r *http.Request) (int64, error) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid id: %q", mux.Vars(r)["id"])
+	}
+	return id, nil
+}
@@ -0,0 +1,108 @@
+package adapters
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ercancavusoglu/messaging/internal/adapters/mocks"
+	"github.com/ercancavusoglu/messaging/internal/domain"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestTemplateHandler_CreateTemplate(t *testing.T) {
+	mockService := &mocks.MockTemplateService{}
+	handler := NewTemplateHandler(mockService)
+
+	mockService.On("Create", int64(7), mock.AnythingOfType("*domain.Template")).Return(nil).Run(func(args mock.Arguments) {
+		args.Get(1).(*domain.Template).ID = 7
+	})
+
+	body := `{"name":"otp","default_locale":"en","variants":{"en":"Your code is {{code}}"}}`
+	req := httptest.NewRequest(http.MethodPost, "/templates", strings.NewReader(body))
+	req = req.WithContext(WithTenant(req.Context(), &domain.Tenant{ID: 7}))
+	w := httptest.NewRecorder()
+
+	handler.CreateTemplate(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var response domain.Template
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Equal(t, int64(7), response.ID)
+	assert.Equal(t, "Your code is {{code}}", response.Variants["en"])
+
+	mockService.AssertExpectations(t)
+}
+
+func TestTemplateHandler_GetTemplate_NotFound(t *testing.T) {
+	mockService := &mocks.MockTemplateService{}
+	handler := NewTemplateHandler(mockService)
+
+	mockService.On("Get", int64(7), int64(3)).Return(nil, domain.ErrTemplateNotFound)
+
+	req := httptest.NewRequest(http.MethodGet, "/templates/3", nil)
+	req = req.WithContext(WithTenant(req.Context(), &domain.Tenant{ID: 7}))
+	req = mux.SetURLVars(req, map[string]string{"id": "3"})
+	w := httptest.NewRecorder()
+
+	handler.GetTemplate(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestTemplateHandler_GetTemplate_InvalidID(t *testing.T) {
+	mockService := &mocks.MockTemplateService{}
+	handler := NewTemplateHandler(mockService)
+
+	req := httptest.NewRequest(http.MethodGet, "/templates/abc", nil)
+	req = req.WithContext(WithTenant(req.Context(), &domain.Tenant{ID: 7}))
+	req = mux.SetURLVars(req, map[string]string{"id": "abc"})
+	w := httptest.NewRecorder()
+
+	handler.GetTemplate(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertNotCalled(t, "Get", mock.Anything, mock.Anything)
+}
+
+func TestTemplateHandler_RenderTemplate_MissingVariables(t *testing.T) {
+	mockService := &mocks.MockTemplateService{}
+	handler := NewTemplateHandler(mockService)
+
+	mockService.On("Render", int64(7), int64(1), "tr", map[string]string{}).
+		Return("", &domain.MissingVariablesError{Variables: []string{"code"}})
+
+	req := httptest.NewRequest(http.MethodPost, "/templates/1/render", strings.NewReader(`{"locale":"tr","variables":{}}`))
+	req = req.WithContext(WithTenant(req.Context(), &domain.Tenant{ID: 7}))
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+	w := httptest.NewRecorder()
+
+	handler.RenderTemplate(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+	var response map[string]string
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Equal(t, "missing template variables: code", response["error"])
+}
+
+func TestTemplateHandler_RequiresTenant(t *testing.T) {
+	mockService := &mocks.MockTemplateService{}
+	handler := NewTemplateHandler(mockService)
+
+	// Admin anahtarı bir tenant'a ait olmadığı için şablonları göremez
+	req := httptest.NewRequest(http.MethodGet, "/templates", nil)
+	req = req.WithContext(WithPrincipal(req.Context(), domain.AdminPrincipal()))
+	w := httptest.NewRecorder()
+
+	handler.ListTemplates(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	mockService.AssertNotCalled(t, "List", mock.Anything)
+}
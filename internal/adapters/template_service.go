@@ -0,0 +1,65 @@
+package adapters
+
+import (
+	"github.com/ercancavusoglu/messaging/internal/domain"
+	"github.com/ercancavusoglu/messaging/internal/domain/valueobject"
+	"github.com/ercancavusoglu/messaging/internal/ports"
+)
+
+type templateService struct {
+	repo  ports.TemplateRepository
+	rules domain.MessageRules
+}
+
+func NewTemplateService(repo ports.TemplateRepository, rules domain.MessageRules) ports.TemplateService {
+	return &templateService{
+		repo:  repo,
+		rules: rules,
+	}
+}
+
+func (s *templateService) Create(tenantID int64, template *domain.Template) error {
+	if err := template.Validate(); err != nil {
+		return err
+	}
+	return s.repo.ForTenant(tenantID).Create(template)
+}
+
+func (s *templateService) Update(tenantID int64, template *domain.Template) error {
+	if err := template.Validate(); err != nil {
+		return err
+	}
+	return s.repo.ForTenant(tenantID).Update(template)
+}
+
+func (s *templateService) Delete(tenantID, id int64) error {
+	return s.repo.ForTenant(tenantID).Delete(id)
+}
+
+func (s *templateService) Get(tenantID, id int64) (*domain.Template, error) {
+	return s.repo.ForTenant(tenantID).Get(id)
+}
+
+func (s *templateService) List(tenantID int64) ([]*domain.Template, error) {
+	return s.repo.ForTenant(tenantID).List()
+}
+
+// Render fills the template for the locale and validates the result like any hand written message content
+func (s *templateService) Render(tenantID, id int64, locale string, variables map[string]string) (string, error) {
+	template, err := s.repo.ForTenant(tenantID).Get(id)
+	if err != nil {
+		return "", err
+	}
+
+	rendered, err := template.Render(locale, variables)
+	if err != nil {
+		return "", err
+	}
+
+	content, err := valueobject.NewMessageContent(rendered, s.rules.MaxSegments)
+	if err != nil {
+		return "", err
+	}
+
+	return content.String(), nil
+}
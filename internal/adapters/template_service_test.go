@@ -0,0 +1,91 @@
+package adapters
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ercancavusoglu/messaging/internal/adapters/mocks"
+	"github.com/ercancavusoglu/messaging/internal/domain"
+	"github.com/ercancavusoglu/messaging/internal/domain/valueobject"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func createTestTemplate() *domain.Template {
+	return &domain.Template{
+		ID:            1,
+		Name:          "otp",
+		DefaultLocale: "en",
+		Variants: map[string]string{
+			"en": "Your code is {{code}}",
+			"tr": "Doğrulama kodunuz: {{code}}",
+		},
+	}
+}
+
+func TestTemplateService_Create_Invalid(t *testing.T) {
+	mockRepo := &mocks.MockTemplateRepository{}
+	service := NewTemplateService(mockRepo, domain.DefaultMessageRules())
+
+	err := service.Create(7, &domain.Template{Name: "otp", DefaultLocale: "en"})
+	assert.ErrorIs(t, err, domain.ErrTemplateLocaleNotFound)
+
+	// Geçersiz template veritabanına yazılmamalı
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestTemplateService_Render(t *testing.T) {
+	mockRepo := &mocks.MockTemplateRepository{}
+	service := NewTemplateService(mockRepo, domain.DefaultMessageRules())
+
+	mockRepo.On("ForTenant", int64(7)).Return()
+	mockRepo.On("Get", int64(1)).Return(createTestTemplate(), nil)
+
+	content, err := service.Render(7, 1, "tr-TR", map[string]string{"code": "123456"})
+	assert.NoError(t, err)
+	assert.Equal(t, "Doğrulama kodunuz: 123456", content)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTemplateService_Render_MissingVariables(t *testing.T) {
+	mockRepo := &mocks.MockTemplateRepository{}
+	service := NewTemplateService(mockRepo, domain.DefaultMessageRules())
+
+	mockRepo.On("ForTenant", int64(7)).Return()
+	mockRepo.On("Get", int64(1)).Return(createTestTemplate(), nil)
+
+	_, err := service.Render(7, 1, "en", nil)
+
+	var missingErr *domain.MissingVariablesError
+	assert.ErrorAs(t, err, &missingErr)
+	assert.Equal(t, []string{"code"}, missingErr.Variables)
+}
+
+func TestTemplateService_Render_ContentTooLong(t *testing.T) {
+	mockRepo := &mocks.MockTemplateRepository{}
+	service := NewTemplateService(mockRepo, domain.DefaultMessageRules())
+
+	mockRepo.On("ForTenant", int64(7)).Return()
+	mockRepo.On("Get", int64(1)).Return(createTestTemplate(), nil)
+
+	_, err := service.Render(7, 1, "en", map[string]string{"code": strings.Repeat("9", 1000)})
+	assert.ErrorIs(t, err, valueobject.ErrContentTooLong)
+}
+
+func TestTemplateService_ScopesToTenant(t *testing.T) {
+	mockRepo := &mocks.MockTemplateRepository{}
+	service := NewTemplateService(mockRepo, domain.DefaultMessageRules())
+
+	// Başka bir tenant'ın şablonu bulunamamalı
+	mockRepo.On("ForTenant", int64(8)).Return()
+	mockRepo.On("Get", int64(1)).Return(nil, domain.ErrTemplateNotFound)
+	mockRepo.On("Delete", int64(1)).Return(domain.ErrTemplateNotFound)
+
+	_, err := service.Get(8, 1)
+	assert.ErrorIs(t, err, domain.ErrTemplateNotFound)
+	assert.ErrorIs(t, service.Delete(8, 1), domain.ErrTemplateNotFound)
+
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "ForTenant", int64(0))
+}
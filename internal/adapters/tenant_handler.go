@@ -0,0 +1,141 @@
+package adapters
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/ercancavusoglu/messaging/internal/domain"
+	"github.com/ercancavusoglu/messaging/internal/ports"
+)
+
+type TenantHandler struct {
+	tenantService ports.TenantService
+}
+
+// tenantKeyResponse is the only response that ever contains a plain API key
+type tenantKeyResponse struct {
+	Tenant *domain.Tenant `json:"tenant"`
+	APIKey string         `json:"api_key"`
+}
+
+func NewTenantHandler(tenantService ports.TenantService) *TenantHandler {
+	return &TenantHandler{
+		tenantService: tenantService,
+	}
+}
+
+func (h *TenantHandler) ListTenants(w http.ResponseWriter, r *http.Request) {
+	tenants, err := h.tenantService.List()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	redacted := make([]*domain.Tenant, 0, len(tenants))
+	for _, tenant := range tenants {
+		redacted = append(redacted, tenant.Redacted())
+	}
+
+	writeJSON(w, http.StatusOK, redacted)
+}
+
+func (h *TenantHandler) GetTenant(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	tenant, err := h.tenantService.Get(id)
+	if err != nil {
+		writeError(w, tenantErrorStatus(err), err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, tenant.Redacted())
+}
+
+func (h *TenantHandler) CreateTenant(w http.ResponseWriter, r *http.Request) {
+	var tenant domain.Tenant
+	if err := json.NewDecoder(r.Body).Decode(&tenant); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %v", err))
+		return
+	}
+
+	apiKey, err := h.tenantService.Create(&tenant)
+	if err != nil {
+		writeError(w, tenantErrorStatus(err), err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, tenantKeyResponse{Tenant: tenant.Redacted(), APIKey: apiKey})
+}
+
+func (h *TenantHandler) UpdateTenant(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	// Fields missing from the body keep their current values
+	tenant, err := h.tenantService.Get(id)
+	if err != nil {
+		writeError(w, tenantErrorStatus(err), err)
+		return
+	}
+
+	// Decoding writes into the credentials map, so keep a copy of the stored tokens
+	stored := make(map[string]domain.ProviderCredential, len(tenant.ProviderCredentials))
+	for provider, credential := range tenant.ProviderCredentials {
+		stored[provider] = credential
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(tenant); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %v", err))
+		return
+	}
+	tenant.ID = id
+	tenant.KeepStoredTokens(stored)
+
+	if err := h.tenantService.Update(tenant); err != nil {
+		writeError(w, tenantErrorStatus(err), err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, tenant.Redacted())
+}
+
+func (h *TenantHandler) RotateAPIKey(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	apiKey, err := h.tenantService.RotateAPIKey(id)
+	if err != nil {
+		writeError(w, tenantErrorStatus(err), err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{
+		"api_key": apiKey,
+	})
+}
+
+func tenantErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrTenantNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrTenantNameRequired),
+		errors.Is(err, domain.ErrInvalidTenantQuota),
+		errors.Is(err, domain.ErrInvalidCredential),
+		errors.Is(err, domain.ErrInvalidScope):
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,143 @@
+package adapters
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ercancavusoglu/messaging/internal/adapters/mocks"
+	"github.com/ercancavusoglu/messaging/internal/domain"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestTenantHandler_CreateTenant(t *testing.T) {
+	mockService := &mocks.MockTenantService{}
+	handler := NewTenantHandler(mockService)
+
+	mockService.On("Create", mock.AnythingOfType("*domain.Tenant")).Return("msk_plain", nil)
+
+	body := `{"name":"payments","daily_quota":1000,"provider_credentials":{"client_one":{"url":"https://sms.example.com","token":"secret"}}}`
+	req := httptest.NewRequest(http.MethodPost, "/tenants", strings.NewReader(body))
+	w := httptest.NewRecorder()
+
+	handler.CreateTenant(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.NotContains(t, w.Body.String(), "secret")
+
+	var response struct {
+		Tenant domain.Tenant `json:"tenant"`
+		APIKey string        `json:"api_key"`
+	}
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Equal(t, "msk_plain", response.APIKey)
+	assert.Equal(t, 1000, response.Tenant.DailyQuota)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestTenantHandler_UpdateTenant_KeepsMissingFields(t *testing.T) {
+	mockService := &mocks.MockTenantService{}
+	handler := NewTenantHandler(mockService)
+
+	mockService.On("Get", int64(7)).Return(&domain.Tenant{ID: 7, Name: "payments", DailyQuota: 1000, Active: true}, nil)
+	mockService.On("Update", mock.MatchedBy(func(tenant *domain.Tenant) bool {
+		return tenant.ID == 7 && tenant.DailyQuota == 500 && tenant.Active && tenant.Name == "payments"
+	})).Return(nil)
+
+	req := httptest.NewRequest(http.MethodPut, "/tenants/7", strings.NewReader(`{"daily_quota":500}`))
+	req = mux.SetURLVars(req, map[string]string{"id": "7"})
+	w := httptest.NewRecorder()
+
+	handler.UpdateTenant(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestTenantHandler_UpdateTenant_RoundTripKeepsTokens(t *testing.T) {
+	mockService := &mocks.MockTenantService{}
+	handler := NewTenantHandler(mockService)
+
+	stored := func() *domain.Tenant {
+		return &domain.Tenant{ID: 7, Name: "payments", Active: true, ProviderCredentials: map[string]domain.ProviderCredential{
+			"client_one": {URL: "https://sms.example.com", Token: "secret-one"},
+			"client_two": {URL: "https://sms.example.org", Token: "secret-two"},
+		}}
+	}
+	mockService.On("Get", int64(7)).Return(stored(), nil).Once()
+	mockService.On("Get", int64(7)).Return(stored(), nil).Once()
+
+	// GET yanıtı maskeli token içerir
+	req := httptest.NewRequest(http.MethodGet, "/tenants/7", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "7"})
+	w := httptest.NewRecorder()
+	handler.GetTenant(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "secret")
+
+	var tenant map[string]interface{}
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&tenant))
+	tenant["daily_quota"] = 500
+	// Yeni token yazılan sağlayıcı güncellenmeli, maskeli olan saklı token'ı korumalı
+	credentials := tenant["provider_credentials"].(map[string]interface{})
+	credentials["client_two"].(map[string]interface{})["token"] = "rotated"
+	body, err := json.Marshal(tenant)
+	assert.NoError(t, err)
+
+	mockService.On("Update", mock.MatchedBy(func(tenant *domain.Tenant) bool {
+		return tenant.DailyQuota == 500 &&
+			tenant.ProviderCredentials["client_one"].Token == "secret-one" &&
+			tenant.ProviderCredentials["client_two"].Token == "rotated"
+	})).Return(nil)
+
+	req = httptest.NewRequest(http.MethodPut, "/tenants/7", strings.NewReader(string(body)))
+	req = mux.SetURLVars(req, map[string]string{"id": "7"})
+	w = httptest.NewRecorder()
+	handler.UpdateTenant(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "secret")
+	mockService.AssertExpectations(t)
+}
+
+func TestTenantHandler_InvalidScope(t *testing.T) {
+	mockService := &mocks.MockTenantService{}
+	handler := NewTenantHandler(mockService)
+
+	invalidScope := fmt.Errorf("%w: %q", domain.ErrInvalidScope, domain.ScopeSchedulerAdmin)
+	mockService.On("Create", mock.AnythingOfType("*domain.Tenant")).Return("", invalidScope)
+	mockService.On("Get", int64(7)).Return(&domain.Tenant{ID: 7, Name: "payments", Active: true}, nil)
+	mockService.On("Update", mock.AnythingOfType("*domain.Tenant")).Return(invalidScope)
+
+	body := `{"name":"payments","scopes":["scheduler:admin"]}`
+	w := httptest.NewRecorder()
+	handler.CreateTenant(w, httptest.NewRequest(http.MethodPost, "/tenants", strings.NewReader(body)))
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+	req := httptest.NewRequest(http.MethodPut, "/tenants/7", strings.NewReader(body))
+	req = mux.SetURLVars(req, map[string]string{"id": "7"})
+	w = httptest.NewRecorder()
+	handler.UpdateTenant(w, req)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+}
+
+func TestTenantHandler_RotateAPIKey_NotFound(t *testing.T) {
+	mockService := &mocks.MockTenantService{}
+	handler := NewTenantHandler(mockService)
+
+	mockService.On("RotateAPIKey", int64(9)).Return("", domain.ErrTenantNotFound)
+
+	req := httptest.NewRequest(http.MethodPost, "/tenants/9/rotate-key", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "9"})
+	w := httptest.NewRecorder()
+
+	handler.RotateAPIKey(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
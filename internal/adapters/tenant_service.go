@@ -0,0 +1,87 @@
+package adapters
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/ercancavusoglu/messaging/internal/domain"
+	"github.com/ercancavusoglu/messaging/internal/ports"
+)
+
+type tenantService struct {
+	repo ports.TenantRepository
+}
+
+func NewTenantService(repo ports.TenantRepository) ports.TenantService {
+	return &tenantService{
+		repo: repo,
+	}
+}
+
+func (s *tenantService) Create(tenant *domain.Tenant) (string, error) {
+	if err := tenant.Validate(); err != nil {
+		return "", err
+	}
+
+	apiKey, err := domain.NewAPIKey()
+	if err != nil {
+		return "", err
+	}
+
+	tenant.APIKeyHash = domain.HashAPIKey(apiKey)
+	tenant.Active = true
+	if err := s.repo.Create(tenant); err != nil {
+		return "", err
+	}
+
+	return apiKey, nil
+}
+
+func (s *tenantService) Update(tenant *domain.Tenant) error {
+	if err := tenant.Validate(); err != nil {
+		return err
+	}
+	return s.repo.Update(tenant)
+}
+
+// RotateAPIKey replaces the tenant's key; the previous key stops working immediately
+func (s *tenantService) RotateAPIKey(id int64) (string, error) {
+	apiKey, err := domain.NewAPIKey()
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.repo.UpdateAPIKeyHash(id, domain.HashAPIKey(apiKey)); err != nil {
+		return "", err
+	}
+
+	return apiKey, nil
+}
+
+func (s *tenantService) Get(id int64) (*domain.Tenant, error) {
+	return s.repo.Get(id)
+}
+
+func (s *tenantService) List() ([]*domain.Tenant, error) {
+	return s.repo.List()
+}
+
+func (s *tenantService) Authenticate(apiKey string) (*domain.Tenant, error) {
+	if !strings.HasPrefix(apiKey, domain.APIKeyPrefix) {
+		return nil, domain.ErrInvalidAPIKey
+	}
+
+	tenant, err := s.repo.GetByAPIKeyHash(domain.HashAPIKey(apiKey))
+	if errors.Is(err, domain.ErrTenantNotFound) {
+		return nil, domain.ErrInvalidAPIKey
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !tenant.Active {
+		return nil, domain.ErrInvalidAPIKey
+	}
+
+	return tenant, nil
+}
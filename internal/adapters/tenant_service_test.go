@@ -0,0 +1,69 @@
+package adapters
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ercancavusoglu/messaging/internal/adapters/mocks"
+	"github.com/ercancavusoglu/messaging/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestTenantService_Create(t *testing.T) {
+	mockRepo := &mocks.MockTenantRepository{}
+	service := NewTenantService(mockRepo)
+
+	mockRepo.On("Create", mock.AnythingOfType("*domain.Tenant")).Return(nil)
+
+	tenant := &domain.Tenant{Name: "payments", DailyQuota: 1000}
+	apiKey, err := service.Create(tenant)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(apiKey, domain.APIKeyPrefix))
+
+	// Düz anahtar saklanmamalı, sadece hash'i
+	assert.Equal(t, domain.HashAPIKey(apiKey), tenant.APIKeyHash)
+	assert.True(t, tenant.Active)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTenantService_Authenticate(t *testing.T) {
+	mockRepo := &mocks.MockTenantRepository{}
+	service := NewTenantService(mockRepo)
+
+	tenant := &domain.Tenant{ID: 7, Name: "payments", Active: true}
+	mockRepo.On("GetByAPIKeyHash", domain.HashAPIKey("msk_valid")).Return(tenant, nil)
+	mockRepo.On("GetByAPIKeyHash", domain.HashAPIKey("msk_unknown")).Return(nil, domain.ErrTenantNotFound)
+
+	authenticated, err := service.Authenticate("msk_valid")
+	assert.NoError(t, err)
+	assert.Equal(t, tenant, authenticated)
+
+	_, err = service.Authenticate("msk_unknown")
+	assert.ErrorIs(t, err, domain.ErrInvalidAPIKey)
+
+	_, err = service.Authenticate("not-a-key")
+	assert.ErrorIs(t, err, domain.ErrInvalidAPIKey)
+}
+
+func TestTenantService_Authenticate_Inactive(t *testing.T) {
+	mockRepo := &mocks.MockTenantRepository{}
+	service := NewTenantService(mockRepo)
+
+	mockRepo.On("GetByAPIKeyHash", domain.HashAPIKey("msk_valid")).Return(&domain.Tenant{ID: 7, Active: false}, nil)
+
+	_, err := service.Authenticate("msk_valid")
+	assert.ErrorIs(t, err, domain.ErrInvalidAPIKey)
+}
+
+func TestTenantService_RotateAPIKey(t *testing.T) {
+	mockRepo := &mocks.MockTenantRepository{}
+	service := NewTenantService(mockRepo)
+
+	mockRepo.On("UpdateAPIKeyHash", int64(7), mock.AnythingOfType("string")).Return(nil)
+
+	apiKey, err := service.RotateAPIKey(7)
+	assert.NoError(t, err)
+	mockRepo.AssertCalled(t, "UpdateAPIKeyHash", int64(7), domain.HashAPIKey(apiKey))
+}
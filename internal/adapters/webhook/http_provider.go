@@ -0,0 +1,232 @@
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ercancavusoglu/messaging/internal/config"
+	"github.com/ercancavusoglu/messaging/internal/domain"
+)
+
+const defaultProviderTimeout = 10 * time.Second
+
+// HTTPProvider sends messages to an HTTP provider described by a config.Provider, so a new provider is
+// a configuration change rather than a new client
+type HTTPProvider struct {
+	spec   config.Provider
+	client *http.Client
+}
+
+// NewHTTPProvider fills in the defaults of the settings the spec leaves empty
+func NewHTTPProvider(spec config.Provider) *HTTPProvider {
+	if spec.Method == "" {
+		spec.Method = http.MethodPost
+	}
+	if spec.Timeout == 0 {
+		spec.Timeout = defaultProviderTimeout
+	}
+	if spec.Encoding == "" {
+		spec.Encoding = config.EncodingJSON
+	}
+	if len(spec.Body) == 0 {
+		spec.Body = map[string]string{"to": "{{to}}", "content": "{{content}}", "provider": "{{provider}}"}
+	}
+	if spec.Auth.Header == "" {
+		spec.Auth.Header = "X-Signature"
+	}
+	if spec.Auth.Param == "" {
+		spec.Auth.Param = "api_key"
+	}
+	if spec.Response.MessageID == "" {
+		spec.Response.MessageID = "$.messageId"
+	}
+	if spec.Response.Message == "" {
+		spec.Response.Message = "$.message"
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if spec.InsecureSkipVerify {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+
+	return &HTTPProvider{
+		spec:   spec,
+		client: &http.Client{Transport: transport, Timeout: spec.Timeout},
+	}
+}
+
+func (p *HTTPProvider) Name() string {
+	return p.spec.Name
+}
+
+func (p *HTTPProvider) SendMessage(msg *domain.Message) (*domain.WebhookResponse, error) {
+	log.Printf("[Webhook] Sending message through %s [to: %s]", p.spec.Name, msg.To)
+
+	req, err := p.newRequest(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	log.Printf("[Webhook] Response status from %s: %d", p.spec.Name, resp.StatusCode)
+
+	if !p.acceptedStatus(resp.StatusCode) {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %v", err)
+	}
+
+	return p.readResponse(bodyBytes)
+}
+
+func (p *HTTPProvider) newRequest(msg *domain.Message) (*http.Request, error) {
+	fill := strings.NewReplacer(
+		"{{to}}", msg.To,
+		"{{content}}", msg.Content,
+		"{{subject}}", msg.Subject,
+		"{{channel}}", string(msg.Channel.OrDefault()),
+		"{{provider}}", p.spec.Name,
+	).Replace
+
+	body, contentType, err := p.encodeBody(fill)
+	if err != nil {
+		return nil, err
+	}
+
+	target := p.spec.URL
+	if p.spec.Auth.Scheme == config.AuthAPIKeyQuery {
+		parsed, err := url.Parse(target)
+		if err != nil {
+			return nil, fmt.Errorf("invalid provider url: %v", err)
+		}
+		query := parsed.Query()
+		query.Set(p.spec.Auth.Param, p.spec.Auth.Token)
+		parsed.RawQuery = query.Encode()
+		target = parsed.String()
+	}
+
+	req, err := http.NewRequest(p.spec.Method, target, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+
+	req.Header.Set("Content-Type", contentType)
+	for name, value := range p.spec.Headers {
+		req.Header.Set(name, fill(value))
+	}
+
+	switch p.spec.Auth.Scheme {
+	case config.AuthBearer:
+		if p.spec.Auth.Token != "" {
+			req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", p.spec.Auth.Token))
+		}
+	case config.AuthBasic:
+		req.SetBasicAuth(p.spec.Auth.Username, p.spec.Auth.Password)
+	case config.AuthHMAC:
+		mac := hmac.New(sha256.New, []byte(p.spec.Auth.Token))
+		mac.Write(body)
+		req.Header.Set(p.spec.Auth.Header, hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	return req, nil
+}
+
+// encodeBody builds the body from the field mapping; dotted JSON fields become nested objects
+func (p *HTTPProvider) encodeBody(fill func(string) string) ([]byte, string, error) {
+	if p.spec.Encoding == config.EncodingForm {
+		form := url.Values{}
+		for field, value := range p.spec.Body {
+			form.Set(field, fill(value))
+		}
+		return []byte(form.Encode()), "application/x-www-form-urlencoded", nil
+	}
+
+	payload := make(map[string]interface{})
+	for field, value := range p.spec.Body {
+		parts := strings.Split(field, ".")
+		object := payload
+		for _, part := range parts[:len(parts)-1] {
+			nested, ok := object[part].(map[string]interface{})
+			if !ok {
+				nested = make(map[string]interface{})
+				object[part] = nested
+			}
+			object = nested
+		}
+		object[parts[len(parts)-1]] = fill(value)
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to marshal payload: %v", err)
+	}
+	return body, "application/json", nil
+}
+
+func (p *HTTPProvider) acceptedStatus(status int) bool {
+	if len(p.spec.Response.SuccessStatus) == 0 {
+		return status >= 200 && status <= 299
+	}
+
+	for _, accepted := range p.spec.Response.SuccessStatus {
+		if status == accepted {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *HTTPProvider) readResponse(body []byte) (*domain.WebhookResponse, error) {
+	var document interface{}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	// Keeps long numeric message IDs intact
+	decoder.UseNumber()
+	if err := decoder.Decode(&document); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %v", err)
+	}
+
+	if path := p.spec.Response.SuccessPath; path != "" {
+		value, err := lookupJSONPath(document, path)
+		if err != nil {
+			return nil, fmt.Errorf("provider rejected message: %v", err)
+		}
+		if jsonString(value) != p.spec.Response.SuccessValue {
+			return nil, fmt.Errorf("provider rejected message: %s is %q", path, jsonString(value))
+		}
+	}
+
+	messageID, err := lookupJSONPath(document, p.spec.Response.MessageID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read message ID: %v", err)
+	}
+
+	response := &domain.WebhookResponse{
+		MessageID: jsonString(messageID),
+		Provider:  p.spec.Name,
+	}
+	// The description is informative only, so a response without one is fine
+	if message, err := lookupJSONPath(document, p.spec.Response.Message); err == nil {
+		response.Message = jsonString(message)
+	}
+
+	return response, nil
+}
@@ -0,0 +1,234 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ercancavusoglu/messaging/internal/config"
+	"github.com/ercancavusoglu/messaging/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func builtin(url string) config.Provider {
+	return config.Webhook{URLOne: url, TokenOne: "test-api-key"}.ProviderList()[0]
+}
+
+func TestHTTPProvider_SendMessage_Success(t *testing.T) {
+	// Test sunucusu oluştur
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Request kontrolü
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.Equal(t, "Bearer test-api-key", r.Header.Get("Authorization"))
+
+		// Request body kontrolü
+		var requestBody map[string]string
+		err := json.NewDecoder(r.Body).Decode(&requestBody)
+		assert.NoError(t, err)
+		assert.Equal(t, "+905551234567", requestBody["to"])
+		assert.Equal(t, "Test message", requestBody["content"])
+		assert.Equal(t, "client_one", requestBody["provider"])
+
+		// Response
+		response := domain.WebhookResponse{
+			MessageID: "msg_123",
+			Message:   "Message sent successfully",
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	provider := NewHTTPProvider(builtin(server.URL))
+
+	response, err := provider.SendMessage(sms("+905551234567", "Test message"))
+	assert.NoError(t, err)
+	assert.NotNil(t, response)
+	assert.Equal(t, "msg_123", response.MessageID)
+	assert.Equal(t, "Message sent successfully", response.Message)
+	assert.Equal(t, "client_one", response.Provider)
+}
+
+func TestHTTPProvider_SendMessage_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("Internal Server Error"))
+	}))
+	defer server.Close()
+
+	provider := NewHTTPProvider(builtin(server.URL))
+
+	response, err := provider.SendMessage(sms("+905551234567", "Test message"))
+	assert.Error(t, err)
+	assert.Nil(t, response)
+	assert.Contains(t, err.Error(), "unexpected status code: 500")
+}
+
+func TestHTTPProvider_SendMessage_InvalidResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("invalid json"))
+	}))
+	defer server.Close()
+
+	provider := NewHTTPProvider(builtin(server.URL))
+
+	response, err := provider.SendMessage(sms("+905551234567", "Test message"))
+	assert.Error(t, err)
+	assert.Nil(t, response)
+	assert.Contains(t, err.Error(), "failed to decode response")
+}
+
+func TestHTTPProvider_FormBodyWithAPIKeyAndMapping(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "secret-key", r.URL.Query().Get("key"))
+		assert.Equal(t, "application/x-www-form-urlencoded", r.Header.Get("Content-Type"))
+		assert.Equal(t, "acme", r.Header.Get("X-Account"))
+
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "+905551234567", r.PostForm.Get("msisdn"))
+		assert.Equal(t, "Merhaba", r.PostForm.Get("text"))
+
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte(`{"result": {"status": "OK", "ids": [9007199254740993]}}`))
+	}))
+	defer server.Close()
+
+	provider := NewHTTPProvider(config.Provider{
+		Name:     "acme",
+		URL:      server.URL,
+		Encoding: config.EncodingForm,
+		Body:     map[string]string{"msisdn": "{{to}}", "text": "{{content}}"},
+		Headers:  map[string]string{"X-Account": "{{provider}}"},
+		Auth:     config.ProviderAuth{Scheme: config.AuthAPIKeyQuery, Token: "secret-key", Param: "key"},
+		Response: config.ProviderResponse{
+			MessageID:     "$.result.ids[0]",
+			SuccessStatus: []int{http.StatusAccepted},
+			SuccessPath:   "$.result.status",
+			SuccessValue:  "OK",
+		},
+	})
+
+	response, err := provider.SendMessage(sms("+905551234567", "Merhaba"))
+	require.NoError(t, err)
+
+	// Büyük sayısal ID'ler hassasiyet kaybetmemeli
+	assert.Equal(t, "9007199254740993", response.MessageID)
+	assert.Equal(t, "", response.Message)
+	assert.Equal(t, "acme", response.Provider)
+}
+
+func TestHTTPProvider_SuccessPredicateRejects(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status": "ERROR", "id": "1"}`))
+	}))
+	defer server.Close()
+
+	provider := NewHTTPProvider(config.Provider{
+		Name:     "acme",
+		URL:      server.URL,
+		Response: config.ProviderResponse{MessageID: "$.id", SuccessPath: "$.status", SuccessValue: "OK"},
+	})
+
+	_, err := provider.SendMessage(sms("+905551234567", "Hi"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `provider rejected message: $.status is "ERROR"`)
+}
+
+func TestHTTPProvider_HMACAndNestedBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		mac := hmac.New(sha256.New, []byte("shared-secret"))
+		mac.Write(body)
+		assert.Equal(t, hex.EncodeToString(mac.Sum(nil)), r.Header.Get("X-Hub-Signature"))
+		assert.JSONEq(t, `{"message": {"to": "+905551234567", "text": "Hi"}}`, string(body))
+
+		w.Write([]byte(`{"data": {"id": "abc"}}`))
+	}))
+	defer server.Close()
+
+	provider := NewHTTPProvider(config.Provider{
+		Name:     "signed",
+		URL:      server.URL,
+		Body:     map[string]string{"message.to": "{{to}}", "message.text": "{{content}}"},
+		Auth:     config.ProviderAuth{Scheme: config.AuthHMAC, Token: "shared-secret", Header: "X-Hub-Signature"},
+		Response: config.ProviderResponse{MessageID: "$.data.id"},
+	})
+
+	response, err := provider.SendMessage(sms("+905551234567", "Hi"))
+	require.NoError(t, err)
+	assert.Equal(t, "abc", response.MessageID)
+}
+
+func TestHTTPProvider_ChannelPlaceholders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		assert.JSONEq(t, `{"token": "device-1", "title": "Kargo", "body": "Yolda", "type": "push"}`, string(body))
+
+		w.Write([]byte(`{"messageId": "push_1"}`))
+	}))
+	defer server.Close()
+
+	provider := NewHTTPProvider(config.Provider{
+		Name:    "push",
+		URL:     server.URL,
+		Channel: domain.ChannelPush,
+		Body:    map[string]string{"token": "{{to}}", "title": "{{subject}}", "body": "{{content}}", "type": "{{channel}}"},
+	})
+
+	response, err := provider.SendMessage(&domain.Message{Channel: domain.ChannelPush, To: "device-1", Subject: "Kargo", Content: "Yolda"})
+	require.NoError(t, err)
+	assert.Equal(t, "push_1", response.MessageID)
+}
+
+func TestHTTPProvider_BasicAuthAndMissingMessageID(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, password, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "user", username)
+		assert.Equal(t, "tenant-token", password)
+
+		w.Write([]byte(`{"accepted": true}`))
+	}))
+	defer server.Close()
+
+	spec := config.Provider{
+		Name: "basic",
+		URL:  "https://unused.example.com",
+		Auth: config.ProviderAuth{Scheme: config.AuthBasic, Username: "user", Password: "shared"},
+	}
+	provider := NewHTTPProvider(spec.WithCredential(server.URL, "tenant-token"))
+
+	_, err := provider.SendMessage(sms("+905551234567", "Hi"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `failed to read message ID: $.messageId: no field "messageId"`)
+}
+
+func TestLookupJSONPath(t *testing.T) {
+	var document interface{}
+	require.NoError(t, json.Unmarshal([]byte(`{"a": {"b": [{"c": 1}, {"d.e": true}]}}`), &document))
+
+	value, err := lookupJSONPath(document, "$.a.b[0].c")
+	require.NoError(t, err)
+	assert.Equal(t, "1", jsonString(value))
+
+	value, err = lookupJSONPath(document, "$.a.b[1]['d.e']")
+	require.NoError(t, err)
+	assert.Equal(t, "true", jsonString(value))
+
+	_, err = lookupJSONPath(document, "$.a.b[2]")
+	assert.EqualError(t, err, "$.a.b[2]: no element 2")
+
+	_, err = lookupJSONPath(document, "a.b")
+	assert.Error(t, err)
+}
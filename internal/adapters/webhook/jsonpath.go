@@ -0,0 +1,89 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// lookupJSONPath resolves a JSONPath such as $.data.messages[0].id in a decoded JSON document. Only
+// child and index steps are supported, which is what provider responses need.
+func lookupJSONPath(document interface{}, path string) (interface{}, error) {
+	if !strings.HasPrefix(path, "$") {
+		return nil, fmt.Errorf("invalid JSONPath %q: must start with $", path)
+	}
+
+	current := document
+	rest := path[1:]
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			end := strings.IndexAny(rest[1:], ".[")
+			if end == -1 {
+				end = len(rest) - 1
+			}
+			name := rest[1 : end+1]
+			rest = rest[end+1:]
+
+			object, ok := current.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("%s: %q is not an object", path, name)
+			}
+			if current, ok = object[name]; !ok {
+				return nil, fmt.Errorf("%s: no field %q", path, name)
+			}
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end == -1 {
+				return nil, fmt.Errorf("invalid JSONPath %q: unclosed [", path)
+			}
+			step := rest[1:end]
+			rest = rest[end+1:]
+
+			if name, err := strconv.Unquote(strings.ReplaceAll(step, "'", `"`)); err == nil {
+				object, ok := current.(map[string]interface{})
+				if !ok {
+					return nil, fmt.Errorf("%s: %q is not an object", path, name)
+				}
+				if current, ok = object[name]; !ok {
+					return nil, fmt.Errorf("%s: no field %q", path, name)
+				}
+				continue
+			}
+
+			index, err := strconv.Atoi(step)
+			if err != nil {
+				return nil, fmt.Errorf("invalid JSONPath %q: bad index %q", path, step)
+			}
+			array, ok := current.([]interface{})
+			if !ok || index < 0 || index >= len(array) {
+				return nil, fmt.Errorf("%s: no element %d", path, index)
+			}
+			current = array[index]
+		default:
+			return nil, fmt.Errorf("invalid JSONPath %q: unexpected %q", path, rest[0])
+		}
+	}
+
+	return current, nil
+}
+
+// jsonString renders a JSON value the way it is compared and stored, e.g. 42 rather than 42.000000
+func jsonString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	}
+
+	encoded, _ := json.Marshal(value)
+	return string(encoded)
+}
@@ -19,15 +19,15 @@ func NewRetryableWebhookClient(clients []ports.WebhookClient, maxRetries int) *R
 	}
 }
 
-func (c *RetryableWebhookClient) SendMessage(to, content string) (*domain.WebhookResponse, error) {
+func (c *RetryableWebhookClient) SendMessage(msg *domain.Message) (*domain.WebhookResponse, error) {
 	var lastErr error
 
-	fmt.Println("Sending message to", to, "with content", content)
+	fmt.Println("Sending message to", msg.To, "with content", msg.Content)
 	for attempt := 0; attempt < c.maxRetries; attempt++ {
 		clientIndex := attempt % len(c.clients)
 		client := c.clients[clientIndex]
 
-		response, err := client.SendMessage(to, content)
+		response, err := client.SendMessage(msg)
 		if err == nil {
 			fmt.Println("attempt", attempt+1, "succeeded with client", clientIndex+1)
 			return response, nil
@@ -14,14 +14,18 @@ type MockWebhookClient struct {
 	mock.Mock
 }
 
-func (m *MockWebhookClient) SendMessage(to, content string) (*domain.WebhookResponse, error) {
-	args := m.Called(to, content)
+func (m *MockWebhookClient) SendMessage(msg *domain.Message) (*domain.WebhookResponse, error) {
+	args := m.Called(msg)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*domain.WebhookResponse), args.Error(1)
 }
 
+func sms(to, content string) *domain.Message {
+	return &domain.Message{Channel: domain.ChannelSMS, To: to, Content: content}
+}
+
 func TestRetryableWebhookClient_SendMessage_Success(t *testing.T) {
 	mockClient1 := new(MockWebhookClient)
 	mockClient2 := new(MockWebhookClient)
@@ -32,15 +36,15 @@ func TestRetryableWebhookClient_SendMessage_Success(t *testing.T) {
 	}
 
 	// İlk client hata döndürür
-	mockClient1.On("SendMessage", "+905551234567", "Test message").Return(nil, errors.New("connection error"))
+	mockClient1.On("SendMessage", mock.Anything).Return(nil, errors.New("connection error"))
 
 	// İkinci client başarılı olur
-	mockClient2.On("SendMessage", "+905551234567", "Test message").Return(expectedResponse, nil)
+	mockClient2.On("SendMessage", mock.Anything).Return(expectedResponse, nil)
 
 	clients := []ports.WebhookClient{mockClient1, mockClient2}
 	retryableClient := NewRetryableWebhookClient(clients, 3)
 
-	response, err := retryableClient.SendMessage("+905551234567", "Test message")
+	response, err := retryableClient.SendMessage(sms("+905551234567", "Test message"))
 	assert.NoError(t, err)
 	assert.NotNil(t, response)
 	assert.Equal(t, expectedResponse.MessageID, response.MessageID)
@@ -55,13 +59,13 @@ func TestRetryableWebhookClient_SendMessage_AllFail(t *testing.T) {
 	mockClient2 := new(MockWebhookClient)
 
 	// Her iki client de hata döndürür
-	mockClient1.On("SendMessage", "+905551234567", "Test message").Return(nil, errors.New("connection error"))
-	mockClient2.On("SendMessage", "+905551234567", "Test message").Return(nil, errors.New("timeout error"))
+	mockClient1.On("SendMessage", mock.Anything).Return(nil, errors.New("connection error"))
+	mockClient2.On("SendMessage", mock.Anything).Return(nil, errors.New("timeout error"))
 
 	clients := []ports.WebhookClient{mockClient1, mockClient2}
 	retryableClient := NewRetryableWebhookClient(clients, 3)
 
-	response, err := retryableClient.SendMessage("+905551234567", "Test message")
+	response, err := retryableClient.SendMessage(sms("+905551234567", "Test message"))
 	assert.Error(t, err)
 	assert.Nil(t, response)
 	assert.Contains(t, err.Error(), "all retry attempts failed")
@@ -79,17 +83,17 @@ func TestRetryableWebhookClient_SendMessage_EventualSuccess(t *testing.T) {
 	}
 
 	// İlk iki deneme başarısız, üçüncü deneme başarılı
-	mockClient.On("SendMessage", "+905551234567", "Test message").
+	mockClient.On("SendMessage", mock.Anything).
 		Return(nil, errors.New("error 1")).Once()
-	mockClient.On("SendMessage", "+905551234567", "Test message").
+	mockClient.On("SendMessage", mock.Anything).
 		Return(nil, errors.New("error 2")).Once()
-	mockClient.On("SendMessage", "+905551234567", "Test message").
+	mockClient.On("SendMessage", mock.Anything).
 		Return(expectedResponse, nil).Once()
 
 	clients := []ports.WebhookClient{mockClient}
 	retryableClient := NewRetryableWebhookClient(clients, 3)
 
-	response, err := retryableClient.SendMessage("+905551234567", "Test message")
+	response, err := retryableClient.SendMessage(sms("+905551234567", "Test message"))
 	assert.NoError(t, err)
 	assert.NotNil(t, response)
 	assert.Equal(t, expectedResponse.MessageID, response.MessageID)
@@ -0,0 +1,84 @@
+package webhook
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/ercancavusoglu/messaging/internal/config"
+	"github.com/ercancavusoglu/messaging/internal/domain"
+	"github.com/ercancavusoglu/messaging/internal/ports"
+)
+
+// ChannelClients holds the shared client of every channel that has providers configured
+type ChannelClients map[domain.Channel]ports.WebhookClient
+
+// TenantClientResolver sends through the tenant's own provider accounts when the tenant has
+// credentials configured for the message's channel and through the channel's shared client otherwise.
+// A tenant credential is keyed by provider name and reuses that provider's request format with the
+// tenant's url and token.
+type TenantClientResolver struct {
+	mu         sync.RWMutex
+	shared     ChannelClients
+	providers  []config.Provider
+	tenants    ports.TenantRepository
+	maxRetries int
+}
+
+func NewTenantClientResolver(shared ChannelClients, providers []config.Provider, tenants ports.TenantRepository, maxRetries int) *TenantClientResolver {
+	return &TenantClientResolver{
+		shared:     shared,
+		providers:  providers,
+		tenants:    tenants,
+		maxRetries: maxRetries,
+	}
+}
+
+// Reload replaces the shared clients, the providers and the retry count; messages being sent keep the
+// client they resolved
+func (r *TenantClientResolver) Reload(shared ChannelClients, providers []config.Provider, maxRetries int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.shared = shared
+	r.providers = providers
+	r.maxRetries = maxRetries
+}
+
+func (r *TenantClientResolver) ClientFor(tenantID int64, channel domain.Channel) (ports.WebhookClient, error) {
+	r.mu.RLock()
+	shared, providers, maxRetries := r.shared[channel], r.providers, r.maxRetries
+	r.mu.RUnlock()
+
+	if tenantID == 0 || r.tenants == nil {
+		return sharedClient(shared, channel)
+	}
+
+	tenant, err := r.tenants.Get(tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load tenant %d: %v", tenantID, err)
+	}
+
+	var clients []ports.WebhookClient
+	for _, provider := range providers {
+		// SMPP and SMTP accounts are sessions rather than a url and token, so tenants share them
+		if provider.Type != "" && provider.Type != config.TypeHTTP || provider.DeliveryChannel() != channel {
+			continue
+		}
+		if credential, ok := tenant.ProviderCredentials[provider.Name]; ok {
+			clients = append(clients, NewHTTPProvider(provider.WithCredential(credential.URL, credential.Token)))
+		}
+	}
+
+	if len(clients) == 0 {
+		return sharedClient(shared, channel)
+	}
+
+	return NewRetryableWebhookClient(clients, maxRetries), nil
+}
+
+func sharedClient(shared ports.WebhookClient, channel domain.Channel) (ports.WebhookClient, error) {
+	if shared == nil {
+		return nil, fmt.Errorf("%w %s", domain.ErrNoChannelProvider, channel)
+	}
+	return shared, nil
+}
@@ -0,0 +1,126 @@
+package webhook
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ercancavusoglu/messaging/internal/adapters/mocks"
+	"github.com/ercancavusoglu/messaging/internal/config"
+	"github.com/ercancavusoglu/messaging/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTenantClientResolver_ClientFor_SharedWithoutTenant(t *testing.T) {
+	shared := new(MockWebhookClient)
+	mockTenants := &mocks.MockTenantRepository{}
+
+	resolver := NewTenantClientResolver(ChannelClients{domain.ChannelSMS: shared}, config.Default().Webhook.ProviderList(), mockTenants, 2)
+
+	client, err := resolver.ClientFor(0, domain.ChannelSMS)
+	assert.NoError(t, err)
+	assert.Same(t, shared, client)
+
+	// Tenant'ı olmayan mesajlar için veritabanına gidilmemeli
+	mockTenants.AssertNotCalled(t, "Get", int64(0))
+}
+
+func TestTenantClientResolver_ClientFor_SharedWithoutCredentials(t *testing.T) {
+	shared := new(MockWebhookClient)
+	mockTenants := &mocks.MockTenantRepository{}
+
+	resolver := NewTenantClientResolver(ChannelClients{domain.ChannelSMS: shared}, config.Default().Webhook.ProviderList(), mockTenants, 2)
+
+	mockTenants.On("Get", int64(7)).Return(&domain.Tenant{ID: 7, Name: "payments"}, nil)
+
+	client, err := resolver.ClientFor(7, domain.ChannelSMS)
+	assert.NoError(t, err)
+	assert.Same(t, shared, client)
+}
+
+func TestTenantClientResolver_ClientFor_TenantCredentials(t *testing.T) {
+	shared := new(MockWebhookClient)
+	mockTenants := &mocks.MockTenantRepository{}
+
+	resolver := NewTenantClientResolver(ChannelClients{domain.ChannelSMS: shared}, config.Default().Webhook.ProviderList(), mockTenants, 2)
+
+	mockTenants.On("Get", int64(7)).Return(&domain.Tenant{
+		ID:   7,
+		Name: "payments",
+		ProviderCredentials: map[string]domain.ProviderCredential{
+			"client_two": {URL: "https://sms.example.com", Token: "secret"},
+		},
+	}, nil)
+
+	client, err := resolver.ClientFor(7, domain.ChannelSMS)
+	assert.NoError(t, err)
+
+	retryable, ok := client.(*RetryableWebhookClient)
+	assert.True(t, ok)
+	assert.Len(t, retryable.clients, 1)
+	assert.Equal(t, "https://sms.example.com", retryable.clients[0].(*HTTPProvider).spec.URL)
+	assert.Equal(t, "secret", retryable.clients[0].(*HTTPProvider).spec.Auth.Token)
+	assert.Equal(t, "client_two", retryable.clients[0].(*HTTPProvider).Name())
+}
+
+func TestTenantClientResolver_Reload(t *testing.T) {
+	shared := new(MockWebhookClient)
+	rotated := new(MockWebhookClient)
+	mockTenants := &mocks.MockTenantRepository{}
+
+	resolver := NewTenantClientResolver(ChannelClients{domain.ChannelSMS: shared}, config.Default().Webhook.ProviderList(), mockTenants, 2)
+	before, _ := resolver.ClientFor(0, domain.ChannelSMS)
+
+	resolver.Reload(ChannelClients{domain.ChannelSMS: rotated}, config.Default().Webhook.ProviderList(), 5)
+
+	// Önceden alınan istemci değişmez, yeni mesajlar yeni istemciyi kullanır
+	after, err := resolver.ClientFor(0, domain.ChannelSMS)
+	assert.NoError(t, err)
+	assert.Same(t, shared, before)
+	assert.Same(t, rotated, after)
+
+	mockTenants.On("Get", int64(7)).Return(&domain.Tenant{
+		ID: 7,
+		ProviderCredentials: map[string]domain.ProviderCredential{
+			"client_one": {URL: "https://sms.example.com", Token: "secret"},
+		},
+	}, nil)
+
+	client, err := resolver.ClientFor(7, domain.ChannelSMS)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, client.(*RetryableWebhookClient).maxRetries)
+}
+
+func TestTenantClientResolver_ClientFor_ChannelWithoutProvider(t *testing.T) {
+	shared := new(MockWebhookClient)
+
+	resolver := NewTenantClientResolver(ChannelClients{domain.ChannelSMS: shared}, config.Default().Webhook.ProviderList(), nil, 2)
+
+	_, err := resolver.ClientFor(0, domain.ChannelEmail)
+	assert.True(t, errors.Is(err, domain.ErrNoChannelProvider))
+}
+
+func TestTenantClientResolver_ClientFor_CredentialsOfOtherChannel(t *testing.T) {
+	sms := new(MockWebhookClient)
+	whatsapp := new(MockWebhookClient)
+	mockTenants := &mocks.MockTenantRepository{}
+
+	providers := config.Default().Webhook.ProviderList()
+	providers[1].Channel = domain.ChannelWhatsApp
+	resolver := NewTenantClientResolver(ChannelClients{domain.ChannelSMS: sms, domain.ChannelWhatsApp: whatsapp}, providers, mockTenants, 2)
+
+	mockTenants.On("Get", int64(7)).Return(&domain.Tenant{
+		ID: 7,
+		ProviderCredentials: map[string]domain.ProviderCredential{
+			providers[1].Name: {URL: "https://wa.example.com", Token: "secret"},
+		},
+	}, nil)
+
+	// WhatsApp sağlayıcısının bilgileri SMS mesajlarında kullanılmamalı
+	client, err := resolver.ClientFor(7, domain.ChannelSMS)
+	assert.NoError(t, err)
+	assert.Same(t, sms, client)
+
+	client, err = resolver.ClientFor(7, domain.ChannelWhatsApp)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://wa.example.com", client.(*RetryableWebhookClient).clients[0].(*HTTPProvider).spec.URL)
+}
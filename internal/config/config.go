@@ -0,0 +1,475 @@
+// Package config resolves the service settings. Every setting has a default and can be overridden, in
+// increasing precedence, by a YAML file, an environment variable and a command line flag.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ercancavusoglu/messaging/internal/domain"
+	"gopkg.in/yaml.v3"
+)
+
+// redacted replaces secret values in the printed configuration
+const redacted = "******"
+
+// Config is the typed configuration. Field tags name the YAML key, the environment variable, whether
+// the value is a secret ("true", or "url" to only hide the password of a URL), whether a command
+// using the section requires it and whether a running process applies changes without a restart.
+type Config struct {
+	Server    Server    `yaml:"server"`
+	Database  Database  `yaml:"database"`
+	Redis     Redis     `yaml:"redis"`
+	RabbitMQ  RabbitMQ  `yaml:"rabbitmq"`
+	Kafka     Kafka     `yaml:"kafka"`
+	EventBus  EventBus  `yaml:"event_bus"`
+	Log       Log       `yaml:"log"`
+	Webhook   Webhook   `yaml:"webhook"`
+	Scheduler Scheduler `yaml:"scheduler"`
+	Consumer  Consumer  `yaml:"consumer"`
+	Message   Message   `yaml:"message"`
+	Inbound   Inbound   `yaml:"inbound"`
+	Cache     Cache     `yaml:"cache"`
+}
+
+type Server struct {
+	Port string `yaml:"port" env:"SERVER_PORT" required:"true"`
+	// AdminAPIKey is the operator key for tenant management; the admin API is disabled when empty
+	AdminAPIKey string `yaml:"admin_api_key" env:"ADMIN_API_KEY" secret:"true"`
+}
+
+type Database struct {
+	Host     string `yaml:"host" env:"DB_HOST" required:"true"`
+	Port     string `yaml:"port" env:"DB_PORT" required:"true"`
+	User     string `yaml:"user" env:"DB_USER" required:"true"`
+	Password string `yaml:"password" env:"DB_PASSWORD" secret:"true"`
+	Name     string `yaml:"name" env:"DB_NAME" required:"true"`
+	SSLMode  string `yaml:"ssl_mode" env:"DB_SSL_MODE"`
+}
+
+type Redis struct {
+	Host string `yaml:"host" env:"REDIS_HOST" required:"true"`
+	Port string `yaml:"port" env:"REDIS_PORT" required:"true"`
+}
+
+type RabbitMQ struct {
+	URL string `yaml:"url" env:"RABBITMQ_URL" secret:"url" required:"true"`
+	// Prefetch is how many unacknowledged deliveries RabbitMQ hands each queue's consumer at once
+	Prefetch int `yaml:"prefetch" env:"RABBITMQ_PREFETCH"`
+	// DispatchWorkers handle each queue's deliveries concurrently, so events of one queue may finish out of order
+	DispatchWorkers int `yaml:"dispatch_workers" env:"RABBITMQ_DISPATCH_WORKERS"`
+}
+
+type Kafka struct {
+	Brokers []string `yaml:"brokers" env:"KAFKA_BROKERS" required:"true"`
+	// GroupID is the consumer group the workers share; delayed events are relayed by a group of its own
+	GroupID string `yaml:"group_id" env:"KAFKA_GROUP_ID"`
+	// TopicPrefix namespaces the topics, e.g. so environments can share a cluster
+	TopicPrefix string `yaml:"topic_prefix" env:"KAFKA_TOPIC_PREFIX"`
+	// Partitions and ReplicationFactor apply to the topics the bus creates; existing topics are kept as they are
+	Partitions        int `yaml:"partitions" env:"KAFKA_PARTITIONS"`
+	ReplicationFactor int `yaml:"replication_factor" env:"KAFKA_REPLICATION_FACTOR"`
+	// MaxAttempts is how often the handlers get an event before it is moved to the dead letter topic
+	MaxAttempts int `yaml:"max_attempts" env:"KAFKA_MAX_ATTEMPTS"`
+}
+
+const (
+	DriverRabbitMQ = "rabbitmq"
+	DriverKafka    = "kafka"
+	// DriverMemory keeps events inside the process, so publishers and consumers must run in the same
+	// process, e.g. the standalone command or tests
+	DriverMemory = "memory"
+)
+
+type EventBus struct {
+	Driver string `yaml:"driver" env:"EVENT_BUS_DRIVER"`
+	// BufferSize bounds the events waiting in each queue of the memory driver
+	BufferSize int `yaml:"buffer_size" env:"EVENT_BUS_BUFFER_SIZE"`
+}
+
+type Log struct {
+	Path string `yaml:"path" env:"LOG_PATH" required:"true"`
+}
+
+// Webhook configures the SMS providers. URLOne, TokenOne, URLTwo and TokenTwo configure the built-in
+// providers, which are only used when no providers are declared.
+type Webhook struct {
+	URLOne     string `yaml:"url_one" env:"WEBHOOK_URL_ONE" reload:"true"`
+	TokenOne   string `yaml:"token_one" env:"WEBHOOK_TOKEN_ONE" secret:"true" reload:"true"`
+	URLTwo     string `yaml:"url_two" env:"WEBHOOK_URL_TWO" reload:"true"`
+	TokenTwo   string `yaml:"token_two" env:"WEBHOOK_TOKEN_TWO" secret:"true" reload:"true"`
+	MaxRetries int    `yaml:"max_retries" env:"WEBHOOK_MAX_RETRIES" reload:"true"`
+	// Providers are declared in the config file only, in failover order
+	Providers []Provider `yaml:"providers,omitempty" reload:"true"`
+}
+
+type Scheduler struct {
+	// Interval is used until an interval is set through the API
+	Interval  time.Duration `yaml:"interval" env:"SCHEDULER_INTERVAL"`
+	BatchSize int           `yaml:"batch_size" env:"SCHEDULER_BATCH_SIZE" reload:"true"`
+	Lease     time.Duration `yaml:"lease" env:"SCHEDULER_LEASE"`
+	// VisibilityTimeout is how long a claimed message may stay queued before the scheduler claims it again
+	VisibilityTimeout time.Duration `yaml:"visibility_timeout" env:"SCHEDULER_VISIBILITY_TIMEOUT"`
+	// MaxInFlight caps the messages claimed but not settled by the consumers, so claims follow their pace
+	MaxInFlight int `yaml:"max_in_flight" env:"SCHEDULER_MAX_IN_FLIGHT" reload:"true"`
+	// AutoStart runs the scheduler on boot until someone stops it through the API
+	AutoStart bool `yaml:"auto_start" env:"SCHEDULER_AUTO_START"`
+}
+
+// Consumer splits the workers between the priority classes, see consumer.WorkerShares
+type Consumer struct {
+	CriticalWorkers int `yaml:"critical_workers" env:"CONSUMER_CRITICAL_WORKERS" reload:"true"`
+	NormalWorkers   int `yaml:"normal_workers" env:"CONSUMER_NORMAL_WORKERS" reload:"true"`
+	BulkWorkers     int `yaml:"bulk_workers" env:"CONSUMER_BULK_WORKERS" reload:"true"`
+	SharedWorkers   int `yaml:"shared_workers" env:"CONSUMER_SHARED_WORKERS" reload:"true"`
+}
+
+type Message struct {
+	// DefaultRegion is used to parse phone numbers written in national format
+	DefaultRegion string `yaml:"default_region" env:"DEFAULT_REGION"`
+	MaxSegments   int    `yaml:"max_segments" env:"MESSAGE_MAX_SEGMENTS"`
+}
+
+type Inbound struct {
+	// SigningKey verifies the inbound webhook calls of providers without a signing key of their own
+	SigningKey    string   `yaml:"signing_key" env:"INBOUND_SIGNING_KEY" secret:"true" reload:"true"`
+	StopKeywords  []string `yaml:"stop_keywords" env:"INBOUND_STOP_KEYWORDS"`
+	StartKeywords []string `yaml:"start_keywords" env:"INBOUND_START_KEYWORDS"`
+	HelpKeywords  []string `yaml:"help_keywords" env:"INBOUND_HELP_KEYWORDS"`
+}
+
+type Cache struct {
+	// TTL bounds how long sent message markers stay in Redis
+	TTL time.Duration `yaml:"ttl" env:"CACHE_TTL" reload:"true"`
+}
+
+// Default returns the settings used when nothing overrides them
+func Default() *Config {
+	return &Config{
+		Server:   Server{Port: "8080"},
+		Database: Database{Port: "5432", SSLMode: "disable"},
+		Redis:    Redis{Port: "6379"},
+		RabbitMQ: RabbitMQ{Prefetch: 16, DispatchWorkers: 4},
+		Kafka:    Kafka{GroupID: "messaging", TopicPrefix: "messaging", Partitions: 6, ReplicationFactor: 1, MaxAttempts: 10},
+		EventBus: EventBus{Driver: DriverRabbitMQ, BufferSize: 10000},
+		Log:      Log{Path: "logs/dev.log"},
+		Webhook:  Webhook{MaxRetries: 2},
+		Scheduler: Scheduler{
+			Interval:          2 * time.Second,
+			BatchSize:         500,
+			Lease:             15 * time.Second,
+			VisibilityTimeout: 10 * time.Minute,
+			MaxInFlight:       2000,
+			AutoStart:         true,
+		},
+		Consumer: Consumer{
+			CriticalWorkers: 2,
+			NormalWorkers:   1,
+			BulkWorkers:     1,
+			SharedWorkers:   2,
+		},
+		Message: Message{DefaultRegion: "TR", MaxSegments: 6},
+		Inbound: Inbound{
+			StopKeywords:  domain.DefaultStopKeywords,
+			StartKeywords: domain.DefaultStartKeywords,
+			HelpKeywords:  domain.DefaultHelpKeywords,
+		},
+		Cache: Cache{TTL: 24 * time.Hour},
+	}
+}
+
+// Load resolves the configuration from the defaults, the YAML file at path (skipped when empty), the
+// environment and the flag overrides keyed by setting, e.g. "scheduler.interval"
+func Load(path string, lookupEnv func(string) (string, bool), overrides map[string]string) (*Config, error) {
+	cfg := Default()
+
+	if path != "" {
+		if err := cfg.loadFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	var errs []error
+	for _, f := range cfg.fields() {
+		if f.Env == "" {
+			continue
+		}
+		value, ok := lookupEnv(f.Env)
+		if !ok {
+			continue
+		}
+		// An empty variable clears a text setting, so a required one is reported instead of silently
+		// falling back to its default; other settings keep their value
+		if value == "" && f.value.Kind() != reflect.String {
+			continue
+		}
+		if err := f.set(value); err != nil {
+			errs = append(errs, fmt.Errorf("invalid %s: %w", f.Env, err))
+		}
+	}
+
+	known := make(map[string]Field)
+	for _, f := range cfg.fields() {
+		if f.Env != "" {
+			known[f.Key] = f
+		}
+	}
+	for key, value := range overrides {
+		f, ok := known[key]
+		if !ok {
+			errs = append(errs, fmt.Errorf("unknown setting -%s", key))
+			continue
+		}
+		if err := f.set(value); err != nil {
+			errs = append(errs, fmt.Errorf("invalid -%s: %w", key, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return cfg, nil
+}
+
+func (c *Config) loadFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open config file: %v", err)
+	}
+	defer file.Close()
+
+	decoder := yaml.NewDecoder(file)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(c); err != nil {
+		return fmt.Errorf("failed to parse config file %s: %v", path, err)
+	}
+
+	return nil
+}
+
+// Validate checks the values that every command relies on; connection settings are checked by Require
+// once a command knows which connections it opens
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, key, format string, args ...interface{}) {
+		if !ok {
+			f := c.field(key)
+			errs = append(errs, fmt.Errorf("%s (%s) %s", f.Key, f.Env, fmt.Sprintf(format, args...)))
+		}
+	}
+
+	check(c.Server.Port == "" || validPort(c.Server.Port), "server.port", "must be a port number, got %q", c.Server.Port)
+	check(c.Database.Port == "" || validPort(c.Database.Port), "database.port", "must be a port number, got %q", c.Database.Port)
+	check(c.Redis.Port == "" || validPort(c.Redis.Port), "redis.port", "must be a port number, got %q", c.Redis.Port)
+	check(c.EventBus.Driver == DriverRabbitMQ || c.EventBus.Driver == DriverKafka || c.EventBus.Driver == DriverMemory, "event_bus.driver",
+		"must be %s, %s or %s, got %q", DriverRabbitMQ, DriverKafka, DriverMemory, c.EventBus.Driver)
+	check(c.EventBus.BufferSize >= 1, "event_bus.buffer_size", "must be at least 1")
+	check(c.RabbitMQ.Prefetch >= 1, "rabbitmq.prefetch", "must be at least 1")
+	check(c.RabbitMQ.DispatchWorkers >= 1 && c.RabbitMQ.DispatchWorkers <= c.RabbitMQ.Prefetch, "rabbitmq.dispatch_workers",
+		"must be between 1 and rabbitmq.prefetch (%d), got %d", c.RabbitMQ.Prefetch, c.RabbitMQ.DispatchWorkers)
+	check(c.Kafka.GroupID != "", "kafka.group_id", "cannot be empty")
+	check(c.Kafka.TopicPrefix != "", "kafka.topic_prefix", "cannot be empty")
+	check(c.Kafka.Partitions >= 1, "kafka.partitions", "must be at least 1")
+	check(c.Kafka.ReplicationFactor >= 1, "kafka.replication_factor", "must be at least 1")
+	check(c.Kafka.MaxAttempts >= 1, "kafka.max_attempts", "must be at least 1")
+	check(c.Webhook.MaxRetries >= 0, "webhook.max_retries", "cannot be negative")
+	names := make(map[string]bool)
+	for i, provider := range c.Webhook.Providers {
+		if err := provider.validate(); err != nil {
+			errs = append(errs, fmt.Errorf("webhook.providers[%d] %s", i, strings.ReplaceAll(err.Error(), "\n", "; ")))
+		}
+		if names[provider.Name] {
+			errs = append(errs, fmt.Errorf("webhook.providers[%d] name %q is used twice", i, provider.Name))
+		}
+		names[provider.Name] = true
+	}
+	check(domain.ValidateSchedulerInterval(c.Scheduler.Interval) == nil, "scheduler.interval",
+		"must be between %s and %s", domain.MinSchedulerInterval, domain.MaxSchedulerInterval)
+	check(c.Scheduler.BatchSize >= 1, "scheduler.batch_size", "must be at least 1")
+	check(c.Scheduler.Lease >= time.Second, "scheduler.lease", "must be at least 1s")
+	check(c.Scheduler.VisibilityTimeout >= time.Minute, "scheduler.visibility_timeout", "must be at least 1m")
+	check(c.Scheduler.MaxInFlight >= c.Scheduler.BatchSize, "scheduler.max_in_flight",
+		"must be at least scheduler.batch_size (%d), got %d", c.Scheduler.BatchSize, c.Scheduler.MaxInFlight)
+	for _, key := range []string{"consumer.critical_workers", "consumer.normal_workers", "consumer.bulk_workers", "consumer.shared_workers"} {
+		check(c.field(key).value.Int() >= 0, key, "cannot be negative")
+	}
+	check(c.Message.MaxSegments >= 1, "message.max_segments", "must be at least 1")
+	check(c.Cache.TTL > 0, "cache.ttl", "must be positive")
+
+	return errors.Join(errs...)
+}
+
+// Require reports the required settings of the sections that are empty, e.g. Require("database")
+func (c *Config) Require(sections ...string) error {
+	var errs []error
+	for _, f := range c.fields() {
+		if !f.Required || !f.value.IsZero() {
+			continue
+		}
+		for _, section := range sections {
+			if strings.HasPrefix(f.Key, section+".") {
+				errs = append(errs, fmt.Errorf("%s (%s) is required", f.Key, f.Env))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Redacted returns a copy with the secrets hidden, safe to print or log
+func (c *Config) Redacted() *Config {
+	clone := *c
+	for _, f := range clone.fields() {
+		if f.Secret == "" || f.value.String() == "" {
+			continue
+		}
+		if f.Secret == "url" {
+			f.value.SetString(redactURL(f.value.String()))
+		} else {
+			f.value.SetString(redacted)
+		}
+	}
+
+	if len(c.Webhook.Providers) > 0 {
+		clone.Webhook.Providers = make([]Provider, len(c.Webhook.Providers))
+		for i, provider := range c.Webhook.Providers {
+			clone.Webhook.Providers[i] = provider.redacted()
+		}
+	}
+	return &clone
+}
+
+// RestartRequired lists the settings that differ in next but only take effect after a restart
+func (c *Config) RestartRequired(next *Config) []string {
+	var keys []string
+	nextFields := next.fields()
+	for i, f := range c.fields() {
+		if !f.Reloadable && !reflect.DeepEqual(f.value.Interface(), nextFields[i].value.Interface()) {
+			keys = append(keys, f.Key)
+		}
+	}
+	return keys
+}
+
+// YAML renders the configuration in the format Load reads
+func (c *Config) YAML() ([]byte, error) {
+	return yaml.Marshal(c)
+}
+
+func (d Database) URL() string {
+	return fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=%s",
+		d.User, d.Password, d.Host, d.Port, d.Name, d.SSLMode)
+}
+
+func (r Redis) Addr() string {
+	return net.JoinHostPort(r.Host, r.Port)
+}
+
+func validPort(value string) bool {
+	port, err := strconv.Atoi(value)
+	return err == nil && port >= 1 && port <= 65535
+}
+
+func redactURL(value string) string {
+	parsed, err := url.Parse(value)
+	if err != nil {
+		return redacted
+	}
+	if _, ok := parsed.User.Password(); ok {
+		parsed.User = url.UserPassword(parsed.User.Username(), redacted)
+	}
+	// Keep the placeholder readable instead of percent-encoded
+	return strings.Replace(parsed.String(), url.QueryEscape(redacted), redacted, 1)
+}
+
+// Field is one setting, addressed by its dotted YAML key
+type Field struct {
+	Key        string
+	Env        string
+	Secret     string
+	Required   bool
+	Reloadable bool
+	value      reflect.Value
+}
+
+// Fields lists the settings with their keys and environment variables, e.g. to register flags; settings
+// without an environment variable can only be set in the config file
+func (c *Config) Fields() []Field {
+	return c.fields()
+}
+
+func (c *Config) fields() []Field {
+	var fields []Field
+	root := reflect.ValueOf(c).Elem()
+	for i := 0; i < root.NumField(); i++ {
+		section := root.Type().Field(i)
+		sectionValue := root.Field(i)
+		for j := 0; j < sectionValue.NumField(); j++ {
+			setting := section.Type.Field(j)
+			fields = append(fields, Field{
+				Key:        section.Tag.Get("yaml") + "." + setting.Tag.Get("yaml"),
+				Env:        setting.Tag.Get("env"),
+				Secret:     setting.Tag.Get("secret"),
+				Required:   setting.Tag.Get("required") == "true",
+				Reloadable: setting.Tag.Get("reload") == "true",
+				value:      sectionValue.Field(j),
+			})
+		}
+	}
+	return fields
+}
+
+func (c *Config) field(key string) Field {
+	for _, f := range c.fields() {
+		if f.Key == key {
+			return f
+		}
+	}
+	panic("config: unknown setting " + key)
+}
+
+// set parses a raw environment or flag value into the setting
+func (f Field) set(raw string) error {
+	raw = strings.TrimSpace(raw)
+
+	switch {
+	case f.value.Type() == reflect.TypeOf(time.Duration(0)):
+		duration, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("%q is not a duration such as 30s or 5m", raw)
+		}
+		f.value.SetInt(int64(duration))
+	case f.value.Kind() == reflect.Int:
+		number, err := strconv.Atoi(raw)
+		if err != nil {
+			return fmt.Errorf("%q is not a number", raw)
+		}
+		f.value.SetInt(int64(number))
+	case f.value.Kind() == reflect.Bool:
+		value, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("%q is not true or false", raw)
+		}
+		f.value.SetBool(value)
+	case f.value.Kind() == reflect.Slice:
+		// Comma separated; an empty value would have kept the default
+		var items []string
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		f.value.Set(reflect.ValueOf(items))
+	default:
+		f.value.SetString(raw)
+	}
+
+	return nil
+}
@@ -0,0 +1,339 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ercancavusoglu/messaging/internal/domain"
+	"github.com/ercancavusoglu/messaging/internal/domain/valueobject"
+)
+
+// Authentication schemes of an HTTP provider
+const (
+	AuthNone        = "none"
+	AuthBearer      = "bearer"
+	AuthBasic       = "basic"
+	AuthHMAC        = "hmac"
+	AuthAPIKeyQuery = "api_key_query"
+)
+
+// Transports of a provider
+const (
+	TypeHTTP = "http"
+	TypeSMPP = "smpp"
+	TypeSMTP = "smtp"
+)
+
+// Bind modes of an SMPP provider
+const (
+	BindTransmitter = "transmitter"
+	BindTransceiver = "transceiver"
+)
+
+// TLS modes of an SMTP provider
+const (
+	SMTPStartTLS = "starttls"
+	SMTPTLS      = "tls"
+	SMTPPlain    = "none"
+)
+
+// Body encodings of an HTTP provider
+const (
+	EncodingJSON = "json"
+	EncodingForm = "form"
+)
+
+// Provider declares a delivery provider of one channel. An HTTP provider describes how to build its
+// request and how to read its response; an SMPP provider describes its SMSC session and an SMTP
+// provider its mail server. Empty settings fall back to the defaults documented on each field.
+type Provider struct {
+	Name string `yaml:"name"`
+	// Type is the transport: http (default), smpp or smtp
+	Type string `yaml:"type,omitempty"`
+	// Channel is the channel the provider delivers: sms by default, email for smtp providers
+	Channel domain.Channel `yaml:"channel,omitempty"`
+	URL     string         `yaml:"url,omitempty"`
+	// Method is POST by default
+	Method string `yaml:"method,omitempty"`
+	// Timeout bounds a single request, 10s by default
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+	// Encoding of the body: json (default) or form
+	Encoding string `yaml:"encoding,omitempty"`
+	// Body maps request fields to values in which {{to}}, {{content}}, {{subject}}, {{channel}} and
+	// {{provider}} are replaced; a dotted field such as message.text nests JSON objects. By default to,
+	// content and provider are sent.
+	Body    map[string]string `yaml:"body,omitempty"`
+	Headers map[string]string `yaml:"headers,omitempty"`
+	Auth    ProviderAuth      `yaml:"auth,omitempty"`
+	// Response describes how to read the provider's answer
+	Response ProviderResponse `yaml:"response,omitempty"`
+	// InsecureSkipVerify disables TLS certificate verification
+	InsecureSkipVerify bool `yaml:"insecure_skip_verify,omitempty"`
+	// SMPP holds the session settings of an smpp provider
+	SMPP ProviderSMPP `yaml:"smpp,omitempty"`
+	// SMTP holds the mail server settings of an smtp provider
+	SMTP ProviderSMTP `yaml:"smtp,omitempty"`
+	// Inbound verifies the provider's calls to the inbound webhook
+	Inbound ProviderInbound `yaml:"inbound,omitempty"`
+}
+
+type ProviderAuth struct {
+	// Scheme is one of none (default), bearer, basic, hmac and api_key_query
+	Scheme string `yaml:"scheme,omitempty"`
+	// Token is the bearer token, the HMAC secret or the API key
+	Token    string `yaml:"token,omitempty"`
+	Username string `yaml:"username,omitempty"`
+	Password string `yaml:"password,omitempty"`
+	// Header carries the hex HMAC-SHA256 signature of the body, X-Signature by default
+	Header string `yaml:"header,omitempty"`
+	// Param is the query parameter carrying the API key, api_key by default
+	Param string `yaml:"param,omitempty"`
+}
+
+// ProviderInbound is how a provider signs the messages it posts to /api/v1/inbound/{name}
+type ProviderInbound struct {
+	// SigningKey is the HMAC secret, inbound.signing_key by default
+	SigningKey string `yaml:"signing_key,omitempty"`
+	// Header carries the hex HMAC-SHA256 signature of the body, X-Signature by default
+	Header string `yaml:"header,omitempty"`
+}
+
+type ProviderSMPP struct {
+	// Addr is the host:port of the SMSC
+	Addr       string `yaml:"addr,omitempty"`
+	SystemID   string `yaml:"system_id,omitempty"`
+	Password   string `yaml:"password,omitempty"`
+	SystemType string `yaml:"system_type,omitempty"`
+	// Bind is transceiver (default), which also receives delivery receipts, or transmitter
+	Bind string `yaml:"bind,omitempty"`
+	// SourceAddr is the sender address of submitted messages
+	SourceAddr string `yaml:"source_addr,omitempty"`
+	// EnquireLink is the keepalive interval, 30s by default
+	EnquireLink time.Duration `yaml:"enquire_link,omitempty"`
+	// Window bounds the submits awaiting a response, 10 by default
+	Window int `yaml:"window,omitempty"`
+	// ReconnectDelay is the pause before binding again after a lost session, 5s by default
+	ReconnectDelay time.Duration `yaml:"reconnect_delay,omitempty"`
+}
+
+type ProviderSMTP struct {
+	// Addr is the host:port of the mail server
+	Addr     string `yaml:"addr,omitempty"`
+	Username string `yaml:"username,omitempty"`
+	Password string `yaml:"password,omitempty"`
+	// From is the sender address
+	From string `yaml:"from,omitempty"`
+	// TLS is starttls (default), tls for implicit TLS as on port 465, or none
+	TLS string `yaml:"tls,omitempty"`
+}
+
+type ProviderResponse struct {
+	// MessageID is the JSONPath of the provider's message ID, $.messageId by default
+	MessageID string `yaml:"message_id,omitempty"`
+	// Message is the JSONPath of the provider's description, $.message by default
+	Message string `yaml:"message,omitempty"`
+	// SuccessStatus lists the accepted status codes; any 2xx status by default
+	SuccessStatus []int `yaml:"success_status,omitempty"`
+	// SuccessPath, when set, also requires the value at this JSONPath to equal SuccessValue
+	SuccessPath  string `yaml:"success_path,omitempty"`
+	SuccessValue string `yaml:"success_value,omitempty"`
+}
+
+// ProviderList returns the declared providers in failover order. Without any, it returns the built-in
+// client_one and client_two providers configured by the URL and token settings.
+func (w Webhook) ProviderList() []Provider {
+	if len(w.Providers) > 0 {
+		return w.Providers
+	}
+
+	return []Provider{
+		builtinProvider("client_one", w.URLOne, w.TokenOne),
+		builtinProvider("client_two", w.URLTwo, w.TokenTwo),
+	}
+}
+
+// InboundSignatures returns how each provider signs its inbound webhook calls. Providers without a signing
+// key of their own use inbound.signing_key; providers left without any key are not listed.
+func (c *Config) InboundSignatures() map[string]ProviderInbound {
+	signatures := make(map[string]ProviderInbound)
+	for _, provider := range c.Webhook.ProviderList() {
+		signature := provider.Inbound
+		if signature.SigningKey == "" {
+			signature.SigningKey = c.Inbound.SigningKey
+		}
+		if signature.SigningKey == "" {
+			continue
+		}
+		if signature.Header == "" {
+			signature.Header = "X-Signature"
+		}
+		signatures[provider.Name] = signature
+	}
+	return signatures
+}
+
+// builtinProvider keeps the request format of the original provider clients
+func builtinProvider(name, url, token string) Provider {
+	return Provider{
+		Name:               name,
+		URL:                url,
+		Auth:               ProviderAuth{Scheme: AuthBearer, Token: token},
+		InsecureSkipVerify: true,
+	}
+}
+
+// DeliveryChannel is the channel the provider delivers, sms unless configured otherwise or an smtp provider
+func (p Provider) DeliveryChannel() domain.Channel {
+	if p.Channel != "" {
+		return p.Channel
+	}
+	if p.Type == TypeSMTP {
+		return domain.ChannelEmail
+	}
+	return domain.ChannelSMS
+}
+
+// WithCredential returns a copy sending to url with token, e.g. a tenant's own provider account. The
+// token replaces the password of basic authentication and the token of the other schemes.
+func (p Provider) WithCredential(url, token string) Provider {
+	p.URL = url
+	if p.Auth.Scheme == AuthBasic {
+		p.Auth.Password = token
+	} else {
+		p.Auth.Token = token
+	}
+	return p
+}
+
+func (p Provider) validate() error {
+	var errs []error
+
+	if strings.TrimSpace(p.Name) == "" {
+		errs = append(errs, errors.New("name is required"))
+	}
+	if p.Timeout < 0 {
+		errs = append(errs, errors.New("timeout cannot be negative"))
+	}
+
+	if p.Channel != "" {
+		if _, err := domain.ParseChannel(string(p.Channel)); err != nil {
+			errs = append(errs, fmt.Errorf("channel must be one of sms, email, push and whatsapp, got %q", p.Channel))
+		}
+	}
+
+	switch p.Type {
+	case "", TypeHTTP:
+	case TypeSMPP:
+		if p.DeliveryChannel() != domain.ChannelSMS {
+			errs = append(errs, fmt.Errorf("smpp providers only deliver sms, got channel %q", p.Channel))
+		}
+		return errors.Join(append(errs, p.SMPP.validate()...)...)
+	case TypeSMTP:
+		if p.DeliveryChannel() != domain.ChannelEmail {
+			errs = append(errs, fmt.Errorf("smtp providers only deliver email, got channel %q", p.Channel))
+		}
+		return errors.Join(append(errs, p.SMTP.validate()...)...)
+	default:
+		errs = append(errs, fmt.Errorf("type must be http, smpp or smtp, got %q", p.Type))
+	}
+
+	if p.URL == "" {
+		errs = append(errs, errors.New("url is required"))
+	}
+
+	switch p.Encoding {
+	case "", EncodingJSON, EncodingForm:
+	default:
+		errs = append(errs, fmt.Errorf("encoding must be json or form, got %q", p.Encoding))
+	}
+
+	switch p.Auth.Scheme {
+	case "", AuthNone:
+	case AuthBasic:
+		if p.Auth.Username == "" {
+			errs = append(errs, errors.New("auth.username is required for basic authentication"))
+		}
+	case AuthBearer, AuthHMAC, AuthAPIKeyQuery:
+		if p.Auth.Token == "" {
+			errs = append(errs, fmt.Errorf("auth.token is required for %s authentication", p.Auth.Scheme))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("auth.scheme must be one of none, bearer, basic, hmac and api_key_query, got %q", p.Auth.Scheme))
+	}
+
+	for key, path := range map[string]string{
+		"response.message_id":   p.Response.MessageID,
+		"response.message":      p.Response.Message,
+		"response.success_path": p.Response.SuccessPath,
+	} {
+		if path != "" && !strings.HasPrefix(path, "$") {
+			errs = append(errs, fmt.Errorf("%s must be a JSONPath starting with $, got %q", key, path))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (s ProviderSMPP) validate() []error {
+	var errs []error
+
+	if s.Addr == "" {
+		errs = append(errs, errors.New("smpp.addr is required"))
+	}
+	if s.SystemID == "" {
+		errs = append(errs, errors.New("smpp.system_id is required"))
+	}
+	switch s.Bind {
+	case "", BindTransmitter, BindTransceiver:
+	default:
+		errs = append(errs, fmt.Errorf("smpp.bind must be transmitter or transceiver, got %q", s.Bind))
+	}
+	if s.EnquireLink < 0 || s.ReconnectDelay < 0 {
+		errs = append(errs, errors.New("smpp.enquire_link and smpp.reconnect_delay cannot be negative"))
+	}
+	if s.Window < 0 {
+		errs = append(errs, errors.New("smpp.window cannot be negative"))
+	}
+
+	return errs
+}
+
+func (s ProviderSMTP) validate() []error {
+	var errs []error
+
+	if s.Addr == "" {
+		errs = append(errs, errors.New("smtp.addr is required"))
+	}
+	if _, err := valueobject.NewEmailAddress(s.From); err != nil {
+		errs = append(errs, fmt.Errorf("smtp.from must be an email address, got %q", s.From))
+	}
+	switch s.TLS {
+	case "", SMTPStartTLS, SMTPTLS, SMTPPlain:
+	default:
+		errs = append(errs, fmt.Errorf("smtp.tls must be one of starttls, tls and none, got %q", s.TLS))
+	}
+
+	return errs
+}
+
+// redacted returns a copy with the credentials hidden
+func (p Provider) redacted() Provider {
+	if p.Auth.Token != "" {
+		p.Auth.Token = redacted
+	}
+	if p.Auth.Password != "" {
+		p.Auth.Password = redacted
+	}
+	if p.SMPP.Password != "" {
+		p.SMPP.Password = redacted
+	}
+	if p.SMTP.Password != "" {
+		p.SMTP.Password = redacted
+	}
+	if p.Inbound.SigningKey != "" {
+		p.Inbound.SigningKey = redacted
+	}
+	return p
+}
@@ -0,0 +1,121 @@
+package config
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// defaultPollInterval is how often the watched files are checked for changes
+const defaultPollInterval = 2 * time.Second
+
+// Watcher reloads the configuration when a watched file changes or the process receives SIGHUP.
+// A configuration that fails to load or validate is rejected and the current one stays in effect.
+type Watcher struct {
+	load         func() (*Config, error)
+	files        []string
+	pollInterval time.Duration
+
+	mu      sync.Mutex
+	current *Config
+	stamps  map[string]fileStamp
+}
+
+type fileStamp struct {
+	exists  bool
+	size    int64
+	modTime time.Time
+}
+
+// NewWatcher watches files, e.g. the config file and .env; load resolves and validates a new configuration
+func NewWatcher(load func() (*Config, error), current *Config, files ...string) *Watcher {
+	w := &Watcher{
+		load:         load,
+		pollInterval: defaultPollInterval,
+		current:      current,
+		stamps:       make(map[string]fileStamp),
+	}
+
+	for _, file := range files {
+		if file != "" {
+			w.files = append(w.files, file)
+			w.stamps[file] = stat(file)
+		}
+	}
+
+	return w
+}
+
+// Current returns the configuration in effect
+func (w *Watcher) Current() *Config {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.current
+}
+
+// Reload loads the configuration and makes it current; on error the current one is kept
+func (w *Watcher) Reload() (*Config, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	next, err := w.load()
+	if err != nil {
+		return nil, err
+	}
+
+	w.current = next
+	return next, nil
+}
+
+// Run reloads until ctx is done, passing every new configuration to apply and every rejected one to reject
+func (w *Watcher) Run(ctx context.Context, apply func(*Config), reject func(error)) {
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	defer signal.Stop(hangup)
+
+	ticker := time.NewTicker(w.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hangup:
+		case <-ticker.C:
+			if !w.changed() {
+				continue
+			}
+		}
+
+		next, err := w.Reload()
+		if err != nil {
+			reject(err)
+			continue
+		}
+		apply(next)
+	}
+}
+
+// changed reports whether a watched file was written, created or removed since the last check
+func (w *Watcher) changed() bool {
+	changed := false
+	for _, file := range w.files {
+		stamp := stat(file)
+		if stamp != w.stamps[file] {
+			w.stamps[file] = stamp
+			changed = true
+		}
+	}
+	return changed
+}
+
+func stat(file string) fileStamp {
+	info, err := os.Stat(file)
+	if err != nil {
+		return fileStamp{}
+	}
+	return fileStamp{exists: true, size: info.Size(), modTime: info.ModTime()}
+}
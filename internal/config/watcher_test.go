@@ -0,0 +1,73 @@
+package config
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatcher_ReloadsChangedFile(t *testing.T) {
+	path := writeFile(t, "scheduler:\n  batch_size: 100\n")
+	load := func() (*Config, error) {
+		cfg, err := Load(path, env(nil), nil)
+		if err != nil {
+			return nil, err
+		}
+		return cfg, cfg.Validate()
+	}
+
+	initial, err := load()
+	require.NoError(t, err)
+
+	watcher := NewWatcher(load, initial, path)
+	watcher.pollInterval = 10 * time.Millisecond
+
+	applied := make(chan *Config, 1)
+	rejected := make(chan error, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go watcher.Run(ctx, func(cfg *Config) { applied <- cfg }, func(err error) { rejected <- err })
+
+	rewrite(t, path, "scheduler:\n  batch_size: 250\n")
+	select {
+	case cfg := <-applied:
+		assert.Equal(t, 250, cfg.Scheduler.BatchSize)
+	case <-time.After(time.Second):
+		t.Fatal("changed config was not applied")
+	}
+
+	// Geçersiz yapılandırma reddedilir, mevcut yapılandırma çalışmaya devam eder
+	rewrite(t, path, "scheduler:\n  batch_size: 0\n")
+	select {
+	case err := <-rejected:
+		assert.Contains(t, err.Error(), "scheduler.batch_size (SCHEDULER_BATCH_SIZE) must be at least 1")
+	case <-time.After(time.Second):
+		t.Fatal("invalid config was not rejected")
+	}
+	assert.Equal(t, 250, watcher.Current().Scheduler.BatchSize)
+}
+
+func TestConfig_RestartRequired(t *testing.T) {
+	current := Default()
+	next := Default()
+	next.Webhook.TokenOne = "rotated"
+	next.Consumer.SharedWorkers = 8
+	next.Server.Port = "9090"
+	next.Inbound.StopKeywords = []string{"STOP"}
+
+	assert.Equal(t, []string{"server.port", "inbound.stop_keywords"}, current.RestartRequired(next))
+}
+
+// rewrite changes the file with a later modification time, so the change is seen on coarse clocks too
+func rewrite(t *testing.T, path, content string) {
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	modTime := info.ModTime().Add(time.Second)
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+}
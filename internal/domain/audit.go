@@ -0,0 +1,19 @@
+package domain
+
+import "time"
+
+const (
+	AuditActionSchedulerStart    = "scheduler.start"
+	AuditActionSchedulerStop     = "scheduler.stop"
+	AuditActionSchedulerInterval = "scheduler.interval"
+)
+
+// AuditEntry records who performed a privileged action, from where and with which outcome
+type AuditEntry struct {
+	ID         int64     `json:"id"`
+	Actor      string    `json:"actor"`
+	Action     string    `json:"action"`
+	Result     string    `json:"result"`
+	RemoteAddr string    `json:"remote_addr,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
@@ -0,0 +1,223 @@
+package domain
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	// CallbackSecretPrefix marks callback signing secrets so they are easy to recognize in secret scanners
+	CallbackSecretPrefix = "whsec_"
+	// EventCallbackPing is the event of the test deliveries sent through the ping endpoint
+	EventCallbackPing = "callback.ping"
+
+	// MaxCallbackAttempts is the number of tries before a delivery is given up as failed
+	MaxCallbackAttempts = 8
+	callbackBaseBackoff = 10 * time.Second
+	callbackMaxBackoff  = time.Hour
+)
+
+// CallbackEvents are the message events a subscription can receive
+var CallbackEvents = []string{EventMessageQueued, EventMessageSent, EventMessageFailed}
+
+var (
+	ErrCallbackNotFound       = errors.New("callback subscription not found")
+	ErrInvalidCallbackURL     = errors.New("callback url must be an absolute http or https url")
+	ErrCallbackHostNotAllowed = errors.New("callback url must resolve to public addresses only")
+	ErrCallbackEventsMissing  = errors.New("callback subscription needs at least one event")
+	ErrInvalidCallbackEvent   = errors.New("callback event must be one of message.queued, message.sent or message.failed")
+)
+
+type CallbackDeliveryStatus string
+
+const (
+	CallbackPending   CallbackDeliveryStatus = "pending"
+	CallbackDelivered CallbackDeliveryStatus = "delivered"
+	CallbackFailed    CallbackDeliveryStatus = "failed"
+)
+
+// CallbackSubscription is a tenant's URL that receives the events it lists as signed POST requests.
+// The secret is only returned when the subscription is created.
+type CallbackSubscription struct {
+	ID        int64     `json:"id"`
+	TenantID  int64     `json:"tenant_id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	Secret    string    `json:"secret,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// lookupCallbackHost resolves a callback host, replaced in tests to avoid DNS
+var lookupCallbackHost = net.LookupIP
+
+// Validate checks the url and de-duplicates the events. The host has to resolve to public addresses only,
+// so a tenant cannot point the dispatcher at the service's own network. The dispatcher checks the
+// addresses again when it connects, since DNS can change after validation.
+func (s *CallbackSubscription) Validate() error {
+	target, err := url.Parse(s.URL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Hostname() == "" {
+		return fmt.Errorf("%w: %q", ErrInvalidCallbackURL, s.URL)
+	}
+
+	ips, err := lookupCallbackHost(target.Hostname())
+	if err != nil {
+		return fmt.Errorf("%w: failed to resolve %q: %v", ErrCallbackHostNotAllowed, target.Hostname(), err)
+	}
+	for _, ip := range ips {
+		if !IsPublicIP(ip) {
+			return fmt.Errorf("%w: %q resolves to %s", ErrCallbackHostNotAllowed, target.Hostname(), ip)
+		}
+	}
+
+	if len(s.Events) == 0 {
+		return ErrCallbackEventsMissing
+	}
+
+	seen := make(map[string]bool, len(s.Events))
+	events := make([]string, 0, len(s.Events))
+	for _, event := range s.Events {
+		if !isCallbackEvent(event) {
+			return fmt.Errorf("%w: %q", ErrInvalidCallbackEvent, event)
+		}
+		if !seen[event] {
+			seen[event] = true
+			events = append(events, event)
+		}
+	}
+	s.Events = events
+
+	return nil
+}
+
+// Receives reports whether the subscription lists the event
+func (s *CallbackSubscription) Receives(eventName string) bool {
+	for _, event := range s.Events {
+		if event == eventName {
+			return true
+		}
+	}
+	return false
+}
+
+// Redacted returns a copy without the secret
+func (s *CallbackSubscription) Redacted() *CallbackSubscription {
+	redacted := *s
+	redacted.Secret = ""
+	return &redacted
+}
+
+// IsPublicIP reports whether callbacks may connect to the address: loopback, private, link-local (which
+// holds the cloud metadata endpoints), unspecified and multicast addresses are refused
+func IsPublicIP(ip net.IP) bool {
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified())
+}
+
+func isCallbackEvent(eventName string) bool {
+	for _, event := range CallbackEvents {
+		if event == eventName {
+			return true
+		}
+	}
+	return false
+}
+
+// NewCallbackSecret generates the key a subscription's payloads are signed with
+func NewCallbackSecret() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate callback secret: %v", err)
+	}
+	return CallbackSecretPrefix + hex.EncodeToString(buf), nil
+}
+
+// SignCallback returns the hex HMAC-SHA256 of "<unix timestamp>.<body>" keyed with the secret. Signing the
+// timestamp lets receivers reject replayed requests.
+func SignCallback(secret string, timestamp time.Time, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp.Unix(), 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// CallbackDelivery is one event on its way to a subscription, and its entry in the delivery log
+type CallbackDelivery struct {
+	ID             int64                  `json:"id"`
+	SubscriptionID int64                  `json:"subscription_id"`
+	TenantID       int64                  `json:"tenant_id"`
+	MessageID      int64                  `json:"message_id,omitempty"`
+	Event          string                 `json:"event"`
+	Payload        json.RawMessage        `json:"payload"`
+	Status         CallbackDeliveryStatus `json:"status"`
+	Attempts       int                    `json:"attempts"`
+	ResponseStatus int                    `json:"response_status,omitempty"`
+	LastError      string                 `json:"last_error,omitempty"`
+	NextAttemptAt  time.Time              `json:"next_attempt_at"`
+	CreatedAt      time.Time              `json:"created_at"`
+	DeliveredAt    *time.Time             `json:"delivered_at,omitempty"`
+}
+
+// NewCallbackDelivery returns a pending delivery of the update that is due right away
+func NewCallbackDelivery(subscription *CallbackSubscription, messageID int64, payload MessageUpdate) (*CallbackDelivery, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode callback payload: %v", err)
+	}
+
+	now := time.Now()
+	return &CallbackDelivery{
+		SubscriptionID: subscription.ID,
+		TenantID:       subscription.TenantID,
+		MessageID:      messageID,
+		Event:          payload.Event,
+		Payload:        body,
+		Status:         CallbackPending,
+		NextAttemptAt:  now,
+		CreatedAt:      now,
+	}, nil
+}
+
+// Succeed records an attempt the subscriber answered with a 2xx status
+func (d *CallbackDelivery) Succeed(responseStatus int, now time.Time) {
+	d.Attempts++
+	d.Status = CallbackDelivered
+	d.ResponseStatus = responseStatus
+	d.LastError = ""
+	d.DeliveredAt = &now
+}
+
+// Fail records a failed attempt and schedules the next one with exponential backoff, or gives the
+// delivery up after MaxCallbackAttempts
+func (d *CallbackDelivery) Fail(responseStatus int, reason string, now time.Time) {
+	d.Attempts++
+	d.ResponseStatus = responseStatus
+	d.LastError = reason
+
+	if d.Attempts >= MaxCallbackAttempts {
+		d.Status = CallbackFailed
+		return
+	}
+	d.NextAttemptAt = now.Add(CallbackBackoff(d.Attempts))
+}
+
+// CallbackBackoff is the wait after the given number of failed attempts: 10s, 20s, 40s and so on, up to an hour
+func CallbackBackoff(attempts int) time.Duration {
+	backoff := callbackBaseBackoff
+	for i := 1; i < attempts && backoff < callbackMaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > callbackMaxBackoff {
+		backoff = callbackMaxBackoff
+	}
+	return backoff
+}
@@ -0,0 +1,97 @@
+package domain
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestCallbackSubscription_Validate(t *testing.T) {
+	// DNS yerine sabit kayıtlar kullanılır
+	hosts := map[string][]net.IP{
+		"hooks.example.com":  {net.ParseIP("93.184.216.34")},
+		"internal.example":   {net.ParseIP("93.184.216.34"), net.ParseIP("10.0.0.5")},
+		"rebind.example.com": {net.ParseIP("::1")},
+	}
+	defer func(lookup func(string) ([]net.IP, error)) { lookupCallbackHost = lookup }(lookupCallbackHost)
+	lookupCallbackHost = func(host string) ([]net.IP, error) {
+		if ip := net.ParseIP(host); ip != nil {
+			return []net.IP{ip}, nil
+		}
+		if ips, ok := hosts[host]; ok {
+			return ips, nil
+		}
+		return nil, errors.New("no such host")
+	}
+
+	tests := []struct {
+		name         string
+		subscription CallbackSubscription
+		want         error
+	}{
+		{name: "valid", subscription: CallbackSubscription{URL: "https://hooks.example.com/sms", Events: []string{EventMessageSent}}},
+		{name: "relative url", subscription: CallbackSubscription{URL: "/hooks", Events: []string{EventMessageSent}}, want: ErrInvalidCallbackURL},
+		{name: "other scheme", subscription: CallbackSubscription{URL: "ftp://hooks.example.com", Events: []string{EventMessageSent}}, want: ErrInvalidCallbackURL},
+		{name: "no events", subscription: CallbackSubscription{URL: "https://hooks.example.com"}, want: ErrCallbackEventsMissing},
+		{name: "unknown event", subscription: CallbackSubscription{URL: "https://hooks.example.com", Events: []string{EventMessageInbound}}, want: ErrInvalidCallbackEvent},
+		{name: "public address", subscription: CallbackSubscription{URL: "https://93.184.216.34:8443/sms", Events: []string{EventMessageSent}}},
+		{name: "loopback", subscription: CallbackSubscription{URL: "http://127.0.0.1:8080/admin", Events: []string{EventMessageSent}}, want: ErrCallbackHostNotAllowed},
+		{name: "metadata endpoint", subscription: CallbackSubscription{URL: "http://169.254.169.254/latest/meta-data", Events: []string{EventMessageSent}}, want: ErrCallbackHostNotAllowed},
+		{name: "private ipv6", subscription: CallbackSubscription{URL: "http://[fd00::1]/hooks", Events: []string{EventMessageSent}}, want: ErrCallbackHostNotAllowed},
+		{name: "one private record", subscription: CallbackSubscription{URL: "https://internal.example/hooks", Events: []string{EventMessageSent}}, want: ErrCallbackHostNotAllowed},
+		{name: "name of loopback", subscription: CallbackSubscription{URL: "https://rebind.example.com/hooks", Events: []string{EventMessageSent}}, want: ErrCallbackHostNotAllowed},
+		{name: "unresolvable host", subscription: CallbackSubscription{URL: "https://missing.example.com/hooks", Events: []string{EventMessageSent}}, want: ErrCallbackHostNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.subscription.Validate(); !errors.Is(err, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestSignCallback(t *testing.T) {
+	timestamp := time.Unix(1760000000, 0)
+	body := []byte(`{"event":"message.sent"}`)
+
+	signature := SignCallback("whsec_abc", timestamp, body)
+	if len(signature) != 64 {
+		t.Fatalf("Expected a hex SHA-256 signature, got %q", signature)
+	}
+	if SignCallback("whsec_abc", timestamp.Add(time.Second), body) == signature {
+		t.Error("Expected the timestamp to be signed")
+	}
+	if SignCallback("whsec_other", timestamp, body) == signature {
+		t.Error("Expected the secret to change the signature")
+	}
+}
+
+func TestCallbackDelivery_Fail(t *testing.T) {
+	now := time.Now()
+	delivery := &CallbackDelivery{Status: CallbackPending}
+
+	delivery.Fail(503, "unexpected status code: 503", now)
+	if delivery.Status != CallbackPending || !delivery.NextAttemptAt.Equal(now.Add(10*time.Second)) {
+		t.Errorf("Expected a retry in 10s, got %+v", delivery)
+	}
+
+	for delivery.Status == CallbackPending {
+		delivery.Fail(0, "connection refused", now)
+	}
+	if delivery.Attempts != MaxCallbackAttempts || delivery.Status != CallbackFailed {
+		t.Errorf("Expected the delivery to fail after %d attempts, got %d (%s)", MaxCallbackAttempts, delivery.Attempts, delivery.Status)
+	}
+}
+
+func TestCallbackBackoff(t *testing.T) {
+	tests := map[int]time.Duration{1: 10 * time.Second, 2: 20 * time.Second, 4: 80 * time.Second, 20: time.Hour}
+
+	for attempts, want := range tests {
+		if got := CallbackBackoff(attempts); got != want {
+			t.Errorf("CallbackBackoff(%d) = %v, want %v", attempts, got, want)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type CampaignStatus string
+
+const (
+	CampaignScheduled CampaignStatus = "scheduled"
+	CampaignRunning   CampaignStatus = "running"
+	CampaignPaused    CampaignStatus = "paused"
+	CampaignCompleted CampaignStatus = "completed"
+	CampaignCancelled CampaignStatus = "cancelled"
+)
+
+// MaxCampaignAudience bounds the number of recipients created with a single request
+const MaxCampaignAudience = 100000
+
+var (
+	ErrCampaignNotFound          = errors.New("campaign not found")
+	ErrCampaignNameRequired      = errors.New("campaign name is required")
+	ErrCampaignTemplateRequired  = errors.New("campaign template is required")
+	ErrCampaignAudienceEmpty     = errors.New("campaign audience cannot be empty")
+	ErrCampaignAudienceTooLarge  = fmt.Errorf("campaign audience cannot exceed %d recipients", MaxCampaignAudience)
+	ErrInvalidCampaignTransition = errors.New("invalid campaign status transition")
+)
+
+// CampaignRecipient is one member of the audience; its variables override the campaign variables
+type CampaignRecipient struct {
+	To        string            `json:"to"`
+	Variables map[string]string `json:"variables,omitempty"`
+}
+
+// InvalidRecipientError reports the audience member whose message could not be built
+type InvalidRecipientError struct {
+	Index int
+	Err   error
+}
+
+func (e *InvalidRecipientError) Error() string {
+	return fmt.Sprintf("audience[%d]: %v", e.Index, e.Err)
+}
+
+func (e *InvalidRecipientError) Unwrap() error {
+	return e.Err
+}
+
+// CampaignProgress counts the campaign's messages by outcome. Delivered is a subset of sent and
+// only grows when providers report delivery receipts.
+type CampaignProgress struct {
+	Pending   int `json:"pending"`
+	Sent      int `json:"sent"`
+	Failed    int `json:"failed"`
+	Delivered int `json:"delivered"`
+}
+
+// Campaign sends one template to an audience. Its messages are created up front and the scheduler
+// only picks them up while the campaign is due and neither paused nor cancelled.
+type Campaign struct {
+	ID           int64               `json:"id"`
+	TenantID     int64               `json:"tenant_id"`
+	Name         string              `json:"name"`
+	TemplateID   int64               `json:"template_id"`
+	Locale       string              `json:"locale,omitempty"`
+	Variables    map[string]string   `json:"variables,omitempty"`
+	Audience     []CampaignRecipient `json:"audience,omitempty"`
+	AudienceSize int                 `json:"audience_size"`
+	Priority     Priority            `json:"priority"`
+	ScheduledAt  *time.Time          `json:"scheduled_at,omitempty"`
+	Status       CampaignStatus      `json:"status"`
+	Progress     CampaignProgress    `json:"progress"`
+	CreatedAt    time.Time           `json:"created_at"`
+	UpdatedAt    time.Time           `json:"updated_at"`
+}
+
+// Validate checks the campaign and defaults its priority to bulk
+func (c *Campaign) Validate() error {
+	c.Name = strings.TrimSpace(c.Name)
+	if c.Name == "" {
+		return ErrCampaignNameRequired
+	}
+
+	if c.TemplateID == 0 {
+		return ErrCampaignTemplateRequired
+	}
+
+	if len(c.Audience) == 0 {
+		return ErrCampaignAudienceEmpty
+	}
+	if len(c.Audience) > MaxCampaignAudience {
+		return ErrCampaignAudienceTooLarge
+	}
+
+	if c.Priority == "" {
+		c.Priority = PriorityBulk
+	}
+	priority, err := ParsePriority(string(c.Priority))
+	if err != nil {
+		return err
+	}
+	c.Priority = priority
+
+	return nil
+}
+
+// Start sets the initial status: scheduled when the campaign starts in the future, running otherwise
+func (c *Campaign) Start(now time.Time) {
+	c.AudienceSize = len(c.Audience)
+	c.Progress = CampaignProgress{Pending: c.AudienceSize}
+	c.Status = c.activeStatus(now)
+}
+
+func (c *Campaign) Pause() error {
+	if c.Status != CampaignScheduled && c.Status != CampaignRunning {
+		return c.transitionError("pause")
+	}
+	c.Status = CampaignPaused
+	return nil
+}
+
+func (c *Campaign) Resume(now time.Time) error {
+	if c.Status != CampaignPaused {
+		return c.transitionError("resume")
+	}
+	c.Status = c.activeStatus(now)
+	return nil
+}
+
+func (c *Campaign) Cancel() error {
+	if c.Status == CampaignCompleted || c.Status == CampaignCancelled {
+		return c.transitionError("cancel")
+	}
+	c.Status = CampaignCancelled
+	return nil
+}
+
+// VariablesFor merges the campaign variables with the recipient's own
+func (c *Campaign) VariablesFor(recipient CampaignRecipient) map[string]string {
+	variables := make(map[string]string, len(c.Variables)+len(recipient.Variables))
+	for name, value := range c.Variables {
+		variables[name] = value
+	}
+	for name, value := range recipient.Variables {
+		variables[name] = value
+	}
+	return variables
+}
+
+func (c *Campaign) activeStatus(now time.Time) CampaignStatus {
+	if c.ScheduledAt != nil && c.ScheduledAt.After(now) {
+		return CampaignScheduled
+	}
+	return CampaignRunning
+}
+
+func (c *Campaign) transitionError(action string) error {
+	return fmt.Errorf("%w: cannot %s a %s campaign", ErrInvalidCampaignTransition, action, c.Status)
+}
+
+// CampaignProgressFor returns the change an event makes to the progress of the message's campaign
+func CampaignProgressFor(eventName string) (CampaignProgress, bool) {
+	switch eventName {
+	case EventMessageSent:
+		return CampaignProgress{Pending: -1, Sent: 1}, true
+	case EventMessageFailed:
+		return CampaignProgress{Pending: -1, Failed: 1}, true
+	case EventMessageDelivered:
+		return CampaignProgress{Delivered: 1}, true
+	}
+	return CampaignProgress{}, false
+}
@@ -0,0 +1,99 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func createTestCampaign() *Campaign {
+	return &Campaign{
+		Name:       " Spring sale ",
+		TemplateID: 1,
+		Variables:  map[string]string{"discount": "20"},
+		Audience: []CampaignRecipient{
+			{To: "+905551234567", Variables: map[string]string{"name": "Ayşe"}},
+			{To: "+905551234568", Variables: map[string]string{"name": "Mehmet", "discount": "30"}},
+		},
+	}
+}
+
+func TestCampaign_Validate(t *testing.T) {
+	campaign := createTestCampaign()
+	if err := campaign.Validate(); err != nil {
+		t.Fatalf("Validate() unexpected error = %v", err)
+	}
+
+	if campaign.Name != "Spring sale" {
+		t.Errorf("Expected name to be trimmed, got %q", campaign.Name)
+	}
+	if campaign.Priority != PriorityBulk {
+		t.Errorf("Expected campaigns to default to bulk priority, got %s", campaign.Priority)
+	}
+
+	tests := []struct {
+		name     string
+		modify   func(*Campaign)
+		expected error
+	}{
+		{name: "Missing name", modify: func(c *Campaign) { c.Name = " " }, expected: ErrCampaignNameRequired},
+		{name: "Missing template", modify: func(c *Campaign) { c.TemplateID = 0 }, expected: ErrCampaignTemplateRequired},
+		{name: "Empty audience", modify: func(c *Campaign) { c.Audience = nil }, expected: ErrCampaignAudienceEmpty},
+		{name: "Invalid priority", modify: func(c *Campaign) { c.Priority = "urgent" }, expected: ErrInvalidPriority},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			campaign := createTestCampaign()
+			tt.modify(campaign)
+			if err := campaign.Validate(); !errors.Is(err, tt.expected) {
+				t.Errorf("Validate() error = %v, want %v", err, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCampaign_Lifecycle(t *testing.T) {
+	now := time.Now()
+	later := now.Add(time.Hour)
+
+	campaign := createTestCampaign()
+	campaign.ScheduledAt = &later
+	campaign.Start(now)
+
+	if campaign.Status != CampaignScheduled || campaign.Progress.Pending != 2 || campaign.AudienceSize != 2 {
+		t.Fatalf("Unexpected initial state: %s %+v", campaign.Status, campaign.Progress)
+	}
+
+	if err := campaign.Pause(); err != nil {
+		t.Fatalf("Pause() unexpected error = %v", err)
+	}
+	if err := campaign.Pause(); !errors.Is(err, ErrInvalidCampaignTransition) {
+		t.Errorf("Expected ErrInvalidCampaignTransition, got %v", err)
+	}
+
+	// Planlanan zaman geçtiyse devam ettirilen kampanya hemen çalışmalı
+	if err := campaign.Resume(later.Add(time.Minute)); err != nil || campaign.Status != CampaignRunning {
+		t.Fatalf("Resume() = %v, status %s", err, campaign.Status)
+	}
+
+	if err := campaign.Cancel(); err != nil || campaign.Status != CampaignCancelled {
+		t.Fatalf("Cancel() = %v, status %s", err, campaign.Status)
+	}
+	if err := campaign.Resume(now); !errors.Is(err, ErrInvalidCampaignTransition) {
+		t.Errorf("Expected ErrInvalidCampaignTransition, got %v", err)
+	}
+}
+
+func TestCampaign_VariablesFor(t *testing.T) {
+	campaign := createTestCampaign()
+
+	variables := campaign.VariablesFor(campaign.Audience[1])
+	if variables["discount"] != "30" || variables["name"] != "Mehmet" {
+		t.Errorf("Expected recipient variables to override campaign variables, got %v", variables)
+	}
+
+	if campaign.Variables["name"] != "" {
+		t.Error("Campaign variables must not be modified")
+	}
+}
@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Channel is the medium a message is delivered through; it decides what the recipient is
+type Channel string
+
+const (
+	ChannelSMS      Channel = "sms"
+	ChannelEmail    Channel = "email"
+	ChannelPush     Channel = "push"
+	ChannelWhatsApp Channel = "whatsapp"
+)
+
+var (
+	ErrInvalidChannel      = errors.New("channel must be one of sms, email, push or whatsapp")
+	ErrSubjectNotSupported = errors.New("subject is only supported for email")
+	ErrNoChannelProvider   = errors.New("no provider configured for channel")
+)
+
+var Channels = []Channel{ChannelSMS, ChannelEmail, ChannelPush, ChannelWhatsApp}
+
+// ParseChannel accepts a channel name case-insensitively; an empty value means sms
+func ParseChannel(value string) (Channel, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" {
+		return ChannelSMS, nil
+	}
+
+	for _, channel := range Channels {
+		if string(channel) == value {
+			return channel, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: %q", ErrInvalidChannel, value)
+}
+
+// OrDefault returns sms for messages stored or queued before channels existed
+func (c Channel) OrDefault() Channel {
+	if c == "" {
+		return ChannelSMS
+	}
+	return c
+}
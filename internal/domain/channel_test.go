@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseChannel(t *testing.T) {
+	channel, err := ParseChannel(" Email ")
+	if err != nil || channel != ChannelEmail {
+		t.Errorf("ParseChannel() = %s, %v, want email", channel, err)
+	}
+
+	if channel, _ := ParseChannel(""); channel != ChannelSMS {
+		t.Errorf("ParseChannel(\"\") = %s, want sms", channel)
+	}
+
+	if _, err := ParseChannel("fax"); !errors.Is(err, ErrInvalidChannel) {
+		t.Errorf("Expected ErrInvalidChannel, got %v", err)
+	}
+
+	if Channel("").OrDefault() != ChannelSMS {
+		t.Error("Expected an empty channel to default to sms")
+	}
+}
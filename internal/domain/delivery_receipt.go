@@ -0,0 +1,25 @@
+package domain
+
+// Final delivery states reported by providers, as used in SMPP delivery receipts
+const (
+	ReceiptDelivered   = "DELIVRD"
+	ReceiptExpired     = "EXPIRED"
+	ReceiptDeleted     = "DELETED"
+	ReceiptUndelivered = "UNDELIV"
+	ReceiptRejected    = "REJECTD"
+	ReceiptUnknown     = "UNKNOWN"
+)
+
+// DeliveryReceipt is a provider's report on the fate of a message it accepted, identified by the
+// message ID the provider returned when the message was sent
+type DeliveryReceipt struct {
+	Provider  string `json:"provider"`
+	MessageID string `json:"message_id"`
+	Status    string `json:"status"`
+	Error     string `json:"error,omitempty"`
+}
+
+// Delivered reports whether the recipient received the message
+func (r DeliveryReceipt) Delivered() bool {
+	return r.Status == ReceiptDelivered
+}
@@ -3,25 +3,35 @@ package domain
 import (
 	"fmt"
 	"strconv"
+	"time"
 )
 
 const (
-	EventMessageSent   = "message.sent"
-	EventMessageFailed = "message.failed"
-	EventMessageQueued = "message.queued"
+	EventMessageSent    = "message.sent"
+	EventMessageFailed  = "message.failed"
+	EventMessageQueued  = "message.queued"
+	EventMessageInbound = "message.inbound"
+	// EventMessageDelivered is published when a provider reports that the recipient received the message
+	EventMessageDelivered = "message.delivered"
+	// EventMessageFallback comes back once a sent message has waited for delivery as long as its next
+	// fallback step allows
+	EventMessageFallback = "message.fallback"
 )
 
 type MessageSentEvent struct {
 	BaseEvent
 	Message   *Message `json:"message"`
 	MessageID string   `json:"message_id"`
+	Provider  string   `json:"provider"`
 }
 
-func NewMessageSentEvent(message *Message, messageID string) MessageSentEvent {
+// NewMessageSentEvent records the provider that accepted the message and the ID it assigned
+func NewMessageSentEvent(message *Message, messageID, provider string) MessageSentEvent {
 	return MessageSentEvent{
 		BaseEvent: NewBaseEvent(EventMessageSent, strconv.FormatInt(message.ID, 10)),
 		Message:   message,
 		MessageID: messageID,
+		Provider:  provider,
 	}
 }
 
@@ -50,3 +60,52 @@ func NewMessageQueuedEvent(message *Message) MessageQueuedEvent {
 		Message:   message,
 	}
 }
+
+// EventPriority routes the event to the queue of the message's priority class
+func (e MessageQueuedEvent) EventPriority() Priority {
+	return e.Message.Priority.OrDefault()
+}
+
+type MessageDeliveredEvent struct {
+	BaseEvent
+	Message *Message `json:"message"`
+}
+
+func NewMessageDeliveredEvent(message *Message) MessageDeliveredEvent {
+	return MessageDeliveredEvent{
+		BaseEvent: NewBaseEvent(EventMessageDelivered, strconv.FormatInt(message.ID, 10)),
+		Message:   message,
+	}
+}
+
+type MessageFallbackEvent struct {
+	BaseEvent
+	Message *Message `json:"message"`
+}
+
+func NewMessageFallbackEvent(message *Message) MessageFallbackEvent {
+	return MessageFallbackEvent{
+		BaseEvent: NewBaseEvent(EventMessageFallback, strconv.FormatInt(message.ID, 10)),
+		Message:   message,
+	}
+}
+
+// EventDelay holds the event back for the wait of the message's next fallback step
+func (e MessageFallbackEvent) EventDelay() time.Duration {
+	if len(e.Message.Fallback) == 0 {
+		return 0
+	}
+	return e.Message.Fallback[0].Wait()
+}
+
+type MessageInboundEvent struct {
+	BaseEvent
+	Message *InboundMessage `json:"message"`
+}
+
+func NewMessageInboundEvent(message *InboundMessage) MessageInboundEvent {
+	return MessageInboundEvent{
+		BaseEvent: NewBaseEvent(EventMessageInbound, strconv.FormatInt(message.ID, 10)),
+		Message:   message,
+	}
+}
@@ -22,7 +22,7 @@ func createTestMessage() *Message {
 func TestNewMessageSentEvent(t *testing.T) {
 	msg := createTestMessage()
 	messageID := "msg_123"
-	event := NewMessageSentEvent(msg, messageID)
+	event := NewMessageSentEvent(msg, messageID, "client_one")
 
 	if event.Name != EventMessageSent {
 		t.Errorf("Expected event name to be %s, got %s", EventMessageSent, event.Name)
@@ -36,6 +36,10 @@ func TestNewMessageSentEvent(t *testing.T) {
 		t.Error("Expected message to be the same instance")
 	}
 
+	if event.Provider != "client_one" {
+		t.Errorf("Expected provider to be client_one, got %s", event.Provider)
+	}
+
 	if event.MessageID != messageID {
 		t.Errorf("Expected message ID to be %s, got %s", messageID, event.MessageID)
 	}
@@ -79,3 +83,25 @@ func TestNewMessageQueuedEvent(t *testing.T) {
 		t.Error("Expected message to be the same instance")
 	}
 }
+
+func TestNewMessageInboundEvent(t *testing.T) {
+	msg := &InboundMessage{
+		ID:      42,
+		From:    "+905551234567",
+		Content: "STOP",
+		Keyword: KeywordStop,
+	}
+	event := NewMessageInboundEvent(msg)
+
+	if event.Name != EventMessageInbound {
+		t.Errorf("Expected event name to be %s, got %s", EventMessageInbound, event.Name)
+	}
+
+	if event.AggregateID != "42" {
+		t.Errorf("Expected aggregate ID to be 42, got %s", event.AggregateID)
+	}
+
+	if event.Message != msg {
+		t.Error("Expected message to be the same instance")
+	}
+}
@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// MaxFallbackWait bounds how long an attempt may wait for its delivery before the next step is sent
+const MaxFallbackWait = 24 * time.Hour
+
+var (
+	ErrInvalidFallbackWait = errors.New("fallback wait_seconds must be between 1 and 86400")
+	ErrNoFallback          = errors.New("message has no fallback step left")
+	// ErrFallbackNotNeeded is returned when the parent was delivered or has already fallen back
+	ErrFallbackNotNeeded = errors.New("fallback is not needed")
+)
+
+// FallbackStep is the attempt that follows a message which fails, or which is not delivered within
+// WaitSeconds of being sent. The step goes out on its own channel and recipient with the content of the
+// message it follows.
+type FallbackStep struct {
+	Channel     Channel `json:"channel"`
+	To          string  `json:"to"`
+	Subject     string  `json:"subject,omitempty"`
+	WaitSeconds int     `json:"wait_seconds"`
+}
+
+func (s FallbackStep) Wait() time.Duration {
+	return time.Duration(s.WaitSeconds) * time.Second
+}
+
+// SetFallback validates every step as a message of its own and attaches the chain to the message with
+// the step recipients normalized
+func (m *Message) SetFallback(steps []FallbackStep, rules MessageRules) error {
+	var chain []FallbackStep
+	for i, step := range steps {
+		if step.WaitSeconds < 1 || step.Wait() > MaxFallbackWait {
+			return fmt.Errorf("fallback[%d]: %w", i, ErrInvalidFallbackWait)
+		}
+		next, err := NewChannelMessage(step.Channel, step.To, step.Subject, m.Content, rules)
+		if err != nil {
+			return fmt.Errorf("fallback[%d]: %w", i, err)
+		}
+		step.To = next.To
+		chain = append(chain, step)
+	}
+
+	m.Fallback = chain
+	return nil
+}
+
+// NextFallback returns the pending message of the first fallback step. It is linked to m and carries
+// the steps after it, so the chain continues if the fallback is not delivered either.
+func (m *Message) NextFallback(rules MessageRules) (*Message, error) {
+	if len(m.Fallback) == 0 {
+		return nil, ErrNoFallback
+	}
+
+	step := m.Fallback[0]
+	next, err := NewChannelMessage(step.Channel, step.To, step.Subject, m.Content, rules)
+	if err != nil {
+		return nil, err
+	}
+	next.TenantID = m.TenantID
+	next.ParentID = m.ID
+	next.Priority = m.Priority
+	next.Fallback = m.Fallback[1:]
+
+	return next, nil
+}
@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMessage_SetFallback(t *testing.T) {
+	msg, _ := NewChannelMessage(ChannelPush, "device-token-1", "", "Kodunuz: 123456", DefaultMessageRules())
+
+	err := msg.SetFallback([]FallbackStep{
+		{Channel: ChannelSMS, To: "0555 123 45 67", WaitSeconds: 30},
+		{Channel: ChannelEmail, To: "jane@Example.com", Subject: "Kod", WaitSeconds: 60},
+	}, DefaultMessageRules())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if msg.Fallback[0].To != "+905551234567" || msg.Fallback[1].To != "jane@example.com" {
+		t.Errorf("Expected normalized recipients, got %+v", msg.Fallback)
+	}
+	if msg.Fallback[0].Wait() != 30*time.Second {
+		t.Errorf("Wait() = %v, want 30s", msg.Fallback[0].Wait())
+	}
+
+	tests := []struct {
+		name string
+		step FallbackStep
+		want error
+	}{
+		{name: "no wait", step: FallbackStep{Channel: ChannelSMS, To: "+905551234567"}, want: ErrInvalidFallbackWait},
+		{name: "wait too long", step: FallbackStep{Channel: ChannelSMS, To: "+905551234567", WaitSeconds: 86401}, want: ErrInvalidFallbackWait},
+		{name: "no channel", step: FallbackStep{To: "+905551234567", WaitSeconds: 30}, want: ErrInvalidChannel},
+		{name: "subject on sms", step: FallbackStep{Channel: ChannelSMS, To: "+905551234567", Subject: "Kod", WaitSeconds: 30}, want: ErrSubjectNotSupported},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := msg.SetFallback([]FallbackStep{tt.step}, DefaultMessageRules()); !errors.Is(err, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestMessage_NextFallback(t *testing.T) {
+	msg, _ := NewChannelMessage(ChannelPush, "device-token-1", "", "Kodunuz: 123456", DefaultMessageRules())
+	msg.ID = 3
+	msg.TenantID = 7
+	msg.Priority = PriorityCritical
+	msg.Status = StatusSent
+	msg.SetFallback([]FallbackStep{
+		{Channel: ChannelSMS, To: "+905551234567", WaitSeconds: 30},
+		{Channel: ChannelEmail, To: "jane@example.com", Subject: "Kod", WaitSeconds: 60},
+	}, DefaultMessageRules())
+
+	next, err := msg.NextFallback(DefaultMessageRules())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if next.ParentID != 3 || next.TenantID != 7 || next.Priority != PriorityCritical || next.Status != StatusPending {
+		t.Errorf("Expected a pending critical child of message 3 for tenant 7, got %+v", next)
+	}
+	if next.Channel != ChannelSMS || next.To != "+905551234567" || next.Content != msg.Content || next.Segments != 1 {
+		t.Errorf("Expected an SMS with the parent's content, got %+v", next)
+	}
+	if len(next.Fallback) != 1 || next.Fallback[0].Channel != ChannelEmail {
+		t.Errorf("Expected the email step to remain, got %+v", next.Fallback)
+	}
+
+	last, _ := next.NextFallback(DefaultMessageRules())
+	if _, err := last.NextFallback(DefaultMessageRules()); !errors.Is(err, ErrNoFallback) {
+		t.Errorf("Expected ErrNoFallback at the end of the chain, got %v", err)
+	}
+}
@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"strings"
+	"time"
+	"unicode"
+)
+
+type InboundKeyword string
+
+const (
+	KeywordNone  InboundKeyword = ""
+	KeywordStop  InboundKeyword = "stop"
+	KeywordStart InboundKeyword = "start"
+	KeywordHelp  InboundKeyword = "help"
+)
+
+var (
+	DefaultStopKeywords  = []string{"STOP", "STOPALL", "UNSUBSCRIBE", "CANCEL", "END", "QUIT", "IPTAL", "DUR"}
+	DefaultStartKeywords = []string{"START", "UNSTOP", "BASLA"}
+	DefaultHelpKeywords  = []string{"HELP", "INFO", "YARDIM", "BILGI"}
+)
+
+// turkishFolder maps upper-cased Turkish letters to their ASCII equivalents
+// so that "iptal", "İPTAL" and "IPTAL" all match the same keyword
+var turkishFolder = strings.NewReplacer("İ", "I", "Ş", "S", "Ğ", "G", "Ü", "U", "Ö", "O", "Ç", "C")
+
+// InboundMessage is a mobile-originated reply forwarded by a provider
+type InboundMessage struct {
+	ID                int64          `json:"id"`
+	From              string         `json:"from"`
+	To                string         `json:"to"`
+	Content           string         `json:"content"`
+	Provider          string         `json:"provider"`
+	ProviderMessageID string         `json:"provider_message_id"`
+	Keyword           InboundKeyword `json:"keyword,omitempty"`
+	ReceivedAt        time.Time      `json:"received_at"`
+}
+
+type KeywordMatcher struct {
+	keywords map[string]InboundKeyword
+}
+
+func NewKeywordMatcher(stop, start, help []string) *KeywordMatcher {
+	m := &KeywordMatcher{keywords: make(map[string]InboundKeyword)}
+	for keyword, words := range map[InboundKeyword][]string{
+		KeywordStop:  stop,
+		KeywordStart: start,
+		KeywordHelp:  help,
+	} {
+		for _, word := range words {
+			if normalized := NormalizeKeyword(word); normalized != "" {
+				m.keywords[normalized] = keyword
+			}
+		}
+	}
+
+	return m
+}
+
+func DefaultKeywordMatcher() *KeywordMatcher {
+	return NewKeywordMatcher(DefaultStopKeywords, DefaultStartKeywords, DefaultHelpKeywords)
+}
+
+// Match inspects the first word of the content, ignoring case, punctuation and Turkish diacritics
+func (m *KeywordMatcher) Match(content string) InboundKeyword {
+	fields := strings.Fields(content)
+	if len(fields) == 0 {
+		return KeywordNone
+	}
+
+	return m.keywords[NormalizeKeyword(fields[0])]
+}
+
+func NormalizeKeyword(word string) string {
+	word = strings.TrimFunc(word, func(r rune) bool {
+		return unicode.IsPunct(r) || unicode.IsSpace(r)
+	})
+
+	return turkishFolder.Replace(strings.ToUpper(word))
+}
@@ -0,0 +1,49 @@
+package domain
+
+import "testing"
+
+func TestKeywordMatcher_Match(t *testing.T) {
+	matcher := DefaultKeywordMatcher()
+
+	tests := []struct {
+		name     string
+		content  string
+		expected InboundKeyword
+	}{
+		{name: "Stop", content: "STOP", expected: KeywordStop},
+		{name: "Lowercase stop with punctuation", content: " stop! ", expected: KeywordStop},
+		{name: "Unsubscribe", content: "Unsubscribe", expected: KeywordStop},
+		{name: "Turkish iptal", content: "iptal", expected: KeywordStop},
+		{name: "Turkish İPTAL", content: "İPTAL", expected: KeywordStop},
+		{name: "Turkish başla", content: "başla", expected: KeywordStart},
+		{name: "Start", content: "start", expected: KeywordStart},
+		{name: "Help", content: "HELP me", expected: KeywordHelp},
+		{name: "Turkish yardım", content: "yardım", expected: KeywordHelp},
+		{name: "Keyword not at start", content: "please stop", expected: KeywordNone},
+		{name: "Empty content", content: "   ", expected: KeywordNone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matcher.Match(tt.content); got != tt.expected {
+				t.Errorf("Match(%q) = %q, want %q", tt.content, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestKeywordMatcher_Custom(t *testing.T) {
+	matcher := NewKeywordMatcher([]string{"ABMELDEN"}, []string{"anmelden"}, nil)
+
+	if got := matcher.Match("abmelden"); got != KeywordStop {
+		t.Errorf("Expected custom stop keyword to match, got %q", got)
+	}
+
+	if got := matcher.Match("ANMELDEN"); got != KeywordStart {
+		t.Errorf("Expected custom start keyword to match, got %q", got)
+	}
+
+	if got := matcher.Match("STOP"); got != KeywordNone {
+		t.Errorf("Expected default keywords to be replaced, got %q", got)
+	}
+}
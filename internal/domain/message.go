@@ -1,22 +1,133 @@
 package domain
 
-import "time"
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ercancavusoglu/messaging/internal/domain/valueobject"
+)
 
 type MessageStatus string
 
 const (
 	StatusPending MessageStatus = "pending"
-	StatusSent    MessageStatus = "sent"
-	StatusFailed  MessageStatus = "failed"
+	StatusQueued  MessageStatus = "queued"
+	// StatusSending marks a message a consumer took off the queue and is handing to its provider
+	StatusSending    MessageStatus = "sending"
+	StatusSent       MessageStatus = "sent"
+	StatusFailed     MessageStatus = "failed"
+	StatusSuppressed MessageStatus = "suppressed"
+	StatusCancelled  MessageStatus = "cancelled"
 )
 
+var ErrMessageNotFound = errors.New("message not found")
+
+// MessageRules are the message settings new messages are validated against. Services are given them when
+// they are built, so every message they create follows the same settings.
+type MessageRules struct {
+	// DefaultRegion is used to parse phone numbers written in national format, e.g. "05551234567"
+	DefaultRegion string
+	// MaxSegments is the number of parts SMS content may be split into
+	MaxSegments int
+}
+
+// DefaultMessageRules matches the defaults of the message settings
+func DefaultMessageRules() MessageRules {
+	return MessageRules{DefaultRegion: "TR", MaxSegments: 6}
+}
+
 type Message struct {
-	ID        int64         `json:"id"`
-	To        string        `json:"to"`
-	Content   string        `json:"content"`
-	Status    MessageStatus `json:"status"`
-	MessageID string        `json:"message_id"`
-	Provider  string        `json:"provider"`
-	CreatedAt time.Time     `json:"created_at"`
-	SentAt    *time.Time    `json:"sent_at,omitempty"`
+	ID         int64 `json:"id"`
+	TenantID   int64 `json:"tenant_id,omitempty"`
+	CampaignID int64 `json:"campaign_id,omitempty"`
+	// ParentID links a fallback message to the message it follows
+	ParentID  int64                `json:"parent_id,omitempty"`
+	Channel   Channel              `json:"channel"`
+	To        string               `json:"to"`
+	Subject   string               `json:"subject,omitempty"`
+	Content   string               `json:"content"`
+	Encoding  valueobject.Encoding `json:"encoding,omitempty"`
+	Segments  int                  `json:"segments,omitempty"`
+	Priority  Priority             `json:"priority"`
+	Status    MessageStatus        `json:"status"`
+	MessageID string               `json:"message_id"`
+	Provider  string               `json:"provider"`
+	CreatedAt time.Time            `json:"created_at"`
+	SentAt    *time.Time           `json:"sent_at,omitempty"`
+	// DeliveredAt is set when the provider reports that the recipient received the message
+	DeliveredAt *time.Time     `json:"delivered_at,omitempty"`
+	Fallback    []FallbackStep `json:"fallback,omitempty"`
+}
+
+// NewMessage validates the recipient and content and returns a pending SMS message
+// with the recipient normalized to E.164 and the SMS encoding detected
+func NewMessage(to, content string, rules MessageRules) (*Message, error) {
+	return NewChannelMessage(ChannelSMS, to, "", content, rules)
+}
+
+// NewChannelMessage validates the recipient for the channel: a phone number for sms and whatsapp, an
+// email address for email and a device token for push. Only email messages carry a subject, and only
+// SMS content is split into segments.
+func NewChannelMessage(channel Channel, to, subject, content string, rules MessageRules) (*Message, error) {
+	recipient, err := channelRecipient(channel, to, rules.DefaultRegion)
+	if err != nil {
+		return nil, err
+	}
+
+	if subject != "" && channel != ChannelEmail {
+		return nil, ErrSubjectNotSupported
+	}
+
+	msg := &Message{
+		Channel:   channel,
+		To:        recipient,
+		Subject:   subject,
+		Priority:  PriorityNormal,
+		Status:    StatusPending,
+		CreatedAt: time.Now(),
+	}
+
+	if channel != ChannelSMS {
+		if content == "" {
+			return nil, valueobject.ErrEmptyContent
+		}
+		msg.Content = content
+		return msg, nil
+	}
+
+	messageContent, err := valueobject.NewMessageContent(content, rules.MaxSegments)
+	if err != nil {
+		return nil, err
+	}
+	msg.Content = messageContent.String()
+	msg.Encoding = messageContent.Encoding()
+	msg.Segments = messageContent.Segments()
+
+	return msg, nil
+}
+
+func channelRecipient(channel Channel, to, defaultRegion string) (string, error) {
+	switch channel {
+	case ChannelSMS, ChannelWhatsApp:
+		phoneNumber, err := valueobject.NewPhoneNumber(to, defaultRegion)
+		if err != nil {
+			return "", err
+		}
+		return phoneNumber.String(), nil
+	case ChannelEmail:
+		address, err := valueobject.NewEmailAddress(to)
+		if err != nil {
+			return "", err
+		}
+		return address.String(), nil
+	case ChannelPush:
+		token, err := valueobject.NewDeviceToken(to)
+		if err != nil {
+			return "", err
+		}
+		return token.String(), nil
+	}
+
+	return "", fmt.Errorf("%w: %q", ErrInvalidChannel, channel)
 }
@@ -1,8 +1,12 @@
 package domain
 
 import (
+	"errors"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/ercancavusoglu/messaging/internal/domain/valueobject"
 )
 
 func TestMessageStatus_Constants(t *testing.T) {
@@ -26,6 +30,11 @@ func TestMessageStatus_Constants(t *testing.T) {
 			status:   StatusFailed,
 			expected: "failed",
 		},
+		{
+			name:     "Suppressed status",
+			status:   StatusSuppressed,
+			expected: "suppressed",
+		},
 	}
 
 	for _, tt := range tests {
@@ -82,3 +91,86 @@ func TestMessage_Creation(t *testing.T) {
 		t.Error("Expected SentAt to be nil")
 	}
 }
+
+func TestNewMessage(t *testing.T) {
+	msg, err := NewMessage("0555 123 45 67", "Siparişiniz kargoya verildi", DefaultMessageRules())
+	if err != nil {
+		t.Fatalf("NewMessage() unexpected error = %v", err)
+	}
+
+	if msg.To != "+905551234567" {
+		t.Errorf("Expected To to be normalized to +905551234567, got %s", msg.To)
+	}
+
+	if msg.Encoding != valueobject.EncodingUCS2 {
+		t.Errorf("Expected Encoding to be ucs2, got %s", msg.Encoding)
+	}
+
+	if msg.Segments != 1 {
+		t.Errorf("Expected Segments to be 1, got %d", msg.Segments)
+	}
+
+	if msg.Status != StatusPending {
+		t.Errorf("Expected Status to be pending, got %s", msg.Status)
+	}
+}
+
+func TestNewMessage_Rules(t *testing.T) {
+	rules := MessageRules{DefaultRegion: "GB", MaxSegments: 1}
+
+	msg, err := NewMessage("07911 123456", "Hello", rules)
+	if err != nil {
+		t.Fatalf("NewMessage() unexpected error = %v", err)
+	}
+	if msg.To != "+447911123456" {
+		t.Errorf("Expected To to be parsed in the GB format, got %s", msg.To)
+	}
+
+	if _, err := NewMessage("+905551234567", strings.Repeat("a", 161), rules); err != valueobject.ErrContentTooLong {
+		t.Errorf("Expected ErrContentTooLong, got %v", err)
+	}
+}
+
+func TestNewMessage_InvalidInput(t *testing.T) {
+	if _, err := NewMessage("+90555", "Hello", DefaultMessageRules()); err != valueobject.ErrInvalidPhoneNumberLength {
+		t.Errorf("Expected ErrInvalidPhoneNumberLength, got %v", err)
+	}
+
+	if _, err := NewMessage("+905551234567", "", DefaultMessageRules()); err != valueobject.ErrEmptyContent {
+		t.Errorf("Expected ErrEmptyContent, got %v", err)
+	}
+}
+
+func TestNewChannelMessage(t *testing.T) {
+	msg, err := NewChannelMessage(ChannelEmail, "Jane@Example.com", "Your order", "Siparişiniz kargoya verildi", DefaultMessageRules())
+	if err != nil {
+		t.Fatalf("NewChannelMessage() unexpected error = %v", err)
+	}
+	if msg.Channel != ChannelEmail || msg.To != "Jane@example.com" || msg.Subject != "Your order" {
+		t.Errorf("Unexpected email message %+v", msg)
+	}
+	if msg.Encoding != "" || msg.Segments != 0 {
+		t.Errorf("Expected no SMS encoding for email, got %s/%d", msg.Encoding, msg.Segments)
+	}
+
+	msg, err = NewChannelMessage(ChannelWhatsApp, "0555 123 45 67", "", "Merhaba", DefaultMessageRules())
+	if err != nil {
+		t.Fatalf("NewChannelMessage() unexpected error = %v", err)
+	}
+	if msg.To != "+905551234567" {
+		t.Errorf("Expected WhatsApp recipient to be normalized to +905551234567, got %s", msg.To)
+	}
+
+	if _, err := NewChannelMessage(ChannelEmail, "+905551234567", "", "Hello", DefaultMessageRules()); err != valueobject.ErrInvalidEmailAddress {
+		t.Errorf("Expected ErrInvalidEmailAddress, got %v", err)
+	}
+	if _, err := NewChannelMessage(ChannelPush, "token", "Hi", "Hello", DefaultMessageRules()); err != ErrSubjectNotSupported {
+		t.Errorf("Expected ErrSubjectNotSupported, got %v", err)
+	}
+	if _, err := NewChannelMessage(ChannelPush, "token", "", "", DefaultMessageRules()); err != valueobject.ErrEmptyContent {
+		t.Errorf("Expected ErrEmptyContent, got %v", err)
+	}
+	if _, err := NewChannelMessage("fax", "+905551234567", "", "Hello", DefaultMessageRules()); !errors.Is(err, ErrInvalidChannel) {
+		t.Errorf("Expected ErrInvalidChannel, got %v", err)
+	}
+}
@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrNotMessageUpdate is returned for events that do not report a message status change
+var ErrNotMessageUpdate = errors.New("event is not a message status change")
+
+// updateStatuses is the status each message event moves its message to
+var updateStatuses = map[string]MessageStatus{
+	EventMessageQueued: StatusQueued,
+	EventMessageSent:   StatusSent,
+	EventMessageFailed: StatusFailed,
+}
+
+// MessageUpdate is a message status change as it is shown outside the service, in status callbacks and
+// the live stream: the message as it is after the event
+type MessageUpdate struct {
+	Event      string    `json:"event"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Message    *Message  `json:"message,omitempty"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// NewMessageUpdate decodes a message.queued, message.sent or message.failed envelope. Events carry the
+// message as it was before the change they report, so the status, and the provider and its message ID
+// of a sent message, are filled in from the event.
+func NewMessageUpdate(envelope *EventEnvelope) (*MessageUpdate, error) {
+	status, ok := updateStatuses[envelope.Name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNotMessageUpdate, envelope.Name)
+	}
+
+	var data struct {
+		Message   *Message `json:"message"`
+		MessageID string   `json:"message_id"`
+		Provider  string   `json:"provider"`
+		Error     string   `json:"error"`
+	}
+	if err := json.Unmarshal(envelope.Data, &data); err != nil {
+		return nil, fmt.Errorf("failed to decode %s event: %v", envelope.Name, err)
+	}
+	if data.Message == nil {
+		return nil, fmt.Errorf("%s event has no message", envelope.Name)
+	}
+
+	msg := data.Message
+	msg.Status = status
+	msg.Fallback = nil
+	if data.MessageID != "" {
+		msg.MessageID = data.MessageID
+	}
+	if data.Provider != "" {
+		msg.Provider = data.Provider
+	}
+
+	return &MessageUpdate{
+		Event:      envelope.Name,
+		OccurredAt: envelope.OccurredOn,
+		Message:    msg,
+		Error:      data.Error,
+	}, nil
+}
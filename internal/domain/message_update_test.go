@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestNewMessageUpdate(t *testing.T) {
+	msg := &Message{ID: 11, TenantID: 7, Status: StatusQueued, Fallback: []FallbackStep{{Channel: ChannelSMS, WaitSeconds: 30}}}
+	event := NewMessageSentEvent(msg, "prov-1", "client_one")
+	data, _ := json.Marshal(&event)
+
+	update, err := NewMessageUpdate(&EventEnvelope{Name: EventMessageSent, OccurredOn: event.OccurredAt(), Data: data})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if update.Event != EventMessageSent || !update.OccurredAt.Equal(event.OccurredAt()) {
+		t.Errorf("Expected the event's name and time, got %+v", update)
+	}
+	if update.Message.Status != StatusSent || update.Message.MessageID != "prov-1" || update.Message.Provider != "client_one" {
+		t.Errorf("Expected the message as sent by client_one, got %+v", update.Message)
+	}
+	if update.Message.Fallback != nil {
+		t.Errorf("Expected the fallback chain to be left out, got %+v", update.Message.Fallback)
+	}
+
+	failed := NewMessageFailedEvent(msg, ErrRecipientSuppressed)
+	data, _ = json.Marshal(&failed)
+	update, _ = NewMessageUpdate(&EventEnvelope{Name: EventMessageFailed, Data: data})
+	if update.Message.Status != StatusFailed || update.Error != ErrRecipientSuppressed.Error() {
+		t.Errorf("Expected a failed update with the error, got %+v", update)
+	}
+
+	if _, err := NewMessageUpdate(&EventEnvelope{Name: EventMessageInbound, Data: data}); !errors.Is(err, ErrNotMessageUpdate) {
+		t.Errorf("Expected ErrNotMessageUpdate, got %v", err)
+	}
+}
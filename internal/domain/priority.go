@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type Priority string
+
+const (
+	PriorityCritical Priority = "critical"
+	PriorityNormal   Priority = "normal"
+	PriorityBulk     Priority = "bulk"
+)
+
+var ErrInvalidPriority = errors.New("priority must be one of critical, normal or bulk")
+
+// Priorities lists the classes from the most to the least urgent; higher classes are drained first
+var Priorities = []Priority{PriorityCritical, PriorityNormal, PriorityBulk}
+
+// ParsePriority accepts a class name case-insensitively; an empty value means normal
+func ParsePriority(value string) (Priority, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" {
+		return PriorityNormal, nil
+	}
+
+	for _, priority := range Priorities {
+		if string(priority) == value {
+			return priority, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: %q", ErrInvalidPriority, value)
+}
+
+// OrDefault returns normal for messages stored or queued before priorities existed
+func (p Priority) OrDefault() Priority {
+	if p == "" {
+		return PriorityNormal
+	}
+	return p
+}
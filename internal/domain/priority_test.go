@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParsePriority(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected Priority
+		wantErr  bool
+	}{
+		{value: "critical", expected: PriorityCritical},
+		{value: " BULK ", expected: PriorityBulk},
+		{value: "", expected: PriorityNormal},
+		{value: "urgent", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			priority, err := ParsePriority(tt.value)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidPriority) {
+					t.Errorf("Expected ErrInvalidPriority, got %v", err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ParsePriority() unexpected error = %v", err)
+			}
+			if priority != tt.expected {
+				t.Errorf("ParsePriority() = %s, want %s", priority, tt.expected)
+			}
+		})
+	}
+}
+
+func TestPriority_OrDefault(t *testing.T) {
+	if Priority("").OrDefault() != PriorityNormal {
+		t.Error("Expected empty priority to default to normal")
+	}
+
+	if PriorityBulk.OrDefault() != PriorityBulk {
+		t.Error("Expected bulk priority to be kept")
+	}
+}
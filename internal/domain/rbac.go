@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+type Scope string
+
+const (
+	ScopeMessagesRead      Scope = "messages:read"
+	ScopeMessagesWrite     Scope = "messages:write"
+	ScopeTemplatesWrite    Scope = "templates:write"
+	ScopeSuppressionsWrite Scope = "suppressions:write"
+	ScopeCampaignsWrite    Scope = "campaigns:write"
+	ScopeCallbacksWrite    Scope = "callbacks:write"
+	ScopeSchedulerAdmin    Scope = "scheduler:admin"
+	ScopeAuditRead         Scope = "audit:read"
+	ScopeTenantsAdmin      Scope = "tenants:admin"
+)
+
+// AdminActor identifies requests authenticated with the operator key
+const AdminActor = "admin"
+
+var ErrInvalidScope = errors.New("invalid scope")
+
+// DefaultTenantScopes are granted to tenants created without explicit scopes
+var DefaultTenantScopes = []Scope{
+	ScopeMessagesRead,
+	ScopeMessagesWrite,
+	ScopeTemplatesWrite,
+	ScopeSuppressionsWrite,
+	ScopeCampaignsWrite,
+	ScopeCallbacksWrite,
+}
+
+// TenantGrantableScopes only act on the tenant's own data
+var TenantGrantableScopes = []Scope{
+	ScopeMessagesRead,
+	ScopeMessagesWrite,
+	ScopeTemplatesWrite,
+	ScopeSuppressionsWrite,
+	ScopeCampaignsWrite,
+	ScopeCallbacksWrite,
+}
+
+// OperatorScopes act on every tenant at once, e.g. stopping delivery or reading everyone's audit log,
+// so only the operator key carries them
+var OperatorScopes = []Scope{
+	ScopeSchedulerAdmin,
+	ScopeAuditRead,
+	ScopeTenantsAdmin,
+}
+
+// Principal is the authenticated caller of a request; Tenant is nil for the operator
+type Principal struct {
+	Actor  string
+	Tenant *Tenant
+	Scopes []Scope
+}
+
+func AdminPrincipal() *Principal {
+	scopes := append(append([]Scope{}, TenantGrantableScopes...), OperatorScopes...)
+	return &Principal{Actor: AdminActor, Scopes: scopes}
+}
+
+func TenantPrincipal(tenant *Tenant) *Principal {
+	return &Principal{
+		Actor:  fmt.Sprintf("tenant:%d", tenant.ID),
+		Tenant: tenant,
+		Scopes: tenant.Scopes,
+	}
+}
+
+func (p *Principal) HasScope(scope Scope) bool {
+	for _, granted := range p.Scopes {
+		if granted == scope {
+			return true
+		}
+	}
+	return false
+}
+
+func validateTenantScopes(scopes []Scope) error {
+	grantable := &Principal{Scopes: TenantGrantableScopes}
+	for _, scope := range scopes {
+		if !grantable.HasScope(scope) {
+			return fmt.Errorf("%w: %q", ErrInvalidScope, scope)
+		}
+	}
+	return nil
+}
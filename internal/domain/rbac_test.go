@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPrincipal_HasScope(t *testing.T) {
+	tenant := &Tenant{ID: 7, Name: "payments", Scopes: []Scope{ScopeMessagesRead}}
+	principal := TenantPrincipal(tenant)
+
+	if principal.Actor != "tenant:7" {
+		t.Errorf("Expected actor tenant:7, got %s", principal.Actor)
+	}
+
+	if !principal.HasScope(ScopeMessagesRead) || principal.HasScope(ScopeSchedulerAdmin) {
+		t.Errorf("Unexpected scopes for tenant principal: %v", principal.Scopes)
+	}
+
+	admin := AdminPrincipal()
+	for _, scope := range append(append([]Scope{}, TenantGrantableScopes...), OperatorScopes...) {
+		if !admin.HasScope(scope) {
+			t.Errorf("Expected admin to have scope %s", scope)
+		}
+	}
+}
+
+func TestTenant_Validate_Scopes(t *testing.T) {
+	tenant := &Tenant{Name: "payments"}
+	if err := tenant.Validate(); err != nil {
+		t.Fatalf("Validate() unexpected error = %v", err)
+	}
+
+	if len(tenant.Scopes) != len(DefaultTenantScopes) {
+		t.Errorf("Expected default scopes, got %v", tenant.Scopes)
+	}
+
+	// Tüm kiracıları etkileyen yetkiler yalnızca operatör anahtarında olmalı
+	for _, scope := range OperatorScopes {
+		tenant.Scopes = []Scope{scope}
+		if err := tenant.Validate(); !errors.Is(err, ErrInvalidScope) {
+			t.Errorf("Expected ErrInvalidScope for %s, got %v", scope, err)
+		}
+	}
+}
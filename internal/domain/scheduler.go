@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	MinSchedulerInterval = time.Second
+	MaxSchedulerInterval = time.Hour
+)
+
+var (
+	ErrSchedulerAlreadyRunning  = errors.New("scheduler is already running")
+	ErrSchedulerNotRunning      = errors.New("scheduler is not running")
+	ErrInvalidSchedulerInterval = fmt.Errorf("scheduler interval must be between %s and %s", MinSchedulerInterval, MaxSchedulerInterval)
+)
+
+// SchedulerStatus describes the scheduler ticker and what it has published so far
+type SchedulerStatus struct {
+	Running           bool          `json:"running"`
+	Leader            string        `json:"leader,omitempty"`
+	Interval          time.Duration `json:"-"`
+	LastTickAt        *time.Time    `json:"last_tick_at"`
+	LastTickDuration  time.Duration `json:"-"`
+	LastTickPublished int           `json:"last_tick_published"`
+	LastTickFailed    int           `json:"last_tick_failed"`
+	TotalTicks        int64         `json:"total_ticks"`
+	TotalPublished    int64         `json:"total_published"`
+	TotalFailed       int64         `json:"total_failed"`
+	LastError         string        `json:"last_error,omitempty"`
+	LastErrorAt       *time.Time    `json:"last_error_at,omitempty"`
+	NextTickAt        *time.Time    `json:"next_tick_at"`
+}
+
+// schedulerStatusJSON writes durations as Go duration strings such as "5s"
+type schedulerStatusJSON struct {
+	schedulerStatusAlias
+	Interval         string `json:"interval"`
+	LastTickDuration string `json:"last_tick_duration"`
+}
+
+type schedulerStatusAlias SchedulerStatus
+
+func (s SchedulerStatus) MarshalJSON() ([]byte, error) {
+	return json.Marshal(schedulerStatusJSON{
+		schedulerStatusAlias: schedulerStatusAlias(s),
+		Interval:             s.Interval.String(),
+		LastTickDuration:     s.LastTickDuration.String(),
+	})
+}
+
+func (s *SchedulerStatus) UnmarshalJSON(data []byte) error {
+	var value schedulerStatusJSON
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	*s = SchedulerStatus(value.schedulerStatusAlias)
+	for target, text := range map[*time.Duration]string{&s.Interval: value.Interval, &s.LastTickDuration: value.LastTickDuration} {
+		if text == "" {
+			continue
+		}
+		duration, err := time.ParseDuration(text)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q: %v", text, err)
+		}
+		*target = duration
+	}
+
+	return nil
+}
+
+func ValidateSchedulerInterval(interval time.Duration) error {
+	if interval < MinSchedulerInterval || interval > MaxSchedulerInterval {
+		return ErrInvalidSchedulerInterval
+	}
+	return nil
+}
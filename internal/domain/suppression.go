@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	SuppressionSourceManual  = "manual"
+	SuppressionSourceImport  = "import"
+	SuppressionSourceInbound = "inbound"
+)
+
+var (
+	ErrSuppressionNotFound = errors.New("suppression not found")
+	ErrRecipientSuppressed = errors.New("recipient is suppressed")
+)
+
+// Suppression is an opted-out recipient that must not receive any message of its tenant, or of any
+// tenant when it has none
+type Suppression struct {
+	ID        int64     `json:"id"`
+	TenantID  int64     `json:"tenant_id,omitempty"`
+	Recipient string    `json:"recipient"`
+	Reason    string    `json:"reason"`
+	Source    string    `json:"source"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,114 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+var (
+	ErrTemplateNotFound       = errors.New("template not found")
+	ErrTemplateNameRequired   = errors.New("template name is required")
+	ErrTemplateLocaleNotFound = errors.New("template has no variant for the requested locale")
+	ErrTemplateVariantEmpty   = errors.New("template variant cannot be empty")
+)
+
+var placeholderPattern = regexp.MustCompile(`\{\{\s*([a-zA-Z_][a-zA-Z0-9_]*)\s*\}\}`)
+
+type MissingVariablesError struct {
+	Variables []string
+}
+
+func (e *MissingVariablesError) Error() string {
+	return fmt.Sprintf("missing template variables: %s", strings.Join(e.Variables, ", "))
+}
+
+// Template is a reusable message body with {{name}} placeholders and one variant per locale
+type Template struct {
+	ID            int64             `json:"id"`
+	TenantID      int64             `json:"tenant_id,omitempty"`
+	Name          string            `json:"name"`
+	DefaultLocale string            `json:"default_locale"`
+	Variants      map[string]string `json:"variants"`
+	CreatedAt     time.Time         `json:"created_at"`
+	UpdatedAt     time.Time         `json:"updated_at"`
+}
+
+// Validate normalizes locale keys and checks that the default locale has a variant
+func (t *Template) Validate() error {
+	t.Name = strings.TrimSpace(t.Name)
+	if t.Name == "" {
+		return ErrTemplateNameRequired
+	}
+
+	variants := make(map[string]string, len(t.Variants))
+	for locale, body := range t.Variants {
+		if strings.TrimSpace(body) == "" {
+			return fmt.Errorf("%w: %q", ErrTemplateVariantEmpty, locale)
+		}
+		variants[NormalizeLocale(locale)] = body
+	}
+	t.Variants = variants
+	t.DefaultLocale = NormalizeLocale(t.DefaultLocale)
+
+	if _, ok := t.Variants[t.DefaultLocale]; !ok {
+		return fmt.Errorf("%w: default locale %q", ErrTemplateLocaleNotFound, t.DefaultLocale)
+	}
+
+	return nil
+}
+
+// Variant resolves the body for a locale, falling back from "tr-tr" to "tr" and then to the default locale
+func (t *Template) Variant(locale string) (string, string, error) {
+	locale = NormalizeLocale(locale)
+	candidates := []string{locale}
+	if language, _, found := strings.Cut(locale, "-"); found {
+		candidates = append(candidates, language)
+	}
+	candidates = append(candidates, t.DefaultLocale)
+
+	for _, candidate := range candidates {
+		if body, ok := t.Variants[candidate]; ok && candidate != "" {
+			return candidate, body, nil
+		}
+	}
+
+	return "", "", fmt.Errorf("%w: %q", ErrTemplateLocaleNotFound, locale)
+}
+
+// Render substitutes every placeholder of the resolved variant and reports all missing variables at once
+func (t *Template) Render(locale string, variables map[string]string) (string, error) {
+	_, body, err := t.Variant(locale)
+	if err != nil {
+		return "", err
+	}
+
+	missing := make(map[string]bool)
+	rendered := placeholderPattern.ReplaceAllStringFunc(body, func(placeholder string) string {
+		name := placeholderPattern.FindStringSubmatch(placeholder)[1]
+		value, ok := variables[name]
+		if !ok {
+			missing[name] = true
+		}
+		return value
+	})
+
+	if len(missing) > 0 {
+		names := make([]string, 0, len(missing))
+		for name := range missing {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return "", &MissingVariablesError{Variables: names}
+	}
+
+	return rendered, nil
+}
+
+// NormalizeLocale lower-cases the locale and uses dashes, so "tr_TR" and "tr-TR" are equal
+func NormalizeLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+}
@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func createTestTemplate() *Template {
+	return &Template{
+		ID:            1,
+		Name:          "otp",
+		DefaultLocale: "en",
+		Variants: map[string]string{
+			"en": "Your code is {{code}}",
+			"tr": "Doğrulama kodunuz: {{ code }}",
+		},
+	}
+}
+
+func TestTemplate_Validate(t *testing.T) {
+	tmpl := &Template{
+		Name:          " shipping ",
+		DefaultLocale: "tr_TR",
+		Variants:      map[string]string{"tr-TR": "Kargonuz yolda"},
+	}
+
+	if err := tmpl.Validate(); err != nil {
+		t.Fatalf("Validate() unexpected error = %v", err)
+	}
+
+	if tmpl.Name != "shipping" {
+		t.Errorf("Expected name to be trimmed, got %q", tmpl.Name)
+	}
+
+	if _, ok := tmpl.Variants["tr-tr"]; !ok || tmpl.DefaultLocale != "tr-tr" {
+		t.Errorf("Expected locales to be normalized, got %v / %s", tmpl.Variants, tmpl.DefaultLocale)
+	}
+
+	missingDefault := &Template{Name: "otp", DefaultLocale: "de", Variants: map[string]string{"en": "Hi"}}
+	if err := missingDefault.Validate(); !errors.Is(err, ErrTemplateLocaleNotFound) {
+		t.Errorf("Expected ErrTemplateLocaleNotFound, got %v", err)
+	}
+
+	if err := (&Template{}).Validate(); err != ErrTemplateNameRequired {
+		t.Errorf("Expected ErrTemplateNameRequired, got %v", err)
+	}
+}
+
+func TestTemplate_Render(t *testing.T) {
+	tests := []struct {
+		name     string
+		locale   string
+		expected string
+	}{
+		{name: "Exact locale", locale: "tr", expected: "Doğrulama kodunuz: 123456"},
+		{name: "Falls back to language", locale: "tr_TR", expected: "Doğrulama kodunuz: 123456"},
+		{name: "Falls back to default locale", locale: "de", expected: "Your code is 123456"},
+		{name: "Empty locale uses default", locale: "", expected: "Your code is 123456"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rendered, err := createTestTemplate().Render(tt.locale, map[string]string{"code": "123456"})
+			if err != nil {
+				t.Fatalf("Render() unexpected error = %v", err)
+			}
+
+			if rendered != tt.expected {
+				t.Errorf("Render() = %q, want %q", rendered, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTemplate_Render_MissingVariables(t *testing.T) {
+	tmpl := &Template{
+		Name:          "shipping",
+		DefaultLocale: "en",
+		Variants:      map[string]string{"en": "Order {{order}} ships with {{carrier}} ({{order}})"},
+	}
+
+	_, err := tmpl.Render("en", map[string]string{})
+
+	var missingErr *MissingVariablesError
+	if !errors.As(err, &missingErr) {
+		t.Fatalf("Expected MissingVariablesError, got %v", err)
+	}
+
+	if len(missingErr.Variables) != 2 || missingErr.Variables[0] != "carrier" || missingErr.Variables[1] != "order" {
+		t.Errorf("Unexpected missing variables: %v", missingErr.Variables)
+	}
+}
@@ -0,0 +1,120 @@
+package domain
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// APIKeyPrefix marks tenant API keys so they are easy to recognize in logs and secret scanners
+const APIKeyPrefix = "msk_"
+
+var (
+	ErrTenantNotFound     = errors.New("tenant not found")
+	ErrTenantNameRequired = errors.New("tenant name is required")
+	ErrInvalidAPIKey      = errors.New("invalid api key")
+	ErrQuotaExceeded      = errors.New("daily message quota exceeded")
+	ErrInvalidTenantQuota = errors.New("daily quota cannot be negative")
+	ErrInvalidCredential  = errors.New("provider credential requires a url")
+)
+
+// RedactedToken replaces provider tokens in API responses
+const RedactedToken = "********"
+
+// ProviderCredential overrides the shared webhook endpoint and token of a provider for a single tenant
+type ProviderCredential struct {
+	URL   string `json:"url"`
+	Token string `json:"token"`
+}
+
+// Tenant is an account whose messages are isolated from every other tenant.
+// Only the SHA-256 hash of the API key is stored.
+type Tenant struct {
+	ID                  int64                         `json:"id"`
+	Name                string                        `json:"name"`
+	APIKeyHash          string                        `json:"-"`
+	DailyQuota          int                           `json:"daily_quota"`
+	ProviderCredentials map[string]ProviderCredential `json:"provider_credentials,omitempty"`
+	Scopes              []Scope                       `json:"scopes"`
+	Active              bool                          `json:"active"`
+	CreatedAt           time.Time                     `json:"created_at"`
+}
+
+func (t *Tenant) Validate() error {
+	t.Name = strings.TrimSpace(t.Name)
+	if t.Name == "" {
+		return ErrTenantNameRequired
+	}
+
+	if t.DailyQuota < 0 {
+		return fmt.Errorf("%w: %d", ErrInvalidTenantQuota, t.DailyQuota)
+	}
+
+	for provider, credential := range t.ProviderCredentials {
+		if credential.URL == "" {
+			return fmt.Errorf("%w: %q", ErrInvalidCredential, provider)
+		}
+	}
+
+	if t.Scopes == nil {
+		t.Scopes = append([]Scope(nil), DefaultTenantScopes...)
+	}
+
+	return validateTenantScopes(t.Scopes)
+}
+
+// HasQuota reports whether the tenant is limited to DailyQuota messages per day; zero means unlimited
+func (t *Tenant) HasQuota() bool {
+	return t.DailyQuota > 0
+}
+
+// QuotaDayStart returns the start of the UTC day the daily quota of now is counted from
+func QuotaDayStart(now time.Time) time.Time {
+	now = now.UTC()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// Redacted returns a copy that is safe to return from the API, with provider tokens masked
+func (t *Tenant) Redacted() *Tenant {
+	redacted := *t
+	if len(t.ProviderCredentials) > 0 {
+		redacted.ProviderCredentials = make(map[string]ProviderCredential, len(t.ProviderCredentials))
+		for provider, credential := range t.ProviderCredentials {
+			if credential.Token != "" {
+				credential.Token = RedactedToken
+			}
+			redacted.ProviderCredentials[provider] = credential
+		}
+	}
+	return &redacted
+}
+
+// KeepStoredTokens puts the stored token back into every credential that comes without one or with the
+// redaction mask, so a tenant read from the API can be sent back unchanged without losing its tokens
+func (t *Tenant) KeepStoredTokens(stored map[string]ProviderCredential) {
+	for provider, credential := range t.ProviderCredentials {
+		if credential.Token != "" && credential.Token != RedactedToken {
+			continue
+		}
+		credential.Token = stored[provider].Token
+		t.ProviderCredentials[provider] = credential
+	}
+}
+
+// NewAPIKey generates a random API key; the plain key is shown to the caller once and never stored
+func NewAPIKey() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate api key: %v", err)
+	}
+	return APIKeyPrefix + hex.EncodeToString(buf), nil
+}
+
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
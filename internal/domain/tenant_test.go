@@ -0,0 +1,90 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewAPIKey(t *testing.T) {
+	first, err := NewAPIKey()
+	if err != nil {
+		t.Fatalf("NewAPIKey() unexpected error = %v", err)
+	}
+
+	second, _ := NewAPIKey()
+
+	if !strings.HasPrefix(first, APIKeyPrefix) {
+		t.Errorf("Expected key to start with %s, got %s", APIKeyPrefix, first)
+	}
+
+	if first == second {
+		t.Error("Expected two generated keys to differ")
+	}
+
+	if HashAPIKey(first) != HashAPIKey(first) || HashAPIKey(first) == HashAPIKey(second) {
+		t.Error("Expected hash to be deterministic and unique per key")
+	}
+}
+
+func TestTenant_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		tenant  Tenant
+		wantErr bool
+	}{
+		{name: "Valid tenant", tenant: Tenant{Name: "payments", DailyQuota: 1000}},
+		{name: "Empty name", tenant: Tenant{Name: "  "}, wantErr: true},
+		{name: "Negative quota", tenant: Tenant{Name: "payments", DailyQuota: -1}, wantErr: true},
+		{
+			name:    "Credential without url",
+			tenant:  Tenant{Name: "payments", ProviderCredentials: map[string]ProviderCredential{"client_one": {Token: "secret"}}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.tenant.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTenant_Redacted(t *testing.T) {
+	tenant := &Tenant{
+		Name:                "payments",
+		ProviderCredentials: map[string]ProviderCredential{"client_one": {URL: "https://sms.example.com", Token: "secret"}},
+	}
+
+	redacted := tenant.Redacted()
+
+	if redacted.ProviderCredentials["client_one"].Token == "secret" {
+		t.Error("Expected provider token to be masked")
+	}
+
+	if tenant.ProviderCredentials["client_one"].Token != "secret" {
+		t.Error("Expected original tenant to be left untouched")
+	}
+}
+
+func TestTenant_KeepStoredTokens(t *testing.T) {
+	stored := map[string]ProviderCredential{
+		"client_one": {URL: "https://sms.example.com", Token: "secret-one"},
+		"client_two": {URL: "https://sms.example.org", Token: "secret-two"},
+	}
+	tenant := &Tenant{ProviderCredentials: map[string]ProviderCredential{
+		"client_one": {URL: "https://sms.example.com", Token: RedactedToken},
+		"client_two": {URL: "https://sms.example.org"},
+		"client_new": {URL: "https://sms.example.net", Token: "secret-new"},
+	}}
+
+	tenant.KeepStoredTokens(stored)
+
+	for provider, want := range map[string]string{"client_one": "secret-one", "client_two": "secret-two", "client_new": "secret-new"} {
+		if got := tenant.ProviderCredentials[provider].Token; got != want {
+			t.Errorf("Expected %s token %q, got %q", provider, want, got)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package valueobject
+
+import (
+	"errors"
+	"strings"
+)
+
+// deviceTokenMaxLength leaves room for the longest push tokens issued by FCM and APNs
+const deviceTokenMaxLength = 4096
+
+var ErrInvalidDeviceToken = errors.New("device token must be 1 to 4096 printable ASCII characters without spaces")
+
+// DeviceToken addresses a push notification to one app installation
+type DeviceToken struct {
+	value string
+}
+
+func NewDeviceToken(token string) (*DeviceToken, error) {
+	token = strings.TrimSpace(token)
+	if token == "" || len(token) > deviceTokenMaxLength {
+		return nil, ErrInvalidDeviceToken
+	}
+
+	for i := 0; i < len(token); i++ {
+		if token[i] <= ' ' || token[i] > '~' {
+			return nil, ErrInvalidDeviceToken
+		}
+	}
+
+	return &DeviceToken{value: token}, nil
+}
+
+func (d *DeviceToken) String() string {
+	return d.value
+}
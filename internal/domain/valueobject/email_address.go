@@ -0,0 +1,35 @@
+package valueobject
+
+import (
+	"errors"
+	"net/mail"
+	"strings"
+)
+
+var ErrInvalidEmailAddress = errors.New("invalid email address")
+
+type EmailAddress struct {
+	value string
+}
+
+// NewEmailAddress accepts a bare RFC 5322 address and lowercases its domain, which is case-insensitive.
+// Display names such as "Jane <jane@example.com>" are rejected, since the recipient is only the address.
+func NewEmailAddress(address string) (*EmailAddress, error) {
+	address = strings.TrimSpace(address)
+
+	parsed, err := mail.ParseAddress(address)
+	if err != nil || parsed.Name != "" || parsed.Address != address {
+		return nil, ErrInvalidEmailAddress
+	}
+
+	at := strings.LastIndexByte(address, '@')
+	if at < 1 || !strings.Contains(address[at+1:], ".") {
+		return nil, ErrInvalidEmailAddress
+	}
+
+	return &EmailAddress{value: address[:at] + "@" + strings.ToLower(address[at+1:])}, nil
+}
+
+func (e *EmailAddress) String() string {
+	return e.value
+}
@@ -0,0 +1,58 @@
+package valueobject
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewEmailAddress(t *testing.T) {
+	tests := []struct {
+		name    string
+		address string
+		want    string
+		wantErr error
+	}{
+		{name: "Plain address", address: "jane@example.com", want: "jane@example.com"},
+		{name: "Domain is lowercased", address: " Jane.Doe@Example.COM ", want: "Jane.Doe@example.com"},
+		{name: "Plus addressing", address: "jane+news@example.com.tr", want: "jane+news@example.com.tr"},
+		{name: "Display name", address: "Jane <jane@example.com>", wantErr: ErrInvalidEmailAddress},
+		{name: "Missing domain", address: "jane@", wantErr: ErrInvalidEmailAddress},
+		{name: "Dotless domain", address: "jane@localhost", wantErr: ErrInvalidEmailAddress},
+		{name: "Phone number", address: "+905551234567", wantErr: ErrInvalidEmailAddress},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewEmailAddress(tt.address)
+			if err != tt.wantErr {
+				t.Fatalf("NewEmailAddress(%q) error = %v, want %v", tt.address, err, tt.wantErr)
+			}
+			if err == nil && got.String() != tt.want {
+				t.Errorf("NewEmailAddress(%q) = %q, want %q", tt.address, got.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestNewDeviceToken(t *testing.T) {
+	tests := []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{
+		{name: "FCM token", token: "dQw4w9WgXcQ:APA91bH-example_token"},
+		{name: "Empty", token: "  ", wantErr: true},
+		{name: "Inner space", token: "abc def", wantErr: true},
+		{name: "Non ASCII", token: "tökén", wantErr: true},
+		{name: "Too long", token: strings.Repeat("a", 4097), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewDeviceToken(tt.token)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewDeviceToken(%q) error = %v, wantErr %v", tt.token, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -2,33 +2,223 @@ package valueobject
 
 import (
 	"errors"
-	"fmt"
+	"unicode/utf16"
 )
 
-type MessageContent struct {
-	value string
-}
+type Encoding string
+
+const (
+	EncodingGSM7 Encoding = "gsm7"
+	EncodingUCS2 Encoding = "ucs2"
+)
+
+// Segment capacities in encoding units (septets for GSM-7, UTF-16 code units for UCS-2).
+// Concatenated messages lose room to the 6 byte user data header.
+const (
+	gsm7SingleSegment = 160
+	gsm7MultiSegment  = 153
+	ucs2SingleSegment = 70
+	ucs2MultiSegment  = 67
+
+	// maxBMPRune is the last rune encoded as a single UTF-16 code unit; anything above needs a surrogate pair
+	maxBMPRune = 0xFFFF
+)
+
+const (
+	// gsm7Alphabet is the GSM 03.38 default alphabet, excluding the escape character
+	gsm7Alphabet = "@£$¥èéùìòÇ\nØø\rÅåΔ_ΦΓΛΩΠΨΣΘΞÆæßÉ !\"#¤%&'()*+,-./0123456789:;<=>?" +
+		"¡ABCDEFGHIJKLMNOPQRSTUVWXYZÄÖÑÜ§¿abcdefghijklmnopqrstuvwxyzäöñüà"
+	// gsm7Extension holds characters reachable through the escape character, costing two septets each
+	gsm7Extension = "\f^{}\\[~]|€"
+	gsm7Escape    = 0x1B
+)
+
+// gsm7ExtensionCodes are the septets following the escape character, in gsm7Extension order
+var gsm7ExtensionCodes = []byte{0x0A, 0x14, 0x28, 0x29, 0x2F, 0x3C, 0x3D, 0x3E, 0x40, 0x65}
+
+var (
+	gsm7Basic    = runeSet(gsm7Alphabet)
+	gsm7Extended = runeSet(gsm7Extension)
+	gsm7Codes    = gsm7CodeTable()
+)
 
 var (
-	MessageContentMaxLength = 20
-	ErrEmptyContent         = errors.New("message content cannot be empty")
-	ErrContentTooLong       = fmt.Errorf("message content cannot be longer than %d characters", MessageContentMaxLength)
+	ErrEmptyContent   = errors.New("message content cannot be empty")
+	ErrContentTooLong = errors.New("message content exceeds the maximum number of segments")
 )
 
-func NewMessageContent(content string) (*MessageContent, error) {
+type MessageContent struct {
+	value    string
+	encoding Encoding
+	units    int
+	segments int
+}
+
+// NewMessageContent detects whether the content fits the GSM-7 alphabet or needs UCS-2 and
+// rejects content that would be split into more than maxSegments parts
+func NewMessageContent(content string, maxSegments int) (*MessageContent, error) {
 	if content == "" {
 		return nil, ErrEmptyContent
 	}
 
-	if len(content) >= MessageContentMaxLength {
+	mc := &MessageContent{value: content}
+	if isGSM7(content) {
+		mc.encoding = EncodingGSM7
+		mc.units, mc.segments = gsm7Segments(content)
+	} else {
+		mc.encoding = EncodingUCS2
+		mc.units, mc.segments = ucs2Segments(content)
+	}
+
+	if mc.segments > maxSegments {
 		return nil, ErrContentTooLong
 	}
 
-	return &MessageContent{
-		value: content,
-	}, nil
+	return mc, nil
 }
 
 func (mc *MessageContent) String() string {
 	return mc.value
 }
+
+func (mc *MessageContent) Encoding() Encoding {
+	return mc.encoding
+}
+
+// Units returns the encoded length: septets for GSM-7, UTF-16 code units for UCS-2
+func (mc *MessageContent) Units() int {
+	return mc.units
+}
+
+func (mc *MessageContent) Segments() int {
+	return mc.segments
+}
+
+// Parts returns the content encoded for transmission and split the way Segments counts it: unpacked
+// GSM 03.38 septets, one per byte with extension characters escaped, or big-endian UCS-2
+func (mc *MessageContent) Parts() [][]byte {
+	var chunks [][]byte
+	for _, r := range mc.value {
+		chunks = append(chunks, mc.encodeRune(r))
+	}
+
+	single, multi, unitSize := gsm7SingleSegment, gsm7MultiSegment, 1
+	if mc.encoding == EncodingUCS2 {
+		single, multi, unitSize = ucs2SingleSegment, ucs2MultiSegment, 2
+	}
+
+	if mc.units <= single {
+		var part []byte
+		for _, chunk := range chunks {
+			part = append(part, chunk...)
+		}
+		return [][]byte{part}
+	}
+
+	parts := [][]byte{nil}
+	for _, chunk := range chunks {
+		if len(parts[len(parts)-1])+len(chunk) > multi*unitSize {
+			parts = append(parts, nil)
+		}
+		parts[len(parts)-1] = append(parts[len(parts)-1], chunk...)
+	}
+	return parts
+}
+
+func (mc *MessageContent) encodeRune(r rune) []byte {
+	if mc.encoding == EncodingGSM7 {
+		if code, ok := gsm7Codes[r]; ok {
+			return []byte{code}
+		}
+		for i, extended := range []rune(gsm7Extension) {
+			if extended == r {
+				return []byte{gsm7Escape, gsm7ExtensionCodes[i]}
+			}
+		}
+	}
+
+	var encoded []byte
+	for _, unit := range utf16.Encode([]rune{r}) {
+		encoded = append(encoded, byte(unit>>8), byte(unit))
+	}
+	return encoded
+}
+
+func isGSM7(content string) bool {
+	for _, r := range content {
+		if !gsm7Basic[r] && !gsm7Extended[r] {
+			return false
+		}
+	}
+	return true
+}
+
+func gsm7Segments(content string) (int, int) {
+	var sizes []int
+	for _, r := range content {
+		size := 1
+		if gsm7Extended[r] {
+			size = 2
+		}
+		sizes = append(sizes, size)
+	}
+
+	return countSegments(sizes, gsm7SingleSegment, gsm7MultiSegment)
+}
+
+func ucs2Segments(content string) (int, int) {
+	var sizes []int
+	for _, r := range content {
+		size := 1
+		if r > maxBMPRune {
+			size = 2
+		}
+		sizes = append(sizes, size)
+	}
+
+	return countSegments(sizes, ucs2SingleSegment, ucs2MultiSegment)
+}
+
+// countSegments packs characters into segments without splitting escape sequences or surrogate pairs
+func countSegments(sizes []int, single, multi int) (int, int) {
+	total := 0
+	for _, size := range sizes {
+		total += size
+	}
+
+	if total <= single {
+		return total, 1
+	}
+
+	segments, used := 1, 0
+	for _, size := range sizes {
+		if used+size > multi {
+			segments++
+			used = 0
+		}
+		used += size
+	}
+
+	return total, segments
+}
+
+// gsm7CodeTable maps the default alphabet to septets; the alphabet skips the escape character's code
+func gsm7CodeTable() map[rune]byte {
+	codes := make(map[rune]byte)
+	for i, r := range []rune(gsm7Alphabet) {
+		code := byte(i)
+		if code >= gsm7Escape {
+			code++
+		}
+		codes[r] = code
+	}
+	return codes
+}
+
+func runeSet(chars string) map[rune]bool {
+	set := make(map[rune]bool)
+	for _, r := range chars {
+		set[r] = true
+	}
+	return set
+}
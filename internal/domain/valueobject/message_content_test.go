@@ -1,9 +1,15 @@
 package valueobject
 
 import (
+	"bytes"
+	"reflect"
+	"strings"
 	"testing"
 )
 
+// testMaxSegments is the default message.max_segments setting
+const testMaxSegments = 6
+
 func TestNewMessageContent(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -25,7 +31,7 @@ func TestNewMessageContent(t *testing.T) {
 		},
 		{
 			name:        "Content too long",
-			content:     "This is a very long message that exceeds the maximum length",
+			content:     strings.Repeat("a", gsm7MultiSegment*testMaxSegments+1),
 			wantErr:     ErrContentTooLong,
 			wantContent: "",
 		},
@@ -33,7 +39,7 @@ func TestNewMessageContent(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			mc, err := NewMessageContent(tt.content)
+			mc, err := NewMessageContent(tt.content, testMaxSegments)
 
 			if tt.wantErr != nil {
 				if err != tt.wantErr {
@@ -54,9 +60,114 @@ func TestNewMessageContent(t *testing.T) {
 	}
 }
 
+func TestMessageContent_EncodingAndSegments(t *testing.T) {
+	tests := []struct {
+		name         string
+		content      string
+		wantEncoding Encoding
+		wantUnits    int
+		wantSegments int
+	}{
+		{
+			name:         "Plain ASCII",
+			content:      "Hello World",
+			wantEncoding: EncodingGSM7,
+			wantUnits:    11,
+			wantSegments: 1,
+		},
+		{
+			name:         "GSM-7 single segment limit",
+			content:      strings.Repeat("a", 160),
+			wantEncoding: EncodingGSM7,
+			wantUnits:    160,
+			wantSegments: 1,
+		},
+		{
+			name:         "GSM-7 concatenated",
+			content:      strings.Repeat("a", 161),
+			wantEncoding: EncodingGSM7,
+			wantUnits:    161,
+			wantSegments: 2,
+		},
+		{
+			name:         "Extension table characters cost two septets",
+			content:      "Price: 10€ [promo]",
+			wantEncoding: EncodingGSM7,
+			wantUnits:    21,
+			wantSegments: 1,
+		},
+		{
+			name:         "Escape sequence is not split across segments",
+			content:      strings.Repeat("a", 152) + "€" + strings.Repeat("a", 10),
+			wantEncoding: EncodingGSM7,
+			wantUnits:    164,
+			wantSegments: 2,
+		},
+		{
+			name:         "Turkish characters in the GSM alphabet",
+			content:      "Ödeme Ücreti",
+			wantEncoding: EncodingGSM7,
+			wantUnits:    12,
+			wantSegments: 1,
+		},
+		{
+			name:         "Turkish characters switch to UCS-2",
+			content:      "Kargonuz yola çıktı, teşekkürler",
+			wantEncoding: EncodingUCS2,
+			wantUnits:    32,
+			wantSegments: 1,
+		},
+		{
+			name:         "UCS-2 concatenated",
+			content:      strings.Repeat("ğ", 71),
+			wantEncoding: EncodingUCS2,
+			wantUnits:    71,
+			wantSegments: 2,
+		},
+		{
+			name:         "Emoji needs a surrogate pair",
+			content:      "Hi 👋",
+			wantEncoding: EncodingUCS2,
+			wantUnits:    5,
+			wantSegments: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc, err := NewMessageContent(tt.content, testMaxSegments)
+			if err != nil {
+				t.Fatalf("NewMessageContent() unexpected error = %v", err)
+			}
+
+			if mc.Encoding() != tt.wantEncoding {
+				t.Errorf("MessageContent.Encoding() = %v, want %v", mc.Encoding(), tt.wantEncoding)
+			}
+
+			if mc.Units() != tt.wantUnits {
+				t.Errorf("MessageContent.Units() = %v, want %v", mc.Units(), tt.wantUnits)
+			}
+
+			if mc.Segments() != tt.wantSegments {
+				t.Errorf("MessageContent.Segments() = %v, want %v", mc.Segments(), tt.wantSegments)
+			}
+		})
+	}
+}
+
+func TestMessageContent_MaxSegmentsIsConfigurable(t *testing.T) {
+	if _, err := NewMessageContent(strings.Repeat("a", 161), 1); err != ErrContentTooLong {
+		t.Errorf("Expected ErrContentTooLong, got %v", err)
+	}
+
+	if _, err := NewMessageContent(strings.Repeat("a", 161), 2); err != nil {
+		t.Errorf("Expected content to fit into two segments, got %v", err)
+	}
+}
+
 func TestMessageContent_String(t *testing.T) {
 	content := "Test message"
-	mc, err := NewMessageContent(content)
+	mc, err := NewMessageContent(content, testMaxSegments)
 	if err != nil {
 		t.Fatalf("Failed to create MessageContent: %v", err)
 	}
@@ -65,3 +176,67 @@ func TestMessageContent_String(t *testing.T) {
 		t.Errorf("MessageContent.String() = %v, want %v", mc.String(), content)
 	}
 }
+
+func TestMessageContent_Parts(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    [][]byte
+	}{
+		{
+			name:    "GSM-7 septets with escaped extension character",
+			content: "@a€Ä",
+			want:    [][]byte{{0x00, 0x61, 0x1B, 0x65, 0x5B}},
+		},
+		{
+			name:    "character after the escape code",
+			content: "Æ ",
+			want:    [][]byte{{0x1C, 0x20}},
+		},
+		{
+			name:    "UCS-2 big-endian with surrogate pair",
+			content: "ş😀",
+			want:    [][]byte{{0x01, 0x5F, 0xD8, 0x3D, 0xDE, 0x00}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc, err := NewMessageContent(tt.content, testMaxSegments)
+			if err != nil {
+				t.Fatalf("Failed to create MessageContent: %v", err)
+			}
+
+			if got := mc.Parts(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parts() = %x, want %x", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMessageContent_PartsMatchSegments(t *testing.T) {
+	// The escape sequence at the segment boundary must move to the next part as a whole
+	content := strings.Repeat("a", 152) + "€" + strings.Repeat("b", 10)
+	mc, err := NewMessageContent(content, testMaxSegments)
+	if err != nil {
+		t.Fatalf("Failed to create MessageContent: %v", err)
+	}
+
+	parts := mc.Parts()
+	if len(parts) != mc.Segments() {
+		t.Fatalf("len(Parts()) = %d, want %d", len(parts), mc.Segments())
+	}
+	if len(parts[0]) != 152 || !bytes.HasPrefix(parts[1], []byte{0x1B, 0x65}) {
+		t.Errorf("escape sequence was split: first part has %d septets", len(parts[0]))
+	}
+
+	ucs2, err := NewMessageContent(strings.Repeat("ş", 140), testMaxSegments)
+	if err != nil {
+		t.Fatalf("Failed to create MessageContent: %v", err)
+	}
+	for _, part := range ucs2.Parts() {
+		if len(part) > 134 {
+			t.Errorf("UCS-2 part has %d bytes, want at most 134", len(part))
+		}
+	}
+}
@@ -2,25 +2,248 @@ package valueobject
 
 import (
 	"errors"
+	"strings"
+)
+
+type PhoneNumberType string
+
+const (
+	PhoneNumberTypeMobile   PhoneNumberType = "mobile"
+	PhoneNumberTypeLandline PhoneNumberType = "landline"
+	PhoneNumberTypeUnknown  PhoneNumberType = "unknown"
+)
+
+const (
+	e164MaxDigits          = 15
+	nationalNumberMinimum  = 4
+	internationalPrefix    = "00"
+	internationalPlusSign  = '+'
+	phoneNumberPunctuation = " -().\t"
 )
 
 var (
-	PhoneNumberLength           = 12
-	ErrInvalidPhoneNumberLength = errors.New("invalid phone number length")
+	ErrInvalidPhoneNumberLength     = errors.New("invalid phone number length")
+	ErrInvalidPhoneNumberCharacters = errors.New("phone number contains invalid characters")
+	ErrUnknownCallingCode           = errors.New("unknown country calling code")
+	ErrUnknownRegion                = errors.New("unknown region for national phone number")
 )
 
+// regionMetadata describes the numbering plan of a region. Regions without metadata are still
+// accepted in international format, but only the generic E.164 length limits are applied.
+type regionMetadata struct {
+	callingCode      string
+	trunkPrefix      string
+	nationalLengths  []int
+	mobilePrefixes   []string
+	landlinePrefixes []string
+}
+
+var regions = map[string]regionMetadata{
+	"TR": {callingCode: "90", trunkPrefix: "0", nationalLengths: []int{10}, mobilePrefixes: []string{"5"}, landlinePrefixes: []string{"2", "3", "4"}},
+	"US": {callingCode: "1", trunkPrefix: "1", nationalLengths: []int{10}},
+	"GB": {callingCode: "44", trunkPrefix: "0", nationalLengths: []int{9, 10}, mobilePrefixes: []string{"7"}, landlinePrefixes: []string{"1", "2"}},
+	"DE": {callingCode: "49", trunkPrefix: "0", nationalLengths: []int{6, 7, 8, 9, 10, 11}, mobilePrefixes: []string{"15", "16", "17"}, landlinePrefixes: []string{"2", "3", "4", "5", "6", "7", "8", "9"}},
+	"NL": {callingCode: "31", trunkPrefix: "0", nationalLengths: []int{9}, mobilePrefixes: []string{"6"}, landlinePrefixes: []string{"1", "2", "3", "4", "5", "7"}},
+	"FR": {callingCode: "33", trunkPrefix: "0", nationalLengths: []int{9}, mobilePrefixes: []string{"6", "7"}, landlinePrefixes: []string{"1", "2", "3", "4", "5"}},
+	"ES": {callingCode: "34", nationalLengths: []int{9}, mobilePrefixes: []string{"6", "7"}, landlinePrefixes: []string{"8", "9"}},
+	"IT": {callingCode: "39", nationalLengths: []int{6, 7, 8, 9, 10, 11}, mobilePrefixes: []string{"3"}, landlinePrefixes: []string{"0"}},
+	"RU": {callingCode: "7", trunkPrefix: "8", nationalLengths: []int{10}, mobilePrefixes: []string{"9"}, landlinePrefixes: []string{"3", "4", "8"}},
+	"AZ": {callingCode: "994", trunkPrefix: "0", nationalLengths: []int{9}, mobilePrefixes: []string{"10", "40", "50", "51", "55", "60", "70", "77", "99"}, landlinePrefixes: []string{"1", "2"}},
+	"AE": {callingCode: "971", trunkPrefix: "0", nationalLengths: []int{8, 9}, mobilePrefixes: []string{"5"}, landlinePrefixes: []string{"2", "3", "4", "6", "7", "9"}},
+	"SA": {callingCode: "966", trunkPrefix: "0", nationalLengths: []int{8, 9}, mobilePrefixes: []string{"5"}, landlinePrefixes: []string{"1"}},
+	"CY": {callingCode: "357", nationalLengths: []int{8}, mobilePrefixes: []string{"9"}, landlinePrefixes: []string{"2"}},
+}
+
+// callingCodes maps every assigned country calling code to its primary region. Calling codes are
+// prefix-free, so at most one of the 1, 2 or 3 digit prefixes of a number can match.
+var callingCodes = map[string]string{
+	"1": "US", "7": "RU", "20": "EG", "27": "ZA", "30": "GR", "31": "NL", "32": "BE", "33": "FR", "34": "ES",
+	"36": "HU", "39": "IT", "40": "RO", "41": "CH", "43": "AT", "44": "GB", "45": "DK", "46": "SE", "47": "NO",
+	"48": "PL", "49": "DE", "51": "PE", "52": "MX", "53": "CU", "54": "AR", "55": "BR", "56": "CL", "57": "CO",
+	"58": "VE", "60": "MY", "61": "AU", "62": "ID", "63": "PH", "64": "NZ", "65": "SG", "66": "TH", "81": "JP",
+	"82": "KR", "84": "VN", "86": "CN", "90": "TR", "91": "IN", "92": "PK", "93": "AF", "94": "LK", "95": "MM",
+	"98": "IR", "211": "SS", "212": "MA", "213": "DZ", "216": "TN", "218": "LY", "220": "GM", "221": "SN",
+	"222": "MR", "223": "ML", "224": "GN", "225": "CI", "226": "BF", "227": "NE", "228": "TG", "229": "BJ",
+	"230": "MU", "231": "LR", "232": "SL", "233": "GH", "234": "NG", "235": "TD", "236": "CF", "237": "CM",
+	"238": "CV", "239": "ST", "240": "GQ", "241": "GA", "242": "CG", "243": "CD", "244": "AO", "245": "GW",
+	"246": "IO", "248": "SC", "249": "SD", "250": "RW", "251": "ET", "252": "SO", "253": "DJ", "254": "KE",
+	"255": "TZ", "256": "UG", "257": "BI", "258": "MZ", "260": "ZM", "261": "MG", "262": "RE", "263": "ZW",
+	"264": "NA", "265": "MW", "266": "LS", "267": "BW", "268": "SZ", "269": "KM", "290": "SH", "291": "ER",
+	"297": "AW", "298": "FO", "299": "GL", "350": "GI", "351": "PT", "352": "LU", "353": "IE", "354": "IS",
+	"355": "AL", "356": "MT", "357": "CY", "358": "FI", "359": "BG", "370": "LT", "371": "LV", "372": "EE",
+	"373": "MD", "374": "AM", "375": "BY", "376": "AD", "377": "MC", "378": "SM", "380": "UA", "381": "RS",
+	"382": "ME", "383": "XK", "385": "HR", "386": "SI", "387": "BA", "389": "MK", "420": "CZ", "421": "SK",
+	"423": "LI", "500": "FK", "501": "BZ", "502": "GT", "503": "SV", "504": "HN", "505": "NI", "506": "CR",
+	"507": "PA", "508": "PM", "509": "HT", "590": "GP", "591": "BO", "592": "GY", "593": "EC", "594": "GF",
+	"595": "PY", "596": "MQ", "597": "SR", "598": "UY", "599": "CW", "670": "TL", "672": "NF", "673": "BN",
+	"674": "NR", "675": "PG", "676": "TO", "677": "SB", "678": "VU", "679": "FJ", "680": "PW", "681": "WF",
+	"682": "CK", "683": "NU", "685": "WS", "686": "KI", "687": "NC", "688": "TV", "689": "PF", "690": "TK",
+	"691": "FM", "692": "MH", "850": "KP", "852": "HK", "853": "MO", "855": "KH", "856": "LA", "880": "BD",
+	"886": "TW", "960": "MV", "961": "LB", "962": "JO", "963": "SY", "964": "IQ", "965": "KW", "966": "SA",
+	"967": "YE", "968": "OM", "970": "PS", "971": "AE", "972": "IL", "973": "BH", "974": "QA", "975": "BT",
+	"976": "MN", "977": "NP", "992": "TJ", "993": "TM", "994": "AZ", "995": "GE", "996": "KG", "998": "UZ",
+}
+
 type PhoneNumber struct {
-	number string
+	callingCode    string
+	nationalNumber string
+	region         string
+	numberType     PhoneNumberType
 }
 
-func NewPhoneNumber(number string) (*PhoneNumber, error) {
-	if len(number) != PhoneNumberLength {
+// NewPhoneNumber parses a number in international format ("+90 555 123 45 67", "0090...")
+// or in the national format of defaultRegion ("0555 123 45 67")
+func NewPhoneNumber(number, defaultRegion string) (*PhoneNumber, error) {
+	digits, international, err := stripPhoneNumber(number)
+	if err != nil {
+		return nil, err
+	}
+
+	if international {
+		return parseInternational(digits)
+	}
+
+	return parseNational(digits, strings.ToUpper(defaultRegion))
+}
+
+func stripPhoneNumber(number string) (string, bool, error) {
+	number = strings.TrimSpace(number)
+	international := false
+
+	if strings.HasPrefix(number, string(internationalPlusSign)) {
+		international = true
+		number = number[1:]
+	}
+
+	var digits strings.Builder
+	for _, r := range number {
+		switch {
+		case r >= '0' && r <= '9':
+			digits.WriteRune(r)
+		case strings.ContainsRune(phoneNumberPunctuation, r):
+			continue
+		default:
+			return "", false, ErrInvalidPhoneNumberCharacters
+		}
+	}
+
+	result := digits.String()
+	if !international && strings.HasPrefix(result, internationalPrefix) {
+		international = true
+		result = strings.TrimPrefix(result, internationalPrefix)
+	}
+
+	if result == "" {
+		return "", false, ErrInvalidPhoneNumberLength
+	}
+
+	return result, international, nil
+}
+
+func parseInternational(digits string) (*PhoneNumber, error) {
+	for size := 1; size <= 3 && size < len(digits); size++ {
+		region, ok := callingCodes[digits[:size]]
+		if !ok {
+			continue
+		}
+
+		return newPhoneNumber(digits[:size], digits[size:], region)
+	}
+
+	if len(digits) < 4 {
 		return nil, ErrInvalidPhoneNumberLength
 	}
 
-	return &PhoneNumber{number: number}, nil
+	return nil, ErrUnknownCallingCode
+}
+
+func parseNational(digits, region string) (*PhoneNumber, error) {
+	metadata, ok := regions[region]
+	if !ok {
+		return nil, ErrUnknownRegion
+	}
+
+	national := digits
+	if metadata.trunkPrefix != "" && strings.HasPrefix(digits, metadata.trunkPrefix) &&
+		metadata.validLength(len(digits)-len(metadata.trunkPrefix)) {
+		national = digits[len(metadata.trunkPrefix):]
+	} else if !metadata.validLength(len(digits)) && strings.HasPrefix(digits, metadata.callingCode) &&
+		metadata.validLength(len(digits)-len(metadata.callingCode)) {
+		// Numbers such as "905551234567" carry the calling code without the leading plus sign
+		national = digits[len(metadata.callingCode):]
+	}
+
+	return newPhoneNumber(metadata.callingCode, national, region)
 }
 
+func newPhoneNumber(callingCode, national, region string) (*PhoneNumber, error) {
+	metadata, known := regions[region]
+	if known && metadata.callingCode == callingCode {
+		if !metadata.validLength(len(national)) {
+			return nil, ErrInvalidPhoneNumberLength
+		}
+	} else {
+		known = false
+		if len(national) < nationalNumberMinimum || len(callingCode)+len(national) > e164MaxDigits {
+			return nil, ErrInvalidPhoneNumberLength
+		}
+	}
+
+	numberType := PhoneNumberTypeUnknown
+	if known {
+		numberType = metadata.typeOf(national)
+	}
+
+	return &PhoneNumber{
+		callingCode:    callingCode,
+		nationalNumber: national,
+		region:         region,
+		numberType:     numberType,
+	}, nil
+}
+
+func (m regionMetadata) validLength(length int) bool {
+	for _, l := range m.nationalLengths {
+		if l == length {
+			return true
+		}
+	}
+	return false
+}
+
+func (m regionMetadata) typeOf(national string) PhoneNumberType {
+	for _, prefix := range m.mobilePrefixes {
+		if strings.HasPrefix(national, prefix) {
+			return PhoneNumberTypeMobile
+		}
+	}
+	for _, prefix := range m.landlinePrefixes {
+		if strings.HasPrefix(national, prefix) {
+			return PhoneNumberTypeLandline
+		}
+	}
+	return PhoneNumberTypeUnknown
+}
+
+// String returns the number in E.164 format, e.g. "+905551234567"
 func (p PhoneNumber) String() string {
-	return p.number
+	return string(internationalPlusSign) + p.callingCode + p.nationalNumber
+}
+
+func (p PhoneNumber) CallingCode() string {
+	return p.callingCode
+}
+
+func (p PhoneNumber) NationalNumber() string {
+	return p.nationalNumber
+}
+
+// Country returns the ISO 3166-1 alpha-2 code of the region the calling code belongs to.
+// Shared calling codes such as +1 resolve to their primary region.
+func (p PhoneNumber) Country() string {
+	return p.region
+}
+
+func (p PhoneNumber) Type() PhoneNumberType {
+	return p.numberType
 }
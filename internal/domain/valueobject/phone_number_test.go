@@ -6,14 +6,94 @@ import (
 
 func TestNewPhoneNumber(t *testing.T) {
 	tests := []struct {
-		name    string
-		number  string
-		wantErr error
+		name        string
+		number      string
+		wantErr     error
+		wantE164    string
+		wantCountry string
+		wantType    PhoneNumberType
 	}{
 		{
-			name:    "Valid phone number",
+			name:        "Valid E.164 mobile number",
+			number:      "+905551234567",
+			wantE164:    "+905551234567",
+			wantCountry: "TR",
+			wantType:    PhoneNumberTypeMobile,
+		},
+		{
+			name:        "Formatted international number",
+			number:      "+90 (555) 123-45-67",
+			wantE164:    "+905551234567",
+			wantCountry: "TR",
+			wantType:    PhoneNumberTypeMobile,
+		},
+		{
+			name:        "International prefix 00",
+			number:      "00905551234567",
+			wantE164:    "+905551234567",
+			wantCountry: "TR",
+			wantType:    PhoneNumberTypeMobile,
+		},
+		{
+			name:        "National format with trunk prefix",
+			number:      "05551234567",
+			wantE164:    "+905551234567",
+			wantCountry: "TR",
+			wantType:    PhoneNumberTypeMobile,
+		},
+		{
+			name:        "National format without trunk prefix",
+			number:      "555 123 45 67",
+			wantE164:    "+905551234567",
+			wantCountry: "TR",
+			wantType:    PhoneNumberTypeMobile,
+		},
+		{
+			name:        "Calling code without plus sign",
+			number:      "905551234567",
+			wantE164:    "+905551234567",
+			wantCountry: "TR",
+			wantType:    PhoneNumberTypeMobile,
+		},
+		{
+			name:        "Turkish landline",
+			number:      "0212 123 45 67",
+			wantE164:    "+902121234567",
+			wantCountry: "TR",
+			wantType:    PhoneNumberTypeLandline,
+		},
+		{
+			name:        "United Kingdom mobile",
+			number:      "+447911123456",
+			wantE164:    "+447911123456",
+			wantCountry: "GB",
+			wantType:    PhoneNumberTypeMobile,
+		},
+		{
+			name:        "German mobile",
+			number:      "+49 151 23456789",
+			wantE164:    "+4915123456789",
+			wantCountry: "DE",
+			wantType:    PhoneNumberTypeMobile,
+		},
+		{
+			name:        "North American number",
+			number:      "+1 202 555 0143",
+			wantE164:    "+12025550143",
+			wantCountry: "US",
+			wantType:    PhoneNumberTypeUnknown,
+		},
+		{
+			name:        "Region without numbering plan metadata",
+			number:      "+6591234567",
+			wantE164:    "+6591234567",
+			wantCountry: "SG",
+			wantType:    PhoneNumberTypeUnknown,
+		},
+		{
+			name:    "Turkish number with missing digit",
 			number:  "+90555123456",
-			wantErr: nil,
+			wantErr: ErrInvalidPhoneNumberLength,
 		},
 		{
 			name:    "Invalid length - too short",
@@ -25,11 +105,26 @@ func TestNewPhoneNumber(t *testing.T) {
 			number:  "+905551234567890",
 			wantErr: ErrInvalidPhoneNumberLength,
 		},
+		{
+			name:    "Letters are rejected",
+			number:  "+90555ABC4567",
+			wantErr: ErrInvalidPhoneNumberCharacters,
+		},
+		{
+			name:    "Empty number",
+			number:  "",
+			wantErr: ErrInvalidPhoneNumberLength,
+		},
+		{
+			name:    "Unknown calling code",
+			number:  "+999123456789",
+			wantErr: ErrUnknownCallingCode,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			pn, err := NewPhoneNumber(tt.number)
+			pn, err := NewPhoneNumber(tt.number, "TR")
 
 			if tt.wantErr != nil {
 				if err != tt.wantErr {
@@ -43,16 +138,43 @@ func TestNewPhoneNumber(t *testing.T) {
 				return
 			}
 
-			if pn.String() != tt.number {
-				t.Errorf("PhoneNumber.String() = %v, want %v", pn.String(), tt.number)
+			if pn.String() != tt.wantE164 {
+				t.Errorf("PhoneNumber.String() = %v, want %v", pn.String(), tt.wantE164)
+			}
+
+			if pn.Country() != tt.wantCountry {
+				t.Errorf("PhoneNumber.Country() = %v, want %v", pn.Country(), tt.wantCountry)
+			}
+
+			if pn.Type() != tt.wantType {
+				t.Errorf("PhoneNumber.Type() = %v, want %v", pn.Type(), tt.wantType)
 			}
 		})
 	}
 }
 
+func TestNewPhoneNumber_Region(t *testing.T) {
+	pn, err := NewPhoneNumber("07911 123456", "GB")
+	if err != nil {
+		t.Fatalf("NewPhoneNumber() unexpected error = %v", err)
+	}
+
+	if pn.String() != "+447911123456" {
+		t.Errorf("PhoneNumber.String() = %v, want +447911123456", pn.String())
+	}
+
+	if pn.CallingCode() != "44" || pn.NationalNumber() != "7911123456" {
+		t.Errorf("Unexpected calling code %s / national number %s", pn.CallingCode(), pn.NationalNumber())
+	}
+
+	if _, err := NewPhoneNumber("0555 123 45 67", "XX"); err != ErrUnknownRegion {
+		t.Errorf("Expected ErrUnknownRegion, got %v", err)
+	}
+}
+
 func TestPhoneNumber_String(t *testing.T) {
-	number := "+90555123456"
-	pn, err := NewPhoneNumber(number)
+	number := "+905551234567"
+	pn, err := NewPhoneNumber(number, "TR")
 	if err != nil {
 		t.Fatalf("Failed to create PhoneNumber: %v", err)
 	}
@@ -0,0 +1,13 @@
+package ports
+
+import "github.com/ercancavusoglu/messaging/internal/domain"
+
+type AuditRepository interface {
+	Create(entry *domain.AuditEntry) error
+	List(limit int) ([]*domain.AuditEntry, error)
+}
+
+type AuditService interface {
+	Record(entry *domain.AuditEntry) error
+	List(limit int) ([]*domain.AuditEntry, error)
+}
@@ -0,0 +1,37 @@
+package ports
+
+import (
+	"time"
+
+	"github.com/ercancavusoglu/messaging/internal/domain"
+)
+
+type CallbackRepository interface {
+	Create(subscription *domain.CallbackSubscription) error
+	Get(tenantID, id int64) (*domain.CallbackSubscription, error)
+	List(tenantID int64) ([]*domain.CallbackSubscription, error)
+	Delete(tenantID, id int64) error
+	// ListForEvent returns the tenant's subscriptions that receive the event
+	ListForEvent(tenantID int64, eventName string) ([]*domain.CallbackSubscription, error)
+	// CreateDelivery stores a pending delivery; an event already recorded for the subscription and
+	// message is ignored, since events are delivered at least once
+	CreateDelivery(delivery *domain.CallbackDelivery) error
+	// ClaimDueDeliveries takes at most limit pending deliveries that are due and hides them from other
+	// claims for lease, so concurrent dispatchers never send the same attempt twice
+	ClaimDueDeliveries(limit int, lease time.Duration) ([]*domain.CallbackDelivery, error)
+	// UpdateDelivery stores the outcome of an attempt
+	UpdateDelivery(delivery *domain.CallbackDelivery) error
+	// ListDeliveries returns the latest deliveries of a subscription, newest first
+	ListDeliveries(tenantID, subscriptionID int64, limit int) ([]*domain.CallbackDelivery, error)
+}
+
+type CallbackService interface {
+	// Create generates the signing secret and returns it on the subscription
+	Create(tenantID int64, subscription *domain.CallbackSubscription) error
+	Get(tenantID, id int64) (*domain.CallbackSubscription, error)
+	List(tenantID int64) ([]*domain.CallbackSubscription, error)
+	Delete(tenantID, id int64) error
+	Deliveries(tenantID, id int64) ([]*domain.CallbackDelivery, error)
+	// Ping sends a callback.ping event to the subscription right away and returns its logged delivery
+	Ping(tenantID, id int64) (*domain.CallbackDelivery, error)
+}
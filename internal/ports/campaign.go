@@ -0,0 +1,28 @@
+package ports
+
+import "github.com/ercancavusoglu/messaging/internal/domain"
+
+type CampaignRepository interface {
+	// Create stores the campaign and its messages in one transaction, or returns domain.ErrQuotaExceeded
+	// when they would take the tenant past a dailyQuota above zero
+	Create(campaign *domain.Campaign, messages []*domain.Message, dailyQuota int) error
+	Get(tenantID, id int64) (*domain.Campaign, error)
+	List(tenantID int64) ([]*domain.Campaign, error)
+	// UpdateStatus moves the campaign to its current status if it is still in from
+	UpdateStatus(campaign *domain.Campaign, from domain.CampaignStatus) error
+	// CancelPending cancels the campaign's messages that were not claimed yet and returns how many there were
+	CancelPending(campaign *domain.Campaign) (int, error)
+	// ApplyProgress adds delta to the campaign progress once per message and event
+	ApplyProgress(campaignID, messageID int64, eventName string, delta domain.CampaignProgress) error
+}
+
+type CampaignService interface {
+	Create(tenant *domain.Tenant, campaign *domain.Campaign) error
+	Get(tenantID, id int64) (*domain.Campaign, error)
+	List(tenantID int64) ([]*domain.Campaign, error)
+	Pause(tenantID, id int64) (*domain.Campaign, error)
+	Resume(tenantID, id int64) (*domain.Campaign, error)
+	Cancel(tenantID, id int64) (*domain.Campaign, error)
+	// HandleEvent updates the progress of the campaign the event's message belongs to
+	HandleEvent(event Event) error
+}
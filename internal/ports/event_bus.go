@@ -2,6 +2,8 @@ package ports
 
 import (
 	"time"
+
+	"github.com/ercancavusoglu/messaging/internal/domain"
 )
 
 type EventHandler func(event Event) error
@@ -12,8 +14,33 @@ type Event interface {
 	GetAggregateID() string
 }
 
+// PrioritizedEvent is implemented by events delivered through a separate queue per priority class
+type PrioritizedEvent interface {
+	Event
+	EventPriority() domain.Priority
+}
+
+// DelayedEvent is implemented by events delivered to the subscribers only once their delay has passed
+type DelayedEvent interface {
+	Event
+	EventDelay() time.Duration
+}
+
+// Subscription is a handler subscribed to an event. Unsubscribe only removes this subscription, even when
+// the same handler is subscribed more than once, and may be called again.
+type Subscription interface {
+	Unsubscribe()
+}
+
 type EventBus interface {
 	Publish(event Event) error
-	Subscribe(eventName string, handler EventHandler)
-	Unsubscribe(eventName string, handler EventHandler)
+	// Subscribe delivers the named events to the handler until the returned subscription is unsubscribed.
+	// It is safe to call while events are being delivered.
+	Subscribe(eventName string, handler EventHandler) Subscription
+}
+
+// EventObserver is implemented by event buses that can hand a process a copy of every event without
+// taking it from the subscribers that process it. Observers cannot reject events.
+type EventObserver interface {
+	Observe(eventName string, handler EventHandler) error
 }
@@ -0,0 +1,11 @@
+package ports
+
+import "github.com/ercancavusoglu/messaging/internal/domain"
+
+type InboundRepository interface {
+	Save(message *domain.InboundMessage) error
+}
+
+type InboundService interface {
+	Receive(message *domain.InboundMessage) error
+}
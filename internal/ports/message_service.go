@@ -1,9 +1,19 @@
 package ports
 
-import "github.com/ercancavusoglu/messaging/internal/domain"
+import (
+	"time"
+
+	"github.com/ercancavusoglu/messaging/internal/domain"
+)
 
 type MessageService interface {
-	GetPendingMessages() ([]*domain.Message, error)
-	GetSendedMessages() ([]*domain.Message, error)
+	GetPendingMessages(limit int) ([]*domain.Message, error)
+	GetSendedMessages(tenantID int64) ([]*domain.Message, error)
+	// CreateMessage stores a pending message for the tenant, enforcing its daily quota
+	CreateMessage(tenant *domain.Tenant, msg *domain.Message) error
 	Publish(msg *domain.Message) error
+	// RequeueStale returns messages lost between their claim and the consumer to pending
+	RequeueStale(visibilityTimeout time.Duration) (int, error)
+	// CountInFlight counts the claimed messages the consumers have not settled yet
+	CountInFlight() (int, error)
 }
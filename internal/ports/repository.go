@@ -1,9 +1,37 @@
 package ports
 
-import "github.com/ercancavusoglu/messaging/internal/domain"
+import (
+	"time"
+
+	"github.com/ercancavusoglu/messaging/internal/domain"
+)
 
 type Repository interface {
-	GetPendingMessages() ([]*domain.Message, error)
+	// ForTenant returns a repository whose queries only see and create messages of the tenant
+	ForTenant(tenantID int64) Repository
+	Create(msg *domain.Message) error
+	// GetPendingMessages claims at most limit pending messages, taking tenants in turns, and marks them queued
+	GetPendingMessages(limit int) ([]*domain.Message, error)
 	UpdateStatus(id int64, status domain.MessageStatus, messageID string, provider string) error
 	GetByStatus(status domain.MessageStatus) ([]*domain.Message, error)
+	// GetByProviderMessageID finds a sent message by the ID its provider returned, e.g. for a delivery receipt
+	GetByProviderMessageID(provider, messageID string) (*domain.Message, error)
+	// MarkDelivered records that the recipient received the message, which stops its fallback chain
+	MarkDelivered(id int64) error
+	// CreateFallback creates the fallback message of msg.ParentID, or returns domain.ErrFallbackNotNeeded
+	// when the parent was delivered or already has one
+	CreateFallback(msg *domain.Message) error
+	CountCreatedSince(since time.Time) (int, error)
+	// CountByStatus returns the number of messages in every status that has any
+	CountByStatus() (map[domain.MessageStatus]int, error)
+	// TakeQueued marks a queued message as sending and reports false when it is no longer queued, e.g.
+	// because another consumer took it
+	TakeQueued(id int64) (bool, error)
+	// CountInFlight counts the queued and sending messages
+	CountInFlight() (int, error)
+	// RequeueStale moves the messages that stayed queued longer than visibilityTimeout after their claim
+	// back to pending and returns how many there were
+	RequeueStale(visibilityTimeout time.Duration) (int, error)
+	// RequeueFailed moves the failed messages created after since back to pending and returns how many there were
+	RequeueFailed(since time.Time) (int, error)
 }
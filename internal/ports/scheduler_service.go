@@ -2,6 +2,9 @@ package ports
 
 import (
 	"context"
+	"time"
+
+	"github.com/ercancavusoglu/messaging/internal/domain"
 )
 
 type SchedulerService interface {
@@ -9,3 +12,38 @@ type SchedulerService interface {
 	Stop()
 	IsRunning() bool
 }
+
+// SchedulerController changes the cluster-wide scheduler state, whichever replica serves the request
+type SchedulerController interface {
+	Start() error
+	Stop() error
+	IsRunning() bool
+	Status() (*domain.SchedulerStatus, error)
+	SetInterval(interval time.Duration) error
+}
+
+// LeaderLock is a lease held by at most one replica at a time
+type LeaderLock interface {
+	// Acquire takes the lease, or renews it when this replica already holds it, and reports whether it is the leader
+	Acquire(ctx context.Context) (bool, error)
+	Release(ctx context.Context) error
+	ID() string
+}
+
+// SchedulerState is the scheduler state shared by all replicas
+type SchedulerState interface {
+	SetDesiredRunning(ctx context.Context, running bool) error
+	DesiredRunning(ctx context.Context) (bool, error)
+	// MarkActive records that holder is running the ticker; the mark expires unless refreshed within ttl
+	MarkActive(ctx context.Context, holder string, ttl time.Duration) error
+	ClearActive(ctx context.Context, holder string) error
+	// ActiveHolder returns the replica running the ticker, or an empty string when none is
+	ActiveHolder(ctx context.Context) (string, error)
+	// SaveStatus stores the run statistics reported by the leader
+	SaveStatus(ctx context.Context, status domain.SchedulerStatus) error
+	// Status returns the last saved statistics, or nil when the scheduler has never ticked
+	Status(ctx context.Context) (*domain.SchedulerStatus, error)
+	SetInterval(ctx context.Context, interval time.Duration) error
+	// Interval returns the interval set through the API, or zero when the configured default applies
+	Interval(ctx context.Context) (time.Duration, error)
+}
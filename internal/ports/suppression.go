@@ -0,0 +1,29 @@
+package ports
+
+import (
+	"io"
+
+	"github.com/ercancavusoglu/messaging/internal/domain"
+)
+
+type SuppressionRepository interface {
+	// ForTenant returns a repository of the tenant's own suppressions; the unscoped repository holds the
+	// global ones that apply to every tenant
+	ForTenant(tenantID int64) SuppressionRepository
+	Add(suppression *domain.Suppression) error
+	Remove(recipient string) error
+	Get(recipient string) (*domain.Suppression, error)
+	List() ([]*domain.Suppression, error)
+}
+
+// SuppressionService manages the suppressions of a tenant, or the global ones when tenantID is zero
+type SuppressionService interface {
+	// IsSuppressed reports whether the recipient is suppressed for the tenant or globally
+	IsSuppressed(tenantID int64, recipient string) (bool, error)
+	Add(tenantID int64, recipient, reason, source string) (*domain.Suppression, error)
+	Remove(tenantID int64, recipient string) error
+	Get(tenantID int64, recipient string) (*domain.Suppression, error)
+	List(tenantID int64) ([]*domain.Suppression, error)
+	Import(tenantID int64, r io.Reader) (int, error)
+	Export(tenantID int64, w io.Writer) error
+}
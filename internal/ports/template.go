@@ -0,0 +1,22 @@
+package ports
+
+import "github.com/ercancavusoglu/messaging/internal/domain"
+
+type TemplateRepository interface {
+	// ForTenant returns a repository whose queries only see and create templates of the tenant
+	ForTenant(tenantID int64) TemplateRepository
+	Create(template *domain.Template) error
+	Update(template *domain.Template) error
+	Delete(id int64) error
+	Get(id int64) (*domain.Template, error)
+	List() ([]*domain.Template, error)
+}
+
+type TemplateService interface {
+	Create(tenantID int64, template *domain.Template) error
+	Update(tenantID int64, template *domain.Template) error
+	Delete(tenantID, id int64) error
+	Get(tenantID, id int64) (*domain.Template, error)
+	List(tenantID int64) ([]*domain.Template, error)
+	Render(tenantID, id int64, locale string, variables map[string]string) (string, error)
+}
@@ -0,0 +1,22 @@
+package ports
+
+import "github.com/ercancavusoglu/messaging/internal/domain"
+
+type TenantRepository interface {
+	Create(tenant *domain.Tenant) error
+	Update(tenant *domain.Tenant) error
+	UpdateAPIKeyHash(id int64, hash string) error
+	Get(id int64) (*domain.Tenant, error)
+	GetByAPIKeyHash(hash string) (*domain.Tenant, error)
+	List() ([]*domain.Tenant, error)
+}
+
+type TenantService interface {
+	// Create stores the tenant and returns its plain API key, which cannot be recovered later
+	Create(tenant *domain.Tenant) (string, error)
+	Update(tenant *domain.Tenant) error
+	RotateAPIKey(id int64) (string, error)
+	Get(id int64) (*domain.Tenant, error)
+	List() ([]*domain.Tenant, error)
+	Authenticate(apiKey string) (*domain.Tenant, error)
+}
@@ -2,6 +2,12 @@ package ports
 
 import "github.com/ercancavusoglu/messaging/internal/domain"
 
+// WebhookClient delivers a message through a provider of the message's channel
 type WebhookClient interface {
-	SendMessage(to, content string) (*domain.WebhookResponse, error)
+	SendMessage(msg *domain.Message) (*domain.WebhookResponse, error)
+}
+
+// WebhookClientResolver picks the client used to send a message of a channel on behalf of a tenant
+type WebhookClientResolver interface {
+	ClientFor(tenantID int64, channel domain.Channel) (WebhookClient, error)
 }